@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/api/routes"
+	"github.com/ericfisherdev/GoJira/internal/auth"
+	"github.com/ericfisherdev/GoJira/internal/config"
+	"github.com/ericfisherdev/GoJira/internal/server"
+)
+
+// Set at build time
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+)
+
+func main() {
+	configPath := flag.String("config", "", "directory containing gojira.yaml")
+	flag.Parse()
+
+	command := "serve"
+	if flag.NArg() > 0 {
+		command = flag.Arg(0)
+	}
+	if command == "version" {
+		fmt.Printf("gojira %s (built %s)\n", Version, BuildTime)
+		return
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gojira: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch command {
+	case "serve":
+		err = serve(cfg)
+	case "health":
+		err = health(cfg)
+	default:
+		err = fmt.Errorf("unknown command %q (serve, health, version)", command)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gojira: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// serve runs the API server until interrupted
+func serve(cfg *config.Config) error {
+	handlers.SetAuthManager(auth.NewManager(cfg))
+	if err := handlers.Configure(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	srv := server.New(&server.Config{
+		Port:        cfg.Server.Port,
+		Mode:        cfg.Server.Mode,
+		EnableCORS:  cfg.Security.EnableCORS,
+		LogRequests: true,
+	})
+	routes.SetupRoutes(srv.Router())
+	return srv.Start()
+}
+
+// health checks the server running on the configured port
+func health(cfg *config.Config) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://localhost:" + cfg.Server.Port + "/health")
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultCacheKeyLimit = 500
+	maxCacheKeyLimit     = 5000
+)
+
+// cacheLayers are the layers the admin API lists and flushes
+var cacheLayers = []string{cache.LayerL1, cache.LayerL2, cache.LayerL3, cache.LayerResponse}
+
+// SetStrategyRequest names the caching strategy of the multi-level cache
+type SetStrategyRequest struct {
+	Name string `json:"name"`
+}
+
+// GetCacheAdminStats returns the statistics of every administered cache
+func GetCacheAdminStats(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"strategies": cache.StrategyNames,
+	}
+	if mc := cache.GlobalAdmin.MultiLevelCache(); mc != nil {
+		data["multiLevel"] = mc.GetDetailedStats()
+	}
+
+	var responses []cache.CacheStats
+	for _, rc := range cache.GlobalAdmin.ResponseCaches() {
+		responses = append(responses, rc.GetStats())
+	}
+	data["response"] = responses
+
+	if reads := currentJiraReads(); reads != nil {
+		data["jiraCache"] = reads.GetStats()
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{Success: true, Data: data})
+}
+
+// ListCacheKeys lists the cached entries, filtered by key prefix, tag and
+// layer, without their values
+func ListCacheKeys(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix, tag, layer := query.Get("prefix"), query.Get("tag"), query.Get("layer")
+
+	if layer != "" && !isCacheLayer(layer) {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("unknown cache layer %q, expected one of %s", layer, strings.Join(cacheLayers, ", "))))
+		return
+	}
+
+	limit := defaultCacheKeyLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxCacheKeyLimit {
+			render.Render(w, r, ErrInvalidRequest(fmt.Errorf("limit must be between 1 and %d", maxCacheKeyLimit)))
+			return
+		}
+		limit = n
+	}
+
+	var entries []cache.EntryInfo
+	if mc := cache.GlobalAdmin.MultiLevelCache(); mc != nil {
+		entries = append(entries, mc.Entries(prefix, tag)...)
+	}
+	for _, rc := range cache.GlobalAdmin.ResponseCaches() {
+		for _, entry := range rc.Entries(prefix) {
+			if tag == "" || hasCacheTag(entry.Tags, tag) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	filtered := make([]cache.EntryInfo, 0, len(entries))
+	for _, entry := range entries {
+		if layer == "" || entry.Layer == layer {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	total := len(filtered)
+	if total > limit {
+		filtered = filtered[:limit]
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"entries":   filtered,
+			"total":     total,
+			"truncated": total > limit,
+		},
+	})
+}
+
+// GetCacheEntry returns the metadata of a key in every layer holding it
+func GetCacheEntry(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("key is required")))
+		return
+	}
+
+	entries := findCacheEntry(key)
+	if len(entries) == 0 {
+		render.Render(w, r, ErrNotFound("cache entry"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"key":     key,
+			"entries": entries,
+		},
+	})
+}
+
+// EvictCacheEntries evicts the entries named by exactly one of the key,
+// pattern (a regular expression) and tag parameters from every layer
+func EvictCacheEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key, pattern, tag := query.Get("key"), query.Get("pattern"), query.Get("tag")
+
+	given := 0
+	for _, value := range []string{key, pattern, tag} {
+		if value != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("exactly one of key, pattern and tag is required")))
+		return
+	}
+
+	mc := cache.GlobalAdmin.MultiLevelCache()
+	responses := cache.GlobalAdmin.ResponseCaches()
+	evicted := 0
+
+	switch {
+	case key != "":
+		evicted = len(findCacheEntry(key))
+		if mc != nil {
+			if err := mc.Delete(key); err != nil {
+				render.Render(w, r, ErrInternalServer(err))
+				return
+			}
+		}
+		for _, rc := range responses {
+			rc.Delete(key)
+		}
+
+	case pattern != "":
+		if _, err := regexp.Compile(pattern); err != nil {
+			render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid pattern: %w", err)))
+			return
+		}
+		if mc != nil {
+			evicted += mc.Invalidate(pattern)
+		}
+		for _, rc := range responses {
+			evicted += rc.InvalidatePattern(pattern)
+		}
+
+	default:
+		if mc != nil {
+			evicted += mc.InvalidateTags(tag)
+		}
+		for _, rc := range responses {
+			evicted += rc.InvalidateTags(tag)
+		}
+		if reads := currentJiraReads(); reads != nil {
+			evicted += reads.InvalidateTags(tag)
+		}
+	}
+
+	log.Info().
+		Str("key", key).
+		Str("pattern", pattern).
+		Str("tag", tag).
+		Int("evicted", evicted).
+		Msg("Cache entries evicted by admin request")
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"evicted": evicted,
+		},
+	})
+}
+
+// FlushCacheLayer removes every entry from one layer, or from every layer
+// when the layer is "all"
+func FlushCacheLayer(w http.ResponseWriter, r *http.Request) {
+	layer := chi.URLParam(r, "layer")
+	if layer != "all" && !isCacheLayer(layer) {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("unknown cache layer %q, expected all or one of %s", layer, strings.Join(cacheLayers, ", "))))
+		return
+	}
+
+	mc := cache.GlobalAdmin.MultiLevelCache()
+	var flushed []string
+
+	switch layer {
+	case "all":
+		if mc != nil {
+			if err := mc.Clear(); err != nil {
+				render.Render(w, r, ErrInternalServer(err))
+				return
+			}
+			flushed = append(flushed, cache.LayerL1, cache.LayerL2, cache.LayerL3)
+		}
+		for _, rc := range cache.GlobalAdmin.ResponseCaches() {
+			rc.Clear()
+		}
+		flushed = append(flushed, cache.LayerResponse)
+
+	case cache.LayerResponse:
+		for _, rc := range cache.GlobalAdmin.ResponseCaches() {
+			rc.Clear()
+		}
+		flushed = append(flushed, layer)
+
+	default:
+		if mc == nil {
+			render.Render(w, r, ErrNotFound("multi-level cache"))
+			return
+		}
+		if err := mc.FlushLayer(layer); err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+		flushed = append(flushed, layer)
+	}
+
+	log.Info().Str("layer", layer).Msg("Cache flushed by admin request")
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"flushed": flushed,
+		},
+	})
+}
+
+// GetCacheStrategy returns the strategy of the multi-level cache
+func GetCacheStrategy(w http.ResponseWriter, r *http.Request) {
+	mc := cache.GlobalAdmin.MultiLevelCache()
+	if mc == nil {
+		render.Render(w, r, ErrNotFound("multi-level cache"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"strategy":   mc.Strategy(),
+			"strategies": cache.StrategyNames,
+		},
+	})
+}
+
+// SetCacheStrategy switches the multi-level cache to a predefined strategy
+func SetCacheStrategy(w http.ResponseWriter, r *http.Request) {
+	mc := cache.GlobalAdmin.MultiLevelCache()
+	if mc == nil {
+		render.Render(w, r, ErrNotFound("multi-level cache"))
+		return
+	}
+
+	var req SetStrategyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid request body: %w", err)))
+		return
+	}
+
+	strategy, err := cache.StrategyByName(req.Name)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	mc.SetStrategy(strategy)
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"strategy": mc.Strategy(),
+		},
+	})
+}
+
+// findCacheEntry returns the metadata of a key in every administered layer
+func findCacheEntry(key string) []cache.EntryInfo {
+	var entries []cache.EntryInfo
+	if mc := cache.GlobalAdmin.MultiLevelCache(); mc != nil {
+		entries = append(entries, mc.Entry(key)...)
+	}
+	for _, rc := range cache.GlobalAdmin.ResponseCaches() {
+		if entry, ok := rc.Entry(key); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func isCacheLayer(layer string) bool {
+	for _, known := range cacheLayers {
+		if layer == known {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCacheTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
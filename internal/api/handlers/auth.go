@@ -8,6 +8,7 @@ import (
 
 	"github.com/ericfisherdev/GoJira/internal/auth"
 	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
 	"github.com/go-chi/render"
 )
 
@@ -101,6 +102,8 @@ func Connect(w http.ResponseWriter, r *http.Request) {
 	// Create and set Jira client for issue operations
 	jiraClient := jira.NewClient(jiraURL, authenticator, nil)
 	SetJiraClient(jiraClient)
+	startWarmup(services.WarmupOnConnect)
+	startMirror()
 
 	// Get user info
 	user, err := authenticator.GetUser()
@@ -146,6 +149,8 @@ func Disconnect(w http.ResponseWriter, r *http.Request) {
 	if authManager != nil {
 		authManager.SetCurrent("")
 	}
+	stopWarmup()
+	stopMirror()
 
 	response := &ConnectResponse{
 		Success: true,
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/ericfisherdev/GoJira/internal/jira"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/rs/zerolog/log"
@@ -13,7 +14,7 @@ import (
 
 // GetBoards retrieves all boards
 func GetBoards(w http.ResponseWriter, r *http.Request) {
-	boards, err := jiraClient.GetBoards()
+	boards, err := currentJiraReads().Boards(r.Context())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get boards")
 		render.Render(w, r, ErrInternalServer(err))
@@ -180,4 +181,101 @@ func MoveIssuesToBoard(w http.ResponseWriter, r *http.Request) {
 		"message": "Issues moved on board successfully",
 		"count":   len(req.Issues),
 	})
-}
\ No newline at end of file
+}
+
+// RankIssues ranks issues before or after another issue
+func RankIssues(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Issues            []string `json:"issues"`
+		RankBeforeIssue   string   `json:"rankBeforeIssue,omitempty"`
+		RankAfterIssue    string   `json:"rankAfterIssue,omitempty"`
+		RankCustomFieldID int      `json:"rankCustomFieldId,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if len(req.Issues) == 0 {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("at least one issue is required")))
+		return
+	}
+	if (req.RankBeforeIssue == "") == (req.RankAfterIssue == "") {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("exactly one of rankBeforeIssue or rankAfterIssue is required")))
+		return
+	}
+
+	result, err := jiraClient.RankIssues(req.Issues, req.RankBeforeIssue, req.RankAfterIssue, req.RankCustomFieldID)
+	if err != nil {
+		log.Error().Err(err).Interface("issues", req.Issues).Msg("Failed to rank issues")
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	renderRankResult(w, r, result, map[string]interface{}{
+		"message": "Issues ranked successfully",
+	})
+}
+
+// RankBacklogIssues moves issues to the top, the bottom or an explicit index of a board's backlog
+func RankBacklogIssues(w http.ResponseWriter, r *http.Request) {
+	boardIDStr := chi.URLParam(r, "id")
+	boardID, err := strconv.Atoi(boardIDStr)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid board ID")))
+		return
+	}
+
+	var req struct {
+		Issues   []string `json:"issues"`
+		Position string   `json:"position,omitempty"` // top or bottom
+		Index    *int     `json:"index,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if len(req.Issues) == 0 {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("at least one issue is required")))
+		return
+	}
+
+	var index int
+	switch {
+	case req.Index != nil:
+		index = *req.Index
+	case req.Position == "top":
+		index = 0
+	case req.Position == "bottom":
+		index = -1
+	default:
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("position must be 'top' or 'bottom', or an index must be given")))
+		return
+	}
+
+	result, err := jiraClient.RankInBacklog(boardID, req.Issues, index)
+	if err != nil {
+		log.Error().Err(err).Int("boardId", boardID).Interface("issues", req.Issues).Msg("Failed to rank backlog issues")
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	renderRankResult(w, r, result, map[string]interface{}{
+		"message": "Backlog issues ranked successfully",
+		"boardId": boardID,
+	})
+}
+
+// renderRankResult responds 200 when every issue was ranked, and 207 Multi-Status
+// naming the issues Jira refused otherwise
+func renderRankResult(w http.ResponseWriter, r *http.Request, result *jira.RankResult, response map[string]interface{}) {
+	response["success"] = result.Failed == 0
+	response["result"] = result
+	if result.Failed > 0 {
+		response["message"] = fmt.Sprintf("%d of %d issues could not be ranked", result.Failed, result.Ranked+result.Failed)
+		response["failedIssues"] = result.FailedIssues()
+		render.Status(r, http.StatusMultiStatus)
+	}
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/queue"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+var (
+	issueJobQueue *queue.JobQueue
+	issueJobMu    sync.RWMutex
+)
+
+// CloneIssueRequest represents a request to clone an issue
+type CloneIssueRequest struct {
+	jira.CloneOptions
+	Async bool `json:"async,omitempty"`
+}
+
+func (c *CloneIssueRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+// MoveIssueRequest represents a request to move an issue to another project
+type MoveIssueRequest struct {
+	jira.MoveOptions
+	Async bool `json:"async,omitempty"`
+}
+
+func (m *MoveIssueRequest) Bind(r *http.Request) error {
+	if m.TargetProject == "" {
+		return fmt.Errorf("targetProject is required")
+	}
+	return nil
+}
+
+// BatchMoveRequest represents a request to move several issues with the same options
+type BatchMoveRequest struct {
+	Issues  []string         `json:"issues"`
+	Options jira.MoveOptions `json:"options"`
+}
+
+func (b *BatchMoveRequest) Bind(r *http.Request) error {
+	if len(b.Issues) == 0 {
+		return fmt.Errorf("issues are required")
+	}
+	if len(b.Issues) > 100 {
+		return fmt.Errorf("too many issues (max 100)")
+	}
+	if b.Options.TargetProject == "" {
+		return fmt.Errorf("options.targetProject is required")
+	}
+	return nil
+}
+
+// CloneJobPayload is the payload of a CLONE_ISSUE job
+type CloneJobPayload struct {
+	IssueKey string            `json:"issueKey"`
+	Options  jira.CloneOptions `json:"options"`
+}
+
+// MoveJobPayload is the payload of a MOVE_ISSUE job
+type MoveJobPayload struct {
+	IssueKey string           `json:"issueKey"`
+	Options  jira.MoveOptions `json:"options"`
+}
+
+// registerIssueJobHandlers installs the clone and move executors on a job queue
+// and makes it the queue used by the asynchronous clone and move endpoints.
+// Failures are marked permanent: a partially completed clone must not be
+// retried, or the retry would create a duplicate issue.
+func registerIssueJobHandlers(q *queue.JobQueue) {
+	q.RegisterHandler(queue.JobTypeCloneIssue, func(ctx context.Context, job queue.Job) (interface{}, error) {
+		var payload CloneJobPayload
+		if err := decodeJobPayload(job.Payload, &payload); err != nil {
+			return nil, queue.Permanent(err)
+		}
+		if jiraClient == nil {
+			return nil, queue.Permanent(fmt.Errorf("not connected to Jira"))
+		}
+
+		result, err := jiraClient.CloneIssue(ctx, payload.IssueKey, payload.Options)
+		if err != nil {
+			return nil, queue.Permanent(err)
+		}
+		return result, nil
+	})
+
+	q.RegisterHandler(queue.JobTypeMoveIssue, func(ctx context.Context, job queue.Job) (interface{}, error) {
+		var payload MoveJobPayload
+		if err := decodeJobPayload(job.Payload, &payload); err != nil {
+			return nil, queue.Permanent(err)
+		}
+		if jiraClient == nil {
+			return nil, queue.Permanent(fmt.Errorf("not connected to Jira"))
+		}
+
+		result, err := jiraClient.MoveIssue(ctx, payload.IssueKey, payload.Options)
+		if err != nil {
+			return nil, queue.Permanent(err)
+		}
+		return result, nil
+	})
+
+	issueJobMu.Lock()
+	issueJobQueue = q
+	issueJobMu.Unlock()
+}
+
+// decodeJobPayload converts a job payload, which is a generic map when the job
+// was submitted through the queue API, into the typed payload
+func decodeJobPayload(payload interface{}, v interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+	return nil
+}
+
+// submitIssueJob queues a clone or move job and returns its ID
+func submitIssueJob(jobType queue.JobType, payload interface{}) (string, error) {
+	issueJobMu.RLock()
+	q := issueJobQueue
+	issueJobMu.RUnlock()
+
+	if q == nil {
+		return "", fmt.Errorf("job queue is not available")
+	}
+
+	job := queue.Job{
+		ID:      queue.NewJobID(jobType),
+		Type:    jobType,
+		Payload: payload,
+	}
+	if err := q.Submit(job); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// renderQueuedJob renders the response for an accepted asynchronous job
+func renderQueuedJob(w http.ResponseWriter, r *http.Request, issueKey, jobID string) {
+	render.Status(r, http.StatusAccepted)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"issue":  issueKey,
+			"jobId":  jobID,
+			"status": "queued",
+		},
+	})
+}
+
+// CloneIssue clones an issue, optionally with its subtasks, links and attachments
+func CloneIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var req CloneIssueRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if req.Async {
+		jobID, err := submitIssueJob(queue.JobTypeCloneIssue, CloneJobPayload{IssueKey: key, Options: req.CloneOptions})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		renderQueuedJob(w, r, key, jobID)
+		return
+	}
+
+	result, err := jiraClient.CloneIssue(r.Context(), key, req.CloneOptions)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// MoveIssue moves an issue to another project
+func MoveIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var req MoveIssueRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if req.Async {
+		jobID, err := submitIssueJob(queue.JobTypeMoveIssue, MoveJobPayload{IssueKey: key, Options: req.MoveOptions})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		renderQueuedJob(w, r, key, jobID)
+		return
+	}
+
+	result, err := jiraClient.MoveIssue(r.Context(), key, req.MoveOptions)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// BatchMoveIssues queues one move job per issue
+func BatchMoveIssues(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req BatchMoveRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	jobs := make(map[string]string, len(req.Issues))
+	failed := make(map[string]string)
+	for _, key := range req.Issues {
+		jobID, err := submitIssueJob(queue.JobTypeMoveIssue, MoveJobPayload{IssueKey: key, Options: req.Options})
+		if err != nil {
+			failed[key] = err.Error()
+			continue
+		}
+		jobs[key] = jobID
+	}
+
+	if len(jobs) == 0 {
+		render.Render(w, r, ErrInternalServer(fmt.Errorf("no move jobs could be queued")))
+		return
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"jobs":      jobs,
+			"failed":    failed,
+			"submitted": len(jobs),
+		},
+	})
+}
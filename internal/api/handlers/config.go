@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/config"
+	"github.com/ericfisherdev/GoJira/internal/mirror"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/ericfisherdev/GoJira/internal/webhook"
+)
+
+// Configure applies the settings of the configuration file the handlers need
+// before serving requests
+func Configure(cfg *config.Config) error {
+	if cfg == nil {
+		return nil
+	}
+
+	SetWebhookVerifier(&webhook.Verifier{
+		Secret:    cfg.Webhooks.Secret,
+		JWTSecret: cfg.Webhooks.JWTSecret,
+		JWTIssuer: cfg.Webhooks.JWTIssuer,
+	})
+
+	profiles := make([]services.WarmupProfile, 0, len(cfg.Warmup.Profiles))
+	for _, profile := range cfg.Warmup.Profiles {
+		profiles = append(profiles, services.WarmupProfile{
+			Name:    profile.Name,
+			Targets: profile.Targets,
+			Boards:  profile.Boards,
+		})
+	}
+	if err := SetWarmupProfiles(profiles, time.Duration(cfg.Warmup.Interval)*time.Minute); err != nil {
+		return fmt.Errorf("invalid warm-up configuration: %w", err)
+	}
+
+	if cfg.Mirror.Dir != "" {
+		err := SetOfflineMirror(cfg.Mirror.Dir, mirror.Options{
+			Projects:      cfg.Mirror.Projects,
+			Interval:      time.Duration(cfg.Mirror.Interval) * time.Minute,
+			ProbeInterval: time.Duration(cfg.Mirror.ProbeInterval) * time.Second,
+			PageSize:      cfg.Mirror.PageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open offline mirror: %w", err)
+		}
+	}
+
+	if cfg.Cache.L2.Addr != "" {
+		err := SetSharedCache(cache.RedisOptions{
+			Addr:       cfg.Cache.L2.Addr,
+			Password:   cfg.Cache.L2.Password,
+			DB:         cfg.Cache.L2.DB,
+			Prefix:     cfg.Cache.L2.Prefix,
+			PoolSize:   cfg.Cache.L2.PoolSize,
+			DefaultTTL: time.Duration(cfg.Cache.L2.TTL) * time.Minute,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect shared cache: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+var (
+	createMetaService   *services.CreateMetaService
+	createMetaServiceMu sync.Mutex
+)
+
+// SetCreateMetaService sets the global create metadata service
+func SetCreateMetaService(service *services.CreateMetaService) {
+	createMetaServiceMu.Lock()
+	defer createMetaServiceMu.Unlock()
+	createMetaService = service
+}
+
+// GetCreateMetaService returns the create metadata service for the current Jira
+// client, creating a new one whenever the client changes
+func GetCreateMetaService() *services.CreateMetaService {
+	createMetaServiceMu.Lock()
+	defer createMetaServiceMu.Unlock()
+
+	if jiraClient == nil {
+		return createMetaService
+	}
+	if createMetaService == nil || createMetaService.Client() != services.CreateMetaClient(jiraClient) {
+		createMetaService = services.NewCreateMetaService(jiraClient)
+	}
+	return createMetaService
+}
+
+// validateCreateFields validates and coerces issue fields against the create
+// metadata of a project and issue type. User references are resolved through
+// the user directory, scoped to the project.
+func validateCreateFields(projectKey, issueType string, fields map[string]interface{}) (*jira.FieldValidationResult, error) {
+	resolveUser := func(reference string) (map[string]interface{}, error) {
+		value, resolution, err := resolveAssigneeField(reference, jira.AssignableUserQuery{ProjectKey: projectKey})
+		if err != nil {
+			return nil, err
+		}
+		if resolution != nil {
+			if resolution.Ambiguous {
+				return nil, fmt.Errorf("'%s' matches %d users", reference, len(resolution.Candidates))
+			}
+			return nil, fmt.Errorf("no user matches '%s'", reference)
+		}
+		return value, nil
+	}
+
+	return GetCreateMetaService().Validate(projectKey, issueType, fields, resolveUser)
+}
+
+// renderFieldValidation reports missing and invalid fields in a create request
+func renderFieldValidation(w http.ResponseWriter, r *http.Request, result *jira.FieldValidationResult) {
+	render.Status(r, http.StatusUnprocessableEntity)
+	render.Render(w, r, &IssueResponse{
+		Success: false,
+		Data:    result,
+		Error:   fmt.Sprintf("%d missing and %d invalid fields", len(result.Missing), len(result.Invalid)),
+	})
+}
+
+// ValidateCreateIssue validates an issue create request without creating the issue
+func ValidateCreateIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req CreateIssueRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	fields, resolution, err := buildCreateIssueFields(&req)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+	if resolution != nil {
+		renderUnresolvedUser(w, r, resolution)
+		return
+	}
+
+	result, err := validateCreateFields(req.Project, req.IssueType, fields)
+	if err != nil {
+		renderProjectError(w, r, "create metadata", err)
+		return
+	}
+
+	if !result.Valid {
+		renderFieldValidation(w, r, result)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// GetProjectCreateMeta returns the create metadata for an issue type in a project
+func GetProjectCreateMeta(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+	issueType := r.URL.Query().Get("issueType")
+
+	if issueType == "" {
+		issueTypes, err := jiraClient.GetCreateMetaIssueTypes(projectKey)
+		if err != nil {
+			renderProjectError(w, r, "project", err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.Render(w, r, &IssueResponse{
+			Success: true,
+			Data: map[string]interface{}{
+				"projectKey": projectKey,
+				"issueTypes": issueTypes,
+			},
+		})
+		return
+	}
+
+	meta, err := GetCreateMetaService().Get(projectKey, issueType)
+	if err != nil {
+		renderProjectError(w, r, "create metadata", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"createMeta":     meta,
+			"requiredFields": meta.RequiredFields(),
+		},
+	})
+}
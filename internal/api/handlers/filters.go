@@ -0,0 +1,617 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// FilterShareRequest shares a filter using readable references, which are
+// resolved to the IDs Jira expects
+type FilterShareRequest struct {
+	Type    string `json:"type"`              // global, authenticated, loggedin, project, projectRole, group, user
+	Project string `json:"project,omitempty"` // project key or ID
+	Role    string `json:"role,omitempty"`    // project role name or ID
+	Group   string `json:"group,omitempty"`
+	User    string `json:"user,omitempty"` // account ID, username, email or display name
+}
+
+func (s *FilterShareRequest) Bind(r *http.Request) error {
+	return s.validate()
+}
+
+func (s *FilterShareRequest) validate() error {
+	switch normalizeShareType(s.Type, s.Role) {
+	case jira.ShareGlobal, jira.ShareAuthenticated, jira.ShareLoggedIn:
+	case jira.ShareProject:
+		if s.Project == "" {
+			return fmt.Errorf("project is required to share with a project")
+		}
+	case jira.ShareProjectRole:
+		if s.Project == "" || s.Role == "" {
+			return fmt.Errorf("project and role are required to share with a project role")
+		}
+	case jira.ShareGroup:
+		if s.Group == "" {
+			return fmt.Errorf("group is required to share with a group")
+		}
+	case jira.ShareUser:
+		if s.User == "" {
+			return fmt.Errorf("user is required to share with a user")
+		}
+	default:
+		return fmt.Errorf("unsupported share type '%s', supported types: global, authenticated, loggedin, project, projectRole, group, user", s.Type)
+	}
+	return nil
+}
+
+// CreateFilterRequest represents a request to save a filter
+type CreateFilterRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	JQL         string               `json:"jql"`
+	Favourite   *bool                `json:"favourite,omitempty"`
+	Share       []FilterShareRequest `json:"share,omitempty"`
+}
+
+func (c *CreateFilterRequest) Bind(r *http.Request) error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if c.JQL == "" {
+		return fmt.Errorf("jql is required")
+	}
+	for i := range c.Share {
+		if err := c.Share[i].validate(); err != nil {
+			return fmt.Errorf("share %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// UpdateFilterRequest represents a partial update of a filter
+type UpdateFilterRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	JQL         *string `json:"jql,omitempty"`
+	Favourite   *bool   `json:"favourite,omitempty"`
+}
+
+func (u *UpdateFilterRequest) Bind(r *http.Request) error {
+	if u.Name == nil && u.Description == nil && u.JQL == nil && u.Favourite == nil {
+		return fmt.Errorf("at least one of name, description, jql or favourite is required")
+	}
+	if u.Name != nil && *u.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if u.JQL != nil && *u.JQL == "" {
+		return fmt.Errorf("jql cannot be empty")
+	}
+	return nil
+}
+
+// FilterColumnsRequest represents the columns of a filter, as field IDs in display order
+type FilterColumnsRequest struct {
+	Columns []string `json:"columns"`
+}
+
+func (f *FilterColumnsRequest) Bind(r *http.Request) error {
+	if len(f.Columns) == 0 {
+		return fmt.Errorf("columns are required")
+	}
+	return nil
+}
+
+// normalizeShareType maps share type aliases to Jira's names; a project
+// share with a role is a project role share
+func normalizeShareType(shareType, role string) string {
+	switch strings.ToLower(strings.ReplaceAll(shareType, "-", "")) {
+	case "global", "public":
+		return jira.ShareGlobal
+	case "authenticated":
+		return jira.ShareAuthenticated
+	case "loggedin":
+		return jira.ShareLoggedIn
+	case "project":
+		if role != "" {
+			return jira.ShareProjectRole
+		}
+		return jira.ShareProject
+	case "projectrole", "role":
+		return jira.ShareProjectRole
+	case "group":
+		return jira.ShareGroup
+	case "user":
+		return jira.ShareUser
+	}
+	return shareType
+}
+
+// resolveFilterShare converts a share request into the permission Jira
+// expects. A non-nil resolution is returned when the user is unknown or ambiguous.
+func resolveFilterShare(ctx context.Context, share FilterShareRequest) (jira.SharePermissionRequest, *services.UserResolution, error) {
+	permission := jira.SharePermissionRequest{Type: normalizeShareType(share.Type, share.Role)}
+
+	switch permission.Type {
+	case jira.ShareProject, jira.ShareProjectRole:
+		permission.ProjectID = share.Project
+		if _, err := strconv.Atoi(share.Project); err != nil {
+			project, err := jiraClient.GetProject(ctx, share.Project)
+			if err != nil {
+				return permission, nil, fmt.Errorf("failed to find project %s: %w", share.Project, err)
+			}
+			permission.ProjectID = project.ID
+		}
+		if permission.Type == jira.ShareProjectRole {
+			roleID, err := resolveProjectRoleID(ctx, share.Project, share.Role)
+			if err != nil {
+				return permission, nil, err
+			}
+			permission.ProjectRoleID = roleID
+		}
+	case jira.ShareGroup:
+		permission.GroupName = share.Group
+	case jira.ShareUser:
+		user, resolution, err := resolveUserReference(share.User, jira.AssignableUserQuery{})
+		if err != nil || resolution != nil {
+			return permission, resolution, err
+		}
+		if user.AccountID == "" {
+			return permission, nil, fmt.Errorf("sharing with a user requires an account ID")
+		}
+		permission.AccountID = user.AccountID
+	}
+	return permission, nil, nil
+}
+
+// resolveProjectRoleID resolves a role name to its ID within a project
+func resolveProjectRoleID(ctx context.Context, project, role string) (string, error) {
+	if _, err := strconv.Atoi(role); err == nil {
+		return role, nil
+	}
+
+	roles, err := jiraClient.GetProjectRoles(ctx, project)
+	if err != nil {
+		return "", fmt.Errorf("failed to get roles of project %s: %w", project, err)
+	}
+	for _, candidate := range roles {
+		if strings.EqualFold(candidate.Name, role) {
+			return strconv.Itoa(candidate.ID), nil
+		}
+	}
+	return "", fmt.Errorf("project %s has no role '%s'", project, role)
+}
+
+// resolveFilterShares resolves the shares of a new filter. A non-nil
+// resolution is returned when a user is unknown or ambiguous.
+func resolveFilterShares(ctx context.Context, shares []FilterShareRequest) ([]jira.SharePermissionRequest, *services.UserResolution, error) {
+	permissions := make([]jira.SharePermissionRequest, 0, len(shares))
+	for _, share := range shares {
+		permission, resolution, err := resolveFilterShare(ctx, share)
+		if err != nil || resolution != nil {
+			return nil, resolution, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, nil, nil
+}
+
+// checkFilterJQL rejects JQL that fails the local checks before it is saved
+func checkFilterJQL(w http.ResponseWriter, r *http.Request, jql string) bool {
+	if _, details := checkJQL(jql); len(details) > 0 {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.Render(w, r, &IssueResponse{
+			Success: false,
+			Data: map[string]interface{}{
+				"jql":     jql,
+				"details": details,
+			},
+			Error: "invalid JQL: " + strings.Join(jqlErrorMessages(details), "; "),
+		})
+		return false
+	}
+	return true
+}
+
+// renderFilterError reports a missing filter as not found and anything else as an internal error
+func renderFilterError(w http.ResponseWriter, r *http.Request, err error) {
+	message := err.Error()
+	if strings.Contains(message, "404") || strings.Contains(message, "filter not found") ||
+		strings.Contains(message, "not available to you") || strings.Contains(message, "does not exist") {
+		render.Render(w, r, ErrNotFound("filter"))
+		return
+	}
+	render.Render(w, r, ErrInternalServer(err))
+}
+
+// CreateFilter saves a JQL query as a named filter, optionally shared and marked as favourite
+func CreateFilter(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req CreateFilterRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if !checkFilterJQL(w, r, req.JQL) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	share, resolution, err := resolveFilterShares(ctx, req.Share)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if resolution != nil {
+		renderUnresolvedUser(w, r, resolution)
+		return
+	}
+
+	filter, err := jiraClient.CreateFilter(&jira.FilterRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		JQL:         req.JQL,
+		Favourite:   req.Favourite,
+		Share:       share,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    filter,
+	})
+}
+
+// UpdateFilter updates a filter; fields that are not given keep their value
+func UpdateFilter(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	var req UpdateFilterRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if req.JQL != nil && !checkFilterJQL(w, r, *req.JQL) {
+		return
+	}
+
+	// Jira replaces the name and description, so unchanged values are sent back
+	current, err := jiraClient.GetFilter(filterID)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	update := &jira.FilterRequest{
+		Name:        current.Name,
+		Description: current.Description,
+		JQL:         current.JQL,
+		Favourite:   req.Favourite,
+	}
+	if req.Name != nil {
+		update.Name = *req.Name
+	}
+	if req.Description != nil {
+		update.Description = *req.Description
+	}
+	if req.JQL != nil {
+		update.JQL = *req.JQL
+	}
+
+	filter, err := jiraClient.UpdateFilter(filterID, update)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    filter,
+	})
+}
+
+// DeleteFilter deletes a filter
+func DeleteFilter(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	if err := jiraClient.DeleteFilter(filterID); err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID": filterID,
+			"deleted":  true,
+		},
+	})
+}
+
+// GetFavouriteFilters lists the current user's favourite filters
+func GetFavouriteFilters(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filters, err := jiraClient.GetFavouriteFilters()
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filters": filters,
+			"count":   len(filters),
+		},
+	})
+}
+
+// FavouriteFilter adds a filter to the current user's favourites
+func FavouriteFilter(w http.ResponseWriter, r *http.Request) {
+	setFilterFavourite(w, r, true)
+}
+
+// UnfavouriteFilter removes a filter from the current user's favourites
+func UnfavouriteFilter(w http.ResponseWriter, r *http.Request) {
+	setFilterFavourite(w, r, false)
+}
+
+func setFilterFavourite(w http.ResponseWriter, r *http.Request, favourite bool) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	filter, err := jiraClient.SetFilterFavourite(filterID, favourite)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    filter,
+	})
+}
+
+// GetFilterPermissions lists the share permissions of a filter
+func GetFilterPermissions(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	permissions, err := jiraClient.GetFilterSharePermissions(filterID)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID":    filterID,
+			"permissions": permissions,
+		},
+	})
+}
+
+// AddFilterPermission shares a filter with a project, role, group, user or everyone
+func AddFilterPermission(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	var req FilterShareRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	permission, resolution, err := resolveFilterShare(ctx, req)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if resolution != nil {
+		renderUnresolvedUser(w, r, resolution)
+		return
+	}
+
+	permissions, err := jiraClient.AddFilterSharePermission(filterID, permission)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID":    filterID,
+			"permissions": permissions,
+		},
+	})
+}
+
+// DeleteFilterPermission removes a share permission from a filter
+func DeleteFilterPermission(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+	permissionID, err := strconv.Atoi(chi.URLParam(r, "permissionId"))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid permission ID")))
+		return
+	}
+
+	if err := jiraClient.DeleteFilterSharePermission(filterID, permissionID); err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID":     filterID,
+			"permissionID": permissionID,
+			"deleted":      true,
+		},
+	})
+}
+
+// GetFilterColumns lists the columns shown when the filter is opened in Jira
+func GetFilterColumns(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	columns, err := jiraClient.GetFilterColumns(filterID)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID": filterID,
+			"columns":  columns,
+		},
+	})
+}
+
+// SetFilterColumns sets the columns of a filter
+func SetFilterColumns(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	var req FilterColumnsRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := jiraClient.SetFilterColumns(filterID, req.Columns); err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID": filterID,
+			"columns":  req.Columns,
+		},
+	})
+}
+
+// ResetFilterColumns resets the columns of a filter to the user's defaults
+func ResetFilterColumns(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	if err := jiraClient.ResetFilterColumns(filterID); err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID": filterID,
+			"reset":    true,
+		},
+	})
+}
+
+// GetFilterSubscriptions lists the email subscriptions of a filter
+func GetFilterSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	filterID := chi.URLParam(r, "id")
+
+	subscriptions, err := jiraClient.GetFilterSubscriptions(filterID)
+	if err != nil {
+		renderFilterError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"filterID":      filterID,
+			"subscriptions": subscriptions,
+		},
+	})
+}
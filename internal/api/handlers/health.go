@@ -4,14 +4,18 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/mirror"
+	"github.com/ericfisherdev/GoJira/internal/services"
 	"github.com/go-chi/render"
 )
 
 type HealthResponse struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Version   string    `json:"version,omitempty"`
-	Uptime    string    `json:"uptime,omitempty"`
+	Status    string                   `json:"status"`
+	Timestamp time.Time                `json:"timestamp"`
+	Version   string                   `json:"version,omitempty"`
+	Uptime    string                   `json:"uptime,omitempty"`
+	Warmup    *services.WarmupProgress `json:"warmup,omitempty"` // cache warm-up, once connected to Jira
+	Mirror    *mirror.Status           `json:"mirror,omitempty"` // offline mirror, when configured
 }
 
 func (hr *HealthResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -27,6 +31,8 @@ func HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Status:    "ok",
 		Timestamp: time.Now(),
 		Uptime:    uptime,
+		Warmup:    warmupProgress(),
+		Mirror:    mirrorStatus(),
 	}
 
 	render.Status(r, http.StatusOK)
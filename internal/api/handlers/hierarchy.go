@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+var (
+	hierarchyService   *services.HierarchyService
+	hierarchyServiceMu sync.Mutex
+)
+
+// SetHierarchyService sets the global hierarchy service
+func SetHierarchyService(service *services.HierarchyService) {
+	hierarchyServiceMu.Lock()
+	defer hierarchyServiceMu.Unlock()
+	hierarchyService = service
+}
+
+// GetHierarchyService returns the hierarchy service for the current Jira client,
+// creating a new one whenever the client changes
+func GetHierarchyService() *services.HierarchyService {
+	hierarchyServiceMu.Lock()
+	defer hierarchyServiceMu.Unlock()
+
+	if jiraClient == nil {
+		return hierarchyService
+	}
+	if hierarchyService == nil || hierarchyService.Client() != services.HierarchyClient(jiraClient) {
+		hierarchyService = services.NewHierarchyService(jiraClient)
+	}
+	return hierarchyService
+}
+
+// CreateSubtaskRequest represents a request to create a subtask
+type CreateSubtaskRequest struct {
+	Summary      string                 `json:"summary"`
+	Description  string                 `json:"description,omitempty"`
+	IssueType    string                 `json:"issueType,omitempty"`
+	Priority     string                 `json:"priority,omitempty"`
+	Assignee     string                 `json:"assignee,omitempty"`
+	Labels       []string               `json:"labels,omitempty"`
+	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+}
+
+func (cs *CreateSubtaskRequest) Bind(r *http.Request) error {
+	if cs.Summary == "" {
+		return fmt.Errorf("summary is required")
+	}
+	return nil
+}
+
+// SetParentRequest represents a request to set or remove the parent of an issue
+type SetParentRequest struct {
+	Parent string `json:"parent"` // empty removes the parent
+}
+
+func (sp *SetParentRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+func parseDepth(r *http.Request, def int) int {
+	if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+		if val, err := strconv.Atoi(depthStr); err == nil && val > 0 {
+			return val
+		}
+	}
+	return def
+}
+
+// CreateSubtask creates a subtask under an issue
+func CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var req CreateSubtaskRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	subtask := jira.SubtaskRequest{
+		Summary:     req.Summary,
+		Description: req.Description,
+		IssueType:   req.IssueType,
+		Priority:    req.Priority,
+		Labels:      req.Labels,
+		Fields:      req.CustomFields,
+	}
+
+	if req.Assignee != "" {
+		assignee, resolution, err := resolveAssigneeField(req.Assignee, jira.AssignableUserQuery{IssueKey: key})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		if resolution != nil {
+			renderUnresolvedUser(w, r, resolution)
+			return
+		}
+		subtask.Assignee = assignee
+	}
+
+	issue, err := GetHierarchyService().CreateSubtask(key, subtask)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"parent":  key,
+			"subtask": issue,
+		},
+	})
+}
+
+// SetIssueParent sets, changes or removes the parent (or epic) of an issue
+func SetIssueParent(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var req SetParentRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if req.Parent == key {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("an issue cannot be its own parent")))
+		return
+	}
+
+	if err := GetHierarchyService().SetParent(key, req.Parent); err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	message := fmt.Sprintf("Parent of %s set to %s", key, req.Parent)
+	if req.Parent == "" {
+		message = fmt.Sprintf("Parent of %s removed", key)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": message,
+			"issue":   key,
+			"parent":  req.Parent,
+		},
+	})
+}
+
+// GetIssueChildren lists the children of an issue. With recursive=true the whole
+// hierarchy below the issue is returned as a tree.
+func GetIssueChildren(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	service := GetHierarchyService()
+
+	if r.URL.Query().Get("recursive") == "true" {
+		tree, err := service.Tree(key, parseDepth(r, 0))
+		if err != nil {
+			renderProjectError(w, r, "issue", err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.Render(w, r, &IssueResponse{
+			Success: true,
+			Data: map[string]interface{}{
+				"issue": key,
+				"tree":  tree,
+				"count": len(tree.Flatten()),
+			},
+		})
+		return
+	}
+
+	children, err := service.Children(key)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"issue":    key,
+			"children": children,
+			"count":    len(children),
+		},
+	})
+}
+
+// GetIssueRollup computes status counts, story point totals and percent done
+// over all descendants of an issue
+func GetIssueRollup(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	tree, rollup, err := GetHierarchyService().Rollup(key, parseDepth(r, 0))
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"issue":   key,
+		"summary": tree.Summary,
+		"rollup":  rollup,
+	}
+	if r.URL.Query().Get("includeTree") == "true" {
+		data["tree"] = tree
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    data,
+	})
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/queue"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	importService   *services.ImportService
+	importServiceMu sync.Mutex
+)
+
+// maxImportRows bounds the size of a single import request
+const maxImportRows = 5000
+
+// ImportIssuesRequest represents a request to import issues from CSV or JSON
+type ImportIssuesRequest struct {
+	jira.ImportRequest
+}
+
+func (i *ImportIssuesRequest) Bind(r *http.Request) error {
+	if i.Format == "" {
+		i.Format = jira.ImportCSV
+		if i.Records != nil {
+			i.Format = jira.ImportJSON
+		}
+	}
+	if i.Data == "" && i.Records == nil {
+		return fmt.Errorf("data or records are required")
+	}
+	if i.ChunkSize < 0 || i.ChunkSize > jira.MaxImportChunkSize {
+		return fmt.Errorf("chunkSize must be between 1 and %d", jira.MaxImportChunkSize)
+	}
+	return nil
+}
+
+// ImportChunkJobPayload is the payload of an IMPORT_ISSUES job
+type ImportChunkJobPayload struct {
+	ImportID string `json:"importId"`
+	Rows     []int  `json:"rows"`
+}
+
+// SetImportService sets the global import service
+func SetImportService(service *services.ImportService) {
+	importServiceMu.Lock()
+	defer importServiceMu.Unlock()
+	importService = service
+}
+
+// GetImportService returns the import service for the current Jira client,
+// creating a new one whenever the client changes. Chunks are executed as
+// IMPORT_ISSUES jobs on the issue job queue.
+func GetImportService() *services.ImportService {
+	importServiceMu.Lock()
+	defer importServiceMu.Unlock()
+
+	if jiraClient == nil {
+		return importService
+	}
+	if importService == nil || importService.Client() != services.ImportClient(jiraClient) {
+		importService = services.NewImportService(jiraClient, func(importID string, rows []int) error {
+			_, err := submitIssueJob(queue.JobTypeImportIssues, ImportChunkJobPayload{ImportID: importID, Rows: rows})
+			return err
+		})
+	}
+	return importService
+}
+
+// registerImportJobHandler installs the import chunk executor on a job queue.
+// A chunk must not be retried: its issues may already have been created.
+func registerImportJobHandler(q *queue.JobQueue) {
+	q.RegisterHandler(queue.JobTypeImportIssues, func(ctx context.Context, job queue.Job) (interface{}, error) {
+		var payload ImportChunkJobPayload
+		if err := decodeJobPayload(job.Payload, &payload); err != nil {
+			return nil, queue.Permanent(err)
+		}
+
+		importServiceMu.Lock()
+		service := importService
+		importServiceMu.Unlock()
+		if service == nil {
+			return nil, queue.Permanent(fmt.Errorf("import %s not found", payload.ImportID))
+		}
+
+		if err := service.RunChunk(ctx, payload.ImportID, payload.Rows); err != nil {
+			return nil, queue.Permanent(err)
+		}
+		return map[string]interface{}{"importId": payload.ImportID, "rows": len(payload.Rows)}, nil
+	})
+}
+
+// ImportIssues validates a CSV or JSON import against the create metadata and,
+// unless it is a dry run, queues the import in chunks. The response carries a
+// per-row report; an import with invalid rows is rejected unless skipInvalid is set.
+func ImportIssues(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req ImportIssuesRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	rows, err := jira.ParseImport(req.ImportRequest)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	if len(rows) == 0 {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("the import contains no rows")))
+		return
+	}
+	if len(rows) > maxImportRows {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("too many rows (%d, max %d)", len(rows), maxImportRows)))
+		return
+	}
+
+	plan := jira.PlanImport(rows, req.ImportRequest, validateCreateFields)
+
+	if req.DryRun {
+		render.Status(r, http.StatusOK)
+		render.Render(w, r, &IssueResponse{
+			Success: true,
+			Data:    plan,
+		})
+		return
+	}
+
+	if !plan.Valid && (!req.SkipInvalid || plan.Summary.Valid == 0) {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.Render(w, r, &IssueResponse{
+			Success: false,
+			Data:    plan,
+			Error:   fmt.Sprintf("%d invalid and %d blocked rows", plan.Summary.Invalid, plan.Summary.Blocked),
+		})
+		return
+	}
+
+	// The Epic Link field is only looked up when a row has an epic
+	epicLinkField := ""
+	for _, row := range plan.Rows {
+		if row.EpicRef == "" {
+			continue
+		}
+		if hf, err := jiraClient.GetHierarchyFields(); err != nil {
+			log.Warn().Err(err).Msg("Failed to discover the Epic Link field; epics are set as parents")
+		} else {
+			epicLinkField = hf.EpicLinkField
+		}
+		break
+	}
+
+	run, err := GetImportService().Start(plan, req.ChunkSize, epicLinkField)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    run,
+	})
+}
+
+// GetImportStatus returns the progress and per-row results of an import
+func GetImportStatus(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	id := chi.URLParam(r, "importId")
+	service := GetImportService()
+	if service == nil {
+		render.Render(w, r, ErrNotFound("import"))
+		return
+	}
+
+	run, ok := service.Get(id)
+	if !ok {
+		render.Render(w, r, ErrNotFound("import"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    run,
+	})
+}
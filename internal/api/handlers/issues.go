@@ -6,19 +6,75 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/cache"
 	"github.com/ericfisherdev/GoJira/internal/claude"
 	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
 )
 
-var jiraClient *jira.Client
+var (
+	jiraClient *jira.Client
 
-// SetJiraClient sets the global Jira client
+	jiraResponsesMu         sync.Mutex
+	jiraResponses           *cache.ValidatedResponseCache
+	unregisterJiraResponses func()
+)
+
+// validatedResponseTTL is how long Jira responses are kept for revalidation
+const validatedResponseTTL = 30 * time.Minute
+
+// SetJiraClient sets the global Jira client. Writes made through the client
+// invalidate the cached data they change.
 func SetJiraClient(client *jira.Client) {
+	if client != nil {
+		client.OnMutation(publishMutation)
+	}
+	resetJiraResponses(client)
+	// The read cache is replaced together with the client it reads through
+	warmupMu.Lock()
 	jiraClient = client
+	resetJiraReads(client)
+	warmupMu.Unlock()
+	setMirrorClient(client)
+}
+
+// resetJiraResponses replaces the responses kept for conditional revalidation
+// with an empty store for a new client
+func resetJiraResponses(client *jira.Client) {
+	jiraResponsesMu.Lock()
+	defer jiraResponsesMu.Unlock()
+
+	if unregisterJiraResponses != nil {
+		unregisterJiraResponses()
+		unregisterJiraResponses = nil
+	}
+	if jiraResponses != nil {
+		jiraResponses.Stop()
+		jiraResponses = nil
+	}
+	if client == nil {
+		return
+	}
+
+	jiraResponses = cache.NewValidatedResponseCache(1000, validatedResponseTTL)
+	unregisterBus := cache.GlobalInvalidationBus.Register(jiraResponses)
+	unregisterAdmin := cache.GlobalAdmin.RegisterResponseCache(jiraResponses.Responses())
+	unregisterJiraResponses = func() {
+		unregisterBus()
+		unregisterAdmin()
+	}
+	client.SetResponseStore(jiraResponses)
+}
+
+// publishMutation evicts the cached data changed by a Jira write
+func publishMutation(m jira.Mutation) {
+	cache.GlobalInvalidationBus.PublishMutation(m)
 }
 
 type CreateIssueRequest struct {
@@ -32,6 +88,7 @@ type CreateIssueRequest struct {
 	Components  []string          `json:"components,omitempty"`
 	Parent      string            `json:"parent,omitempty"` // For subtasks
 	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+	SkipValidation bool             `json:"skipValidation,omitempty"`
 }
 
 func (cir *CreateIssueRequest) Bind(r *http.Request) error {
@@ -99,25 +156,35 @@ type IssueResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   interface{} `json:"error,omitempty"`
+	// Offline marks data answered from the local mirror, or a write held
+	// until Jira is reachable again
+	Offline   bool       `json:"offline,omitempty"`
+	StaleAsOf *time.Time `json:"staleAsOf,omitempty"`
 }
 
 func (ir *IssueResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-// CreateIssue creates a new Jira issue
-func CreateIssue(w http.ResponseWriter, r *http.Request) {
-	if jiraClient == nil || !authManager.IsAuthenticated() {
-		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
-		return
-	}
+// buildCreateIssueFields converts a create request into Jira issue fields. A non-nil
+// resolution is returned when the assignee could not be resolved to a single user.
+func buildCreateIssueFields(req *CreateIssueRequest) (map[string]interface{}, *services.UserResolution, error) {
+	fields := createIssueFields(req)
 
-	var req CreateIssueRequest
-	if err := render.Bind(r, &req); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
+	if req.Assignee != "" {
+		assignee, resolution, err := resolveAssigneeField(req.Assignee, jira.AssignableUserQuery{ProjectKey: req.Project})
+		if err != nil || resolution != nil {
+			return nil, resolution, err
+		}
+		fields["assignee"] = assignee
 	}
 
+	return fields, nil, nil
+}
+
+// createIssueFields converts a create request into Jira issue fields, leaving out
+// the assignee, which has to be resolved against Jira
+func createIssueFields(req *CreateIssueRequest) map[string]interface{} {
 	// Build Jira issue fields
 	fields := map[string]interface{}{
 		"project": map[string]interface{}{
@@ -140,12 +207,6 @@ func CreateIssue(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if req.Assignee != "" {
-		fields["assignee"] = map[string]interface{}{
-			"accountId": req.Assignee,
-		}
-	}
-
 	if len(req.Labels) > 0 {
 		fields["labels"] = req.Labels
 	}
@@ -169,6 +230,56 @@ func CreateIssue(w http.ResponseWriter, r *http.Request) {
 		fields[k] = v
 	}
 
+	return fields
+}
+
+// CreateIssue creates a new Jira issue
+func CreateIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req CreateIssueRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	// Hold the issue until Jira is reachable again; the assignee is looked up
+	// in Jira when the write is replayed
+	offlineWrite := OfflineWrite{Operation: OfflineCreate, Fields: createIssueFields(&req), Assignee: req.Assignee}
+	if holdOfflineWrite(w, r, nil, offlineWrite) {
+		return
+	}
+
+	fields, resolution, err := buildCreateIssueFields(&req)
+	if err != nil {
+		if holdOfflineWrite(w, r, err, offlineWrite) {
+			return
+		}
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+	if resolution != nil {
+		renderUnresolvedUser(w, r, resolution)
+		return
+	}
+
+	// Validate and coerce fields against the create metadata before sending
+	if !req.SkipValidation {
+		validation, err := validateCreateFields(req.Project, req.IssueType, fields)
+		if err != nil {
+			log.Warn().Err(err).Str("project", req.Project).Str("issueType", req.IssueType).
+				Msg("Create metadata unavailable, sending fields without validation")
+		} else if !validation.Valid {
+			renderFieldValidation(w, r, validation)
+			return
+		} else {
+			fields = validation.Fields
+		}
+	}
+
 	// Create the issue
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
@@ -179,6 +290,11 @@ func CreateIssue(w http.ResponseWriter, r *http.Request) {
 
 	issue, err := jiraClient.CreateIssue(ctx, createReq)
 	if err != nil {
+		offlineWrite.Fields = fields
+		offlineWrite.Assignee = ""
+		if holdOfflineWrite(w, r, err, offlineWrite) {
+			return
+		}
 		render.Render(w, r, ErrInternalServer(err))
 		return
 	}
@@ -212,12 +328,27 @@ func GetIssue(w http.ResponseWriter, r *http.Request) {
 		expand = strings.Split(expandParam, ",")
 	}
 
+	// Answer from the local mirror while Jira is unreachable
+	if store := offlineStore(nil); store != nil {
+		renderMirroredIssue(w, r, store, issueKey)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	issue, err := jiraClient.GetIssue(ctx, issueKey, expand)
+	// Expanded issues are not cached
+	var issue *jira.Issue
+	var err error
+	if len(expand) == 0 {
+		issue, err = currentJiraReads().Issue(ctx, issueKey)
+	} else {
+		issue, err = jiraClient.GetIssue(ctx, issueKey, expand)
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if store := offlineStore(err); store != nil {
+			renderMirroredIssue(w, r, store, issueKey)
+		} else if strings.Contains(err.Error(), "404") {
 			render.Render(w, r, ErrNotFound(fmt.Sprintf("issue %s", issueKey)))
 		} else {
 			render.Render(w, r, ErrInternalServer(err))
@@ -225,6 +356,10 @@ func GetIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Transitions, links and linked issues are usually asked for next
+	currentJiraReads().PrefetchIssue(issue)
+	indexIssues(*issue)
+
 	response := &IssueResponse{
 		Success: true,
 		Data:    issue,
@@ -270,13 +405,13 @@ func UpdateIssue(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// The assignee is resolved against Jira once the update is not held offline
+	var assignee string
 	if req.Assignee != nil {
 		if *req.Assignee == "" {
 			fields["assignee"] = nil // Unassign
 		} else {
-			fields["assignee"] = map[string]interface{}{
-				"accountId": *req.Assignee,
-			}
+			assignee = *req.Assignee
 		}
 	}
 
@@ -297,11 +432,34 @@ func UpdateIssue(w http.ResponseWriter, r *http.Request) {
 		fields[k] = v
 	}
 
-	if len(fields) == 0 {
+	if len(fields) == 0 && assignee == "" {
 		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("no fields to update")))
 		return
 	}
 
+	// Hold the update until Jira is reachable again
+	offlineWrite := OfflineWrite{Operation: OfflineUpdate, IssueKey: issueKey, Fields: fields, Assignee: assignee}
+	if holdOfflineWrite(w, r, nil, offlineWrite) {
+		return
+	}
+
+	if assignee != "" {
+		field, resolution, err := resolveAssigneeField(assignee, jira.AssignableUserQuery{IssueKey: issueKey})
+		if err != nil {
+			if holdOfflineWrite(w, r, err, offlineWrite) {
+				return
+			}
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		if resolution != nil {
+			renderUnresolvedUser(w, r, resolution)
+			return
+		}
+		fields["assignee"] = field
+		offlineWrite.Assignee = ""
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
@@ -311,6 +469,9 @@ func UpdateIssue(w http.ResponseWriter, r *http.Request) {
 
 	err := jiraClient.UpdateIssue(ctx, issueKey, updateReq)
 	if err != nil {
+		if holdOfflineWrite(w, r, err, offlineWrite) {
+			return
+		}
 		if strings.Contains(err.Error(), "404") {
 			render.Render(w, r, ErrNotFound(fmt.Sprintf("issue %s", issueKey)))
 		} else {
@@ -409,14 +570,25 @@ func SearchIssues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Answer from the local mirror while Jira is unreachable
+	if store := offlineStore(nil); store != nil {
+		renderMirroredSearch(w, r, store, searchReq.JQL, searchReq.StartAt, searchReq.MaxResults)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second) // Longer timeout for searches
 	defer cancel()
 
 	results, err := jiraClient.SearchIssues(ctx, searchReq.JQL, searchReq.StartAt, searchReq.MaxResults, searchReq.Expand)
 	if err != nil {
+		if store := offlineStore(err); store != nil {
+			renderMirroredSearch(w, r, store, searchReq.JQL, searchReq.StartAt, searchReq.MaxResults)
+			return
+		}
 		render.Render(w, r, ErrInternalServer(err))
 		return
 	}
+	indexIssues(results.Issues...)
 
 	response := &IssueResponse{
 		Success: true,
@@ -443,7 +615,7 @@ func GetIssueTransitions(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	transitions, err := jiraClient.GetIssueTransitions(ctx, issueKey)
+	transitions, err := currentJiraReads().Transitions(ctx, issueKey)
 	if err != nil {
 		if strings.Contains(err.Error(), "404") {
 			render.Render(w, r, ErrNotFound(fmt.Sprintf("issue %s", issueKey)))
@@ -506,11 +678,20 @@ func TransitionIssue(w http.ResponseWriter, r *http.Request) {
 		transitionReq.Fields = req.Fields
 	}
 
+	// Hold the transition until Jira is reachable again
+	offlineWrite := OfflineWrite{Operation: OfflineTransition, IssueKey: issueKey, Transition: transitionReq}
+	if holdOfflineWrite(w, r, nil, offlineWrite) {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
 	err := jiraClient.TransitionIssue(ctx, issueKey, transitionReq)
 	if err != nil {
+		if holdOfflineWrite(w, r, err, offlineWrite) {
+			return
+		}
 		if strings.Contains(err.Error(), "404") {
 			render.Render(w, r, ErrNotFound(fmt.Sprintf("issue %s", issueKey)))
 		} else {
@@ -544,7 +725,7 @@ func GetIssueLinks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	links, err := jiraClient.GetIssueLinks(issueKey)
+	links, err := currentJiraReads().Links(r.Context(), issueKey)
 	if err != nil {
 		render.Render(w, r, ErrInternalServer(err))
 		return
@@ -654,7 +835,7 @@ func GetLinkTypes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	linkTypes, err := jiraClient.GetIssueLinkTypes()
+	linkTypes, err := currentJiraReads().LinkTypes(r.Context())
 	if err != nil {
 		render.Render(w, r, ErrInternalServer(err))
 		return
@@ -684,7 +865,7 @@ func GetCustomFields(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	customFields, err := jiraClient.GetCustomFields(projectKey)
+	customFields, err := currentJiraReads().CustomFields(r.Context())
 	if err != nil {
 		render.Render(w, r, ErrInternalServer(err))
 		return
@@ -768,7 +949,9 @@ func AdvancedSearchIssues(w http.ResponseWriter, r *http.Request) {
 	render.Render(w, r, response)
 }
 
-// ValidateJQL validates a JQL query
+// ValidateJQL validates a JQL query. The query is parsed and checked against
+// the instance's fields and functions locally; Jira is only asked to validate
+// it when it passes the local checks and remote=true is given.
 func ValidateJQL(w http.ResponseWriter, r *http.Request) {
 	if jiraClient == nil || !authManager.IsAuthenticated() {
 		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
@@ -781,19 +964,32 @@ func ValidateJQL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isValid, errors, err := jiraClient.ValidateJQL(jqlQuery)
-	if err != nil {
-		render.Render(w, r, ErrInternalServer(err))
-		return
+	query, details := checkJQL(jqlQuery)
+	isValid := len(details) == 0
+	errors := jqlErrorMessages(details)
+
+	if isValid && r.URL.Query().Get("remote") == "true" {
+		var err error
+		isValid, errors, err = jiraClient.ValidateJQL(jqlQuery)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"jql":     jqlQuery,
+		"valid":   isValid,
+		"errors":  errors,
+		"details": details,
+	}
+	if query != nil {
+		data["canonical"] = query.String()
 	}
 
 	response := &IssueResponse{
 		Success: true,
-		Data: map[string]interface{}{
-			"jql":     jqlQuery,
-			"valid":   isValid,
-			"errors":  errors,
-		},
+		Data:    data,
 	}
 
 	render.Status(r, http.StatusOK)
@@ -1050,8 +1246,9 @@ func ExportSearchResults(w http.ResponseWriter, r *http.Request) {
 	}
 
 	exportReq := jira.ExportRequest{
-		Format: jira.ExportFormat(formatStr),
-		Fields: searchReq.Fields,
+		Format:  jira.ExportFormat(formatStr),
+		Fields:  searchReq.Fields,
+		GroupBy: r.URL.Query().Get("groupBy"),
 	}
 
 	// Validate export request
@@ -1070,6 +1267,12 @@ func ExportSearchResults(w http.ResponseWriter, r *http.Request) {
 		Properties: searchReq.Properties,
 	}
 
+	// CSV exports stream every match page by page instead of buffering one page
+	if exportReq.Format == jira.FormatCSV {
+		streamCSVExport(w, r, jiraSearchReq, exportReq.Fields)
+		return
+	}
+
 	searchResult, err := jiraClient.SearchIssuesAdvanced(jiraSearchReq)
 	if err != nil {
 		render.Render(w, r, ErrInternalServer(err))
@@ -1083,7 +1286,11 @@ func ExportSearchResults(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set appropriate headers and return file
+	writeExportResult(w, exportResult)
+}
+
+// writeExportResult sends an export as a file download
+func writeExportResult(w http.ResponseWriter, exportResult *jira.ExportResult) {
 	w.Header().Set("Content-Type", exportResult.ContentType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", exportResult.Filename))
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", exportResult.Size))
@@ -1137,7 +1344,13 @@ func GetSearchPage(w http.ResponseWriter, r *http.Request) {
 	render.Render(w, r, response)
 }
 
-// GetAllSearchPages retrieves all pages of search results
+// searchRateLimiter is shared by every parallel page fetch so that concurrent
+// reports cannot exceed the request rate Jira tolerates
+var searchRateLimiter = jira.NewRateLimiter(10, 20)
+
+// GetAllSearchPages retrieves all pages of search results. Pages after the first
+// are fetched concurrently (?concurrency=, default 4, max 10) and merged in
+// order; ?snapshot=true pins the results to issues updated before the search.
 func GetAllSearchPages(w http.ResponseWriter, r *http.Request) {
 	if jiraClient == nil || !authManager.IsAuthenticated() {
 		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
@@ -1158,6 +1371,12 @@ func GetAllSearchPages(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	concurrency := 4
+	if val, err := strconv.Atoi(r.URL.Query().Get("concurrency")); err == nil && val > 0 && val <= 10 {
+		concurrency = val
+	}
+	snapshot, _ := strconv.ParseBool(r.URL.Query().Get("snapshot"))
+
 	searchReq := jira.SearchRequest{
 		JQL:        req.JQL,
 		StartAt:    req.StartAt,
@@ -1167,24 +1386,33 @@ func GetAllSearchPages(w http.ResponseWriter, r *http.Request) {
 		Properties: req.Properties,
 	}
 
-	allResults, err := jiraClient.SearchAllPages(searchReq, maxPages)
+	combined, err := jiraClient.SearchAllPagesParallel(r.Context(), searchReq, jira.ParallelSearchOptions{
+		MaxPages:    maxPages,
+		Concurrency: concurrency,
+		Snapshot:    snapshot,
+		RateLimiter: searchRateLimiter,
+	})
 	if err != nil {
 		render.Render(w, r, ErrInternalServer(err))
 		return
 	}
 
-	// Combine all results
-	combined := jira.CombineSearchResults(allResults)
+	data := map[string]interface{}{
+		"totalPages":   combined.Pages,
+		"totalIssues":  len(combined.Issues),
+		"searchTotal":  combined.Total,
+		"duplicates":   combined.Duplicates,
+		"issues":       combined.Issues,
+		"warnings":     combined.WarningMessages,
+	}
+	if combined.SnapshotAt != nil {
+		data["snapshotAt"] = combined.SnapshotAt
+		data["jql"] = combined.JQL
+	}
 
 	response := &IssueResponse{
 		Success: true,
-		Data: map[string]interface{}{
-			"totalPages":   len(allResults),
-			"totalIssues":  len(combined.Issues),
-			"searchTotal":  combined.Total,
-			"issues":       combined.Issues,
-			"warnings":     combined.WarningMessages,
-		},
+		Data:    data,
 	}
 
 	render.Status(r, http.StatusOK)
@@ -1207,13 +1435,32 @@ func (req *NLRequest) Bind(r *http.Request) error {
 
 // JQLRequest represents a JQL generation request
 type JQLRequest struct {
-	Query string `json:"query"`
+	Query  string             `json:"query"`
+	SaveAs *SaveFilterRequest `json:"saveAs,omitempty"` // save the generated JQL as a filter
+}
+
+// SaveFilterRequest names the filter a generated JQL query is saved as
+type SaveFilterRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Favourite   *bool                `json:"favourite,omitempty"`
+	Share       []FilterShareRequest `json:"share,omitempty"`
 }
 
 func (req *JQLRequest) Bind(r *http.Request) error {
 	if req.Query == "" {
 		return fmt.Errorf("query is required")
 	}
+	if req.SaveAs != nil {
+		if req.SaveAs.Name == "" {
+			return fmt.Errorf("saveAs.name is required")
+		}
+		for i := range req.SaveAs.Share {
+			if err := req.SaveAs.Share[i].validate(); err != nil {
+				return fmt.Errorf("saveAs.share %d: %w", i+1, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -1233,8 +1480,22 @@ func ClaudeGetIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Answer from the local mirror while Jira is unreachable
+	if store := offlineStore(nil); store != nil {
+		response, status := claudeMirroredIssue(formatter, store, issueKey)
+		render.Status(r, status)
+		render.JSON(w, r, response)
+		return
+	}
+
 	issue, err := jiraClient.GetIssue(context.Background(), issueKey, nil)
 	if err != nil {
+		if store := offlineStore(err); store != nil {
+			response, status := claudeMirroredIssue(formatter, store, issueKey)
+			render.Status(r, status)
+			render.JSON(w, r, response)
+			return
+		}
 		response := formatter.FormatErrorResponse(err, "Get Issue")
 		render.Status(r, http.StatusInternalServerError)
 		render.JSON(w, r, response)
@@ -1264,8 +1525,22 @@ func ClaudeSearchIssues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Answer from the local mirror while Jira is unreachable
+	if store := offlineStore(nil); store != nil {
+		response, status := claudeMirroredSearch(formatter, store, req)
+		render.Status(r, status)
+		render.JSON(w, r, response)
+		return
+	}
+
 	result, err := jiraClient.SearchIssuesAdvanced(req)
 	if err != nil {
+		if store := offlineStore(err); store != nil {
+			response, status := claudeMirroredSearch(formatter, store, req)
+			render.Status(r, status)
+			render.JSON(w, r, response)
+			return
+		}
 		response := formatter.FormatErrorResponse(err, "Search Issues")
 		render.Status(r, http.StatusInternalServerError)
 		render.JSON(w, r, response)
@@ -1429,25 +1704,87 @@ func GenerateJQLFromNaturalLanguage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := formatter.FormatGenericResponse(
-		map[string]interface{}{
-			"originalQuery": req.Query,
-			"generatedJQL":  jql,
-		},
-		fmt.Sprintf("✅ Generated JQL: %s", jql),
-		"Generate JQL",
-	)
+	// Generated JQL is checked before it is handed out so it never reaches Jira broken
+	query, details := checkJQL(jql)
+	if len(details) > 0 {
+		response := formatter.FormatErrorResponse(
+			fmt.Errorf("generated JQL %q is invalid: %s", jql, strings.Join(jqlErrorMessages(details), "; ")),
+			"Generate JQL",
+		)
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.JSON(w, r, response)
+		return
+	}
+	jql = query.String()
+
+	data := map[string]interface{}{
+		"originalQuery": req.Query,
+		"generatedJQL":  jql,
+	}
+	message := fmt.Sprintf("✅ Generated JQL: %s", jql)
+
+	if req.SaveAs != nil {
+		filter, status, err := saveGeneratedJQL(r, req.SaveAs, jql)
+		if err != nil {
+			response := formatter.FormatErrorResponse(fmt.Errorf("generated JQL %q could not be saved: %w", jql, err), "Generate JQL")
+			render.Status(r, status)
+			render.JSON(w, r, response)
+			return
+		}
+		data["filter"] = filter
+		message = fmt.Sprintf("✅ Generated JQL: %s (saved as filter %s '%s')", jql, filter.ID, filter.Name)
+	}
+
+	response := formatter.FormatGenericResponse(data, message, "Generate JQL")
 
 	response.Suggestions = []string{
 		"Use this JQL to search for issues",
 		"Refine the natural language query for better results",
 		"Test the generated JQL",
 	}
+	if req.SaveAs == nil {
+		response.Suggestions = append(response.Suggestions, "Save this JQL as a filter by repeating the request with saveAs")
+	}
 
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, response)
 }
 
+// saveGeneratedJQL saves a generated query as a named filter and returns the
+// HTTP status to report when it cannot be saved
+func saveGeneratedJQL(r *http.Request, saveAs *SaveFilterRequest, jql string) (*jira.SearchFilter, int, error) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		return nil, http.StatusUnauthorized, fmt.Errorf("not connected to Jira")
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	share, resolution, err := resolveFilterShares(ctx, saveAs.Share)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if resolution != nil {
+		status := http.StatusUnprocessableEntity
+		if resolution.Ambiguous {
+			status = http.StatusConflict
+		}
+		return nil, status, fmt.Errorf("user '%s' could not be resolved: %s", resolution.Input, resolution.Message)
+	}
+
+	filter, err := jiraClient.CreateFilter(&jira.FilterRequest{
+		Name:        saveAs.Name,
+		Description: saveAs.Description,
+		JQL:         jql,
+		Favourite:   saveAs.Favourite,
+		Share:       share,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return filter, http.StatusOK, nil
+}
+
 // GetCommandSuggestions provides command suggestions for partial input
 func GetCommandSuggestions(w http.ResponseWriter, r *http.Request) {
 	claudeManager := claude.GetManager()
@@ -1509,16 +1846,25 @@ func AddIssueComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
-	defer cancel()
-
 	commentReq := &jira.CreateCommentRequest{
 		Body:       req.Body,
 		Visibility: req.Visibility,
 	}
 
+	// Hold the comment until Jira is reachable again
+	offlineWrite := OfflineWrite{Operation: OfflineComment, IssueKey: issueKey, Comment: commentReq}
+	if holdOfflineWrite(w, r, nil, offlineWrite) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
 	comment, err := jiraClient.AddComment(ctx, issueKey, commentReq)
 	if err != nil {
+		if holdOfflineWrite(w, r, err, offlineWrite) {
+			return
+		}
 		if strings.Contains(err.Error(), "404") {
 			render.Render(w, r, ErrNotFound(fmt.Sprintf("issue %s", issueKey)))
 		} else {
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	jqlService   *services.JQLService
+	jqlServiceMu sync.Mutex
+)
+
+// SetJQLService sets the global JQL service
+func SetJQLService(service *services.JQLService) {
+	jqlServiceMu.Lock()
+	defer jqlServiceMu.Unlock()
+	jqlService = service
+}
+
+// GetJQLService returns the JQL service for the current Jira client, creating
+// a new one whenever the client changes
+func GetJQLService() *services.JQLService {
+	jqlServiceMu.Lock()
+	defer jqlServiceMu.Unlock()
+
+	if jiraClient == nil {
+		return jqlService
+	}
+	if jqlService == nil || jqlService.Client() != services.JQLClient(jiraClient) {
+		jqlService = services.NewJQLService(jiraClient)
+	}
+	return jqlService
+}
+
+// checkJQL parses a query and, when connected to Jira, validates it against the
+// instance's fields and functions. Syntax errors are always reported; if the
+// field metadata cannot be loaded only the syntax is checked.
+func checkJQL(jql string) (*jira.JQLQuery, []jira.JQLError) {
+	if jiraClient != nil && authManager != nil && authManager.IsAuthenticated() {
+		if service := GetJQLService(); service != nil {
+			query, errs, err := service.Validate(jql)
+			if err == nil {
+				return query, errs
+			}
+			log.Warn().Err(err).Msg("JQL metadata unavailable, checking syntax only")
+		}
+	}
+
+	query, err := jira.ParseJQL(jql)
+	if err != nil {
+		if jqlErr, ok := err.(*jira.JQLError); ok {
+			return nil, []jira.JQLError{*jqlErr}
+		}
+		return nil, []jira.JQLError{{Message: err.Error()}}
+	}
+	return query, nil
+}
+
+// jqlErrorMessages flattens validation errors into "line 1, column 5: message" strings
+func jqlErrorMessages(errs []jira.JQLError) []string {
+	messages := make([]string, len(errs))
+	for i := range errs {
+		messages[i] = errs[i].Error()
+	}
+	return messages
+}
+
+// ParseJQLRequest represents a request to parse a JQL query
+type ParseJQLRequest struct {
+	JQL string `json:"jql"`
+}
+
+func (p *ParseJQLRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+// ParseJQL parses a JQL query locally and returns its canonical form and syntax tree
+func ParseJQL(w http.ResponseWriter, r *http.Request) {
+	var req ParseJQLRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	query, errs := checkJQL(req.JQL)
+	if query == nil {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.Render(w, r, &IssueResponse{
+			Success: false,
+			Data: map[string]interface{}{
+				"jql":    req.JQL,
+				"valid":  false,
+				"errors": errs,
+			},
+			Error: fmt.Sprintf("invalid JQL: %s", errs[0].Error()),
+		})
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"jql":       req.JQL,
+			"canonical": query.String(),
+			"valid":     len(errs) == 0,
+			"errors":    errs,
+			"ast":       query,
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/mirror"
+	"github.com/ericfisherdev/GoJira/internal/textindex"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// LocalSearchRequest represents a full-text search of the local index
+type LocalSearchRequest struct {
+	Query   string `json:"query"`
+	Project string `json:"project,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+func (l *LocalSearchRequest) Bind(r *http.Request) error {
+	if l.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	return nil
+}
+
+// indexMirror indexes the issues of a mirror and keeps the index in step with it
+func indexMirror(store *mirror.Store) {
+	textindex.GlobalIndex.Add(store.Issues("")...)
+	store.OnChange(func(stored []jira.Issue, deleted []string) {
+		textindex.GlobalIndex.Add(stored...)
+		textindex.GlobalIndex.Remove(deleted...)
+	})
+}
+
+// indexIssues adds issues read from Jira to the local index
+func indexIssues(issues ...jira.Issue) {
+	textindex.GlobalIndex.Add(issues...)
+}
+
+// SearchLocal runs a BM25 full-text search over the summaries, descriptions
+// and comments of mirrored and previously read issues, without calling Jira
+func SearchLocal(w http.ResponseWriter, r *http.Request) {
+	var req LocalSearchRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("q")
+		req.Project = query.Get("project")
+		if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+			req.Limit = limit
+		}
+		if err := req.Bind(r); err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+	} else if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	hits := textindex.GlobalIndex.Search(req.Query, textindex.SearchOptions{Project: req.Project, Limit: req.Limit})
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"query": req.Query,
+			"total": len(hits),
+			"hits":  hits,
+			"index": textindex.GlobalIndex.Stats(),
+		},
+	})
+}
+
+// GetSimilarIssues ranks the indexed issues most like an issue and flags the
+// likely duplicates. An issue not indexed yet is read from Jira first.
+func GetSimilarIssues(w http.ResponseWriter, r *http.Request) {
+	issueKey := chi.URLParam(r, "key")
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if reads := currentJiraReads(); !textindex.GlobalIndex.Contains(issueKey) && reads != nil && offlineStore(nil) == nil {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		if issue, err := reads.Issue(ctx, issueKey); err == nil {
+			indexIssues(*issue)
+		}
+	}
+
+	similar, ok := textindex.GlobalIndex.Similar(issueKey, limit)
+	if !ok {
+		render.Render(w, r, ErrNotFound(fmt.Sprintf("issue %s", issueKey)))
+		return
+	}
+
+	duplicates := 0
+	for _, match := range similar {
+		if match.Duplicate {
+			duplicates++
+		}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"issue":      issueKey,
+			"similar":    similar,
+			"duplicates": duplicates,
+		},
+	})
+}
@@ -10,6 +10,13 @@ import (
 // GetMetrics returns current performance metrics
 func GetMetrics(w http.ResponseWriter, r *http.Request) {
 	stats := monitoring.GlobalMetrics.GetStats()
+	if jiraClient != nil {
+		stats["coalescing"] = jiraClient.GetCoalescingStats()
+		stats["conditional"] = jiraClient.GetConditionalStats()
+	}
+	if reads := currentJiraReads(); reads != nil {
+		stats["jiraCache"] = reads.GetStats()
+	}
 	
 	response := &IssueResponse{
 		Success: true,
@@ -71,6 +78,7 @@ func GetHealthWithMetrics(w http.ResponseWriter, r *http.Request) {
 			"healthy": healthy,
 			"issues":  issues,
 			"metrics": stats,
+			"warmup":  warmupProgress(),
 		},
 	}
 
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/ericfisherdev/GoJira/internal/jira"
 	"github.com/ericfisherdev/GoJira/internal/nlp"
 	"github.com/rs/zerolog/log"
 )
@@ -13,6 +15,12 @@ import (
 type NLPHandler struct {
 	parser        *nlp.Parser
 	disambiguator *nlp.Disambiguator
+
+	// usersMu guards the versions below and the parser's caches, which are
+	// replaced while other requests parse
+	usersMu      sync.RWMutex
+	userVersion  uint64
+	namesVersion uint64
 }
 
 // NewNLPHandler creates a new NLP handler
@@ -113,8 +121,12 @@ func (h *NLPHandler) ProcessNaturalLanguageCommand(w http.ResponseWriter, r *htt
 		h.applyContext(req.Context, req.SessionID)
 	}
 
+	h.syncParserCaches()
+
 	// Parse the command
+	h.usersMu.RLock()
 	parseResult, err := h.parser.Parse(req.Command)
+	h.usersMu.RUnlock()
 	if err != nil {
 		log.Error().Err(err).Str("command", req.Command).Msg("Failed to parse command")
 		
@@ -129,7 +141,9 @@ func (h *NLPHandler) ProcessNaturalLanguageCommand(w http.ResponseWriter, r *htt
 	}
 
 	// Disambiguate the intent
+	h.usersMu.RLock()
 	disambiguatedIntent, clarifications, err := h.disambiguator.Disambiguate(parseResult.Intent)
+	h.usersMu.RUnlock()
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to disambiguate intent")
 		RespondWithError(w, http.StatusInternalServerError, "Failed to process command")
@@ -139,6 +153,11 @@ func (h *NLPHandler) ProcessNaturalLanguageCommand(w http.ResponseWriter, r *htt
 	// Generate execution plan
 	executionPlan := h.generateExecutionPlan(disambiguatedIntent)
 
+	// Resolve the assignee to an account ID so the plan can be executed as-is
+	if disambiguatedIntent.Type == nlp.IntentAssign {
+		clarifications = h.resolveAssignee(disambiguatedIntent, executionPlan, clarifications)
+	}
+
 	// Generate next steps
 	nextSteps := h.generateNextSteps(disambiguatedIntent, clarifications)
 
@@ -171,8 +190,12 @@ func (h *NLPHandler) ExtractEntities(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug().Str("text", req.Text).Msg("Extracting entities")
 
+	h.syncParserCaches()
+
 	// Use parser's entity extraction
+	h.usersMu.RLock()
 	entities := h.parser.ExtractEntities(req.Text)
+	h.usersMu.RUnlock()
 
 	response := EntityExtractionResponse{
 		Entities: entities,
@@ -214,8 +237,12 @@ func (h *NLPHandler) ValidateCommand(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.syncParserCaches()
+
 	// Parse the command
+	h.usersMu.RLock()
 	parseResult, err := h.parser.Parse(req.Command)
+	h.usersMu.RUnlock()
 	if err != nil {
 		response := ProcessCommandResponse{
 			Success:    false,
@@ -241,7 +268,12 @@ func (h *NLPHandler) ValidateCommand(w http.ResponseWriter, r *http.Request) {
 
 // GetParserStatus returns the current parser status and statistics
 func (h *NLPHandler) GetParserStatus(w http.ResponseWriter, r *http.Request) {
+	h.syncParserCaches()
+
 	context := h.parser.GetContext()
+	h.usersMu.RLock()
+	cacheStats := h.parser.GetCacheStats()
+	h.usersMu.RUnlock()
 	
 	status := map[string]interface{}{
 		"sessionId":      context.SessionID,
@@ -250,7 +282,7 @@ func (h *NLPHandler) GetParserStatus(w http.ResponseWriter, r *http.Request) {
 		"lastIssue":      context.LastIssue,
 		"lastAssignee":   context.LastAssignee,
 		"historySize":    len(context.History),
-		"cacheStats": h.parser.GetCacheStats(),
+		"cacheStats": cacheStats,
 	}
 
 	RespondWithJSON(w, http.StatusOK, status)
@@ -304,6 +336,75 @@ func (h *NLPHandler) applyContext(contextData map[string]string, sessionID strin
 	h.parser.SetContext(context)
 }
 
+// syncParserCaches feeds users discovered by the user directory, and the
+// components and versions of the projects read through the API, into the parser
+func (h *NLPHandler) syncParserCaches() {
+	h.usersMu.Lock()
+	defer h.usersMu.Unlock()
+
+	if directory := GetUserDirectory(); directory != nil {
+		if version := directory.Version(); version != h.userVersion {
+			h.parser.SetUserCache(directory.NLPUsers())
+			h.userVersion = version
+		}
+	}
+
+	if components, versions, version := knownProjectNames.snapshot(); version != h.namesVersion {
+		h.parser.SetComponentCache(components)
+		h.parser.SetVersionCache(versions)
+		h.namesVersion = version
+	}
+}
+
+// resolveAssignee maps the assignee entity of an assign intent to an account ID,
+// asking for clarification when the reference matches several users
+func (h *NLPHandler) resolveAssignee(intent *nlp.Intent, plan *ExecutionPlan, clarifications []nlp.Clarification) []nlp.Clarification {
+	entity, ok := intent.Entities[string(nlp.EntityAssignee)]
+	if !ok {
+		return clarifications
+	}
+	assignee, ok := entity.Value.(string)
+	if !ok || assignee == "current_user" {
+		return clarifications
+	}
+	if jiraClient == nil || authManager == nil || !authManager.IsAuthenticated() {
+		return clarifications
+	}
+
+	scope := jira.AssignableUserQuery{}
+	if issueKey, ok := intent.Entities[string(nlp.EntityIssueKey)]; ok {
+		scope.IssueKey = issueKey.Text
+	}
+
+	resolution, err := GetUserDirectory().Resolve(assignee, scope)
+	if err != nil {
+		log.Warn().Err(err).Str("assignee", assignee).Msg("Failed to resolve assignee")
+		return clarifications
+	}
+
+	if resolution.Resolved {
+		plan.Parameters["assigneeAccountId"] = resolution.AccountID
+		h.syncParserCaches()
+		return clarifications
+	}
+
+	clarification := nlp.Clarification{
+		Field:      "assignee",
+		Message:    resolution.Message,
+		Required:   true,
+		EntityType: nlp.EntityAssignee,
+	}
+	for _, candidate := range resolution.Candidates {
+		option := candidate.User.DisplayName
+		if candidate.User.EmailAddress != "" {
+			option += " <" + candidate.User.EmailAddress + ">"
+		}
+		clarification.Options = append(clarification.Options, option)
+	}
+
+	return append(clarifications, clarification)
+}
+
 func (h *NLPHandler) generateExecutionPlan(intent *nlp.Intent) *ExecutionPlan {
 	plan := &ExecutionPlan{
 		Action:     intent.Action,
@@ -0,0 +1,514 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/claude"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/mirror"
+	"github.com/ericfisherdev/GoJira/internal/queue"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+)
+
+// Offline write operations held in the outbox
+const (
+	OfflineCreate     = "create"
+	OfflineUpdate     = "update"
+	OfflineTransition = "transition"
+	OfflineComment    = "comment"
+)
+
+// staleAsOfHeader tells clients a response was answered from the mirror
+const staleAsOfHeader = "X-GoJira-Stale-As-Of"
+
+var (
+	mirrorMu      sync.Mutex
+	mirrorStore   *mirror.Store
+	mirrorOptions mirror.Options
+	mirrorSyncer  *mirror.Syncer
+	writeOutbox   *queue.Outbox
+)
+
+// OfflineWrite is the payload of a JIRA_WRITE job: a write made while Jira was
+// unreachable, replayed once it answers again
+type OfflineWrite struct {
+	Operation  string                     `json:"operation"`
+	IssueKey   string                     `json:"issueKey,omitempty"`
+	Fields     map[string]interface{}     `json:"fields,omitempty"`
+	Assignee   string                     `json:"assignee,omitempty"` // reference resolved into Fields on replay
+	Transition *jira.TransitionRequest    `json:"transition,omitempty"`
+	Comment    *jira.CreateCommentRequest `json:"comment,omitempty"`
+}
+
+// SetOfflineMirror mirrors the projects of opts into dir, so issue reads and
+// searches are answered from it while Jira is unreachable. Writes made in the
+// meantime are held in an outbox in the same directory and replayed in order
+// when Jira answers again. An empty dir turns the mirror off.
+func SetOfflineMirror(dir string, opts mirror.Options) error {
+	var store *mirror.Store
+	var outbox *queue.Outbox
+	if dir != "" {
+		var err error
+		if store, err = mirror.Open(dir); err != nil {
+			return err
+		}
+		if outbox, err = queue.NewOutbox(filepath.Join(dir, "outbox")); err != nil {
+			return err
+		}
+		indexMirror(store)
+	}
+
+	mirrorMu.Lock()
+	if mirrorSyncer != nil {
+		mirrorSyncer.Stop()
+		mirrorSyncer = nil
+	}
+	mirrorStore = store
+	mirrorOptions = opts
+	writeOutbox = outbox
+	mirrorMu.Unlock()
+
+	if jiraClient != nil {
+		setMirrorClient(jiraClient)
+	}
+	return nil
+}
+
+// setMirrorClient points the mirror at a new Jira client
+func setMirrorClient(client *jira.Client) {
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+
+	if mirrorSyncer != nil {
+		mirrorSyncer.Stop()
+		mirrorSyncer = nil
+	}
+	if client == nil || mirrorStore == nil {
+		return
+	}
+	mirrorSyncer = mirror.NewSyncer(client, mirrorStore, mirrorOptions)
+	mirrorSyncer.OnReconnect(replayOfflineWrites)
+}
+
+// startMirror starts syncing the mirror in the background
+func startMirror() {
+	if syncer := currentMirror(); syncer != nil {
+		syncer.Start()
+	}
+}
+
+// stopMirror stops syncing the mirror
+func stopMirror() {
+	if syncer := currentMirror(); syncer != nil {
+		syncer.Stop()
+	}
+}
+
+func currentMirror() *mirror.Syncer {
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+	return mirrorSyncer
+}
+
+func currentOutbox() *queue.Outbox {
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+	return writeOutbox
+}
+
+// mirrorStatus returns the state of the mirror for health reports
+func mirrorStatus() *mirror.Status {
+	syncer := currentMirror()
+	if syncer == nil {
+		return nil
+	}
+	status := syncer.Status()
+	return &status
+}
+
+// offlineStore returns the mirror when a read should be answered from it:
+// Jira is known to be unreachable (err is nil), or the read just failed
+// because Jira could not be reached
+func offlineStore(err error) *mirror.Store {
+	syncer := currentMirror()
+	if syncer == nil {
+		return nil
+	}
+	if err == nil {
+		if syncer.Online() {
+			return nil
+		}
+		return syncer.Store()
+	}
+	if !jira.IsUnreachable(err) {
+		return nil
+	}
+	syncer.MarkOffline(err)
+	return syncer.Store()
+}
+
+// ErrJiraUnreachable renders a request that cannot be answered without Jira
+func ErrJiraUnreachable(err error) render.Renderer {
+	return &ErrorResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusServiceUnavailable,
+		StatusText:     "Jira is unreachable",
+		ErrorText:      err.Error(),
+	}
+}
+
+// renderStale renders data answered from the mirror, marking how old it may be
+func renderStale(w http.ResponseWriter, r *http.Request, data interface{}, asOf *time.Time) {
+	if asOf != nil {
+		w.Header().Set(staleAsOfHeader, asOf.UTC().Format(time.RFC3339))
+	}
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success:   true,
+		Data:      data,
+		Offline:   true,
+		StaleAsOf: asOf,
+	})
+}
+
+// renderMirroredIssue answers an issue read from the mirror
+func renderMirroredIssue(w http.ResponseWriter, r *http.Request, store *mirror.Store, issueKey string) {
+	issue, ok := store.Issue(issueKey)
+	if !ok {
+		render.Render(w, r, ErrJiraUnreachable(fmt.Errorf("issue %s is not in the local mirror", issueKey)))
+		return
+	}
+	renderStale(w, r, issue, store.IssueStaleAsOf(issue))
+}
+
+// renderMirroredSearch answers a search from the mirror
+func renderMirroredSearch(w http.ResponseWriter, r *http.Request, store *mirror.Store, jql string, startAt, maxResults int) {
+	results, err := store.Search(jql, startAt, maxResults)
+	if err != nil {
+		if errors.Is(err, mirror.ErrUnsupportedQuery) {
+			render.Render(w, r, ErrJiraUnreachable(err))
+		} else {
+			render.Render(w, r, ErrInvalidRequest(err))
+		}
+		return
+	}
+	renderStale(w, r, results, store.ResultStaleAsOf(results.Issues))
+}
+
+// claudeMirroredIssue builds a Claude response for an issue read from the mirror
+func claudeMirroredIssue(formatter *claude.ResponseFormatter, store *mirror.Store, issueKey string) (*claude.ClaudeResponse, int) {
+	issue, ok := store.Issue(issueKey)
+	if !ok {
+		err := fmt.Errorf("Jira is unreachable and issue %s is not in the local mirror", issueKey)
+		return formatter.FormatErrorResponse(err, "Get Issue"), http.StatusServiceUnavailable
+	}
+	response := formatter.FormatIssueResponse(issue, "get")
+	formatter.MarkStale(response, store.IssueStaleAsOf(issue))
+	return response, http.StatusOK
+}
+
+// claudeMirroredSearch builds a Claude response for a search answered from the mirror
+func claudeMirroredSearch(formatter *claude.ResponseFormatter, store *mirror.Store, req jira.SearchRequest) (*claude.ClaudeResponse, int) {
+	if req.MaxResults <= 0 {
+		req.MaxResults = 50
+	}
+	results, err := store.Search(req.JQL, req.StartAt, req.MaxResults)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, mirror.ErrUnsupportedQuery) {
+			status = http.StatusServiceUnavailable
+		}
+		return formatter.FormatErrorResponse(err, "Search Issues"), status
+	}
+
+	response := formatter.FormatSearchResponse(&jira.ExtendedSearchResult{
+		StartAt:    results.StartAt,
+		MaxResults: results.MaxResults,
+		Total:      results.Total,
+		Issues:     results.Issues,
+	}, req.JQL)
+	formatter.MarkStale(response, store.ResultStaleAsOf(results.Issues))
+	return response, http.StatusOK
+}
+
+// holdOfflineWrite holds a write in the outbox when Jira is known to be
+// unreachable (err is nil) or the write just failed because Jira could not be
+// reached, and renders the accepted response. It reports whether the write
+// was handled.
+func holdOfflineWrite(w http.ResponseWriter, r *http.Request, err error, write OfflineWrite) bool {
+	syncer, outbox := currentMirror(), currentOutbox()
+	if syncer == nil || outbox == nil {
+		return false
+	}
+	if err == nil {
+		if syncer.Online() {
+			return false
+		}
+	} else {
+		if !jira.IsUnreachable(err) {
+			return false
+		}
+		syncer.MarkOffline(err)
+	}
+
+	entry, addErr := outbox.Add(queue.Job{Type: queue.JobTypeJiraWrite, Payload: write})
+	if addErr != nil {
+		render.Render(w, r, ErrInternalServer(addErr))
+		return true
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Offline: true,
+		Data: map[string]interface{}{
+			"issue":     write.IssueKey,
+			"operation": write.Operation,
+			"jobId":     entry.Job.ID,
+			"status":    "queued_offline",
+			"queued":    entry.Queued,
+		},
+	})
+	return true
+}
+
+// registerOfflineWriteHandler registers the handler replaying held writes
+func registerOfflineWriteHandler(q *queue.JobQueue) {
+	q.RegisterHandler(queue.JobTypeJiraWrite, func(ctx context.Context, job queue.Job) (interface{}, error) {
+		var write OfflineWrite
+		if err := decodeJobPayload(job.Payload, &write); err != nil {
+			return nil, queue.Permanent(err)
+		}
+		if jiraClient == nil {
+			return nil, fmt.Errorf("not connected to Jira")
+		}
+
+		result, err := runOfflineWrite(ctx, write)
+		if err != nil {
+			// Writes Jira never received are kept for the next replay
+			if jira.IsUnreachable(err) {
+				return nil, err
+			}
+			return nil, queue.Permanent(err)
+		}
+		return result, nil
+	})
+}
+
+func runOfflineWrite(ctx context.Context, write OfflineWrite) (interface{}, error) {
+	switch write.Operation {
+	case OfflineCreate:
+		fields, err := offlineWriteFields(write)
+		if err != nil {
+			return nil, err
+		}
+		return jiraClient.CreateIssue(ctx, &jira.CreateIssueRequest{Fields: fields})
+	case OfflineUpdate:
+		fields, err := offlineWriteFields(write)
+		if err != nil {
+			return nil, err
+		}
+		if err := jiraClient.UpdateIssue(ctx, write.IssueKey, &jira.UpdateIssueRequest{Fields: fields}); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"key": write.IssueKey, "updated": true}, nil
+	case OfflineTransition:
+		if write.Transition == nil {
+			return nil, fmt.Errorf("transition is required")
+		}
+		if err := jiraClient.TransitionIssue(ctx, write.IssueKey, write.Transition); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"key": write.IssueKey, "transitioned": true}, nil
+	case OfflineComment:
+		if write.Comment == nil {
+			return nil, fmt.Errorf("comment is required")
+		}
+		return jiraClient.AddComment(ctx, write.IssueKey, write.Comment)
+	default:
+		return nil, fmt.Errorf("unknown offline write operation %q", write.Operation)
+	}
+}
+
+// offlineWriteFields returns the fields of a held create or update with its
+// assignee reference resolved, now that Jira can be asked
+func offlineWriteFields(write OfflineWrite) (map[string]interface{}, error) {
+	if write.Assignee == "" {
+		return write.Fields, nil
+	}
+
+	scope := jira.AssignableUserQuery{IssueKey: write.IssueKey}
+	if write.Operation == OfflineCreate {
+		if project, ok := write.Fields["project"].(map[string]interface{}); ok {
+			scope = jira.AssignableUserQuery{ProjectKey: fmt.Sprint(project["key"])}
+		}
+	}
+	assignee, resolution, err := resolveAssigneeField(write.Assignee, scope)
+	if err != nil {
+		return nil, err
+	}
+	if resolution != nil {
+		return nil, fmt.Errorf("assignee not resolved: %s", resolution.Message)
+	}
+
+	fields := make(map[string]interface{}, len(write.Fields)+1)
+	for k, v := range write.Fields {
+		fields[k] = v
+	}
+	fields["assignee"] = assignee
+	return fields, nil
+}
+
+// replayOfflineWrites replays the held writes once Jira answers again
+func replayOfflineWrites(ctx context.Context) {
+	outbox := currentOutbox()
+	issueJobMu.RLock()
+	q := issueJobQueue
+	issueJobMu.RUnlock()
+	if outbox == nil || q == nil || outbox.Pending() == 0 {
+		return
+	}
+
+	replay := outbox.Replay(ctx, q)
+	log.Info().
+		Int("replayed", replay.Replayed).
+		Int("failed", replay.Failed).
+		Int("remaining", replay.Remaining).
+		Str("error", replay.Error).
+		Msg("Replayed writes held while Jira was unreachable")
+}
+
+// GetMirrorStatus reports the state of the offline mirror and its outbox
+func GetMirrorStatus(w http.ResponseWriter, r *http.Request) {
+	status := mirrorStatus()
+	outbox := currentOutbox()
+	if status == nil || outbox == nil {
+		render.Render(w, r, ErrNotFound("offline mirror"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"mirror": status,
+			"outbox": map[string]interface{}{
+				"pending": outbox.Pending(),
+				"entries": len(outbox.Entries()),
+			},
+		},
+	})
+}
+
+// SyncMirror starts a sync of the offline mirror. With wait=true the sync
+// runs before responding and the resulting status is returned.
+func SyncMirror(w http.ResponseWriter, r *http.Request) {
+	syncer := currentMirror()
+	if syncer == nil {
+		render.Render(w, r, ErrNotFound("offline mirror"))
+		return
+	}
+
+	if wait, _ := strconv.ParseBool(r.URL.Query().Get("wait")); wait {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+		defer cancel()
+
+		if err := syncer.Sync(ctx); err != nil {
+			if jira.IsUnreachable(err) {
+				render.Render(w, r, ErrJiraUnreachable(err))
+			} else {
+				render.Render(w, r, ErrInternalServer(err))
+			}
+			return
+		}
+		render.Status(r, http.StatusOK)
+		render.Render(w, r, &IssueResponse{Success: true, Data: syncer.Status()})
+		return
+	}
+
+	// A running mirror picks the request up; otherwise sync once here
+	if !syncer.Trigger() {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			if err := syncer.Sync(ctx); err != nil {
+				log.Warn().Err(err).Msg("Mirror sync failed")
+			}
+		}()
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    map[string]interface{}{"status": "syncing"},
+	})
+}
+
+// GetMirrorOutbox lists the writes held while Jira was unreachable
+func GetMirrorOutbox(w http.ResponseWriter, r *http.Request) {
+	outbox := currentOutbox()
+	if outbox == nil {
+		render.Render(w, r, ErrNotFound("offline mirror"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"pending": outbox.Pending(),
+			"entries": outbox.Entries(),
+		},
+	})
+}
+
+// ReplayMirrorOutbox replays the held writes now
+func ReplayMirrorOutbox(w http.ResponseWriter, r *http.Request) {
+	outbox := currentOutbox()
+	if outbox == nil {
+		render.Render(w, r, ErrNotFound("offline mirror"))
+		return
+	}
+
+	issueJobMu.RLock()
+	q := issueJobQueue
+	issueJobMu.RUnlock()
+	if q == nil {
+		render.Render(w, r, ErrInternalServer(fmt.Errorf("job queue is not available")))
+		return
+	}
+
+	replay := outbox.Replay(r.Context(), q)
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{Success: replay.Error == "", Data: replay})
+}
+
+// DiscardMirrorOutboxEntry drops a held write without sending it to Jira
+func DiscardMirrorOutboxEntry(w http.ResponseWriter, r *http.Request) {
+	outbox := currentOutbox()
+	if outbox == nil {
+		render.Render(w, r, ErrNotFound("offline mirror"))
+		return
+	}
+
+	jobID := chi.URLParam(r, "jobId")
+	if err := outbox.Discard(jobID); err != nil {
+		render.Render(w, r, ErrNotFound(fmt.Sprintf("outbox job %s", jobID)))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    map[string]interface{}{"jobId": jobID, "discarded": true},
+	})
+}
@@ -0,0 +1,647 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// projectNames holds the component and version names of the projects read
+// through the API, which the NLP parser validates entities against
+type projectNames struct {
+	mu         sync.RWMutex
+	components map[string][]string // project key -> component names
+	versions   map[string][]string // project key -> version names
+	version    uint64              // increases on every change
+}
+
+var knownProjectNames = &projectNames{
+	components: make(map[string][]string),
+	versions:   make(map[string][]string),
+}
+
+// setComponents records the active components of a project
+func (n *projectNames) setComponents(projectKey string, components []jira.Component) {
+	names := make([]string, 0, len(components))
+	for _, component := range components {
+		if !component.Archived {
+			names = append(names, component.Name)
+		}
+	}
+	n.set(n.components, projectKey, names)
+}
+
+// setVersions records the active versions of a project
+func (n *projectNames) setVersions(projectKey string, versions []jira.Version) {
+	names := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if !version.Archived {
+			names = append(names, version.Name)
+		}
+	}
+	n.set(n.versions, projectKey, names)
+}
+
+func (n *projectNames) set(names map[string][]string, projectKey string, values []string) {
+	sort.Strings(values)
+	projectKey = strings.ToUpper(projectKey)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if slices.Equal(names[projectKey], values) {
+		return
+	}
+	names[projectKey] = values
+	n.version++
+}
+
+// snapshot returns the names of every project and the current version
+func (n *projectNames) snapshot() (components, versions []string, version uint64) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, names := range n.components {
+		components = append(components, names...)
+	}
+	for _, names := range n.versions {
+		versions = append(versions, names...)
+	}
+	return components, versions, n.version
+}
+
+// ComponentRequest represents a request to create or update a component
+type ComponentRequest struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	LeadAccountID string `json:"leadAccountId,omitempty"`
+	AssigneeType  string `json:"assigneeType,omitempty"`
+}
+
+func (c *ComponentRequest) Bind(r *http.Request) error {
+	if r.Method == http.MethodPost && c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return nil
+}
+
+// VersionRequest represents a request to create or update a version
+type VersionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+func (v *VersionRequest) Bind(r *http.Request) error {
+	if r.Method == http.MethodPost && v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	for _, date := range []string{v.StartDate, v.ReleaseDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid date %q, expected yyyy-MM-dd", date)
+		}
+	}
+	return nil
+}
+
+// ReleaseVersionRequest represents a request to release a version
+type ReleaseVersionRequest struct {
+	ReleaseDate         string `json:"releaseDate,omitempty"`
+	MoveUnfixedIssuesTo string `json:"moveUnfixedIssuesTo,omitempty"`
+	Force               bool   `json:"force,omitempty"`
+}
+
+func (rv *ReleaseVersionRequest) Bind(r *http.Request) error {
+	if rv.ReleaseDate != "" {
+		if _, err := time.Parse("2006-01-02", rv.ReleaseDate); err != nil {
+			return fmt.Errorf("invalid releaseDate %q, expected yyyy-MM-dd", rv.ReleaseDate)
+		}
+	}
+	return nil
+}
+
+// renderProjectError maps Jira client errors to HTTP responses
+func renderProjectError(w http.ResponseWriter, r *http.Request, resource string, err error) {
+	if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "No project could be found") {
+		render.Render(w, r, ErrNotFound(resource))
+		return
+	}
+	render.Render(w, r, ErrInternalServer(err))
+}
+
+// GetProjects lists all visible projects
+func GetProjects(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	projects, err := currentJiraReads().Projects(ctx)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"projects": projects,
+			"count":    len(projects),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProject retrieves a project by key
+func GetProject(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	project, err := jiraClient.GetProject(ctx, projectKey)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("project %s", projectKey), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    project,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProjectComponents lists the components of a project
+func GetProjectComponents(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+	includeArchived := r.URL.Query().Get("includeArchived") == "true"
+
+	components, err := jiraClient.GetProjectComponents(r.Context(), projectKey)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("project %s", projectKey), err)
+		return
+	}
+	knownProjectNames.setComponents(projectKey, components)
+
+	if !includeArchived {
+		active := make([]jira.Component, 0, len(components))
+		for _, component := range components {
+			if !component.Archived {
+				active = append(active, component)
+			}
+		}
+		components = active
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"projectKey": projectKey,
+			"components": components,
+			"count":      len(components),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// CreateProjectComponent creates a component in a project
+func CreateProjectComponent(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+
+	var req ComponentRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	component, err := jiraClient.CreateComponent(r.Context(), &jira.CreateComponentRequest{
+		Name:          req.Name,
+		Description:   req.Description,
+		Project:       projectKey,
+		LeadAccountID: req.LeadAccountID,
+		AssigneeType:  req.AssigneeType,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    component,
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, response)
+}
+
+// UpdateProjectComponent updates a component
+func UpdateProjectComponent(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	componentID := chi.URLParam(r, "componentId")
+
+	var req ComponentRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	component, err := jiraClient.UpdateComponent(r.Context(), componentID, &jira.CreateComponentRequest{
+		Name:          req.Name,
+		Description:   req.Description,
+		LeadAccountID: req.LeadAccountID,
+		AssigneeType:  req.AssigneeType,
+	})
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("component %s", componentID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    component,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// ArchiveProjectComponent archives a component
+func ArchiveProjectComponent(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	componentID := chi.URLParam(r, "componentId")
+
+	component, err := jiraClient.ArchiveComponent(r.Context(), componentID)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("component %s", componentID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    component,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// DeleteProjectComponent deletes a component
+func DeleteProjectComponent(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	componentID := chi.URLParam(r, "componentId")
+	moveIssuesTo := r.URL.Query().Get("moveIssuesTo")
+
+	if err := jiraClient.DeleteComponent(r.Context(), componentID, moveIssuesTo); err != nil {
+		renderProjectError(w, r, fmt.Sprintf("component %s", componentID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"componentId": componentID,
+			"deleted":     true,
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProjectVersions lists the versions of a project
+func GetProjectVersions(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+	includeArchived := r.URL.Query().Get("includeArchived") == "true"
+	releasedFilter := r.URL.Query().Get("released")
+
+	versions, err := jiraClient.GetProjectVersions(r.Context(), projectKey)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("project %s", projectKey), err)
+		return
+	}
+	knownProjectNames.setVersions(projectKey, versions)
+
+	filtered := make([]jira.Version, 0, len(versions))
+	for _, version := range versions {
+		if version.Archived && !includeArchived {
+			continue
+		}
+		if releasedFilter != "" && strconv.FormatBool(version.Released) != releasedFilter {
+			continue
+		}
+		filtered = append(filtered, version)
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"projectKey": projectKey,
+			"versions":   filtered,
+			"count":      len(filtered),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// CreateProjectVersion creates a version in a project
+func CreateProjectVersion(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+
+	var req VersionRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	version, err := jiraClient.CreateVersion(r.Context(), &jira.CreateVersionRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Project:     projectKey,
+		StartDate:   req.StartDate,
+		ReleaseDate: req.ReleaseDate,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    version,
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, response)
+}
+
+// UpdateProjectVersion updates a version
+func UpdateProjectVersion(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	versionID := chi.URLParam(r, "versionId")
+
+	var req VersionRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	version, err := jiraClient.UpdateVersion(r.Context(), versionID, &jira.CreateVersionRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		StartDate:   req.StartDate,
+		ReleaseDate: req.ReleaseDate,
+	})
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("version %s", versionID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    version,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// ArchiveProjectVersion archives a version
+func ArchiveProjectVersion(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	versionID := chi.URLParam(r, "versionId")
+
+	version, err := jiraClient.ArchiveVersion(r.Context(), versionID)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("version %s", versionID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    version,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProjectVersionIssueCounts returns fixed, affected and unresolved issue counts for a version
+func GetProjectVersionIssueCounts(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	versionID := chi.URLParam(r, "versionId")
+
+	counts, err := jiraClient.GetVersionIssueCounts(r.Context(), versionID)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("version %s", versionID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    counts,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// ReleaseProjectVersion releases a version, handling unresolved issues
+func ReleaseProjectVersion(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	versionID := chi.URLParam(r, "versionId")
+
+	var req ReleaseVersionRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	opts := jira.ReleaseVersionOptions{
+		MoveUnfixedIssuesTo: req.MoveUnfixedIssuesTo,
+		Force:               req.Force,
+	}
+	if req.ReleaseDate != "" {
+		opts.ReleaseDate, _ = time.Parse("2006-01-02", req.ReleaseDate)
+	}
+
+	version, err := jiraClient.ReleaseVersion(r.Context(), versionID, opts)
+	if err != nil {
+		if strings.Contains(err.Error(), "unresolved issues") {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+		renderProjectError(w, r, fmt.Sprintf("version %s", versionID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    version,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProjectRoles lists the roles of a project
+func GetProjectRoles(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+
+	roles, err := jiraClient.GetProjectRoles(r.Context(), projectKey)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("project %s", projectKey), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"projectKey": projectKey,
+			"roles":      roles,
+			"count":      len(roles),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProjectRole retrieves a project role and its members
+func GetProjectRole(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+	roleID, err := strconv.Atoi(chi.URLParam(r, "roleId"))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid role ID")))
+		return
+	}
+
+	role, err := jiraClient.GetProjectRole(r.Context(), projectKey, roleID)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("role %d", roleID), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    role,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// GetProjectIssueTypes lists the issue types available in a project
+func GetProjectIssueTypes(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	projectKey := chi.URLParam(r, "projectKey")
+
+	issueTypes, err := jiraClient.GetProjectIssueTypes(r.Context(), projectKey)
+	if err != nil {
+		renderProjectError(w, r, fmt.Sprintf("project %s", projectKey), err)
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"projectKey": projectKey,
+			"issueTypes": issueTypes,
+			"count":      len(issueTypes),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
@@ -40,6 +40,9 @@ func NewQueueHandler() *QueueHandler {
 	}
 
 	jobQueue := queue.NewJobQueue(config)
+	registerIssueJobHandlers(jobQueue)
+	registerImportJobHandler(jobQueue)
+	registerOfflineWriteHandler(jobQueue)
 	jobQueue.Start()
 
 	return &QueueHandler{
@@ -85,7 +88,9 @@ func (h *QueueHandler) SubmitJob(w http.ResponseWriter, r *http.Request) {
 		queue.JobTypeTransition,
 		queue.JobTypeBulkUpdate,
 		queue.JobTypeSprintMove,
-		queue.JobTypeWorkflowChange:
+		queue.JobTypeWorkflowChange,
+		queue.JobTypeCloneIssue,
+		queue.JobTypeMoveIssue:
 		// Valid job type
 	default:
 		RespondWithError(w, http.StatusBadRequest, "Invalid job type")
@@ -94,6 +99,7 @@ func (h *QueueHandler) SubmitJob(w http.ResponseWriter, r *http.Request) {
 
 	// Create job
 	job := queue.Job{
+		ID:       queue.NewJobID(jobType),
 		Type:     jobType,
 		Priority: req.Priority,
 		Payload:  req.Payload,
@@ -226,6 +232,7 @@ func (h *QueueHandler) SubmitBatchJobs(w http.ResponseWriter, r *http.Request) {
 		
 		// Create job
 		job := queue.Job{
+			ID:       queue.NewJobID(jobType),
 			Type:     jobType,
 			Priority: jobReq.Priority,
 			Payload:  jobReq.Payload,
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// RemoteLinkRequest represents a request to create or update a remote link
+type RemoteLinkRequest struct {
+	jira.RemoteLink
+}
+
+func (rl *RemoteLinkRequest) Bind(r *http.Request) error {
+	if rl.Object.URL == "" {
+		return fmt.Errorf("object.url is required")
+	}
+	return nil
+}
+
+// CommitLinkRequest represents a request to link a git commit
+type CommitLinkRequest struct {
+	jira.CommitLinkOptions
+}
+
+func (cl *CommitLinkRequest) Bind(r *http.Request) error {
+	if cl.RepositoryURL == "" || cl.SHA == "" {
+		return fmt.Errorf("repositoryUrl and sha are required")
+	}
+	return nil
+}
+
+// PullRequestLinkRequest represents a request to link a pull request
+type PullRequestLinkRequest struct {
+	jira.PullRequestLinkOptions
+}
+
+func (pl *PullRequestLinkRequest) Bind(r *http.Request) error {
+	if pl.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	return nil
+}
+
+// CIRunLinkRequest represents a request to link a CI run
+type CIRunLinkRequest struct {
+	jira.CIRunLinkOptions
+}
+
+func (cr *CIRunLinkRequest) Bind(r *http.Request) error {
+	if cr.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	return nil
+}
+
+// upsertRemoteLink saves a remote link and renders the result. 201 is returned
+// for a new link and 200 when an existing link with the same global ID was updated.
+func upsertRemoteLink(w http.ResponseWriter, r *http.Request, issueKey string, link *jira.RemoteLink) {
+	result, err := jiraClient.UpsertRemoteLink(issueKey, link)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+
+	render.Status(r, status)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"issue":    issueKey,
+			"id":       result.ID,
+			"self":     result.Self,
+			"created":  result.Created,
+			"globalId": link.GlobalID,
+			"link":     link,
+		},
+	})
+}
+
+// GetRemoteLinks lists the remote links of an issue, optionally filtered by globalId
+func GetRemoteLinks(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	if globalID := r.URL.Query().Get("globalId"); globalID != "" {
+		link, err := jiraClient.GetRemoteLinkByGlobalID(key, globalID)
+		if err != nil {
+			renderProjectError(w, r, "issue", err)
+			return
+		}
+		if link == nil {
+			render.Render(w, r, ErrNotFound("remote link"))
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.Render(w, r, &IssueResponse{
+			Success: true,
+			Data:    link,
+		})
+		return
+	}
+
+	links, err := jiraClient.GetRemoteLinks(key)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"issue": key,
+			"links": links,
+			"count": len(links),
+		},
+	})
+}
+
+// UpsertRemoteLink creates a remote link or updates the one with the same global ID
+func UpsertRemoteLink(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req RemoteLinkRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	upsertRemoteLink(w, r, chi.URLParam(r, "key"), &req.RemoteLink)
+}
+
+// UpdateRemoteLink replaces a remote link by ID
+func UpdateRemoteLink(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	linkID, err := strconv.Atoi(chi.URLParam(r, "linkId"))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid link ID")))
+		return
+	}
+
+	var req RemoteLinkRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := jiraClient.UpdateRemoteLink(key, linkID, &req.RemoteLink); err != nil {
+		renderProjectError(w, r, "remote link", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": "Remote link updated successfully",
+			"issue":   key,
+			"id":      linkID,
+		},
+	})
+}
+
+// DeleteRemoteLink deletes a remote link by ID, or by globalId when no ID is given
+func DeleteRemoteLink(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var err error
+	if idStr := chi.URLParam(r, "linkId"); idStr != "" {
+		linkID, convErr := strconv.Atoi(idStr)
+		if convErr != nil {
+			render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid link ID")))
+			return
+		}
+		err = jiraClient.DeleteRemoteLink(key, linkID)
+	} else if globalID := r.URL.Query().Get("globalId"); globalID != "" {
+		err = jiraClient.DeleteRemoteLinkByGlobalID(key, globalID)
+	} else {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("link ID or globalId parameter is required")))
+		return
+	}
+
+	if err != nil {
+		renderProjectError(w, r, "remote link", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": "Remote link deleted successfully",
+			"issue":   key,
+		},
+	})
+}
+
+// LinkCommit links a git commit to an issue
+func LinkCommit(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req CommitLinkRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	link, err := jira.NewCommitRemoteLink(req.CommitLinkOptions)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	upsertRemoteLink(w, r, chi.URLParam(r, "key"), link)
+}
+
+// LinkPullRequest links a pull request to an issue
+func LinkPullRequest(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req PullRequestLinkRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	link, err := jira.NewPullRequestRemoteLink(req.PullRequestLinkOptions)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	upsertRemoteLink(w, r, chi.URLParam(r, "key"), link)
+}
+
+// LinkCIRun links a CI run to an issue
+func LinkCIRun(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req CIRunLinkRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	link, err := jira.NewCIRunRemoteLink(req.CIRunLinkOptions)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	upsertRemoteLink(w, r, chi.URLParam(r, "key"), link)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+)
+
+var (
+	sharedCacheMu         sync.Mutex
+	sharedCache           *cache.MultiLevelCache
+	unregisterSharedCache func()
+	stopSharedRelay       func()
+)
+
+// SetSharedCache backs the multi-level cache with a shared L2 cache on the
+// Redis-compatible server of opts. Replicas pointed at the same server and
+// prefix share cached entries, and invalidations published on any of them
+// reach all of them. An empty address removes the shared cache.
+func SetSharedCache(opts cache.RedisOptions) error {
+	var mc *cache.MultiLevelCache
+	var stop func()
+	if opts.Addr != "" {
+		l2, err := cache.NewRedisCache(opts)
+		if err != nil {
+			return err
+		}
+		if stop, err = l2.RelayInvalidations(cache.GlobalInvalidationBus); err != nil {
+			l2.Stop()
+			return fmt.Errorf("failed to relay cache invalidations: %w", err)
+		}
+		strategy := cache.DefaultMultiLevelStrategy()
+		strategy.Name = "shared"
+		strategy.MaxL3Size = 0
+		// Other replicas only see what reaches L2
+		strategy.WriteThrough = true
+		if opts.DefaultTTL > 0 {
+			strategy.L2TTL = opts.DefaultTTL
+		}
+		mc = cache.NewMultiLevelCache(strategy)
+		mc.SetL2Cache(l2)
+	}
+
+	sharedCacheMu.Lock()
+	defer sharedCacheMu.Unlock()
+	if stopSharedRelay != nil {
+		stopSharedRelay()
+		stopSharedRelay = nil
+	}
+	if unregisterSharedCache != nil {
+		unregisterSharedCache()
+		unregisterSharedCache = nil
+	}
+	if sharedCache != nil {
+		if cache.GlobalAdmin.MultiLevelCache() == sharedCache {
+			cache.GlobalAdmin.SetMultiLevelCache(nil)
+		}
+		sharedCache.Stop()
+	}
+
+	sharedCache = mc
+	if mc != nil {
+		cache.GlobalAdmin.SetMultiLevelCache(mc)
+		stopSharedRelay = stop
+		unregisterSharedCache = cache.GlobalInvalidationBus.Register(mc)
+	}
+	return nil
+}
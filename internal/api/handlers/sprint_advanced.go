@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/cache"
 	"github.com/ericfisherdev/GoJira/internal/jira"
 	"github.com/ericfisherdev/GoJira/internal/services"
 	"github.com/go-chi/chi/v5"
@@ -13,17 +14,28 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-var sprintService *services.SprintService
+var (
+	sprintService           *services.SprintService
+	unregisterSprintService func()
+)
 
-// SetSprintService sets the global sprint service
+// SetSprintService sets the global sprint service and subscribes its cache
+// to invalidation by Jira writes
 func SetSprintService(service *services.SprintService) {
+	if unregisterSprintService != nil {
+		unregisterSprintService()
+		unregisterSprintService = nil
+	}
 	sprintService = service
+	if service != nil {
+		unregisterSprintService = cache.GlobalInvalidationBus.Register(service)
+	}
 }
 
 // GetActiveSprints retrieves all active sprints across boards
 func GetActiveSprints(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	ctx := r.Context()
@@ -43,7 +55,7 @@ func GetActiveSprints(w http.ResponseWriter, r *http.Request) {
 // GetUpcomingSprints retrieves upcoming/future sprints for a board
 func GetUpcomingSprints(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	boardIDStr := r.URL.Query().Get("boardId")
@@ -76,7 +88,7 @@ func GetUpcomingSprints(w http.ResponseWriter, r *http.Request) {
 // AutoStartSprint automatically starts a sprint if conditions are met
 func AutoStartSprint(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	sprintIDStr := chi.URLParam(r, "id")
@@ -104,7 +116,7 @@ func AutoStartSprint(w http.ResponseWriter, r *http.Request) {
 // CompleteSprintWithReport closes a sprint and generates a completion report
 func CompleteSprintWithReport(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	sprintIDStr := chi.URLParam(r, "id")
@@ -121,6 +133,13 @@ func CompleteSprintWithReport(w http.ResponseWriter, r *http.Request) {
 		req.MoveIncomplete = "backlog" // Default to backlog
 	}
 	
+	// The format is checked before the sprint is closed, which cannot be undone
+	format := jira.ExportFormat(r.URL.Query().Get("format"))
+	if format != "" && format != jira.FormatJSON && format != jira.FormatXLSX && format != jira.FormatHTML {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("unsupported report format '%s', supported formats: json, xlsx, html", format)))
+		return
+	}
+	
 	ctx := r.Context()
 	report, err := sprintService.CompleteSprintWithReport(ctx, sprintID, req.MoveIncomplete)
 	if err != nil {
@@ -129,6 +148,16 @@ func CompleteSprintWithReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	if format == jira.FormatXLSX || format == jira.FormatHTML {
+		exportResult, err := jira.RenderExport(report.ExportDocument(), format, fmt.Sprintf("sprint_%d_completion", sprintID))
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		writeExportResult(w, exportResult)
+		return
+	}
+	
 	render.JSON(w, r, map[string]interface{}{
 		"success": true,
 		"report":  report,
@@ -138,7 +167,7 @@ func CompleteSprintWithReport(w http.ResponseWriter, r *http.Request) {
 // GetSprintMetrics retrieves detailed metrics for a sprint
 func GetSprintMetrics(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	sprintIDStr := chi.URLParam(r, "id")
@@ -162,7 +191,7 @@ func GetSprintMetrics(w http.ResponseWriter, r *http.Request) {
 // PredictSprintSuccess predicts the likelihood of sprint success
 func PredictSprintSuccess(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	sprintIDStr := chi.URLParam(r, "id")
@@ -186,7 +215,7 @@ func PredictSprintSuccess(w http.ResponseWriter, r *http.Request) {
 // ValidateSprintRequest validates a sprint creation/update request
 func ValidateSprintRequest(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	var req jira.CreateSprintRequest
@@ -213,7 +242,7 @@ func ValidateSprintRequest(w http.ResponseWriter, r *http.Request) {
 // GetSprintHealthCheck performs a health check on active sprints
 func GetSprintHealthCheck(w http.ResponseWriter, r *http.Request) {
 	if sprintService == nil {
-		sprintService = services.NewSprintService(jiraClient)
+		SetSprintService(services.NewSprintService(jiraClient))
 	}
 	
 	ctx := r.Context()
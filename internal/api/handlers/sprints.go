@@ -27,7 +27,7 @@ func GetSprints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sprints, err := jiraClient.GetSprints(boardID)
+	sprints, err := currentJiraReads().Sprints(r.Context(), boardID)
 	if err != nil {
 		log.Error().Err(err).Int("boardId", boardID).Msg("Failed to get sprints")
 		render.Render(w, r, ErrInternalServer(err))
@@ -53,6 +53,9 @@ func GetSprint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The issues of a sprint are usually asked for next
+	currentJiraReads().PrefetchSprint(sprintID)
+
 	render.JSON(w, r, sprint)
 }
 
@@ -183,7 +186,7 @@ func GetSprintIssues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	issues, err := jiraClient.GetSprintIssues(sprintID)
+	issues, err := currentJiraReads().SprintIssues(r.Context(), sprintID)
 	if err != nil {
 		log.Error().Err(err).Int("sprintId", sprintID).Msg("Failed to get sprint issues")
 		render.Render(w, r, ErrInternalServer(err))
@@ -237,6 +240,12 @@ func GetSprintReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format := jira.ExportFormat(r.URL.Query().Get("format"))
+	if format != "" && format != jira.FormatJSON && format != jira.FormatXLSX && format != jira.FormatHTML {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("unsupported report format '%s', supported formats: json, xlsx, html", format)))
+		return
+	}
+
 	report, err := jiraClient.GetSprintReport(sprintID)
 	if err != nil {
 		log.Error().Err(err).Int("sprintId", sprintID).Msg("Failed to generate sprint report")
@@ -244,5 +253,15 @@ func GetSprintReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if format == jira.FormatXLSX || format == jira.FormatHTML {
+		exportResult, err := jira.RenderExport(jira.SprintReportDocument(report), format, fmt.Sprintf("sprint_%d_report", sprintID))
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		writeExportResult(w, exportResult)
+		return
+	}
+
 	render.JSON(w, r, report)
 }
\ No newline at end of file
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+)
+
+// streamContext returns a context for a long-running streamed response. The
+// request timeout middleware cancels the request context with a deadline,
+// which must not cut a healthy stream short, so only client disconnects
+// (context.Canceled) are propagated.
+func streamContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	go func() {
+		select {
+		case <-r.Context().Done():
+			if errors.Is(r.Context().Err(), context.Canceled) {
+				cancel()
+			}
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// prepareStream lifts the server write deadline for a streamed response and
+// returns a function that flushes written data to the client
+func prepareStream(w http.ResponseWriter) func() error {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Warn().Err(err).Msg("Failed to clear write deadline for stream")
+	}
+	return func() error {
+		err := rc.Flush()
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil
+		}
+		return err
+	}
+}
+
+// parseStreamSearchRequest reads a search from the JSON body of a POST, or from
+// the jql, fields and pageSize query parameters of a GET so that browsers can
+// use EventSource
+func parseStreamSearchRequest(r *http.Request) (*jira.SearchRequest, error) {
+	var req AdvancedSearchRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.JQL = query.Get("jql")
+		if fields := query.Get("fields"); fields != "" {
+			req.Fields = strings.Split(fields, ",")
+		}
+		req.MaxResults, _ = strconv.Atoi(query.Get("pageSize"))
+		if err := req.Bind(r); err != nil {
+			return nil, err
+		}
+	} else if err := render.Bind(r, &req); err != nil {
+		return nil, err
+	}
+
+	return &jira.SearchRequest{
+		JQL:        req.JQL,
+		StartAt:    req.StartAt,
+		MaxResults: req.MaxResults,
+		Fields:     req.Fields,
+		Expand:     req.Expand,
+		Properties: req.Properties,
+	}, nil
+}
+
+// parseMaxIssues reads the optional maxIssues query parameter
+func parseMaxIssues(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("maxIssues")
+	if value == "" {
+		return 0, nil
+	}
+	maxIssues, err := strconv.Atoi(value)
+	if err != nil || maxIssues < 0 {
+		return 0, fmt.Errorf("maxIssues must be a non-negative integer")
+	}
+	return maxIssues, nil
+}
+
+// StreamSearch streams search results as they are fetched from Jira, either as
+// NDJSON (one issue per line) or as Server-Sent Events when format=sse or the
+// client accepts text/event-stream
+func StreamSearch(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	searchReq, err := parseStreamSearchRequest(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	maxIssues, err := parseMaxIssues(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "ndjson"
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			format = "sse"
+		}
+	}
+	if format != "ndjson" && format != "sse" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("unsupported stream format '%s', supported formats: ndjson, sse", format)))
+		return
+	}
+
+	ctx, cancel := streamContext(r)
+	defer cancel()
+	flush := prepareStream(w)
+
+	// The status line is held back until the first page arrives so that
+	// errors such as invalid JQL still get a proper error response
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		if format == "sse" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("X-Accel-Buffering", "no")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	encoder := json.NewEncoder(w)
+	writeEvent := func(event string, data interface{}) error {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: ", event); err != nil {
+			return err
+		}
+		// Encode terminates the data line with the newline, the extra one ends the event
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		_, err := fmt.Fprint(w, "\n")
+		return err
+	}
+
+	delivered := 0
+	summary, err := jiraClient.StreamSearch(ctx, *searchReq, jira.SearchStreamOptions{MaxIssues: maxIssues}, func(page *jira.ExtendedSearchResult) error {
+		start()
+		for i := range page.Issues {
+			var err error
+			if format == "sse" {
+				err = writeEvent("issue", page.Issues[i])
+			} else {
+				err = encoder.Encode(page.Issues[i])
+			}
+			if err != nil {
+				return fmt.Errorf("client write failed: %w", err)
+			}
+		}
+		delivered += len(page.Issues)
+
+		if format == "sse" {
+			if err := writeEvent("progress", map[string]int{"delivered": delivered, "total": page.Total}); err != nil {
+				return fmt.Errorf("client write failed: %w", err)
+			}
+		}
+		return flush()
+	})
+
+	if err != nil {
+		log.Warn().Err(err).Int("delivered", delivered).Msg("Search stream ended early")
+		if !started {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		// The status line is already sent, so errors are reported in-band
+		if ctx.Err() == nil {
+			if format == "sse" {
+				writeEvent("error", map[string]string{"error": err.Error()})
+			} else {
+				encoder.Encode(map[string]string{"error": err.Error()})
+			}
+			flush()
+		}
+		return
+	}
+
+	start()
+	if format == "sse" {
+		writeEvent("done", summary)
+	}
+	flush()
+}
+
+// streamCSVExport streams every match of a search as CSV rows
+func streamCSVExport(w http.ResponseWriter, r *http.Request, searchReq jira.SearchRequest, fields []string) {
+	maxIssues, err := parseMaxIssues(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	ctx, cancel := streamContext(r)
+	defer cancel()
+	flush := prepareStream(w)
+
+	started := false
+	var csvWriter *jira.CSVStreamWriter
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"jira_search_results_%s.csv\"", time.Now().Format("20060102_150405")))
+		w.Header().Set("Trailer", "X-Export-Status")
+		w.WriteHeader(http.StatusOK)
+		csvWriter = jiraClient.NewCSVStreamWriter(w, fields)
+	}
+
+	summary, err := jiraClient.StreamSearch(ctx, searchReq, jira.SearchStreamOptions{MaxIssues: maxIssues}, func(page *jira.ExtendedSearchResult) error {
+		start()
+		if err := csvWriter.WriteIssues(page.Issues); err != nil {
+			return fmt.Errorf("client write failed: %w", err)
+		}
+		return flush()
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("CSV export stream ended early")
+		if !started {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		// The status line is already sent, so a truncated export is flagged in the trailer
+		w.Header().Set("X-Export-Status", "incomplete: "+err.Error())
+		return
+	}
+
+	// An empty result still gets the header row
+	start()
+	if summary.Delivered == 0 {
+		csvWriter.WriteIssues(nil)
+	}
+	w.Header().Set("X-Export-Status", "complete")
+	log.Info().Int("issues", summary.Delivered).Int("pages", summary.Pages).Msg("CSV export streamed")
+}
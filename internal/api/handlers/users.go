@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/render"
+)
+
+var (
+	userDirectory   *services.UserDirectory
+	userDirectoryMu sync.Mutex
+)
+
+// SetUserDirectory sets the global user directory
+func SetUserDirectory(directory *services.UserDirectory) {
+	userDirectoryMu.Lock()
+	defer userDirectoryMu.Unlock()
+	userDirectory = directory
+}
+
+// GetUserDirectory returns the user directory for the current Jira client,
+// creating a new one whenever the client changes
+func GetUserDirectory() *services.UserDirectory {
+	userDirectoryMu.Lock()
+	defer userDirectoryMu.Unlock()
+
+	if jiraClient == nil {
+		return userDirectory
+	}
+	if userDirectory == nil || userDirectory.Client() != services.UserClient(jiraClient) {
+		userDirectory = services.NewUserDirectory(jiraClient)
+	}
+	return userDirectory
+}
+
+// ResolveUserRequest represents a request to resolve a user reference
+type ResolveUserRequest struct {
+	Query    string `json:"query"`
+	Project  string `json:"project,omitempty"`
+	IssueKey string `json:"issueKey,omitempty"`
+}
+
+func (ru *ResolveUserRequest) Bind(r *http.Request) error {
+	if ru.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	return nil
+}
+
+func parseMaxResults(r *http.Request, def int) int {
+	if maxStr := r.URL.Query().Get("maxResults"); maxStr != "" {
+		if val, err := strconv.Atoi(maxStr); err == nil && val > 0 && val <= 1000 {
+			return val
+		}
+	}
+	return def
+}
+
+// SearchUsers searches for users by name or email
+func SearchUsers(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("query parameter is required")))
+		return
+	}
+
+	users, err := GetUserDirectory().Search(query, parseMaxResults(r, 50))
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"query": query,
+			"users": users,
+			"count": len(users),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// FindAssignableUsers finds users assignable to a project or issue
+func FindAssignableUsers(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	q := jira.AssignableUserQuery{
+		Query:      r.URL.Query().Get("query"),
+		ProjectKey: r.URL.Query().Get("project"),
+		IssueKey:   r.URL.Query().Get("issueKey"),
+		MaxResults: parseMaxResults(r, 50),
+	}
+	if q.ProjectKey == "" && q.IssueKey == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("project or issueKey parameter is required")))
+		return
+	}
+
+	users, err := GetUserDirectory().FindAssignable(q)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"query":    q.Query,
+			"project":  q.ProjectKey,
+			"issueKey": q.IssueKey,
+			"users":    users,
+			"count":    len(users),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// UserPicker returns user suggestions for a partial name
+func UserPicker(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("query parameter is required")))
+		return
+	}
+
+	result, err := jiraClient.UserPicker(query, parseMaxResults(r, 10))
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: true,
+		Data:    result,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, response)
+}
+
+// ResolveUser resolves a free-form user reference to an account ID
+func ResolveUser(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req ResolveUserRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	resolution, err := GetUserDirectory().Resolve(req.Query, jira.AssignableUserQuery{
+		ProjectKey: req.Project,
+		IssueKey:   req.IssueKey,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	response := &IssueResponse{
+		Success: resolution.Resolved,
+		Data:    resolution,
+	}
+
+	status := http.StatusOK
+	if resolution.Ambiguous {
+		status = http.StatusConflict
+	} else if !resolution.Resolved {
+		status = http.StatusNotFound
+	}
+
+	render.Status(r, status)
+	render.Render(w, r, response)
+}
+
+// resolveAssigneeField converts an assignee reference into a Jira user field value.
+// Account IDs pass through untouched; names, handles and emails are resolved through
+// the user directory. A non-nil resolution is returned when the reference could not
+// be resolved to exactly one user.
+func resolveAssigneeField(assignee string, scope jira.AssignableUserQuery) (map[string]interface{}, *services.UserResolution, error) {
+	if services.IsAccountID(assignee) {
+		return map[string]interface{}{"accountId": assignee}, nil, nil
+	}
+
+	resolution, err := GetUserDirectory().Resolve(assignee, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !resolution.Resolved {
+		return nil, resolution, nil
+	}
+
+	if resolution.User != nil && resolution.User.AccountID == "" {
+		// Server/Data Center identifies users by name
+		return map[string]interface{}{"name": resolution.AccountID}, nil, nil
+	}
+	return map[string]interface{}{"accountId": resolution.AccountID}, nil, nil
+}
+
+// renderUnresolvedUser reports a user reference that matched no user or several users
+func renderUnresolvedUser(w http.ResponseWriter, r *http.Request, resolution *services.UserResolution) {
+	status := http.StatusUnprocessableEntity
+	if resolution.Ambiguous {
+		status = http.StatusConflict
+	}
+
+	render.Status(r, status)
+	render.Render(w, r, &IssueResponse{
+		Success: false,
+		Error:   resolution,
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+)
+
+var (
+	jiraReads           *services.JiraCache
+	unregisterJiraReads func()
+	warmer              *services.Warmer
+
+	warmupMu       sync.Mutex
+	warmupProfiles []services.WarmupProfile
+	warmupInterval = 30 * time.Minute
+)
+
+// SetWarmupProfiles sets the profiles warmed after connecting and every
+// interval; an interval of zero disables scheduled warm-ups. The default
+// profile is used when no profiles are given.
+func SetWarmupProfiles(profiles []services.WarmupProfile, interval time.Duration) error {
+	for _, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+	}
+
+	warmupMu.Lock()
+	defer warmupMu.Unlock()
+	warmupProfiles = profiles
+	warmupInterval = interval
+	if jiraClient != nil {
+		resetJiraReads(jiraClient)
+	}
+	return nil
+}
+
+// currentJiraReads returns the Jira read cache of the connected client,
+// creating it if the client was set without one; it is nil when not connected
+func currentJiraReads() *services.JiraCache {
+	warmupMu.Lock()
+	defer warmupMu.Unlock()
+	if jiraReads == nil && jiraClient != nil {
+		resetJiraReads(jiraClient)
+	}
+	return jiraReads
+}
+
+// resetJiraReads replaces the Jira read cache and its warmer for a new client;
+// warmupMu must be held
+func resetJiraReads(client *jira.Client) {
+	if unregisterJiraReads != nil {
+		unregisterJiraReads()
+		unregisterJiraReads = nil
+	}
+	if warmer != nil {
+		warmer.Stop()
+		warmer = nil
+	}
+	jiraReads = nil
+	if client == nil {
+		return
+	}
+
+	jiraReads = services.NewJiraCache(client)
+	unregisterJiraReads = cache.GlobalInvalidationBus.Register(jiraReads)
+	// Profiles were validated by SetWarmupProfiles
+	warmer, _ = services.NewWarmer(jiraReads, warmupProfiles)
+}
+
+// startWarmup warms the Jira read cache and schedules further warm-ups
+func startWarmup(trigger string) {
+	warmupMu.Lock()
+	w, interval := warmer, warmupInterval
+	warmupMu.Unlock()
+
+	if w == nil {
+		return
+	}
+	w.Start(trigger)
+	w.Schedule(interval)
+}
+
+// stopWarmup ends scheduled warm-ups
+func stopWarmup() {
+	warmupMu.Lock()
+	defer warmupMu.Unlock()
+	if warmer != nil {
+		warmer.Stop()
+	}
+}
+
+// warmupProgress returns the progress of the warm-up, or nil when not connected
+func warmupProgress() *services.WarmupProgress {
+	warmupMu.Lock()
+	w := warmer
+	warmupMu.Unlock()
+
+	if w == nil {
+		return nil
+	}
+	progress := w.Progress()
+	return &progress
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// WatcherRequest represents a request to add a watcher
+type WatcherRequest struct {
+	User string `json:"user"` // account ID, username, email or display name
+}
+
+func (wr *WatcherRequest) Bind(r *http.Request) error {
+	if wr.User == "" {
+		return fmt.Errorf("user is required")
+	}
+	return nil
+}
+
+// NotifyIssueRequest represents a request to send a notification about an issue
+type NotifyIssueRequest struct {
+	Subject  string `json:"subject"`
+	TextBody string `json:"textBody,omitempty"`
+	HTMLBody string `json:"htmlBody,omitempty"`
+	To       struct {
+		Reporter bool     `json:"reporter,omitempty"`
+		Assignee bool     `json:"assignee,omitempty"`
+		Watchers bool     `json:"watchers,omitempty"`
+		Voters   bool     `json:"voters,omitempty"`
+		Users    []string `json:"users,omitempty"`
+		Groups   []string `json:"groups,omitempty"`
+	} `json:"to"`
+	RestrictToGroups []string `json:"restrictToGroups,omitempty"`
+}
+
+func (nr *NotifyIssueRequest) Bind(r *http.Request) error {
+	if nr.Subject == "" {
+		return fmt.Errorf("subject is required")
+	}
+	if nr.TextBody == "" && nr.HTMLBody == "" {
+		return fmt.Errorf("textBody or htmlBody is required")
+	}
+	to := nr.To
+	if !to.Reporter && !to.Assignee && !to.Watchers && !to.Voters && len(to.Users) == 0 && len(to.Groups) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+	return nil
+}
+
+// resolveUserReference resolves a user reference to an account ID or, on
+// Server/Data Center, a username. A non-nil resolution is returned when the
+// reference is unknown or ambiguous.
+func resolveUserReference(reference string, scope jira.AssignableUserQuery) (jira.NotifyUser, *services.UserResolution, error) {
+	field, resolution, err := resolveAssigneeField(reference, scope)
+	if err != nil || resolution != nil {
+		return jira.NotifyUser{}, resolution, err
+	}
+
+	user := jira.NotifyUser{}
+	if accountID, ok := field["accountId"].(string); ok {
+		user.AccountID = accountID
+	}
+	if name, ok := field["name"].(string); ok {
+		user.Name = name
+	}
+	return user, nil, nil
+}
+
+// GetIssueWatchers lists the watchers of an issue
+func GetIssueWatchers(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	watchers, err := jiraClient.GetWatchers(key)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    watchers,
+	})
+}
+
+// AddIssueWatcher adds a user to the watchers of an issue
+func AddIssueWatcher(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var req WatcherRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	user, resolution, err := resolveUserReference(req.User, jira.AssignableUserQuery{})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+	if resolution != nil {
+		renderUnresolvedUser(w, r, resolution)
+		return
+	}
+
+	id := user.AccountID
+	if id == "" {
+		id = user.Name
+	}
+	if err := jiraClient.AddWatcher(key, id); err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": fmt.Sprintf("%s is now watching %s", req.User, key),
+			"issue":   key,
+			"user":    user,
+		},
+	})
+}
+
+// RemoveIssueWatcher removes a user from the watchers of an issue
+func RemoveIssueWatcher(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	reference := r.URL.Query().Get("user")
+	if reference == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("user parameter is required")))
+		return
+	}
+
+	user, resolution, err := resolveUserReference(reference, jira.AssignableUserQuery{})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+	if resolution != nil {
+		renderUnresolvedUser(w, r, resolution)
+		return
+	}
+
+	if user.AccountID != "" {
+		err = jiraClient.RemoveWatcher(key, user.AccountID)
+	} else {
+		err = jiraClient.RemoveWatcherByUsername(key, user.Name)
+	}
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": fmt.Sprintf("%s is no longer watching %s", reference, key),
+			"issue":   key,
+			"user":    user,
+		},
+	})
+}
+
+// GetIssueVotes returns the votes on an issue
+func GetIssueVotes(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	votes, err := jiraClient.GetVotes(key)
+	if err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    votes,
+	})
+}
+
+// VoteIssue casts the current user's vote for an issue
+func VoteIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	if err := jiraClient.AddVote(key); err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": fmt.Sprintf("Voted for %s", key),
+			"issue":   key,
+		},
+	})
+}
+
+// UnvoteIssue withdraws the current user's vote for an issue
+func UnvoteIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	if err := jiraClient.RemoveVote(key); err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": fmt.Sprintf("Vote for %s withdrawn", key),
+			"issue":   key,
+		},
+	})
+}
+
+// NotifyIssue sends an email notification about an issue to users and groups
+func NotifyIssue(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	var req NotifyIssueRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	notify := &jira.NotifyRequest{
+		Subject:  req.Subject,
+		TextBody: req.TextBody,
+		HTMLBody: req.HTMLBody,
+		To: jira.NotifyRecipients{
+			Reporter: req.To.Reporter,
+			Assignee: req.To.Assignee,
+			Watchers: req.To.Watchers,
+			Voters:   req.To.Voters,
+		},
+	}
+
+	for _, reference := range req.To.Users {
+		user, resolution, err := resolveUserReference(reference, jira.AssignableUserQuery{})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(err))
+			return
+		}
+		if resolution != nil {
+			renderUnresolvedUser(w, r, resolution)
+			return
+		}
+		notify.To.Users = append(notify.To.Users, user)
+	}
+	for _, group := range req.To.Groups {
+		notify.To.Groups = append(notify.To.Groups, jira.NotifyGroupRef{Name: group})
+	}
+	if len(req.RestrictToGroups) > 0 {
+		notify.Restrict = &jira.NotifyRestriction{}
+		for _, group := range req.RestrictToGroups {
+			notify.Restrict.Groups = append(notify.Restrict.Groups, jira.NotifyGroupRef{Name: group})
+		}
+	}
+
+	if err := jiraClient.NotifyIssue(key, notify); err != nil {
+		renderProjectError(w, r, "issue", err)
+		return
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message":    fmt.Sprintf("Notification about %s queued", key),
+			"issue":      key,
+			"recipients": notify.To,
+		},
+	})
+}
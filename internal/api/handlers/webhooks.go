@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/webhook"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	webhookVerifier   = &webhook.Verifier{}
+	webhookVerifierMu sync.RWMutex
+)
+
+// maxWebhookBody bounds the size of a webhook delivery
+const maxWebhookBody = 5 << 20
+
+func init() {
+	// Changes made in Jira directly evict cached data like GoJira's own writes
+	webhook.GlobalBus.Subscribe(webhook.CacheInvalidator(cache.GlobalInvalidationBus))
+}
+
+// SetWebhookVerifier sets the credentials /webhooks/jira deliveries must carry
+func SetWebhookVerifier(verifier *webhook.Verifier) {
+	webhookVerifierMu.Lock()
+	defer webhookVerifierMu.Unlock()
+	if verifier == nil {
+		verifier = &webhook.Verifier{}
+	}
+	webhookVerifier = verifier
+}
+
+func getWebhookVerifier() *webhook.Verifier {
+	webhookVerifierMu.RLock()
+	defer webhookVerifierMu.RUnlock()
+	return webhookVerifier
+}
+
+// ReceiveJiraWebhook verifies a webhook delivery from Jira and dispatches its
+// event to the subscribers of the webhook bus
+func ReceiveJiraWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("failed to read webhook payload: %w", err)))
+		return
+	}
+	if len(body) > maxWebhookBody {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("webhook payload exceeds %d bytes", maxWebhookBody)))
+		return
+	}
+
+	if err := getWebhookVerifier().Verify(r, body); err != nil {
+		if errors.Is(err, webhook.ErrNotConfigured) {
+			log.Error().Msg("Rejected Jira webhook: no webhook secret is configured")
+			render.Render(w, r, &ErrorResponse{
+				Err:            err,
+				HTTPStatusCode: http.StatusServiceUnavailable,
+				StatusText:     "Webhooks not configured",
+				ErrorText:      err.Error(),
+			})
+			return
+		}
+		log.Warn().Err(err).Str("remoteAddr", r.RemoteAddr).Msg("Rejected Jira webhook")
+		render.Render(w, r, ErrUnauthorized(err))
+		return
+	}
+
+	event, err := webhook.Parse(body)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+	event.ID = r.Header.Get(webhook.IdentifierHeader)
+
+	delivered := webhook.GlobalBus.Dispatch(event)
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"event":     event.Type,
+			"category":  event.Category(),
+			"issueKey":  event.IssueKey(),
+			"delivered": delivered,
+		},
+	})
+}
+
+// RegisterWebhookRequest represents a request to register GoJira's webhook in Jira
+type RegisterWebhookRequest struct {
+	Name   string   `json:"name,omitempty"`
+	URL    string   `json:"url"` // public URL of /webhooks/jira
+	Events []string `json:"events,omitempty"`
+	JQL    string   `json:"jql,omitempty"`
+	Sign   bool     `json:"sign,omitempty"` // have Jira Cloud sign deliveries instead of passing the secret in the URL
+}
+
+func (req *RegisterWebhookRequest) Bind(r *http.Request) error {
+	if req.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if req.Name == "" {
+		req.Name = "GoJira"
+	}
+	return nil
+}
+
+// webhookRequest builds the Jira registration. The configured secret is
+// either passed for signing or added to the URL, since Jira Server and Data
+// Center cannot sign deliveries.
+func (req *RegisterWebhookRequest) webhookRequest(secret string) *jira.WebhookRequest {
+	wr := &jira.WebhookRequest{Name: req.Name, URL: req.URL, Events: req.Events, JQL: req.JQL}
+	if secret == "" {
+		return wr
+	}
+	if req.Sign {
+		wr.Secret = secret
+		return wr
+	}
+	u, _ := url.Parse(req.URL)
+	query := u.Query()
+	query.Set("secret", secret)
+	u.RawQuery = query.Encode()
+	wr.URL = u.String()
+	return wr
+}
+
+// RegisterJiraWebhook registers GoJira's webhook receiver in Jira
+func RegisterJiraWebhook(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	var req RegisterWebhookRequest
+	if err := render.Bind(r, &req); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	verifier := getWebhookVerifier()
+	if verifier.Secret == "" {
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("a webhook secret must be configured before registering the webhook")))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	registered, err := jiraClient.RegisterWebhook(ctx, req.webhookRequest(verifier.Secret))
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data:    redactWebhook(*registered),
+	})
+}
+
+// GetJiraWebhooks lists the webhooks registered in Jira
+func GetJiraWebhooks(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	webhooks, err := jiraClient.GetWebhooks(ctx)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	result := make([]map[string]interface{}, len(webhooks))
+	for i, wh := range webhooks {
+		result[i] = redactWebhook(wh)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"webhooks": result,
+			"total":    len(result),
+		},
+	})
+}
+
+// DeleteJiraWebhook removes a webhook registered in Jira
+func DeleteJiraWebhook(w http.ResponseWriter, r *http.Request) {
+	if jiraClient == nil || !authManager.IsAuthenticated() {
+		render.Render(w, r, ErrUnauthorized(fmt.Errorf("not connected to Jira")))
+		return
+	}
+
+	webhookID := chi.URLParam(r, "id")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := jiraClient.DeleteWebhook(ctx, webhookID); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			render.Render(w, r, ErrNotFound("webhook"))
+			return
+		}
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"webhookId": webhookID,
+			"deleted":   true,
+		},
+	})
+}
+
+// GetWebhookStats returns the counters of the webhook bus
+func GetWebhookStats(w http.ResponseWriter, r *http.Request) {
+	render.Status(r, http.StatusOK)
+	render.Render(w, r, &IssueResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"configured": getWebhookVerifier().Configured(),
+			"stats":      webhook.GlobalBus.GetStats(),
+		},
+	})
+}
+
+// redactWebhook hides the secret GoJira adds to the webhook URL
+func redactWebhook(wh jira.Webhook) map[string]interface{} {
+	if u, err := url.Parse(wh.URL); err == nil && u.Query().Has("secret") {
+		query := u.Query()
+		query.Set("secret", "REDACTED")
+		u.RawQuery = query.Encode()
+		wh.URL = u.String()
+	}
+	return map[string]interface{}{
+		"id":      wh.ID(),
+		"name":    wh.Name,
+		"url":     wh.URL,
+		"events":  wh.Events,
+		"filters": wh.Filters,
+		"enabled": wh.Enabled,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/jql"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/rs/zerolog/log"
@@ -20,7 +21,7 @@ func GetWorkflows(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workflows, err := jiraClient.GetWorkflows()
+	workflows, err := currentJiraReads().Workflows(r.Context())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get workflows")
 		render.Render(w, r, ErrInternalServer(err))
@@ -439,7 +440,7 @@ func GetProjectTransitions(w http.ResponseWriter, r *http.Request) {
 	// This is more reliable than workflow scheme APIs which may not be available in Jira Cloud
 	
 	// Search for recent issues in the project (limit to get variety of statuses)
-	searchJQL := fmt.Sprintf("project = %s ORDER BY updated DESC", projectKey)
+	searchJQL := jql.Field("project").Eq(projectKey).OrderBy("updated", jql.Desc).String()
 	
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
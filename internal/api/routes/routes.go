@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/api/middleware"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -19,8 +20,14 @@ func SetupRoutes(r *chi.Mux) {
 	r.Post("/metrics/reset", handlers.ResetMetrics)
 	r.Get("/health/detailed", handlers.GetHealthWithMetrics)
 
+	// Jira webhook receiver, authenticated by the webhook secret or JWT
+	r.Post("/webhooks/jira", handlers.ReceiveJiraWebhook)
+
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
+		// Unchanged GET responses are answered 304 Not Modified
+		r.Use(middleware.ETag())
+
 		// Authentication routes
 		r.Route("/auth", func(r chi.Router) {
 			r.Post("/connect", handlers.Connect)
@@ -33,6 +40,10 @@ func SetupRoutes(r *chi.Mux) {
 		// Issue routes
 		r.Route("/issues", func(r chi.Router) {
 			r.Post("/", handlers.CreateIssue)
+			r.Post("/validate", handlers.ValidateCreateIssue)
+			r.Post("/move/batch", handlers.BatchMoveIssues)
+			r.Post("/import", handlers.ImportIssues)
+			r.Get("/import/{importId}", handlers.GetImportStatus)
 			r.Get("/{key}", handlers.GetIssue)
 			r.Put("/{key}", handlers.UpdateIssue)
 			r.Delete("/{key}", handlers.DeleteIssue)
@@ -43,6 +54,36 @@ func SetupRoutes(r *chi.Mux) {
 			r.Post("/{key}/transition", handlers.TransitionIssue) // Support both singular and plural
 			r.Get("/{key}/links", handlers.GetIssueLinks)
 			r.Get("/{key}/customfields", handlers.GetCustomFields)
+			r.Get("/{key}/similar", handlers.GetSimilarIssues)
+
+			// Hierarchy
+			r.Post("/{key}/subtasks", handlers.CreateSubtask)
+			r.Put("/{key}/parent", handlers.SetIssueParent)
+			r.Get("/{key}/children", handlers.GetIssueChildren)
+			r.Get("/{key}/rollup", handlers.GetIssueRollup)
+
+			// Watchers, votes and notifications
+			r.Get("/{key}/watchers", handlers.GetIssueWatchers)
+			r.Post("/{key}/watchers", handlers.AddIssueWatcher)
+			r.Delete("/{key}/watchers", handlers.RemoveIssueWatcher)
+			r.Get("/{key}/votes", handlers.GetIssueVotes)
+			r.Post("/{key}/votes", handlers.VoteIssue)
+			r.Delete("/{key}/votes", handlers.UnvoteIssue)
+			r.Post("/{key}/notify", handlers.NotifyIssue)
+
+			// Remote links
+			r.Get("/{key}/remotelinks", handlers.GetRemoteLinks)
+			r.Post("/{key}/remotelinks", handlers.UpsertRemoteLink)
+			r.Delete("/{key}/remotelinks", handlers.DeleteRemoteLink)
+			r.Put("/{key}/remotelinks/{linkId}", handlers.UpdateRemoteLink)
+			r.Delete("/{key}/remotelinks/{linkId}", handlers.DeleteRemoteLink)
+			r.Post("/{key}/remotelinks/commit", handlers.LinkCommit)
+			r.Post("/{key}/remotelinks/pullrequest", handlers.LinkPullRequest)
+			r.Post("/{key}/remotelinks/ci", handlers.LinkCIRun)
+
+			// Clone and move
+			r.Post("/{key}/clone", handlers.CloneIssue)
+			r.Post("/{key}/move", handlers.MoveIssue)
 			
 			// Comments
 			r.Get("/{key}/comments", handlers.GetIssueComments)
@@ -53,18 +94,61 @@ func SetupRoutes(r *chi.Mux) {
 			r.Post("/link", handlers.CreateIssueLink)
 			r.Delete("/link/{id}", handlers.DeleteIssueLink)
 			r.Get("/linktypes", handlers.GetLinkTypes)
+
+			// Ranking
+			r.Post("/rank", handlers.RankIssues)
+		})
+
+		// Project metadata routes
+		r.Route("/projects", func(r chi.Router) {
+			r.Get("/", handlers.GetProjects)
+			r.Get("/{projectKey}", handlers.GetProject)
+			r.Get("/{projectKey}/issuetypes", handlers.GetProjectIssueTypes)
+			r.Get("/{projectKey}/createmeta", handlers.GetProjectCreateMeta)
+
+			// Components
+			r.Get("/{projectKey}/components", handlers.GetProjectComponents)
+			r.Post("/{projectKey}/components", handlers.CreateProjectComponent)
+			r.Put("/{projectKey}/components/{componentId}", handlers.UpdateProjectComponent)
+			r.Post("/{projectKey}/components/{componentId}/archive", handlers.ArchiveProjectComponent)
+			r.Delete("/{projectKey}/components/{componentId}", handlers.DeleteProjectComponent)
+
+			// Versions
+			r.Get("/{projectKey}/versions", handlers.GetProjectVersions)
+			r.Post("/{projectKey}/versions", handlers.CreateProjectVersion)
+			r.Put("/{projectKey}/versions/{versionId}", handlers.UpdateProjectVersion)
+			r.Post("/{projectKey}/versions/{versionId}/archive", handlers.ArchiveProjectVersion)
+			r.Post("/{projectKey}/versions/{versionId}/release", handlers.ReleaseProjectVersion)
+			r.Get("/{projectKey}/versions/{versionId}/issuecounts", handlers.GetProjectVersionIssueCounts)
+
+			// Roles
+			r.Get("/{projectKey}/roles", handlers.GetProjectRoles)
+			r.Get("/{projectKey}/roles/{roleId}", handlers.GetProjectRole)
+		})
+
+		// User directory routes
+		r.Route("/users", func(r chi.Router) {
+			r.Get("/search", handlers.SearchUsers)
+			r.Get("/assignable", handlers.FindAssignableUsers)
+			r.Get("/picker", handlers.UserPicker)
+			r.Post("/resolve", handlers.ResolveUser)
 		})
 
 		// Search routes
 		r.Route("/search", func(r chi.Router) {
 			r.Get("/", handlers.SearchIssues)
 			r.Post("/", handlers.SearchIssues)
+			r.Get("/local", handlers.SearchLocal)
+			r.Post("/local", handlers.SearchLocal)
 			r.Post("/advanced", handlers.AdvancedSearchIssues)
 			r.Post("/paginated", handlers.SearchWithPaginationHandler)
 			r.Post("/export", handlers.ExportSearchResults)
 			r.Post("/page", handlers.GetSearchPage)
 			r.Post("/all-pages", handlers.GetAllSearchPages)
+			r.Get("/stream", handlers.StreamSearch)
+			r.Post("/stream", handlers.StreamSearch)
 			r.Get("/validate", handlers.ValidateJQL)
+			r.Post("/parse", handlers.ParseJQL)
 			r.Get("/suggestions", handlers.GetJQLSuggestions)
 			r.Get("/fields", handlers.GetJQLFields)
 			r.Get("/functions", handlers.GetJQLFunctions)
@@ -73,8 +157,21 @@ func SetupRoutes(r *chi.Mux) {
 		// Filter routes
 		r.Route("/filters", func(r chi.Router) {
 			r.Get("/", handlers.GetAllFilters)
+			r.Post("/", handlers.CreateFilter)
+			r.Get("/favourites", handlers.GetFavouriteFilters)
 			r.Get("/{id}", handlers.GetFilter)
+			r.Put("/{id}", handlers.UpdateFilter)
+			r.Delete("/{id}", handlers.DeleteFilter)
 			r.Get("/{id}/search", handlers.SearchWithFilter)
+			r.Put("/{id}/favourite", handlers.FavouriteFilter)
+			r.Delete("/{id}/favourite", handlers.UnfavouriteFilter)
+			r.Get("/{id}/permissions", handlers.GetFilterPermissions)
+			r.Post("/{id}/permissions", handlers.AddFilterPermission)
+			r.Delete("/{id}/permissions/{permissionId}", handlers.DeleteFilterPermission)
+			r.Get("/{id}/columns", handlers.GetFilterColumns)
+			r.Put("/{id}/columns", handlers.SetFilterColumns)
+			r.Delete("/{id}/columns", handlers.ResetFilterColumns)
+			r.Get("/{id}/subscriptions", handlers.GetFilterSubscriptions)
 		})
 
 		// Sprint routes
@@ -106,6 +203,7 @@ func SetupRoutes(r *chi.Mux) {
 			r.Get("/{id}/configuration", handlers.GetBoardConfiguration)
 			r.Get("/{id}/issues", handlers.GetBoardIssues)
 			r.Get("/{id}/backlog", handlers.GetBoardBacklog)
+			r.Post("/{id}/backlog/rank", handlers.RankBacklogIssues)
 			r.Get("/{id}/sprints", handlers.GetBoardSprints)
 		})
 
@@ -170,6 +268,34 @@ func SetupRoutes(r *chi.Mux) {
 			r.Post("/context", nlpHandler.UpdateContext)
 		})
 
+		// Jira webhook registration
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Get("/", handlers.GetJiraWebhooks)
+			r.Post("/", handlers.RegisterJiraWebhook)
+			r.Get("/stats", handlers.GetWebhookStats)
+			r.Delete("/{id}", handlers.DeleteJiraWebhook)
+		})
+
+		// Cache inspection and administration
+		r.Route("/admin/cache", func(r chi.Router) {
+			r.Get("/", handlers.GetCacheAdminStats)
+			r.Get("/keys", handlers.ListCacheKeys)
+			r.Get("/entry", handlers.GetCacheEntry)
+			r.Delete("/entries", handlers.EvictCacheEntries)
+			r.Post("/flush/{layer}", handlers.FlushCacheLayer)
+			r.Get("/strategy", handlers.GetCacheStrategy)
+			r.Put("/strategy", handlers.SetCacheStrategy)
+		})
+
+		// Offline mirror of selected projects and writes held while Jira is unreachable
+		r.Route("/mirror", func(r chi.Router) {
+			r.Get("/", handlers.GetMirrorStatus)
+			r.Post("/sync", handlers.SyncMirror)
+			r.Get("/outbox", handlers.GetMirrorOutbox)
+			r.Post("/outbox/replay", handlers.ReplayMirrorOutbox)
+			r.Delete("/outbox/{jobId}", handlers.DiscardMirrorOutboxEntry)
+		})
+
 		// Queue management routes
 		r.Route("/queue", func(r chi.Router) {
 			r.Post("/jobs", queueHandler.SubmitJob)
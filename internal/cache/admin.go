@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache layers reported by the admin API. The response layer holds the
+// formatted responses of the Claude integration.
+const (
+	LayerL1       = "l1"
+	LayerL2       = "l2"
+	LayerL3       = "l3"
+	LayerResponse = "response"
+)
+
+// EntryInfo describes a cached entry without its value
+type EntryInfo struct {
+	Key       string     `json:"key"`
+	Layer     string     `json:"layer"`
+	Size      int64      `json:"size,omitempty"` // bytes, estimated for in-memory layers
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	TTLLeft   float64    `json:"ttlLeftSeconds"`
+	Hits      int64      `json:"hits"`
+	Tags      []string   `json:"tags,omitempty"`
+}
+
+// Inspector is implemented by caches whose entries can be listed
+type Inspector interface {
+	// Entries returns the live entries whose key starts with prefix
+	Entries(prefix string) []EntryInfo
+	// Entry returns the live entry of a key
+	Entry(key string) (EntryInfo, bool)
+}
+
+var (
+	_ Inspector = (*MemoryCache)(nil)
+	_ Inspector = (*DiskCache)(nil)
+	_ Inspector = (*RedisCache)(nil)
+	_ Inspector = (*ResponseCache)(nil)
+)
+
+// newEntryInfo fills the expiry fields of an entry expiring at expiration;
+// a zero expiration means the entry does not expire
+func newEntryInfo(key, layer string, expiration time.Time) EntryInfo {
+	info := EntryInfo{Key: key, Layer: layer}
+	if !expiration.IsZero() {
+		info.ExpiresAt = &expiration
+		info.TTLLeft = time.Until(expiration).Seconds()
+	}
+	return info
+}
+
+// sortEntries orders entries by key, then layer
+func sortEntries(entries []EntryInfo) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Key != entries[j].Key {
+			return entries[i].Key < entries[j].Key
+		}
+		return entries[i].Layer < entries[j].Layer
+	})
+}
+
+// estimateSize returns the JSON size of a value held in memory
+func estimateSize(value interface{}) int64 {
+	if data, err := json.Marshal(value); err == nil {
+		return int64(len(data))
+	}
+	return 0
+}
+
+// StrategyNames lists the strategies SetStrategy accepts by name
+var StrategyNames = []string{"default", "aggressive", "conservative"}
+
+// StrategyByName returns one of the predefined strategies
+func StrategyByName(name string) (CacheStrategy, error) {
+	switch strings.ToLower(name) {
+	case "default":
+		return DefaultMultiLevelStrategy(), nil
+	case "aggressive":
+		return AggressiveStrategy(), nil
+	case "conservative":
+		return ConservativeStrategy(), nil
+	}
+	return CacheStrategy{}, fmt.Errorf("unknown cache strategy %q, expected one of %s", name, strings.Join(StrategyNames, ", "))
+}
+
+// AdminRegistry holds the caches managed through the admin API
+type AdminRegistry struct {
+	mu        sync.RWMutex
+	multi     *MultiLevelCache
+	responses map[int]*ResponseCache
+	nextID    int
+}
+
+// GlobalAdmin is the registry the admin API manages
+var GlobalAdmin = NewAdminRegistry()
+
+// NewAdminRegistry creates an empty registry
+func NewAdminRegistry() *AdminRegistry {
+	return &AdminRegistry{responses: make(map[int]*ResponseCache)}
+}
+
+// SetMultiLevelCache sets the multi-level cache; nil removes it
+func (a *AdminRegistry) SetMultiLevelCache(mc *MultiLevelCache) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.multi = mc
+}
+
+// MultiLevelCache returns the multi-level cache, or nil when none is set
+func (a *AdminRegistry) MultiLevelCache() *MultiLevelCache {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.multi
+}
+
+// RegisterResponseCache adds a response cache and returns a function that
+// removes it
+func (a *AdminRegistry) RegisterResponseCache(rc *ResponseCache) (unregister func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id := a.nextID
+	a.nextID++
+	a.responses[id] = rc
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.responses, id)
+	}
+}
+
+// ResponseCaches returns the registered response caches
+func (a *AdminRegistry) ResponseCaches() []*ResponseCache {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ids := make([]int, 0, len(a.responses))
+	for id := range a.responses {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	caches := make([]*ResponseCache, 0, len(ids))
+	for _, id := range ids {
+		caches = append(caches, a.responses[id])
+	}
+	return caches
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss is returned by Backend.Get when a key is absent or expired
+var ErrCacheMiss = errors.New("cache miss")
+
+// Backend is a cache level of a MultiLevelCache. Get returns an error
+// wrapping ErrCacheMiss for absent keys, so that other errors can be told
+// apart from misses. InvalidatePattern takes a regular expression.
+type Backend interface {
+	Get(key string) (interface{}, error)
+	Set(key string, value interface{}, ttl time.Duration) error
+	Delete(key string) error
+	Clear() error
+	InvalidatePattern(pattern string) int
+	Stop()
+}
+
+// TagIndexer is implemented by shared backends that record the dependency
+// tags of their keys, so that tag invalidation also reaches entries written
+// by other processes
+type TagIndexer interface {
+	Invalidator
+	// TagKey records tags for a key stored with the given ttl
+	TagKey(key string, ttl time.Duration, tags ...string) error
+}
+
+var (
+	_ Backend = (*MemoryCache)(nil)
+	_ Backend = (*DiskCache)(nil)
+	_ Backend = (*RedisCache)(nil)
+
+	_ TagIndexer  = (*RedisCache)(nil)
+	_ Invalidator = (*DiskCache)(nil)
+)
@@ -0,0 +1,326 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// ErrStaleEntry is returned by CodecRegistry.Decode for data written with
+// another schema version, for a type no longer registered, or in a format
+// the registry cannot read. Backends evict such entries and report a miss.
+var ErrStaleEntry = errors.New("stale cache entry")
+
+// Compression selects how a codec compresses the values it encodes
+type Compression string
+
+const (
+	CompressNone Compression = "none"
+	CompressGzip Compression = "gzip"
+	// CompressAuto gzips values above compressThreshold when it saves space
+	CompressAuto Compression = "auto"
+)
+
+const compressThreshold = 1024
+
+// gobFallbackTag is the type tag of values without a registered codec. They
+// are encoded with gob as before codecs existed, so their concrete types must
+// be registered with gob.Register.
+const gobFallbackTag = "gob"
+
+// Codec encodes the values of one type for the L2 and L3 caches. The tag
+// names the type in stored data and Version is its schema version: data
+// written with another version is stale.
+type Codec struct {
+	Tag         string
+	Version     int
+	Compression Compression
+	Encode      func(value interface{}) ([]byte, error)
+	Decode      func(data []byte) (interface{}, error)
+}
+
+// CodecInfo describes a registered codec
+type CodecInfo struct {
+	Tag         string      `json:"tag"`
+	Type        string      `json:"type"`
+	Version     int         `json:"version"`
+	Compression Compression `json:"compression"`
+}
+
+// JSONCodec returns a codec encoding values of type T as JSON; they are
+// decoded as T again rather than as generic maps
+func JSONCodec[T any](tag string, version int, compression Compression) Codec {
+	return Codec{
+		Tag:         tag,
+		Version:     version,
+		Compression: compression,
+		Encode: func(value interface{}) ([]byte, error) {
+			return json.Marshal(value)
+		},
+		Decode: func(data []byte) (interface{}, error) {
+			var value T
+			err := json.Unmarshal(data, &value)
+			return value, err
+		},
+	}
+}
+
+// RegisterType registers a JSON codec for the values of type T
+func RegisterType[T any](r *CodecRegistry, tag string, version int, compression Compression) error {
+	return r.Register(reflect.TypeOf((*T)(nil)).Elem(), JSONCodec[T](tag, version, compression))
+}
+
+// codecEnvelope is the stored form of an encoded value
+type codecEnvelope struct {
+	Type        string
+	Version     int
+	Compression Compression // applied to Data: none or gzip
+	Tags        []string    // dependency tags of a MultiLevelCache entry
+	Data        []byte
+}
+
+// gobValue carries a value without a registered codec
+type gobValue struct {
+	Value interface{}
+}
+
+// CodecRegistry maps the types stored in the L2 and L3 caches to codecs
+type CodecRegistry struct {
+	mu     sync.RWMutex
+	byType map[reflect.Type]Codec
+	byTag  map[string]reflect.Type
+}
+
+// DefaultCodecs is used by the disk and Redis caches unless they are given
+// another registry. It knows the basic types and the Jira values GoJira caches.
+var DefaultCodecs = NewCodecRegistry()
+
+func init() {
+	registerDefaultCodecs(DefaultCodecs)
+}
+
+// NewCodecRegistry creates an empty registry
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{
+		byType: make(map[reflect.Type]Codec),
+		byTag:  make(map[string]reflect.Type),
+	}
+}
+
+// Register sets the codec of a type. Registering a type again replaces its
+// codec, which is how a schema version is bumped; a tag cannot be shared by
+// two types.
+func (r *CodecRegistry) Register(typ reflect.Type, codec Codec) error {
+	if codec.Tag == "" || codec.Tag == gobFallbackTag {
+		return fmt.Errorf("invalid codec tag %q", codec.Tag)
+	}
+	if codec.Encode == nil || codec.Decode == nil {
+		return fmt.Errorf("codec %s needs both Encode and Decode", codec.Tag)
+	}
+	switch codec.Compression {
+	case "":
+		codec.Compression = CompressNone
+	case CompressNone, CompressGzip, CompressAuto:
+	default:
+		return fmt.Errorf("codec %s has unknown compression %q", codec.Tag, codec.Compression)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.byTag[codec.Tag]; ok && existing != typ {
+		return fmt.Errorf("codec tag %s is already registered for %s", codec.Tag, existing)
+	}
+	if old, ok := r.byType[typ]; ok && old.Tag != codec.Tag {
+		delete(r.byTag, old.Tag)
+	}
+	r.byType[typ] = codec
+	r.byTag[codec.Tag] = typ
+	return nil
+}
+
+// Codecs describes the registered codecs, ordered by tag
+func (r *CodecRegistry) Codecs() []CodecInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]CodecInfo, 0, len(r.byType))
+	for typ, codec := range r.byType {
+		infos = append(infos, CodecInfo{
+			Tag:         codec.Tag,
+			Type:        typ.String(),
+			Version:     codec.Version,
+			Compression: codec.Compression,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Tag < infos[j].Tag })
+	return infos
+}
+
+// Encode encodes a value with the codec of its type, or with gob when its
+// type has none. The tags of a MultiLevelCache entry are stored alongside.
+func (r *CodecRegistry) Encode(value interface{}) ([]byte, error) {
+	var tags []string
+	if tv, ok := value.(taggedValue); ok {
+		value, tags = tv.Value, tv.Tags
+	}
+
+	r.mu.RLock()
+	codec, ok := r.byType[reflect.TypeOf(value)]
+	r.mu.RUnlock()
+
+	envelope := codecEnvelope{Tags: tags, Compression: CompressNone}
+	var data []byte
+	var err error
+	if ok {
+		envelope.Type, envelope.Version = codec.Tag, codec.Version
+		data, err = codec.Encode(value)
+	} else {
+		envelope.Type = gobFallbackTag
+		var buf bytes.Buffer
+		err = gob.NewEncoder(&buf).Encode(gobValue{Value: value})
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", value, err)
+	}
+
+	if ok && (codec.Compression == CompressGzip || codec.Compression == CompressAuto && len(data) > compressThreshold) {
+		compressed, err := gzipData(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress %T: %w", value, err)
+		}
+		if codec.Compression == CompressGzip || len(compressed) < len(data) {
+			data, envelope.Compression = compressed, CompressGzip
+		}
+	}
+	envelope.Data = data
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(envelope); err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", value, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode returns the value encoded by Encode as its concrete type. Data of
+// another schema version, of an unknown type or in an older format fails
+// with ErrStaleEntry.
+func (r *CodecRegistry) Decode(data []byte) (interface{}, error) {
+	var envelope codecEnvelope
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&envelope); err != nil || envelope.Type == "" {
+		return nil, fmt.Errorf("%w: unreadable entry", ErrStaleEntry)
+	}
+
+	payload := envelope.Data
+	if envelope.Compression == CompressGzip {
+		var err error
+		if payload, err = gunzipData(payload); err != nil {
+			return nil, fmt.Errorf("%w: %s entry is corrupt: %v", ErrStaleEntry, envelope.Type, err)
+		}
+	}
+
+	var value interface{}
+	if envelope.Type == gobFallbackTag {
+		var gv gobValue
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&gv); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrStaleEntry, err)
+		}
+		value = gv.Value
+	} else {
+		r.mu.RLock()
+		typ, ok := r.byTag[envelope.Type]
+		codec := r.byType[typ]
+		r.mu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("%w: no codec for %s", ErrStaleEntry, envelope.Type)
+		}
+		if codec.Version != envelope.Version {
+			return nil, fmt.Errorf("%w: %s schema version %d, expected %d", ErrStaleEntry, envelope.Type, envelope.Version, codec.Version)
+		}
+
+		var err error
+		if value, err = codec.Decode(payload); err != nil {
+			return nil, fmt.Errorf("%w: %s entry is corrupt: %v", ErrStaleEntry, envelope.Type, err)
+		}
+	}
+
+	if len(envelope.Tags) > 0 {
+		return taggedValue{Value: value, Tags: envelope.Tags}, nil
+	}
+	return value, nil
+}
+
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipData(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// registerDefaultCodecs registers the basic types and the Jira values cached
+// by GoJira. Small values are stored as they are; issue data is compressed
+// when it is large enough to gain from it.
+func registerDefaultCodecs(r *CodecRegistry) {
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	must(RegisterType[string](r, "string", 1, CompressAuto))
+	must(RegisterType[bool](r, "bool", 1, CompressNone))
+	must(RegisterType[int](r, "int", 1, CompressNone))
+	must(RegisterType[int64](r, "int64", 1, CompressNone))
+	must(RegisterType[float64](r, "float64", 1, CompressNone))
+	must(RegisterType[[]string](r, "strings", 1, CompressAuto))
+	must(RegisterType[map[string]interface{}](r, "map", 1, CompressAuto))
+	must(RegisterType[[]interface{}](r, "list", 1, CompressAuto))
+	must(r.Register(reflect.TypeOf([]byte(nil)), Codec{
+		Tag:         "bytes",
+		Version:     1,
+		Compression: CompressAuto,
+		Encode:      func(value interface{}) ([]byte, error) { return value.([]byte), nil },
+		Decode:      func(data []byte) (interface{}, error) { return data, nil },
+	}))
+
+	must(RegisterType[*jira.Issue](r, "jira.issue", 1, CompressAuto))
+	must(RegisterType[[]jira.Issue](r, "jira.issues", 1, CompressAuto))
+	must(RegisterType[*jira.SearchResult](r, "jira.search", 1, CompressAuto))
+	must(RegisterType[*jira.ExtendedSearchResult](r, "jira.searchExtended", 1, CompressAuto))
+	must(RegisterType[*jira.TransitionsResult](r, "jira.transitions", 1, CompressAuto))
+	must(RegisterType[*jira.Sprint](r, "jira.sprint", 1, CompressNone))
+	must(RegisterType[*jira.SprintList](r, "jira.sprints", 1, CompressAuto))
+	must(RegisterType[*jira.SprintIssueList](r, "jira.sprintIssues", 1, CompressAuto))
+	must(RegisterType[*jira.BoardList](r, "jira.boards", 1, CompressAuto))
+	must(RegisterType[*jira.BoardConfiguration](r, "jira.boardConfiguration", 1, CompressAuto))
+	must(RegisterType[*jira.Workflow](r, "jira.workflow", 1, CompressAuto))
+	must(RegisterType[*jira.WorkflowList](r, "jira.workflows", 1, CompressAuto))
+	must(RegisterType[[]jira.Project](r, "jira.projects", 1, CompressAuto))
+	must(RegisterType[[]jira.CustomField](r, "jira.fields", 1, CompressAuto))
+	must(RegisterType[[]jira.IssueLinkType](r, "jira.linkTypes", 1, CompressAuto))
+}
@@ -2,14 +2,14 @@ package cache
 
 import (
 	"bytes"
-	"compress/gzip"
 	"crypto/md5"
 	"encoding/gob"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,12 +21,18 @@ type DiskCache struct {
 	baseDir    string
 	maxSize    int64
 	ttl        time.Duration
-	compress   bool
+	codecs     *CodecRegistry
 	mu         sync.RWMutex
-	stats      diskCacheStats
+	stats      DiskCacheStats
 	stopCh     chan struct{}
 	cleanup    *time.Ticker
 	sizeCache  map[string]int64
+	hitCounts  map[string]int64
+
+	// Dependency tags of the stored entries, including those written before
+	// a restart, guarded by mu
+	tagIndex map[string]map[string]struct{} // tag -> keys
+	keyTags  map[string][]string            // key -> tags
 }
 
 // diskCacheEntry represents metadata for a cached file
@@ -34,17 +40,19 @@ type diskCacheEntry struct {
 	Key        string    `gob:"key"`
 	Expiration time.Time `gob:"expiration"`
 	Size       int64     `gob:"size"`
-	Compressed bool      `gob:"compressed"`
+	Tags       []string  `gob:"tags"`
 }
 
-// diskCacheStats tracks disk cache performance
-type diskCacheStats struct {
-	hits        int64
-	misses      int64
-	size        int64
-	fileCount   int
-	diskUsed    int64
-	cleanups    int64
+// DiskCacheStats tracks disk cache performance
+type DiskCacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	FileCount int   `json:"fileCount"`
+	DiskUsed  int64 `json:"diskUsed"`
+	MaxSize   int64 `json:"maxSize"`
+	Cleanups  int64 `json:"cleanups"`
+	// StaleEvictions counts entries evicted on read for a stale schema
+	StaleEvictions int64 `json:"staleEvictions"`
 }
 
 // NewDiskCache creates a new disk-based cache
@@ -61,13 +69,16 @@ func NewDiskCache(maxSize int64, ttl time.Duration) *DiskCache {
 		baseDir:   baseDir,
 		maxSize:   maxSize,
 		ttl:       ttl,
-		compress:  true,
+		codecs:    DefaultCodecs,
 		stopCh:    make(chan struct{}),
 		cleanup:   time.NewTicker(10 * time.Minute),
 		sizeCache: make(map[string]int64),
+		hitCounts: make(map[string]int64),
+		tagIndex:  make(map[string]map[string]struct{}),
+		keyTags:   make(map[string][]string),
 	}
 
-	// Initialize size tracking
+	// Initialize size and tag tracking
 	dc.calculateDiskUsage()
 
 	// Start cleanup goroutine
@@ -78,39 +89,47 @@ func NewDiskCache(maxSize int64, ttl time.Duration) *DiskCache {
 
 // Get retrieves a value from the disk cache
 func (dc *DiskCache) Get(key string) (interface{}, error) {
-	dc.mu.RLock()
-	defer dc.mu.RUnlock()
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
 
 	filePath := dc.getFilePath(key)
 	
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		dc.stats.misses++
-		return nil, fmt.Errorf("cache miss: key not found")
+		dc.stats.Misses++
+		return nil, fmt.Errorf("%w: key not found", ErrCacheMiss)
 	}
 
 	// Read metadata
 	entry, err := dc.readMetadata(filePath)
 	if err != nil {
-		dc.stats.misses++
+		dc.stats.Misses++
 		return nil, fmt.Errorf("failed to read metadata: %v", err)
 	}
 
 	// Check if expired
 	if time.Now().After(entry.Expiration) {
 		dc.deleteFile(filePath)
-		dc.stats.misses++
-		return nil, fmt.Errorf("cache miss: entry expired")
+		dc.stats.Misses++
+		return nil, fmt.Errorf("%w: entry expired", ErrCacheMiss)
 	}
 
-	// Read data
-	value, err := dc.readData(filePath, entry.Compressed)
+	// Read data; entries of a stale schema are evicted
+	value, err := dc.readData(filePath)
+	if errors.Is(err, ErrStaleEntry) {
+		dc.deleteFile(filePath)
+		dc.stats.Misses++
+		dc.stats.StaleEvictions++
+		log.Debug().Err(err).Str("key", key).Msg("Evicted stale disk cache entry")
+		return nil, fmt.Errorf("%w: %v", ErrCacheMiss, err)
+	}
 	if err != nil {
-		dc.stats.misses++
+		dc.stats.Misses++
 		return nil, fmt.Errorf("failed to read data: %v", err)
 	}
 
-	dc.stats.hits++
+	dc.stats.Hits++
+	dc.hitCounts[key]++
 	return value, nil
 }
 
@@ -126,24 +145,21 @@ func (dc *DiskCache) Set(key string, value interface{}, ttl time.Duration) error
 	filePath := dc.getFilePath(key)
 	expiration := time.Now().Add(ttl)
 
-	// Serialize data
-	data, err := dc.serialize(value)
-	if err != nil {
-		return fmt.Errorf("serialization failed: %v", err)
+	// Tagged entries of a MultiLevelCache keep their tags in the metadata
+	var tags []string
+	if tv, ok := value.(taggedValue); ok {
+		tags = tv.Tags
 	}
 
-	// Compress if enabled
-	compressed := false
-	if dc.compress && len(data) > 1024 { // Only compress if > 1KB
-		if compressedData, err := dc.compressData(data); err == nil && len(compressedData) < len(data) {
-			data = compressedData
-			compressed = true
-		}
+	// Serialize data with the codec of its type, which also compresses it
+	data, err := dc.codecs.Encode(value)
+	if err != nil {
+		return fmt.Errorf("serialization failed: %v", err)
 	}
 
 	// Check if we need to make space
 	dataSize := int64(len(data))
-	if dc.stats.diskUsed+dataSize > dc.maxSize {
+	if dc.stats.DiskUsed+dataSize > dc.maxSize {
 		if err := dc.evictOldest(dataSize); err != nil {
 			return fmt.Errorf("failed to make space: %v", err)
 		}
@@ -154,27 +170,27 @@ func (dc *DiskCache) Set(key string, value interface{}, ttl time.Duration) error
 		Key:        key,
 		Expiration: expiration,
 		Size:       dataSize,
-		Compressed: compressed,
+		Tags:       tags,
 	}
 
 	// Write to disk
 	if err := dc.writeFile(filePath, entry, data); err != nil {
 		return fmt.Errorf("failed to write file: %v", err)
 	}
+	dc.indexTags(key, tags)
 
 	// Update stats
 	if oldSize, exists := dc.sizeCache[key]; exists {
-		dc.stats.diskUsed -= oldSize
+		dc.stats.DiskUsed -= oldSize
 	} else {
-		dc.stats.fileCount++
+		dc.stats.FileCount++
 	}
-	dc.stats.diskUsed += dataSize
+	dc.stats.DiskUsed += dataSize
 	dc.sizeCache[key] = dataSize
 
 	log.Debug().
 		Str("key", key).
 		Int64("size", dataSize).
-		Bool("compressed", compressed).
 		Msg("Stored in disk cache")
 
 	return nil
@@ -210,8 +226,11 @@ func (dc *DiskCache) Clear() error {
 	}
 
 	// Reset stats
-	dc.stats = diskCacheStats{}
+	dc.stats = DiskCacheStats{}
 	dc.sizeCache = make(map[string]int64)
+	dc.hitCounts = make(map[string]int64)
+	dc.tagIndex = make(map[string]map[string]struct{})
+	dc.keyTags = make(map[string][]string)
 
 	return nil
 }
@@ -240,67 +259,143 @@ func (dc *DiskCache) InvalidatePattern(pattern string) int {
 	return count
 }
 
+// InvalidateTags removes the entries carrying any of the tags, including
+// entries written before a restart
+func (dc *DiskCache) InvalidateTags(tags ...string) int {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	keys := make(map[string]struct{})
+	for _, tag := range tags {
+		for key := range dc.tagIndex[tag] {
+			keys[key] = struct{}{}
+		}
+	}
+
+	count := 0
+	for key := range keys {
+		if err := dc.deleteFile(dc.getFilePath(key)); err == nil {
+			count++
+		}
+		dc.indexTags(key, nil)
+	}
+	return count
+}
+
+// indexTags replaces the recorded tags of a key; nil tags forget the key.
+// dc.mu must be held.
+func (dc *DiskCache) indexTags(key string, tags []string) {
+	for _, tag := range dc.keyTags[key] {
+		delete(dc.tagIndex[tag], key)
+		if len(dc.tagIndex[tag]) == 0 {
+			delete(dc.tagIndex, tag)
+		}
+	}
+	delete(dc.keyTags, key)
+
+	if len(tags) == 0 {
+		return
+	}
+	dc.keyTags[key] = tags
+	for _, tag := range tags {
+		if dc.tagIndex[tag] == nil {
+			dc.tagIndex[tag] = make(map[string]struct{})
+		}
+		dc.tagIndex[tag][key] = struct{}{}
+	}
+}
+
 // Stop shuts down the disk cache
 func (dc *DiskCache) Stop() {
 	close(dc.stopCh)
 	dc.cleanup.Stop()
 }
 
+// SetCodecs sets the registry values are encoded with
+func (dc *DiskCache) SetCodecs(codecs *CodecRegistry) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.codecs = codecs
+}
+
 // GetStats returns disk cache performance statistics
-func (dc *DiskCache) GetStats() diskCacheStats {
+func (dc *DiskCache) GetStats() DiskCacheStats {
 	dc.mu.RLock()
 	defer dc.mu.RUnlock()
-	return dc.stats
+	stats := dc.stats
+	stats.MaxSize = dc.maxSize
+	return stats
 }
 
-// Helper methods
+// SetMaxSize changes the disk space the cache may use, evicting the oldest
+// entries above it
+func (dc *DiskCache) SetMaxSize(maxSize int64) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
 
-func (dc *DiskCache) getFilePath(key string) string {
-	// Use MD5 hash to create safe filename
-	hash := md5.Sum([]byte(key))
-	filename := fmt.Sprintf("%x.cache", hash)
-	return filepath.Join(dc.baseDir, filename)
+	dc.maxSize = maxSize
+	if excess := dc.stats.DiskUsed - maxSize; excess > 0 {
+		if err := dc.evictOldest(excess); err != nil {
+			log.Warn().Err(err).Msg("Failed to shrink disk cache")
+		}
+	}
 }
 
-func (dc *DiskCache) serialize(value interface{}) ([]byte, error) {
-	var buf bytes.Buffer
-	encoder := gob.NewEncoder(&buf)
-	if err := encoder.Encode(value); err != nil {
-		return nil, err
+// Entries returns the live entries whose key starts with prefix. Entries
+// written before a restart are listed too, their keys being stored on disk.
+func (dc *DiskCache) Entries(prefix string) []EntryInfo {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+
+	files, err := os.ReadDir(dc.baseDir)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read cache directory")
+		return nil
 	}
-	return buf.Bytes(), nil
-}
 
-func (dc *DiskCache) deserialize(data []byte) (interface{}, error) {
-	buf := bytes.NewReader(data)
-	decoder := gob.NewDecoder(buf)
-	var value interface{}
-	if err := decoder.Decode(&value); err != nil {
-		return nil, err
+	now := time.Now()
+	var entries []EntryInfo
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".cache" {
+			continue
+		}
+		metadata, err := dc.readMetadata(filepath.Join(dc.baseDir, file.Name()))
+		if err != nil || !strings.HasPrefix(metadata.Key, prefix) || now.After(metadata.Expiration) {
+			continue
+		}
+		entries = append(entries, dc.entryInfo(metadata))
 	}
-	return value, nil
+	sortEntries(entries)
+	return entries
 }
 
-func (dc *DiskCache) compressData(data []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	writer := gzip.NewWriter(&buf)
-	if _, err := writer.Write(data); err != nil {
-		return nil, err
-	}
-	if err := writer.Close(); err != nil {
-		return nil, err
+// Entry returns the live entry of a key
+func (dc *DiskCache) Entry(key string) (EntryInfo, bool) {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+
+	metadata, err := dc.readMetadata(dc.getFilePath(key))
+	if err != nil || metadata.Key != key || time.Now().After(metadata.Expiration) {
+		return EntryInfo{}, false
 	}
-	return buf.Bytes(), nil
+	return dc.entryInfo(metadata), true
 }
 
-func (dc *DiskCache) decompressData(data []byte) ([]byte, error) {
-	reader, err := gzip.NewReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	defer reader.Close()
+func (dc *DiskCache) entryInfo(metadata *diskCacheEntry) EntryInfo {
+	info := newEntryInfo(metadata.Key, LayerL3, metadata.Expiration)
+	info.Size = metadata.Size
+	info.Hits = dc.hitCounts[metadata.Key]
+	info.Tags = metadata.Tags
+	return info
+}
+
+// Helper methods
 
-	return io.ReadAll(reader)
+func (dc *DiskCache) getFilePath(key string) string {
+	// Use MD5 hash to create safe filename
+	hash := md5.Sum([]byte(key))
+	filename := fmt.Sprintf("%x.cache", hash)
+	return filepath.Join(dc.baseDir, filename)
 }
 
 func (dc *DiskCache) writeFile(filePath string, entry diskCacheEntry, data []byte) error {
@@ -350,51 +445,49 @@ func (dc *DiskCache) readMetadata(filePath string) (*diskCacheEntry, error) {
 	return &entry, nil
 }
 
-func (dc *DiskCache) readData(filePath string, compressed bool) (interface{}, error) {
-	file, err := os.Open(filePath)
+func (dc *DiskCache) readData(filePath string) (interface{}, error) {
+	contents, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	// Skip metadata header
+	// Skip metadata header. A bytes.Reader keeps gob from reading ahead
+	// into the data.
+	reader := bytes.NewReader(contents)
 	var entry diskCacheEntry
-	decoder := gob.NewDecoder(file)
-	if err := decoder.Decode(&entry); err != nil {
-		return nil, err
-	}
-
-	// Read remaining data
-	data, err := io.ReadAll(file)
-	if err != nil {
+	if err := gob.NewDecoder(reader).Decode(&entry); err != nil {
 		return nil, err
 	}
 
-	// Decompress if needed
-	if compressed {
-		if data, err = dc.decompressData(data); err != nil {
-			return nil, err
-		}
-	}
-
-	// Deserialize
-	return dc.deserialize(data)
+	data := contents[len(contents)-reader.Len():]
+	return dc.codecs.Decode(data)
 }
 
 func (dc *DiskCache) deleteFile(filePath string) error {
 	// Get file info for size tracking
 	if info, err := os.Stat(filePath); err == nil {
-		dc.stats.diskUsed -= info.Size()
-		dc.stats.fileCount--
+		dc.stats.DiskUsed -= info.Size()
+		dc.stats.FileCount--
 		
 		// Extract key from path for size cache cleanup
 		for key, size := range dc.sizeCache {
 			if dc.getFilePath(key) == filePath {
 				delete(dc.sizeCache, key)
-				dc.stats.diskUsed += size // Adjust for actual vs tracked size
+				dc.stats.DiskUsed += size // Adjust for actual vs tracked size
+				break
+			}
+		}
+		for key := range dc.hitCounts {
+			if dc.getFilePath(key) == filePath {
+				delete(dc.hitCounts, key)
 				break
 			}
 		}
+		if len(dc.keyTags) > 0 {
+			if metadata, err := dc.readMetadata(filePath); err == nil {
+				dc.indexTags(metadata.Key, nil)
+			}
+		}
 	}
 
 	return os.Remove(filePath)
@@ -406,8 +499,8 @@ func (dc *DiskCache) calculateDiskUsage() {
 		return
 	}
 
-	dc.stats.diskUsed = 0
-	dc.stats.fileCount = 0
+	dc.stats.DiskUsed = 0
+	dc.stats.FileCount = 0
 
 	for _, entry := range entries {
 		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".cache" {
@@ -415,8 +508,14 @@ func (dc *DiskCache) calculateDiskUsage() {
 			if err != nil {
 				continue
 			}
-			dc.stats.diskUsed += info.Size()
-			dc.stats.fileCount++
+			dc.stats.DiskUsed += info.Size()
+			dc.stats.FileCount++
+
+			// Entries written before a restart keep their tags on disk
+			metadata, err := dc.readMetadata(filepath.Join(dc.baseDir, entry.Name()))
+			if err == nil && len(metadata.Tags) > 0 && time.Now().Before(metadata.Expiration) {
+				dc.indexTags(metadata.Key, metadata.Tags)
+			}
 		}
 	}
 }
@@ -510,7 +609,7 @@ func (dc *DiskCache) cleanupExpired() {
 					Msg("Cleaned up expired disk cache entries")
 			}
 			
-			dc.stats.cleanups++
+			dc.stats.Cleanups++
 			dc.mu.Unlock()
 
 		case <-dc.stopCh:
@@ -518,38 +617,3 @@ func (dc *DiskCache) cleanupExpired() {
 		}
 	}
 }
-
-// RedisCache is a placeholder for Redis-based caching (L2 cache)
-// This would be implemented if Redis support is needed
-type RedisCache struct {
-	// Implementation would depend on Redis client library
-}
-
-func (rc *RedisCache) Get(key string) (interface{}, error) {
-	// Redis implementation
-	return nil, fmt.Errorf("Redis cache not implemented")
-}
-
-func (rc *RedisCache) Set(key string, value interface{}, ttl time.Duration) error {
-	// Redis implementation
-	return fmt.Errorf("Redis cache not implemented")
-}
-
-func (rc *RedisCache) Delete(key string) error {
-	// Redis implementation
-	return fmt.Errorf("Redis cache not implemented")
-}
-
-func (rc *RedisCache) Clear() error {
-	// Redis implementation
-	return fmt.Errorf("Redis cache not implemented")
-}
-
-func (rc *RedisCache) InvalidatePattern(pattern string) int {
-	// Redis implementation
-	return 0
-}
-
-func (rc *RedisCache) Stop() {
-	// Redis implementation
-}
\ No newline at end of file
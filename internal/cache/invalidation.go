@@ -0,0 +1,407 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/rs/zerolog/log"
+)
+
+// Dependency tags name the Jira entities a cached entry was built from. A
+// write to Jira publishes the tags of the entities it changed, and every
+// entry carrying one of them is evicted.
+//
+// TagAnyIssue is carried by entries whose content may change when any issue
+// changes, such as unscoped search results; every write publishes it.
+// TagAllJiraData is carried by every entry built from Jira data; it is only
+// published by writes that cannot tell what they changed.
+const (
+	TagAnyIssue    = "issue:*"
+	TagAllJiraData = "jira:*"
+)
+
+// IssueTag returns the dependency tag of an issue
+func IssueTag(issueKey string) string {
+	return "issue:" + strings.ToUpper(issueKey)
+}
+
+// ProjectTag returns the dependency tag of the issues of a project as a
+// set: it is carried by searches restricted to the project and published by
+// every write to one of its issues
+func ProjectTag(projectKey string) string {
+	return "project:" + strings.ToUpper(projectKey)
+}
+
+// ProjectIssuesTag returns the tag carried by every single issue of a
+// project. It is published by project-level writes, such as renaming a
+// component, which change the issues of the project without naming them.
+func ProjectIssuesTag(projectKey string) string {
+	return "issue:" + strings.ToUpper(projectKey) + "-*"
+}
+
+// SprintTag returns the dependency tag of a sprint
+func SprintTag(sprintID int) string {
+	return "sprint:" + strconv.Itoa(sprintID)
+}
+
+// BoardTag returns the dependency tag of a board
+func BoardTag(boardID int) string {
+	return "board:" + strconv.Itoa(boardID)
+}
+
+// MutationTags returns the tags invalidated by a Jira write. Every write
+// publishes TagAnyIssue; a project-level write, naming projects but no
+// issues, publishes the issues tag of its projects; a write naming no entity
+// at all publishes TagAllJiraData.
+func MutationTags(m jira.Mutation) []string {
+	if m.IsEmpty() {
+		return []string{TagAnyIssue, TagAllJiraData}
+	}
+
+	tags := []string{TagAnyIssue}
+	for _, key := range m.Issues {
+		tags = append(tags, IssueTag(key))
+	}
+	for _, key := range m.Projects {
+		tags = append(tags, ProjectTag(key))
+		if len(m.Issues) == 0 {
+			tags = append(tags, ProjectIssuesTag(key))
+		}
+	}
+	for _, id := range m.Sprints {
+		tags = append(tags, SprintTag(id))
+	}
+	for _, id := range m.Boards {
+		tags = append(tags, BoardTag(id))
+	}
+	return uniqueTags(tags)
+}
+
+// IssueTags returns the tags of a set of issues: the tag of each issue, the
+// issues tag of their projects and TagAllJiraData
+func IssueTags(issues []jira.Issue) []string {
+	tags := make([]string, 0, 2*len(issues)+1)
+	tags = append(tags, TagAllJiraData)
+	for _, issue := range issues {
+		if issue.Key == "" {
+			continue
+		}
+		tags = append(tags, IssueTag(issue.Key))
+		project := issue.Fields.Project.Key
+		if project == "" {
+			project = jira.ProjectKeyFromIssueKey(issue.Key)
+		}
+		if project != "" {
+			tags = append(tags, ProjectIssuesTag(project))
+		}
+	}
+	return uniqueTags(tags)
+}
+
+// jqlProjectKey matches a project key. Quoted values and other spellings may
+// be project names or IDs, which do not map onto the key of a project tag.
+var jqlProjectKey = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+
+// SearchTags returns the tags of a cached search. Issues of the result are
+// tagged individually. A search restricted to projects by its JQL can only
+// change when those projects change; any other search carries TagAnyIssue,
+// because an issue outside the result may start matching after a write.
+func SearchTags(jql string, issues []jira.Issue) []string {
+	tags := IssueTags(issues)
+
+	projects := jqlProjects(jql)
+	if len(projects) == 0 {
+		return uniqueTags(append(tags, TagAnyIssue))
+	}
+	for _, project := range projects {
+		tags = append(tags, ProjectTag(project))
+	}
+	return uniqueTags(tags)
+}
+
+// jqlProjects returns the projects a JQL query is restricted to, or nil when
+// the restriction cannot be established from the query. Only the clauses of
+// the top-level AND chain restrict the result; OR and NOT may widen it.
+func jqlProjects(jql string) []string {
+	query, err := jira.ParseJQL(jql)
+	if err != nil || query.Where == nil {
+		return nil
+	}
+
+	var projects []string
+	for _, expr := range jqlConjuncts(query.Where) {
+		clause, ok := expr.(*jira.JQLClause)
+		if !ok || !strings.EqualFold(clause.Field.Name, "project") {
+			continue
+		}
+		if clause.Operator != "=" && clause.Operator != "IN" {
+			continue
+		}
+		keys, ok := jqlProjectKeys(clause.Value)
+		if !ok {
+			return nil
+		}
+		projects = append(projects, keys...)
+	}
+	return projects
+}
+
+// jqlConjuncts flattens the top-level AND chain of a JQL expression
+func jqlConjuncts(expr jira.JQLExpr) []jira.JQLExpr {
+	if binary, ok := expr.(*jira.JQLBinaryExpr); ok && binary.Op == "AND" {
+		return append(jqlConjuncts(binary.Left), jqlConjuncts(binary.Right)...)
+	}
+	return []jira.JQLExpr{expr}
+}
+
+// jqlProjectKeys returns the project keys of a clause value. It reports false
+// when any value is not an unquoted key, such as a quoted project name, a
+// lower-case name, EMPTY or a function call.
+func jqlProjectKeys(operand jira.JQLOperand) ([]string, bool) {
+	switch v := operand.(type) {
+	case *jira.JQLValue:
+		if v.Quoted || v.Empty || !jqlProjectKey.MatchString(v.Text) {
+			return nil, false
+		}
+		return []string{v.Text}, true
+	case *jira.JQLList:
+		var keys []string
+		for _, value := range v.Values {
+			key, ok := jqlProjectKeys(value)
+			if !ok {
+				return nil, false
+			}
+			keys = append(keys, key...)
+		}
+		return keys, len(keys) > 0
+	}
+	return nil, false
+}
+
+// DependencyTags returns the tags of a cached Jira value. Single issues and
+// sprints are tagged precisely; lists and values of unknown shape carry
+// TagAnyIssue as well, since any write may change them.
+func DependencyTags(value interface{}) []string {
+	switch v := value.(type) {
+	case *jira.Issue:
+		if v != nil {
+			return IssueTags([]jira.Issue{*v})
+		}
+	case jira.Issue:
+		return IssueTags([]jira.Issue{v})
+	case *jira.Sprint:
+		if v != nil {
+			tags := []string{TagAllJiraData, SprintTag(v.ID)}
+			if v.OriginBoardID != 0 {
+				tags = append(tags, BoardTag(v.OriginBoardID))
+			}
+			return tags
+		}
+	case []jira.Issue:
+		return uniqueTags(append(IssueTags(v), TagAnyIssue))
+	case *jira.SearchResult:
+		if v != nil {
+			return uniqueTags(append(IssueTags(v.Issues), TagAnyIssue))
+		}
+	case *jira.ExtendedSearchResult:
+		if v != nil {
+			return uniqueTags(append(IssueTags(v.Issues), TagAnyIssue))
+		}
+	}
+	return []string{TagAnyIssue, TagAllJiraData}
+}
+
+func uniqueTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// hasAnyTag reports whether the entry tags contain one of the wanted tags
+func hasAnyTag(entryTags []string, wanted map[string]bool) bool {
+	for _, tag := range entryTags {
+		if wanted[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	return set
+}
+
+// Invalidator is implemented by caches that can evict entries by dependency tag
+type Invalidator interface {
+	// InvalidateTags evicts every entry carrying one of the tags and returns
+	// how many entries were evicted
+	InvalidateTags(tags ...string) int
+}
+
+// InvalidationEvent is a set of dependency tags to evict
+type InvalidationEvent struct {
+	Tags      []string  `json:"tags"`
+	Operation string    `json:"operation,omitempty"`
+	Origin    string    `json:"origin"` // ID of the bus that published the event
+	Time      time.Time `json:"time"`
+}
+
+// InvalidationStats counts the events handled by an InvalidationBus
+type InvalidationStats struct {
+	Published int64 `json:"published"`
+	Received  int64 `json:"received"` // events relayed from other processes
+	Evicted   int64 `json:"evicted"`
+}
+
+// InvalidationRelay forwards events published on a bus to other processes
+type InvalidationRelay func(event InvalidationEvent) error
+
+// InvalidationBus delivers invalidation events to every registered cache. A
+// relay, such as RedisCache.PublishInvalidation, carries the events to the
+// buses of other GoJira processes, which deliver them with Receive.
+type InvalidationBus struct {
+	id          string
+	mu          sync.RWMutex
+	subscribers map[int]Invalidator
+	nextID      int
+	relays      []InvalidationRelay
+	stats       InvalidationStats
+}
+
+// GlobalInvalidationBus is the bus Jira writes are published on
+var GlobalInvalidationBus = NewInvalidationBus()
+
+// NewInvalidationBus creates a bus with a random origin ID
+func NewInvalidationBus() *InvalidationBus {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &InvalidationBus{
+		id:          hex.EncodeToString(id),
+		subscribers: make(map[int]Invalidator),
+	}
+}
+
+// ID returns the origin ID stamped on the events the bus publishes
+func (b *InvalidationBus) ID() string {
+	return b.id
+}
+
+// Register adds a cache to the bus and returns a function that removes it
+func (b *InvalidationBus) Register(inv Invalidator) (unregister func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = inv
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// AddRelay forwards every event published from now on to other processes
+func (b *InvalidationBus) AddRelay(relay InvalidationRelay) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.relays = append(b.relays, relay)
+}
+
+// Publish evicts the tags from the registered caches, then relays the event.
+// It returns the number of entries evicted in this process.
+func (b *InvalidationBus) Publish(tags []string, operation string) int {
+	event := InvalidationEvent{
+		Tags:      uniqueTags(tags),
+		Operation: operation,
+		Origin:    b.id,
+		Time:      time.Now(),
+	}
+	if len(event.Tags) == 0 {
+		return 0
+	}
+
+	evicted := b.deliver(event)
+
+	b.mu.Lock()
+	b.stats.Published++
+	relays := b.relays
+	b.mu.Unlock()
+
+	for _, relay := range relays {
+		if err := relay(event); err != nil {
+			log.Warn().Err(err).Strs("tags", event.Tags).Msg("Failed to relay cache invalidation")
+		}
+	}
+	return evicted
+}
+
+// PublishMutation publishes the tags changed by a Jira write
+func (b *InvalidationBus) PublishMutation(m jira.Mutation) int {
+	return b.Publish(MutationTags(m), m.Operation)
+}
+
+// Receive delivers an event relayed from another process. Events published
+// by this bus are ignored, having been delivered already.
+func (b *InvalidationBus) Receive(event InvalidationEvent) int {
+	if event.Origin == b.id {
+		return 0
+	}
+
+	b.mu.Lock()
+	b.stats.Received++
+	b.mu.Unlock()
+
+	return b.deliver(event)
+}
+
+func (b *InvalidationBus) deliver(event InvalidationEvent) int {
+	b.mu.RLock()
+	subscribers := make([]Invalidator, 0, len(b.subscribers))
+	for _, inv := range b.subscribers {
+		subscribers = append(subscribers, inv)
+	}
+	b.mu.RUnlock()
+
+	evicted := 0
+	for _, inv := range subscribers {
+		evicted += inv.InvalidateTags(event.Tags...)
+	}
+
+	b.mu.Lock()
+	b.stats.Evicted += int64(evicted)
+	b.mu.Unlock()
+
+	log.Debug().
+		Strs("tags", event.Tags).
+		Str("operation", event.Operation).
+		Int("evicted", evicted).
+		Msg("Cache invalidation delivered")
+	return evicted
+}
+
+// GetStats returns the bus statistics
+func (b *InvalidationBus) GetStats() InvalidationStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.stats
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,9 +16,10 @@ type MemoryCache struct {
 	maxSize   int
 	ttl       time.Duration
 	mu        sync.RWMutex
-	stats     memoryCacheStats
+	stats     MemoryCacheStats
 	stopCh    chan struct{}
 	cleanup   *time.Ticker
+	onEvict   func(key string)
 }
 
 // memoryCacheEntry represents a single cache entry
@@ -25,6 +27,7 @@ type memoryCacheEntry struct {
 	key        string
 	value      interface{}
 	expiration time.Time
+	hits       int64
 	next       *memoryCacheEntry
 	prev       *memoryCacheEntry
 }
@@ -36,13 +39,13 @@ type memoryEntryList struct {
 	size int
 }
 
-// memoryCacheStats tracks cache performance metrics
-type memoryCacheStats struct {
-	hits        int64
-	misses      int64
-	evictions   int64
-	size        int
-	memoryUsed  int64
+// MemoryCacheStats tracks cache performance metrics
+type MemoryCacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Size      int   `json:"size"`
+	MaxSize   int   `json:"maxSize"`
 }
 
 // NewMemoryCache creates a new in-memory cache
@@ -62,29 +65,31 @@ func NewMemoryCache(maxSize int, ttl time.Duration) *MemoryCache {
 	return mc
 }
 
-// Get retrieves a value from the cache
-func (mc *MemoryCache) Get(key string) (interface{}, bool) {
+// Get retrieves a value from the cache, returning ErrCacheMiss when the key
+// is absent or expired
+func (mc *MemoryCache) Get(key string) (interface{}, error) {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
 	entry, exists := mc.entries[key]
 	if !exists {
-		mc.stats.misses++
-		return nil, false
+		mc.stats.Misses++
+		return nil, ErrCacheMiss
 	}
 
 	// Check if expired
 	if time.Now().After(entry.expiration) {
 		mc.removeEntry(entry)
-		mc.stats.misses++
-		return nil, false
+		mc.stats.Misses++
+		return nil, ErrCacheMiss
 	}
 
 	// Move to front (most recently used)
 	mc.evictList.moveToFront(entry)
-	mc.stats.hits++
+	entry.hits++
+	mc.stats.Hits++
 	
-	return entry.value, true
+	return entry.value, nil
 }
 
 // Set stores a value in the cache
@@ -117,10 +122,10 @@ func (mc *MemoryCache) Set(key string, value interface{}, ttl time.Duration) err
 
 	mc.entries[key] = entry
 	mc.evictList.addToFront(entry)
-	mc.stats.size++
+	mc.stats.Size++
 
 	// Evict if over capacity
-	if mc.stats.size > mc.maxSize {
+	if mc.stats.Size > mc.maxSize {
 		mc.evictOldest()
 	}
 
@@ -146,8 +151,7 @@ func (mc *MemoryCache) Clear() error {
 
 	mc.entries = make(map[string]*memoryCacheEntry)
 	mc.evictList = &memoryEntryList{}
-	mc.stats.size = 0
-	mc.stats.memoryUsed = 0
+	mc.stats.Size = 0
 
 	return nil
 }
@@ -156,7 +160,7 @@ func (mc *MemoryCache) Clear() error {
 func (mc *MemoryCache) Size() int {
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
-	return mc.stats.size
+	return mc.stats.Size
 }
 
 // InvalidatePattern removes all entries matching the regex pattern
@@ -191,24 +195,85 @@ func (mc *MemoryCache) Stop() {
 }
 
 // GetStats returns cache performance statistics
-func (mc *MemoryCache) GetStats() memoryCacheStats {
+func (mc *MemoryCache) GetStats() MemoryCacheStats {
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
-	return mc.stats
+	stats := mc.stats
+	stats.MaxSize = mc.maxSize
+	return stats
+}
+
+// Resize changes the maximum number of entries, evicting the least recently
+// used entries above it
+func (mc *MemoryCache) Resize(maxSize int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.maxSize = maxSize
+	for mc.stats.Size > mc.maxSize {
+		mc.evictOldest()
+	}
+}
+
+// Entries returns the live entries whose key starts with prefix
+func (mc *MemoryCache) Entries(prefix string) []EntryInfo {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	now := time.Now()
+	var entries []EntryInfo
+	for key, entry := range mc.entries {
+		if strings.HasPrefix(key, prefix) && !now.After(entry.expiration) {
+			entries = append(entries, mc.entryInfo(entry))
+		}
+	}
+	sortEntries(entries)
+	return entries
+}
+
+// Entry returns the live entry of a key
+func (mc *MemoryCache) Entry(key string) (EntryInfo, bool) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	entry, exists := mc.entries[key]
+	if !exists || time.Now().After(entry.expiration) {
+		return EntryInfo{}, false
+	}
+	return mc.entryInfo(entry), true
+}
+
+func (mc *MemoryCache) entryInfo(entry *memoryCacheEntry) EntryInfo {
+	info := newEntryInfo(entry.key, LayerL1, entry.expiration)
+	info.Size = estimateSize(entry.value)
+	info.Hits = entry.hits
+	return info
+}
+
+// SetOnEvict sets a function called with the key of every entry leaving the
+// cache other than by Clear: expired, evicted, deleted or invalidated. It is
+// called with the cache locked and must not call back into it.
+func (mc *MemoryCache) SetOnEvict(fn func(key string)) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.onEvict = fn
 }
 
 // removeEntry removes an entry from both the map and eviction list
 func (mc *MemoryCache) removeEntry(entry *memoryCacheEntry) {
 	delete(mc.entries, entry.key)
 	mc.evictList.remove(entry)
-	mc.stats.size--
+	mc.stats.Size--
+	if mc.onEvict != nil {
+		mc.onEvict(entry.key)
+	}
 }
 
 // evictOldest removes the least recently used entry
 func (mc *MemoryCache) evictOldest() {
 	if mc.evictList.tail != nil {
 		mc.removeEntry(mc.evictList.tail)
-		mc.stats.evictions++
+		mc.stats.Evictions++
 	}
 }
 
@@ -234,7 +299,7 @@ func (mc *MemoryCache) cleanupExpired() {
 			if len(expired) > 0 {
 				log.Debug().
 					Int("expired", len(expired)).
-					Int("remaining", mc.stats.size).
+					Int("remaining", mc.stats.Size).
 					Msg("Cleaned up expired cache entries")
 			}
 			mc.mu.Unlock()
@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,11 +13,25 @@ import (
 // MultiLevelCache implements a multi-tier caching strategy
 type MultiLevelCache struct {
 	l1Cache  *MemoryCache      // In-memory cache (fastest)
-	l2Cache  *RedisCache       // Redis cache (shared) - optional
-	l3Cache  *DiskCache        // Disk cache (persistent) - optional
+	l2Cache  Backend           // Shared cache, e.g. RedisCache - optional
+	l3Cache  Backend           // Disk cache (persistent) - optional
 	strategy CacheStrategy
 	stats    MultiLevelStats
 	mu       sync.RWMutex
+
+	// Dependency tags of the entries in L1, guarded by mu. Keys leave the
+	// index when L1 drops them; L2 and L3 backends implementing Invalidator
+	// track the tags of their own entries.
+	tagIndex map[string]map[string]struct{} // tag -> keys
+	keyTags  map[string][]string            // key -> tags
+}
+
+// taggedValue is stored in L2 and L3 for tagged entries, so that a process
+// reading the entry learns its tags. Codecs store the tags next to the
+// encoded value.
+type taggedValue struct {
+	Value interface{}
+	Tags  []string
 }
 
 // CacheStrategy defines caching behavior across levels
@@ -28,6 +44,7 @@ type CacheStrategy struct {
 	Compress   bool          `json:"compress"`
 	PromoteToL1 bool         `json:"promoteToL1"`
 	WriteThrough bool        `json:"writeThrough"`
+	Name       string        `json:"name,omitempty"`
 }
 
 // MultiLevelStats tracks performance across cache levels
@@ -44,6 +61,31 @@ type MultiLevelStats struct {
 
 // NewMultiLevelCache creates a new multi-level cache
 func NewMultiLevelCache(strategy CacheStrategy) *MultiLevelCache {
+	strategy = withStrategyDefaults(strategy)
+	l1Cache := NewMemoryCache(strategy.MaxL1Size, strategy.L1TTL)
+	
+	mc := &MultiLevelCache{
+		l1Cache:  l1Cache,
+		strategy: strategy,
+		stats:    MultiLevelStats{},
+		tagIndex: make(map[string]map[string]struct{}),
+		keyTags:  make(map[string][]string),
+	}
+	l1Cache.SetOnEvict(func(key string) { mc.indexTags(key, nil) })
+
+	// Initialize optional cache levels
+	if strategy.MaxL3Size > 0 {
+		// A nil *DiskCache must not end up in the interface
+		if diskCache := NewDiskCache(strategy.MaxL3Size, strategy.L3TTL); diskCache != nil {
+			mc.l3Cache = diskCache
+		}
+	}
+
+	return mc
+}
+
+// withStrategyDefaults fills the unset TTLs and L1 size of a strategy
+func withStrategyDefaults(strategy CacheStrategy) CacheStrategy {
 	if strategy.L1TTL == 0 {
 		strategy.L1TTL = 5 * time.Minute
 	}
@@ -56,31 +98,46 @@ func NewMultiLevelCache(strategy CacheStrategy) *MultiLevelCache {
 	if strategy.MaxL1Size == 0 {
 		strategy.MaxL1Size = 1000
 	}
+	return strategy
+}
 
-	l1Cache := NewMemoryCache(strategy.MaxL1Size, strategy.L1TTL)
-	
-	mc := &MultiLevelCache{
-		l1Cache:  l1Cache,
-		strategy: strategy,
-		stats:    MultiLevelStats{},
-	}
+// Strategy returns the current caching strategy
+func (mc *MultiLevelCache) Strategy() CacheStrategy {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return mc.strategy
+}
 
-	// Initialize optional cache levels
-	if strategy.MaxL3Size > 0 {
-		mc.l3Cache = NewDiskCache(strategy.MaxL3Size, strategy.L3TTL)
+// SetStrategy changes the caching strategy at runtime. Entries keep the TTL
+// they were stored with; L1, and L3 when it is a DiskCache, shrink to the
+// new size limits. L3 is not created or removed.
+func (mc *MultiLevelCache) SetStrategy(strategy CacheStrategy) {
+	strategy = withStrategyDefaults(strategy)
+
+	mc.mu.Lock()
+	mc.strategy = strategy
+	mc.mu.Unlock()
+
+	mc.l1Cache.Resize(strategy.MaxL1Size)
+	if disk, ok := mc.l3Cache.(*DiskCache); ok && strategy.MaxL3Size > 0 {
+		disk.SetMaxSize(strategy.MaxL3Size)
 	}
 
-	return mc
+	log.Info().
+		Str("strategy", strategy.Name).
+		Int("maxL1Size", strategy.MaxL1Size).
+		Msg("Multi-level cache strategy changed")
 }
 
 // Get retrieves a value from the multi-level cache
 func (mc *MultiLevelCache) Get(key string) (interface{}, bool) {
 	mc.mu.Lock()
 	mc.stats.TotalRequests++
+	strategy := mc.strategy
 	mc.mu.Unlock()
 
 	// Check L1 (memory) first
-	if val, exists := mc.l1Cache.Get(key); exists {
+	if val, err := mc.l1Cache.Get(key); err == nil {
 		mc.mu.Lock()
 		mc.stats.L1Hits++
 		mc.mu.Unlock()
@@ -92,16 +149,17 @@ func (mc *MultiLevelCache) Get(key string) (interface{}, bool) {
 		return val, true
 	}
 
-	// Check L2 (Redis) if available
+	// Check L2 (shared) if available
 	if mc.l2Cache != nil {
 		if val, err := mc.l2Cache.Get(key); err == nil {
+			val, tags := unwrap(val)
 			mc.mu.Lock()
 			mc.stats.L2Hits++
 			mc.mu.Unlock()
 			
 			// Promote to L1 if enabled
-			if mc.strategy.PromoteToL1 {
-				mc.l1Cache.Set(key, val, mc.strategy.L1TTL)
+			if strategy.PromoteToL1 {
+				mc.setL1(key, val, strategy.L1TTL, tags)
 			}
 			
 			log.Debug().
@@ -109,7 +167,7 @@ func (mc *MultiLevelCache) Get(key string) (interface{}, bool) {
 				Str("level", "L2").
 				Msg("Cache hit")
 			return val, true
-		} else {
+		} else if !errors.Is(err, ErrCacheMiss) {
 			mc.mu.Lock()
 			mc.stats.L2Errors++
 			mc.mu.Unlock()
@@ -120,16 +178,17 @@ func (mc *MultiLevelCache) Get(key string) (interface{}, bool) {
 	// Check L3 (disk) if available
 	if mc.l3Cache != nil {
 		if val, err := mc.l3Cache.Get(key); err == nil {
+			val, tags := unwrap(val)
 			mc.mu.Lock()
 			mc.stats.L3Hits++
 			mc.mu.Unlock()
 			
 			// Promote to L1 and L2 if enabled
-			if mc.strategy.PromoteToL1 {
-				mc.l1Cache.Set(key, val, mc.strategy.L1TTL)
+			if strategy.PromoteToL1 {
+				mc.setL1(key, val, strategy.L1TTL, tags)
 			}
 			if mc.l2Cache != nil {
-				if err := mc.l2Cache.Set(key, val, mc.strategy.L2TTL); err != nil {
+				if err := mc.setL2(key, val, strategy.L2TTL, tags); err != nil {
 					log.Debug().Err(err).Str("key", key).Msg("Failed to promote to L2")
 				}
 			}
@@ -139,7 +198,7 @@ func (mc *MultiLevelCache) Get(key string) (interface{}, bool) {
 				Str("level", "L3").
 				Msg("Cache hit")
 			return val, true
-		} else {
+		} else if !errors.Is(err, ErrCacheMiss) {
 			mc.mu.Lock()
 			mc.stats.L3Errors++
 			mc.mu.Unlock()
@@ -160,24 +219,36 @@ func (mc *MultiLevelCache) Get(key string) (interface{}, bool) {
 
 // Set stores a value in the multi-level cache
 func (mc *MultiLevelCache) Set(key string, value interface{}, ttl time.Duration) error {
+	return mc.SetWithTags(key, value, ttl)
+}
+
+// SetWithTags stores a value together with the dependency tags of the Jira
+// data it was built from, so that InvalidateTags can evict it. A shared L2
+// implementing TagIndexer also records the tags, for processes that never
+// saw the entry.
+func (mc *MultiLevelCache) SetWithTags(key string, value interface{}, ttl time.Duration, tags ...string) error {
 	var errs []error
 
+	strategy := mc.Strategy()
+	tags = uniqueTags(tags)
+	stored := wrap(value, tags)
+
 	// Always store in L1
-	if err := mc.l1Cache.Set(key, value, mc.strategy.L1TTL); err != nil {
+	if err := mc.setL1(key, value, strategy.L1TTL, tags); err != nil {
 		errs = append(errs, fmt.Errorf("L1 set failed: %v", err))
 	}
 
 	// Store in L2 if available and write-through enabled
-	if mc.strategy.WriteThrough && mc.l2Cache != nil {
-		if err := mc.l2Cache.Set(key, value, mc.strategy.L2TTL); err != nil {
-			errs = append(errs, fmt.Errorf("L2 set failed: %v", err))
+	if strategy.WriteThrough && mc.l2Cache != nil {
+		if err := mc.setL2(key, value, strategy.L2TTL, tags); err != nil {
+			errs = append(errs, err)
 			log.Debug().Err(err).Str("key", key).Msg("L2 cache set error")
 		}
 	}
 
 	// Store in L3 if available and write-through enabled
-	if mc.strategy.WriteThrough && mc.l3Cache != nil {
-		if err := mc.l3Cache.Set(key, value, mc.strategy.L3TTL); err != nil {
+	if strategy.WriteThrough && mc.l3Cache != nil {
+		if err := mc.l3Cache.Set(key, stored, strategy.L3TTL); err != nil {
 			errs = append(errs, fmt.Errorf("L3 set failed: %v", err))
 			log.Debug().Err(err).Str("key", key).Msg("L3 cache set error")
 		}
@@ -197,6 +268,8 @@ func (mc *MultiLevelCache) Set(key string, value interface{}, ttl time.Duration)
 func (mc *MultiLevelCache) Delete(key string) error {
 	var errs []error
 
+	mc.indexTags(key, nil)
+
 	// Delete from all levels
 	if err := mc.l1Cache.Delete(key); err != nil {
 		errs = append(errs, fmt.Errorf("L1 delete failed: %v", err))
@@ -243,9 +316,11 @@ func (mc *MultiLevelCache) Clear() error {
 		}
 	}
 
-	// Reset stats
+	// Reset stats and tags
 	mc.mu.Lock()
 	mc.stats = MultiLevelStats{}
+	mc.tagIndex = make(map[string]map[string]struct{})
+	mc.keyTags = make(map[string][]string)
 	mc.mu.Unlock()
 
 	if len(errs) > 0 {
@@ -286,21 +361,125 @@ func (mc *MultiLevelCache) GetDetailedStats() map[string]interface{} {
 			"hitRate":  l1HitRate,
 			"evictions": stats.L1Evictions,
 			"size":     mc.l1Cache.Size(),
+			"backend":  mc.l1Cache.GetStats(),
 		},
 		"l2": map[string]interface{}{
 			"hits":   stats.L2Hits,
 			"errors": stats.L2Errors,
 			"enabled": mc.l2Cache != nil,
+			"backend": backendStats(mc.l2Cache),
 		},
 		"l3": map[string]interface{}{
 			"hits":   stats.L3Hits,
 			"errors": stats.L3Errors,
 			"enabled": mc.l3Cache != nil,
+			"backend": backendStats(mc.l3Cache),
 		},
-		"strategy": mc.strategy,
+		"strategy": mc.Strategy(),
 	}
 }
 
+// backendStats returns the statistics of a cache level, or nil when the
+// level is disabled or keeps none
+func backendStats(b Backend) interface{} {
+	switch c := b.(type) {
+	case *MemoryCache:
+		return c.GetStats()
+	case *DiskCache:
+		return c.GetStats()
+	case *RedisCache:
+		return c.GetStats()
+	}
+	return nil
+}
+
+// levels returns the enabled cache levels by layer name
+func (mc *MultiLevelCache) levels() map[string]Backend {
+	levels := map[string]Backend{LayerL1: mc.l1Cache}
+	if mc.l2Cache != nil {
+		levels[LayerL2] = mc.l2Cache
+	}
+	if mc.l3Cache != nil {
+		levels[LayerL3] = mc.l3Cache
+	}
+	return levels
+}
+
+// Entries returns the live entries of every level whose key starts with
+// prefix. A non-empty tag keeps only the entries known to carry the tag,
+// from the L1 index or the tags a level stores itself. Levels that cannot be
+// listed are skipped.
+func (mc *MultiLevelCache) Entries(prefix, tag string) []EntryInfo {
+	var entries []EntryInfo
+	for _, level := range mc.levels() {
+		if inspector, ok := level.(Inspector); ok {
+			entries = append(entries, inspector.Entries(prefix)...)
+		}
+	}
+
+	mc.mu.RLock()
+	filtered := entries[:0]
+	for _, entry := range entries {
+		tags := entry.Tags
+		if len(tags) == 0 {
+			tags = mc.keyTags[entry.Key]
+		}
+		if tag != "" && !slices.Contains(tags, tag) {
+			continue
+		}
+		entry.Tags = append([]string(nil), tags...)
+		filtered = append(filtered, entry)
+	}
+	mc.mu.RUnlock()
+
+	sortEntries(filtered)
+	return filtered
+}
+
+// Entry returns the live entry of a key in each level holding it, fastest
+// level first
+func (mc *MultiLevelCache) Entry(key string) []EntryInfo {
+	var entries []EntryInfo
+	for _, level := range mc.levels() {
+		if inspector, ok := level.(Inspector); ok {
+			if entry, ok := inspector.Entry(key); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	mc.mu.RLock()
+	tags := mc.keyTags[key]
+	mc.mu.RUnlock()
+	for i := range entries {
+		if len(entries[i].Tags) == 0 {
+			entries[i].Tags = append([]string(nil), tags...)
+		}
+	}
+
+	sortEntries(entries)
+	return entries
+}
+
+// FlushLayer removes every entry from one level, leaving the others alone
+func (mc *MultiLevelCache) FlushLayer(layer string) error {
+	level, ok := mc.levels()[layer]
+	if !ok {
+		switch layer {
+		case LayerL2, LayerL3:
+			return fmt.Errorf("cache layer %s is not enabled", layer)
+		}
+		return fmt.Errorf("unknown cache layer %q", layer)
+	}
+
+	if err := level.Clear(); err != nil {
+		return fmt.Errorf("%s clear failed: %v", layer, err)
+	}
+
+	log.Info().Str("layer", layer).Msg("Cache layer flushed")
+	return nil
+}
+
 // Invalidate removes entries matching a pattern from all levels
 func (mc *MultiLevelCache) Invalidate(pattern string) int {
 	count := 0
@@ -324,6 +503,132 @@ func (mc *MultiLevelCache) Invalidate(pattern string) int {
 	return count
 }
 
+// InvalidateTags evicts the entries carrying any of the tags from every level.
+// Entries in L1 are deleted key by key from every level; L2 and L3 backends
+// implementing Invalidator also evict the tagged entries L1 no longer holds,
+// including those written by other processes or before a restart.
+func (mc *MultiLevelCache) InvalidateTags(tags ...string) int {
+	mc.mu.Lock()
+	keys := make(map[string]struct{})
+	for _, tag := range tags {
+		for key := range mc.tagIndex[tag] {
+			keys[key] = struct{}{}
+		}
+	}
+	mc.mu.Unlock()
+
+	count := 0
+	for key := range keys {
+		mc.indexTags(key, nil)
+
+		if err := mc.l1Cache.Delete(key); err != nil {
+			log.Debug().Err(err).Str("key", key).Msg("L1 cache delete error")
+		}
+		if mc.l2Cache != nil {
+			if err := mc.l2Cache.Delete(key); err != nil {
+				log.Debug().Err(err).Str("key", key).Msg("L2 cache delete error")
+			}
+		}
+		if mc.l3Cache != nil {
+			if err := mc.l3Cache.Delete(key); err != nil {
+				log.Debug().Err(err).Str("key", key).Msg("L3 cache delete error")
+			}
+		}
+		count++
+	}
+
+	if invalidator, ok := mc.l2Cache.(Invalidator); ok {
+		count += invalidator.InvalidateTags(tags...)
+	}
+	if invalidator, ok := mc.l3Cache.(Invalidator); ok {
+		count += invalidator.InvalidateTags(tags...)
+	}
+
+	return count
+}
+
+// TagIndexSize returns how many tags and keys the L1 tag index holds
+func (mc *MultiLevelCache) TagIndexSize() (tags, keys int) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return len(mc.tagIndex), len(mc.keyTags)
+}
+
+// setL1 stores a value in L1 and indexes its tags
+func (mc *MultiLevelCache) setL1(key string, value interface{}, ttl time.Duration, tags []string) error {
+	mc.indexTags(key, tags)
+	err := mc.l1Cache.Set(key, value, ttl)
+
+	// The previous entry of the key may have been evicted, and its tags
+	// forgotten, while the new one was being stored
+	if _, ok := mc.l1Cache.Entry(key); ok {
+		mc.indexTags(key, tags)
+	} else {
+		mc.indexTags(key, nil)
+	}
+	return err
+}
+
+// setL2 stores a value with its tags in L2, recording the tags with a
+// backend implementing TagIndexer
+func (mc *MultiLevelCache) setL2(key string, value interface{}, ttl time.Duration, tags []string) error {
+	if err := mc.l2Cache.Set(key, wrap(value, tags), ttl); err != nil {
+		return fmt.Errorf("L2 set failed: %v", err)
+	}
+	if indexer, ok := mc.l2Cache.(TagIndexer); ok && len(tags) > 0 {
+		if err := indexer.TagKey(key, ttl, tags...); err != nil {
+			return fmt.Errorf("L2 tag failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// indexTags replaces the recorded tags of a key; nil tags forget the key
+func (mc *MultiLevelCache) indexTags(key string, tags []string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.tagIndex == nil {
+		mc.tagIndex = make(map[string]map[string]struct{})
+		mc.keyTags = make(map[string][]string)
+	}
+
+	for _, tag := range mc.keyTags[key] {
+		delete(mc.tagIndex[tag], key)
+		if len(mc.tagIndex[tag]) == 0 {
+			delete(mc.tagIndex, tag)
+		}
+	}
+	delete(mc.keyTags, key)
+
+	if len(tags) == 0 {
+		return
+	}
+	mc.keyTags[key] = tags
+	for _, tag := range tags {
+		if mc.tagIndex[tag] == nil {
+			mc.tagIndex[tag] = make(map[string]struct{})
+		}
+		mc.tagIndex[tag][key] = struct{}{}
+	}
+}
+
+// unwrap returns the value and tags of an entry read from L2 or L3
+func unwrap(val interface{}) (interface{}, []string) {
+	if tv, ok := val.(taggedValue); ok {
+		return tv.Value, tv.Tags
+	}
+	return val, nil
+}
+
+// wrap returns the value to store in L2 or L3, with its tags
+func wrap(val interface{}, tags []string) interface{} {
+	if len(tags) == 0 {
+		return val
+	}
+	return taggedValue{Value: val, Tags: tags}
+}
+
 // Stop gracefully shuts down the multi-level cache
 func (mc *MultiLevelCache) Stop() {
 	log.Info().Msg("Shutting down multi-level cache")
@@ -341,13 +646,13 @@ func (mc *MultiLevelCache) Stop() {
 	}
 }
 
-// SetL2Cache sets the Redis cache (L2) implementation
-func (mc *MultiLevelCache) SetL2Cache(cache *RedisCache) {
+// SetL2Cache sets the shared cache (L2), typically a RedisCache; nil disables L2
+func (mc *MultiLevelCache) SetL2Cache(cache Backend) {
 	mc.l2Cache = cache
 }
 
-// SetL3Cache sets the disk cache (L3) implementation
-func (mc *MultiLevelCache) SetL3Cache(cache *DiskCache) {
+// SetL3Cache sets the persistent cache (L3), typically a DiskCache; nil disables L3
+func (mc *MultiLevelCache) SetL3Cache(cache Backend) {
 	mc.l3Cache = cache
 }
 
@@ -358,7 +663,7 @@ func (mc *MultiLevelCache) Warm(keys []string, loader func(key string) (interfac
 		if _, exists := mc.Get(key); !exists {
 			// Load and cache the data
 			if value, err := loader(key); err == nil {
-				if err := mc.Set(key, value, mc.strategy.L1TTL); err != nil {
+				if err := mc.Set(key, value, mc.Strategy().L1TTL); err != nil {
 					log.Warn().
 						Err(err).
 						Str("key", key).
@@ -386,6 +691,7 @@ func DefaultMultiLevelStrategy() CacheStrategy {
 		Compress:    true,
 		PromoteToL1: true,
 		WriteThrough: false, // Write-behind is typically better for performance
+		Name:        "default",
 	}
 }
 
@@ -400,6 +706,7 @@ func AggressiveStrategy() CacheStrategy {
 		Compress:    true,
 		PromoteToL1: true,
 		WriteThrough: true,
+		Name:        "aggressive",
 	}
 }
 
@@ -414,5 +721,6 @@ func ConservativeStrategy() CacheStrategy {
 		Compress:    true,
 		PromoteToL1: false,
 		WriteThrough: false,
+		Name:        "conservative",
 	}
 }
\ No newline at end of file
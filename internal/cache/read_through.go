@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/monitoring"
+	"github.com/rs/zerolog/log"
+)
+
+// LoadFunc loads a value and the dependency tags it was built from
+type LoadFunc func(ctx context.Context) (value interface{}, tags []string, err error)
+
+// ReadThroughStats tracks read-through cache performance
+type ReadThroughStats struct {
+	Hits          int64 `json:"hits"`
+	StaleHits     int64 `json:"staleHits"` // expired entries served while refreshing
+	Misses        int64 `json:"misses"`
+	Coalesced     int64 `json:"coalesced"` // misses that joined a load in flight
+	Refreshes     int64 `json:"refreshes"`
+	RefreshErrors int64 `json:"refreshErrors"`
+	Evictions     int64 `json:"evictions"`
+	Size          int   `json:"size"`
+}
+
+type readThroughEntry struct {
+	value      interface{}
+	tags       []string
+	loadedAt   time.Time
+	refreshing bool
+}
+
+// ReadThroughCache loads missing values once for all concurrent callers and
+// serves stale-while-revalidate: an entry past its TTL is still returned for
+// up to staleTTL while a single background refresh replaces it. Entries
+// evicted by tag are removed outright and never served stale.
+type ReadThroughCache struct {
+	mu         sync.Mutex
+	entries    map[string]*readThroughEntry
+	ttl        time.Duration
+	staleTTL   time.Duration
+	maxEntries int
+	generation uint64 // bumped on invalidation so loads in flight are not stored
+	loads      jira.CallGroup
+	stats      ReadThroughStats
+}
+
+// NewReadThroughCache creates a read-through cache. A maxEntries of zero
+// leaves the cache unbounded.
+func NewReadThroughCache(ttl, staleTTL time.Duration, maxEntries int) *ReadThroughCache {
+	return &ReadThroughCache{
+		entries:    make(map[string]*readThroughEntry),
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns the cached value of key, loading it when missing or too old
+func (c *ReadThroughCache) Get(ctx context.Context, key string, load LoadFunc) (interface{}, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		age := time.Since(entry.loadedAt)
+		if age < c.ttl {
+			c.stats.Hits++
+			c.mu.Unlock()
+			monitoring.GlobalMetrics.IncrementCacheHits()
+			return entry.value, nil
+		}
+		if age < c.ttl+c.staleTTL {
+			c.stats.StaleHits++
+			if !entry.refreshing {
+				entry.refreshing = true
+				go c.refresh(key, entry, load)
+			}
+			c.mu.Unlock()
+			monitoring.GlobalMetrics.IncrementStaleHits()
+			return entry.value, nil
+		}
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+	monitoring.GlobalMetrics.IncrementCacheMisses()
+
+	value, err, shared := c.loads.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.load(ctx, key, load)
+	})
+	if shared {
+		c.mu.Lock()
+		c.stats.Coalesced++
+		c.mu.Unlock()
+	}
+	return value, err
+}
+
+// refresh reloads a stale entry in the background, keeping it on failure
+func (c *ReadThroughCache) refresh(key string, stale *readThroughEntry, load LoadFunc) {
+	// The stale entry stays in place when the refresh fails or its value is
+	// dropped by an invalidation, so it must be free to refresh again
+	defer func() {
+		c.mu.Lock()
+		stale.refreshing = false
+		c.mu.Unlock()
+	}()
+	monitoring.GlobalMetrics.IncrementRefreshes()
+
+	_, err, _ := c.loads.Do(context.Background(), key, func(ctx context.Context) (interface{}, error) {
+		return c.load(ctx, key, load)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Refreshes++
+	if err != nil {
+		c.stats.RefreshErrors++
+		log.Warn().Err(err).Str("key", key).Msg("Failed to refresh stale cache entry")
+	}
+}
+
+// load calls the loader and stores its value, unless the cache was
+// invalidated while the loader ran
+func (c *ReadThroughCache) load(ctx context.Context, key string, load LoadFunc) (interface{}, error) {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, tags, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation == c.generation {
+		c.entries[key] = &readThroughEntry{value: value, tags: tags, loadedAt: time.Now()}
+		c.evictOldest()
+	}
+	return value, nil
+}
+
+// evictOldest keeps the cache within maxEntries
+func (c *ReadThroughCache) evictOldest() {
+	for c.maxEntries > 0 && len(c.entries) > c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if oldestKey == "" || entry.loadedAt.Before(oldest) {
+				oldestKey, oldest = key, entry.loadedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+		c.stats.Evictions++
+	}
+}
+
+// InvalidateTags removes all entries carrying any of the tags
+func (c *ReadThroughCache) InvalidateTags(tags ...string) int {
+	wanted := tagSet(tags)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.loads.ForgetAll()
+
+	count := 0
+	for key, entry := range c.entries {
+		if hasAnyTag(entry.tags, wanted) {
+			delete(c.entries, key)
+			c.stats.Evictions++
+			count++
+		}
+	}
+	return count
+}
+
+// Delete removes an entry
+func (c *ReadThroughCache) Delete(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.loads.ForgetAll()
+
+	if _, ok := c.entries[key]; !ok {
+		return false
+	}
+	delete(c.entries, key)
+	return true
+}
+
+// Clear removes all entries
+func (c *ReadThroughCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.loads.ForgetAll()
+	c.entries = make(map[string]*readThroughEntry)
+}
+
+// GetStats returns the cache statistics
+func (c *ReadThroughCache) GetStats() ReadThroughStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = len(c.entries)
+	return stats
+}
@@ -0,0 +1,585 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RedisOptions configures a RedisCache
+type RedisOptions struct {
+	Addr        string        `json:"addr"` // host:port of a Redis-compatible server
+	Username    string        `json:"username,omitempty"`
+	Password    string        `json:"-"`
+	DB          int           `json:"db"`
+	Prefix      string        `json:"prefix"` // namespace for GoJira keys, "gojira:" by default
+	PoolSize    int           `json:"poolSize"`
+	DialTimeout time.Duration `json:"dialTimeout"`
+	IOTimeout   time.Duration `json:"ioTimeout"`
+	DefaultTTL  time.Duration `json:"defaultTTL"`
+	// Codecs encodes the stored values; DefaultCodecs when nil
+	Codecs *CodecRegistry `json:"-"`
+}
+
+// RedisCache is a shared L2 cache backed by any server speaking the Redis
+// protocol (Redis, Valkey, KeyDB, ...). GoJira replicas pointed at the same
+// server and prefix share cached Jira data. Values are encoded by the codec
+// of their type; values without one are gob-encoded, so their concrete types
+// must be registered with gob.
+type RedisCache struct {
+	opts  RedisOptions
+	pool  *respPool
+	mu    sync.Mutex
+	stats RedisCacheStats
+}
+
+// RedisCacheStats tracks L2 cache performance
+type RedisCacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"`
+	// StaleEvictions counts entries evicted on read for a stale schema
+	StaleEvictions int64 `json:"staleEvictions"`
+}
+
+// redisScanCount is the number of keys requested per SCAN iteration
+const redisScanCount = 500
+
+// Names under the cache prefix used for dependency tags and invalidation events
+const (
+	redisTagPrefix           = "~tag:"
+	redisInvalidationChannel = "invalidate"
+)
+
+// NewRedisCache connects to a Redis-compatible server and verifies the
+// connection with PING
+func NewRedisCache(opts RedisOptions) (*RedisCache, error) {
+	if opts.Addr == "" {
+		return nil, fmt.Errorf("redis address is required")
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "gojira:"
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 10
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 2 * time.Second
+	}
+	if opts.IOTimeout <= 0 {
+		opts.IOTimeout = 2 * time.Second
+	}
+	if opts.DefaultTTL <= 0 {
+		opts.DefaultTTL = 15 * time.Minute
+	}
+
+	rc := &RedisCache{opts: opts}
+	rc.pool = newRESPPool(opts.PoolSize, rc.dial)
+
+	if err := rc.Ping(); err != nil {
+		rc.pool.close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", opts.Addr, err)
+	}
+
+	log.Info().Str("addr", opts.Addr).Str("prefix", opts.Prefix).Msg("Connected to L2 cache")
+	return rc, nil
+}
+
+// dial opens an authenticated connection to the configured database
+func (rc *RedisCache) dial() (*respConn, error) {
+	conn, err := net.DialTimeout("tcp", rc.opts.Addr, rc.opts.DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &respConn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		writer:  bufio.NewWriter(conn),
+		timeout: rc.opts.IOTimeout,
+	}
+
+	if rc.opts.Password != "" {
+		args := []string{"AUTH", rc.opts.Password}
+		if rc.opts.Username != "" {
+			args = []string{"AUTH", rc.opts.Username, rc.opts.Password}
+		}
+		if _, err := c.do(args...); err != nil {
+			c.close()
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	if rc.opts.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(rc.opts.DB)); err != nil {
+			c.close()
+			return nil, fmt.Errorf("failed to select database %d: %w", rc.opts.DB, err)
+		}
+	}
+	return c, nil
+}
+
+// Ping checks that the server is reachable
+func (rc *RedisCache) Ping() error {
+	_, err := rc.pool.do("PING")
+	return err
+}
+
+// Get retrieves a value, returning ErrCacheMiss when the key is absent or expired
+func (rc *RedisCache) Get(key string) (interface{}, error) {
+	reply, err := rc.pool.do("GET", rc.opts.Prefix+key)
+	if err != nil {
+		rc.record(&rc.stats.Errors)
+		return nil, fmt.Errorf("redis GET failed: %w", err)
+	}
+	if reply == nil {
+		rc.record(&rc.stats.Misses)
+		return nil, ErrCacheMiss
+	}
+
+	data, ok := reply.([]byte)
+	if !ok {
+		rc.record(&rc.stats.Errors)
+		return nil, fmt.Errorf("unexpected redis GET reply %T", reply)
+	}
+
+	// Entries of a stale schema are evicted and reported as misses
+	value, err := rc.codecs().Decode(data)
+	if errors.Is(err, ErrStaleEntry) {
+		if _, delErr := rc.pool.do("DEL", rc.opts.Prefix+key); delErr != nil {
+			rc.record(&rc.stats.Errors)
+		}
+		rc.record(&rc.stats.StaleEvictions)
+		rc.record(&rc.stats.Misses)
+		log.Debug().Err(err).Str("key", key).Msg("Evicted stale L2 cache entry")
+		return nil, fmt.Errorf("%w: %v", ErrCacheMiss, err)
+	}
+	if err != nil {
+		rc.record(&rc.stats.Errors)
+		return nil, fmt.Errorf("failed to decode cached value: %w", err)
+	}
+
+	rc.record(&rc.stats.Hits)
+	return value, nil
+}
+
+// Set stores a value; a zero ttl uses the default TTL
+func (rc *RedisCache) Set(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = rc.opts.DefaultTTL
+	}
+
+	data, err := rc.codecs().Encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value for %s: %w", key, err)
+	}
+
+	// Expire in milliseconds, never less than one
+	ms := ttl.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	if _, err := rc.pool.do("SET", rc.opts.Prefix+key, string(data), "PX", strconv.FormatInt(ms, 10)); err != nil {
+		rc.record(&rc.stats.Errors)
+		return fmt.Errorf("redis SET failed: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a key
+func (rc *RedisCache) Delete(key string) error {
+	if _, err := rc.pool.do("DEL", rc.opts.Prefix+key); err != nil {
+		rc.record(&rc.stats.Errors)
+		return fmt.Errorf("redis DEL failed: %w", err)
+	}
+	return nil
+}
+
+// Clear removes every key under the cache prefix. Other data on the server,
+// including other prefixes, is left alone.
+func (rc *RedisCache) Clear() error {
+	_, err := rc.deleteMatching(func(string) bool { return true })
+	return err
+}
+
+// InvalidatePattern removes every key matching the regular expression. Keys
+// are matched without the cache prefix.
+func (rc *RedisCache) InvalidatePattern(pattern string) int {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		log.Error().Err(err).Str("pattern", pattern).Msg("Invalid cache invalidation pattern")
+		return 0
+	}
+
+	count, err := rc.deleteMatching(regex.MatchString)
+	if err != nil {
+		log.Warn().Err(err).Str("pattern", pattern).Msg("L2 cache invalidation incomplete")
+	}
+	return count
+}
+
+// deleteMatching scans the keys under the prefix and deletes those accepted
+// by match, returning how many were deleted
+func (rc *RedisCache) deleteMatching(match func(key string) bool) (int, error) {
+	deleted := 0
+	cursor := "0"
+	for {
+		reply, err := rc.pool.do("SCAN", cursor, "MATCH", escapeRedisGlob(rc.opts.Prefix)+"*", "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			rc.record(&rc.stats.Errors)
+			return deleted, fmt.Errorf("redis SCAN failed: %w", err)
+		}
+
+		items, ok := reply.([]interface{})
+		if !ok || len(items) != 2 {
+			return deleted, fmt.Errorf("unexpected redis SCAN reply %T", reply)
+		}
+		next, _ := items[0].([]byte)
+		keys, _ := items[1].([]interface{})
+
+		args := []string{"DEL"}
+		for _, item := range keys {
+			key, _ := item.([]byte)
+			if match(strings.TrimPrefix(string(key), rc.opts.Prefix)) {
+				args = append(args, string(key))
+			}
+		}
+		if len(args) > 1 {
+			reply, err := rc.pool.do(args...)
+			if err != nil {
+				rc.record(&rc.stats.Errors)
+				return deleted, fmt.Errorf("redis DEL failed: %w", err)
+			}
+			if n, ok := reply.(int64); ok {
+				deleted += int(n)
+			}
+		}
+
+		if cursor = string(next); cursor == "0" || cursor == "" {
+			return deleted, nil
+		}
+	}
+}
+
+// TagKey records dependency tags for a key. Each tag is a server-side set of
+// keys which lives at least as long as the keys it holds.
+func (rc *RedisCache) TagKey(key string, ttl time.Duration, tags ...string) error {
+	if ttl <= 0 {
+		ttl = rc.opts.DefaultTTL
+	}
+	ms := ttl.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+
+	for _, tag := range tags {
+		setKey := rc.opts.Prefix + redisTagPrefix + tag
+		if _, err := rc.pool.do("SADD", setKey, rc.opts.Prefix+key); err != nil {
+			rc.record(&rc.stats.Errors)
+			return fmt.Errorf("redis SADD failed: %w", err)
+		}
+
+		// Only ever extend the lifetime of the set
+		reply, err := rc.pool.do("PTTL", setKey)
+		if err != nil {
+			rc.record(&rc.stats.Errors)
+			return fmt.Errorf("redis PTTL failed: %w", err)
+		}
+		if remaining, _ := reply.(int64); remaining < ms {
+			if _, err := rc.pool.do("PEXPIRE", setKey, strconv.FormatInt(ms, 10)); err != nil {
+				rc.record(&rc.stats.Errors)
+				return fmt.Errorf("redis PEXPIRE failed: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// InvalidateTags deletes every key recorded under any of the tags, whichever
+// process wrote it, and returns how many keys were deleted
+func (rc *RedisCache) InvalidateTags(tags ...string) int {
+	count := 0
+	for _, tag := range tags {
+		setKey := rc.opts.Prefix + redisTagPrefix + tag
+		reply, err := rc.pool.do("SMEMBERS", setKey)
+		if err != nil {
+			rc.record(&rc.stats.Errors)
+			log.Warn().Err(err).Str("tag", tag).Msg("L2 cache tag invalidation failed")
+			continue
+		}
+
+		members, _ := reply.([]interface{})
+		args := []string{"DEL", setKey}
+		for _, member := range members {
+			if key, ok := member.([]byte); ok {
+				args = append(args, string(key))
+			}
+		}
+
+		reply, err = rc.pool.do(args...)
+		if err != nil {
+			rc.record(&rc.stats.Errors)
+			log.Warn().Err(err).Str("tag", tag).Msg("L2 cache tag invalidation failed")
+			continue
+		}
+		// The tag set itself is not counted
+		if n, ok := reply.(int64); ok && n > 0 {
+			count += int(n) - 1
+		}
+	}
+	return count
+}
+
+// Entries returns the keys starting with prefix and their time to live. The
+// server tracks neither sizes nor hit counts per key, and tag sets are left out.
+func (rc *RedisCache) Entries(prefix string) []EntryInfo {
+	var entries []EntryInfo
+	cursor := "0"
+	for {
+		reply, err := rc.pool.do("SCAN", cursor, "MATCH", escapeRedisGlob(rc.opts.Prefix+prefix)+"*", "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			rc.record(&rc.stats.Errors)
+			log.Warn().Err(err).Msg("Failed to list L2 cache keys")
+			break
+		}
+
+		items, ok := reply.([]interface{})
+		if !ok || len(items) != 2 {
+			break
+		}
+		next, _ := items[0].([]byte)
+		keys, _ := items[1].([]interface{})
+
+		for _, item := range keys {
+			key, _ := item.([]byte)
+			name := strings.TrimPrefix(string(key), rc.opts.Prefix)
+			if strings.HasPrefix(name, redisTagPrefix) {
+				continue
+			}
+			if info, ok := rc.Entry(name); ok {
+				entries = append(entries, info)
+			}
+		}
+
+		if cursor = string(next); cursor == "0" || cursor == "" {
+			break
+		}
+	}
+	sortEntries(entries)
+	return entries
+}
+
+// Entry returns the time to live of a key
+func (rc *RedisCache) Entry(key string) (EntryInfo, bool) {
+	reply, err := rc.pool.do("PTTL", rc.opts.Prefix+key)
+	if err != nil {
+		rc.record(&rc.stats.Errors)
+		return EntryInfo{}, false
+	}
+
+	// -2 is returned for missing keys and -1 for keys without expiry
+	ms, _ := reply.(int64)
+	if ms == -2 {
+		return EntryInfo{}, false
+	}
+	var expiration time.Time
+	if ms >= 0 {
+		expiration = time.Now().Add(time.Duration(ms) * time.Millisecond)
+	}
+	return newEntryInfo(key, LayerL2, expiration), true
+}
+
+// PublishInvalidation sends an invalidation event to the other processes
+// subscribed with RelayInvalidations
+func (rc *RedisCache) PublishInvalidation(event InvalidationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode invalidation event: %w", err)
+	}
+	if _, err := rc.pool.do("PUBLISH", rc.opts.Prefix+redisInvalidationChannel, string(payload)); err != nil {
+		rc.record(&rc.stats.Errors)
+		return fmt.Errorf("redis PUBLISH failed: %w", err)
+	}
+	return nil
+}
+
+// RelayInvalidations connects a bus to the other GoJira processes sharing
+// this cache: events published on the bus are sent to them, and their events
+// are delivered to the bus. The subscription reconnects after connection
+// failures until the returned stop function is called.
+func (rc *RedisCache) RelayInvalidations(bus *InvalidationBus) (stop func(), err error) {
+	sub := &redisSubscription{rc: rc, handler: func(event InvalidationEvent) { bus.Receive(event) }}
+	if err := sub.connect(); err != nil {
+		return nil, err
+	}
+	bus.AddRelay(func(event InvalidationEvent) error {
+		if sub.isStopped() {
+			return nil
+		}
+		return rc.PublishInvalidation(event)
+	})
+
+	go sub.run()
+	return sub.stop, nil
+}
+
+// redisSubscription reads invalidation events from a dedicated connection
+type redisSubscription struct {
+	rc      *RedisCache
+	handler func(InvalidationEvent)
+
+	mu      sync.Mutex
+	conn    *respConn
+	stopped bool
+}
+
+// connect opens the connection and subscribes to the invalidation channel
+func (s *redisSubscription) connect() error {
+	conn, err := s.rc.dial()
+	if err != nil {
+		return fmt.Errorf("failed to connect invalidation subscriber: %w", err)
+	}
+	if _, err := conn.do("SUBSCRIBE", s.rc.opts.Prefix+redisInvalidationChannel); err != nil {
+		conn.close()
+		return fmt.Errorf("redis SUBSCRIBE failed: %w", err)
+	}
+	// Messages arrive whenever other processes publish
+	conn.conn.SetDeadline(time.Time{})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		conn.close()
+		return fmt.Errorf("subscription stopped")
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *redisSubscription) run() {
+	backoff := 100 * time.Millisecond
+	for {
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+
+		if conn != nil {
+			err := s.read(conn)
+			conn.close()
+			if s.isStopped() {
+				return
+			}
+			log.Warn().Err(err).Msg("Cache invalidation subscription lost, reconnecting")
+		}
+
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+
+		time.Sleep(backoff)
+		if s.isStopped() {
+			return
+		}
+		if err := s.connect(); err != nil {
+			if backoff < 5*time.Second {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = 100 * time.Millisecond
+	}
+}
+
+// read delivers messages until the connection fails
+func (s *redisSubscription) read(conn *respConn) error {
+	for {
+		reply, err := readRESP(conn.reader)
+		if err != nil {
+			return err
+		}
+
+		// Messages are ["message", channel, payload]
+		items, ok := reply.([]interface{})
+		if !ok || len(items) != 3 {
+			continue
+		}
+		if kind, _ := items[0].([]byte); string(kind) != "message" {
+			continue
+		}
+		payload, _ := items[2].([]byte)
+
+		var event InvalidationEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			log.Warn().Err(err).Msg("Ignoring malformed cache invalidation event")
+			continue
+		}
+		s.handler(event)
+	}
+}
+
+func (s *redisSubscription) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+func (s *redisSubscription) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	if s.conn != nil {
+		s.conn.close()
+	}
+}
+
+// Stop closes the pooled connections
+func (rc *RedisCache) Stop() {
+	rc.pool.close()
+}
+
+// GetStats returns L2 cache performance statistics
+func (rc *RedisCache) GetStats() RedisCacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.stats
+}
+
+// codecs returns the registry values are encoded with
+func (rc *RedisCache) codecs() *CodecRegistry {
+	if rc.opts.Codecs != nil {
+		return rc.opts.Codecs
+	}
+	return DefaultCodecs
+}
+
+// Options returns the options the cache was created with
+func (rc *RedisCache) Options() RedisOptions {
+	return rc.opts
+}
+
+func (rc *RedisCache) record(counter *int64) {
+	rc.mu.Lock()
+	*counter++
+	rc.mu.Unlock()
+}
+
+// escapeRedisGlob escapes the characters that are special in SCAN MATCH patterns
+func escapeRedisGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// respError is an error reply sent by the server. The connection that
+// received it remains usable.
+type respError string
+
+func (e respError) Error() string {
+	return string(e)
+}
+
+// respSendError reports a command that failed before it was fully sent, so
+// the server cannot have run it
+type respSendError struct {
+	err error
+}
+
+func (e respSendError) Error() string {
+	return e.err.Error()
+}
+
+func (e respSendError) Unwrap() error {
+	return e.err
+}
+
+// respReadOnly lists the commands that change nothing on the server, so
+// running them twice is harmless
+var respReadOnly = map[string]bool{
+	"PING": true, "GET": true, "EXISTS": true, "PTTL": true, "TTL": true,
+	"SCAN": true, "SMEMBERS": true,
+}
+
+// respConn is a client connection speaking RESP, the Redis serialization protocol
+type respConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	timeout time.Duration
+}
+
+// do sends a command and reads its reply. Replies are decoded as string
+// (simple strings), int64, []byte (bulk strings), nil, []interface{} or respError.
+func (c *respConn) do(args ...string) (interface{}, error) {
+	if c.timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+
+	c.writer.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		c.writer.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+		c.writer.WriteString(arg)
+		c.writer.WriteString("\r\n")
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, respSendError{err}
+	}
+
+	reply, err := readRESP(c.reader)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := reply.(respError); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+func (c *respConn) close() error {
+	return c.conn.Close()
+}
+
+// readRESP reads one RESP value. Error replies are returned as a respError value.
+func readRESP(r *bufio.Reader) (interface{}, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("malformed RESP reply: empty line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return respError(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed RESP integer %q", line)
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed RESP bulk length %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed RESP array length %q", line)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = readRESP(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unexpected RESP reply type %q", line[0])
+	}
+}
+
+// readRESPLine reads a CRLF-terminated line without the terminator
+func readRESPLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("malformed RESP line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+// respPool keeps idle connections to a RESP server for reuse and bounds the
+// number of connections in use at once to its size
+type respPool struct {
+	dial   func() (*respConn, error)
+	idle   chan *respConn
+	active chan struct{} // holds a token per command in progress
+	mu     sync.Mutex
+	closed bool
+}
+
+func newRESPPool(size int, dial func() (*respConn, error)) *respPool {
+	return &respPool{dial: dial, idle: make(chan *respConn, size), active: make(chan struct{}, size)}
+}
+
+// do runs a command on a pooled connection, waiting while the pool is at its
+// size. Connections that fail with a network or protocol error are discarded
+// rather than returned to the pool. A failure on an idle connection, which
+// the server may have dropped in the meantime, is retried once on a new
+// connection, but only when the command cannot have run: it was not fully
+// sent, or it is read-only. Writes such as INCR or PUBLISH are never applied twice.
+func (p *respPool) do(args ...string) (interface{}, error) {
+	p.active <- struct{}{}
+	defer func() { <-p.active }()
+
+	for attempt := 0; ; attempt++ {
+		conn, reused, err := p.get(attempt == 0)
+		if err != nil {
+			return nil, err
+		}
+
+		reply, err := conn.do(args...)
+		var replyErr respError
+		if err != nil && !errors.As(err, &replyErr) {
+			conn.close()
+			var sendErr respSendError
+			if reused && (errors.As(err, &sendErr) || respReadOnly[strings.ToUpper(args[0])]) {
+				continue
+			}
+			return nil, err
+		}
+		p.put(conn)
+		return reply, err
+	}
+}
+
+// get returns an idle connection when allowed and available, or a new one
+func (p *respPool) get(allowIdle bool) (*respConn, bool, error) {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return nil, false, fmt.Errorf("connection pool is closed")
+	}
+
+	if allowIdle {
+		select {
+		case conn, ok := <-p.idle:
+			if !ok {
+				return nil, false, fmt.Errorf("connection pool is closed")
+			}
+			return conn, true, nil
+		default:
+		}
+	}
+
+	conn, err := p.dial()
+	return conn, false, err
+}
+
+func (p *respPool) put(conn *respConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		conn.close()
+		return
+	}
+	select {
+	case p.idle <- conn:
+	default:
+		conn.close()
+	}
+}
+
+func (p *respPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+	p.closed = true
+	close(p.idle)
+	for conn := range p.idle {
+		conn.close()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -249,6 +250,34 @@ func (rc *ResponseCache) InvalidateTag(tag string) int {
 	return count
 }
 
+// InvalidateTags removes all entries carrying any of the tags
+func (rc *ResponseCache) InvalidateTags(tags ...string) int {
+	wanted := tagSet(tags)
+	
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	
+	count := 0
+	for key, entry := range rc.entries {
+		if hasAnyTag(entry.Metadata.Tags, wanted) {
+			delete(rc.entries, key)
+			rc.stats.Size--
+			rc.stats.MemoryUsed -= int64(entry.Size)
+			rc.stats.Evictions++
+			count++
+		}
+	}
+	
+	if count > 0 {
+		log.Debug().
+			Strs("tags", tags).
+			Int("invalidated", count).
+			Msg("Cache tag invalidation completed")
+	}
+	
+	return count
+}
+
 // Clear removes all entries from the cache
 func (rc *ResponseCache) Clear() {
 	rc.mu.Lock()
@@ -279,6 +308,41 @@ func (rc *ResponseCache) GetStats() CacheStats {
 	return stats
 }
 
+// Entries returns the live entries whose key starts with prefix
+func (rc *ResponseCache) Entries(prefix string) []EntryInfo {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	
+	var entries []EntryInfo
+	for key, entry := range rc.entries {
+		if strings.HasPrefix(key, prefix) && !rc.isExpired(entry) {
+			entries = append(entries, responseEntryInfo(key, entry))
+		}
+	}
+	sortEntries(entries)
+	return entries
+}
+
+// Entry returns the live entry of a key
+func (rc *ResponseCache) Entry(key string) (EntryInfo, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	
+	entry, exists := rc.entries[key]
+	if !exists || rc.isExpired(entry) {
+		return EntryInfo{}, false
+	}
+	return responseEntryInfo(key, entry), true
+}
+
+func responseEntryInfo(key string, entry *CacheEntry) EntryInfo {
+	info := newEntryInfo(key, LayerResponse, entry.Timestamp.Add(entry.TTL))
+	info.Size = int64(entry.Size)
+	info.Hits = int64(entry.AccessCount)
+	info.Tags = append([]string(nil), entry.Metadata.Tags...)
+	return info
+}
+
 // Stop shuts down the cache and cleanup routines
 func (rc *ResponseCache) Stop() {
 	close(rc.stopCh)
@@ -0,0 +1,785 @@
+// Package resptest provides an in-process server speaking the Redis
+// serialization protocol (RESP), for testing code that uses a Redis-compatible
+// cache without running one. It implements the commands GoJira uses.
+package resptest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-memory RESP server listening on a loopback port
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	mu       sync.Mutex
+	dbs      map[int]map[string]*entry
+	password string
+	offset   time.Duration // added to the clock by FastForward
+	commands map[string]int
+	conns    map[net.Conn]struct{}
+	channels map[string]map[*client]struct{} // pub/sub subscribers
+	wg       sync.WaitGroup
+	closed   bool
+
+	latency     time.Duration  // added before every reply by SetLatency
+	dropReplies map[string]int // commands run without a reply by DropReply
+	peakConns   int
+}
+
+type entry struct {
+	value   string
+	set     map[string]struct{} // members when the key holds a set
+	expires time.Time           // zero when the key does not expire
+}
+
+// client is the per-connection state
+type client struct {
+	db            int
+	authenticated bool
+
+	// Replies and pushed pub/sub messages share the connection writer
+	wmu      sync.Mutex
+	messages chan [2]string // channel and payload, created by SUBSCRIBE
+	channels map[string]bool
+}
+
+// NewServer starts a server on a random loopback port. The caller must Close it.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("resptest: failed to listen: %v", err))
+	}
+
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		listener: listener,
+		dbs:      make(map[int]map[string]*entry),
+		commands: make(map[string]int),
+		conns:    make(map[net.Conn]struct{}),
+		channels: make(map[string]map[*client]struct{}),
+
+		dropReplies: make(map[string]int),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// RequirePassword makes the server reject commands until AUTH succeeds
+func (s *Server) RequirePassword(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.password = password
+}
+
+// FastForward advances the server clock, expiring keys whose TTL has passed
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// Keys returns the live keys of a database, sorted
+func (s *Server) Keys(db int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.liveKeysLocked(db)
+}
+
+// Get returns the raw value of a key in a database
+func (s *Server) Get(db int, key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.lookupLocked(db, key)
+	if e == nil {
+		return "", false
+	}
+	return e.value, true
+}
+
+// TTL returns the remaining time to live of a key, or zero when it does not expire
+func (s *Server) TTL(db int, key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.lookupLocked(db, key)
+	if e == nil || e.expires.IsZero() {
+		return 0
+	}
+	return e.expires.Sub(s.nowLocked())
+}
+
+// CommandCount returns how many times a command has been received
+func (s *Server) CommandCount(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commands[strings.ToUpper(name)]
+}
+
+// SetLatency delays the reply to every command by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// DropReply makes the server run the next command of that name and then
+// close the connection without replying, as if it failed mid-command
+func (s *Server) DropReply(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropReplies[strings.ToUpper(name)]++
+}
+
+// PeakConnections returns the largest number of connections open at once
+func (s *Server) PeakConnections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peakConns
+}
+
+// CloseClientConnections drops every open connection, simulating a server restart
+func (s *Server) CloseClientConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Close stops the server and closes every connection
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.peakConns = max(s.peakConns, len(s.conns))
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	state := &client{}
+	defer s.unsubscribeAll(state)
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			if err != io.EOF {
+				state.wmu.Lock()
+				writeError(writer, "ERR Protocol error: "+err.Error())
+				writer.Flush()
+				state.wmu.Unlock()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		name := strings.ToUpper(args[0])
+		drop := s.dropReplies[name] > 0
+		if drop {
+			s.dropReplies[name]--
+		}
+		latency := s.latency
+		s.mu.Unlock()
+
+		if drop {
+			s.execute(bufio.NewWriter(io.Discard), state, args)
+			return
+		}
+		time.Sleep(latency)
+
+		state.wmu.Lock()
+		quit := s.execute(writer, state, args)
+		err = writer.Flush()
+		state.wmu.Unlock()
+		if err != nil || quit {
+			return
+		}
+	}
+}
+
+// execute runs one command and writes its reply; it reports whether the
+// connection should be closed
+func (s *Server) execute(w *bufio.Writer, c *client, args []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.ToUpper(args[0])
+	s.commands[name]++
+
+	if s.password != "" && !c.authenticated && name != "AUTH" && name != "QUIT" {
+		writeError(w, "NOAUTH Authentication required.")
+		return false
+	}
+
+	switch name {
+	case "PING":
+		if len(args) > 1 {
+			writeBulk(w, args[1])
+		} else {
+			writeSimple(w, "PONG")
+		}
+	case "QUIT":
+		writeSimple(w, "OK")
+		return true
+	case "AUTH":
+		password := ""
+		switch len(args) {
+		case 2:
+			password = args[1]
+		case 3:
+			password = args[2]
+		default:
+			writeArity(w, name)
+			return false
+		}
+		if s.password == "" || password != s.password {
+			writeError(w, "WRONGPASS invalid username-password pair or user is disabled.")
+			return false
+		}
+		c.authenticated = true
+		writeSimple(w, "OK")
+	case "SELECT":
+		if len(args) != 2 {
+			writeArity(w, name)
+			return false
+		}
+		db, err := strconv.Atoi(args[1])
+		if err != nil || db < 0 || db > 15 {
+			writeError(w, "ERR DB index is out of range")
+			return false
+		}
+		c.db = db
+		writeSimple(w, "OK")
+	case "GET":
+		if len(args) != 2 {
+			writeArity(w, name)
+			return false
+		}
+		if e := s.lookupLocked(c.db, args[1]); e != nil && e.set != nil {
+			writeWrongType(w)
+		} else if e != nil {
+			writeBulk(w, e.value)
+		} else {
+			writeNil(w)
+		}
+	case "SET":
+		s.set(w, c, args)
+	case "DEL":
+		if len(args) < 2 {
+			writeArity(w, name)
+			return false
+		}
+		deleted := 0
+		for _, key := range args[1:] {
+			if s.lookupLocked(c.db, key) != nil {
+				delete(s.dbs[c.db], key)
+				deleted++
+			}
+		}
+		writeInt(w, int64(deleted))
+	case "EXISTS":
+		if len(args) < 2 {
+			writeArity(w, name)
+			return false
+		}
+		count := 0
+		for _, key := range args[1:] {
+			if s.lookupLocked(c.db, key) != nil {
+				count++
+			}
+		}
+		writeInt(w, int64(count))
+	case "PTTL", "TTL":
+		if len(args) != 2 {
+			writeArity(w, name)
+			return false
+		}
+		e := s.lookupLocked(c.db, args[1])
+		switch {
+		case e == nil:
+			writeInt(w, -2)
+		case e.expires.IsZero():
+			writeInt(w, -1)
+		case name == "PTTL":
+			writeInt(w, e.expires.Sub(s.nowLocked()).Milliseconds())
+		default:
+			writeInt(w, int64(e.expires.Sub(s.nowLocked())/time.Second))
+		}
+	case "KEYS":
+		if len(args) != 2 {
+			writeArity(w, name)
+			return false
+		}
+		pattern, err := compileGlob(args[1])
+		if err != nil {
+			writeError(w, "ERR invalid pattern")
+			return false
+		}
+		var keys []string
+		for _, key := range s.liveKeysLocked(c.db) {
+			if pattern.MatchString(key) {
+				keys = append(keys, key)
+			}
+		}
+		writeStrings(w, keys)
+	case "SCAN":
+		s.scan(w, c, args)
+	case "SADD":
+		if len(args) < 3 {
+			writeArity(w, name)
+			return false
+		}
+		e := s.lookupLocked(c.db, args[1])
+		if e != nil && e.set == nil {
+			writeWrongType(w)
+			return false
+		}
+		if e == nil {
+			e = &entry{set: make(map[string]struct{})}
+			if s.dbs[c.db] == nil {
+				s.dbs[c.db] = make(map[string]*entry)
+			}
+			s.dbs[c.db][args[1]] = e
+		}
+		added := 0
+		for _, member := range args[2:] {
+			if _, ok := e.set[member]; !ok {
+				e.set[member] = struct{}{}
+				added++
+			}
+		}
+		writeInt(w, int64(added))
+	case "SMEMBERS":
+		if len(args) != 2 {
+			writeArity(w, name)
+			return false
+		}
+		e := s.lookupLocked(c.db, args[1])
+		if e != nil && e.set == nil {
+			writeWrongType(w)
+			return false
+		}
+		var members []string
+		if e != nil {
+			for member := range e.set {
+				members = append(members, member)
+			}
+			sort.Strings(members)
+		}
+		writeStrings(w, members)
+	case "PEXPIRE", "EXPIRE":
+		if len(args) != 3 {
+			writeArity(w, name)
+			return false
+		}
+		n, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			writeError(w, "ERR value is not an integer or out of range")
+			return false
+		}
+		e := s.lookupLocked(c.db, args[1])
+		if e == nil {
+			writeInt(w, 0)
+			return false
+		}
+		unit := time.Millisecond
+		if name == "EXPIRE" {
+			unit = time.Second
+		}
+		e.expires = s.nowLocked().Add(time.Duration(n) * unit)
+		writeInt(w, 1)
+	case "PUBLISH":
+		if len(args) != 3 {
+			writeArity(w, name)
+			return false
+		}
+		receivers := 0
+		for subscriber := range s.channels[args[1]] {
+			select {
+			case subscriber.messages <- [2]string{args[1], args[2]}:
+				receivers++
+			default:
+			}
+		}
+		writeInt(w, int64(receivers))
+	case "SUBSCRIBE":
+		if len(args) < 2 {
+			writeArity(w, name)
+			return false
+		}
+		s.subscribeLocked(w, c, args[1:])
+	case "FLUSHDB":
+		delete(s.dbs, c.db)
+		writeSimple(w, "OK")
+	case "FLUSHALL":
+		s.dbs = make(map[int]map[string]*entry)
+		writeSimple(w, "OK")
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+	return false
+}
+
+// subscribeLocked implements SUBSCRIBE channel [channel ...]. Once
+// subscribed, published messages are pushed to the connection.
+func (s *Server) subscribeLocked(w *bufio.Writer, c *client, channels []string) {
+	if c.messages == nil {
+		c.messages = make(chan [2]string, 1024)
+		c.channels = make(map[string]bool)
+		s.wg.Add(1)
+		go s.push(w, c)
+	}
+
+	for _, channel := range channels {
+		if !c.channels[channel] {
+			c.channels[channel] = true
+			if s.channels[channel] == nil {
+				s.channels[channel] = make(map[*client]struct{})
+			}
+			s.channels[channel][c] = struct{}{}
+		}
+		w.WriteString("*3\r\n")
+		writeBulk(w, "subscribe")
+		writeBulk(w, channel)
+		writeInt(w, int64(len(c.channels)))
+	}
+}
+
+// push writes published messages to a subscribed connection
+func (s *Server) push(w *bufio.Writer, c *client) {
+	defer s.wg.Done()
+	for message := range c.messages {
+		c.wmu.Lock()
+		w.WriteString("*3\r\n")
+		writeBulk(w, "message")
+		writeBulk(w, message[0])
+		writeBulk(w, message[1])
+		w.Flush()
+		c.wmu.Unlock()
+	}
+}
+
+// unsubscribeAll removes a closing connection from every channel
+func (s *Server) unsubscribeAll(c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.messages == nil {
+		return
+	}
+	for channel := range c.channels {
+		delete(s.channels[channel], c)
+		if len(s.channels[channel]) == 0 {
+			delete(s.channels, channel)
+		}
+	}
+	close(c.messages)
+}
+
+// set implements SET key value [EX seconds | PX milliseconds] [NX | XX]
+func (s *Server) set(w *bufio.Writer, c *client, args []string) {
+	if len(args) < 3 {
+		writeArity(w, "SET")
+		return
+	}
+
+	var ttl time.Duration
+	nx, xx := false, false
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				writeError(w, "ERR syntax error")
+				return
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				writeError(w, "ERR invalid expire time in 'set' command")
+				return
+			}
+			if strings.ToUpper(args[i]) == "EX" {
+				ttl = time.Duration(n) * time.Second
+			} else {
+				ttl = time.Duration(n) * time.Millisecond
+			}
+			i++
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		default:
+			writeError(w, "ERR syntax error")
+			return
+		}
+	}
+
+	exists := s.lookupLocked(c.db, args[1]) != nil
+	if (nx && exists) || (xx && !exists) {
+		writeNil(w)
+		return
+	}
+
+	e := &entry{value: args[2]}
+	if ttl > 0 {
+		e.expires = s.nowLocked().Add(ttl)
+	}
+	if s.dbs[c.db] == nil {
+		s.dbs[c.db] = make(map[string]*entry)
+	}
+	s.dbs[c.db][args[1]] = e
+	writeSimple(w, "OK")
+}
+
+// scan implements SCAN cursor [MATCH pattern] [COUNT count]. The cursor is an
+// offset into the sorted key space, which is enough for a test stand-in.
+func (s *Server) scan(w *bufio.Writer, c *client, args []string) {
+	if len(args) < 2 {
+		writeArity(w, "SCAN")
+		return
+	}
+	cursor, err := strconv.Atoi(args[1])
+	if err != nil || cursor < 0 {
+		writeError(w, "ERR invalid cursor")
+		return
+	}
+
+	count := 10
+	var pattern *regexp.Regexp
+	for i := 2; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			if pattern, err = compileGlob(args[i+1]); err != nil {
+				writeError(w, "ERR invalid pattern")
+				return
+			}
+		case "COUNT":
+			if count, err = strconv.Atoi(args[i+1]); err != nil || count < 1 {
+				writeError(w, "ERR syntax error")
+				return
+			}
+		default:
+			writeError(w, "ERR syntax error")
+			return
+		}
+	}
+
+	keys := s.liveKeysLocked(c.db)
+	end := cursor + count
+	next := end
+	if end >= len(keys) {
+		end = len(keys)
+		next = 0
+	}
+
+	var matched []string
+	for i := cursor; i < end; i++ {
+		if pattern == nil || pattern.MatchString(keys[i]) {
+			matched = append(matched, keys[i])
+		}
+	}
+
+	w.WriteString("*2\r\n")
+	writeBulk(w, strconv.Itoa(next))
+	writeStrings(w, matched)
+}
+
+func (s *Server) nowLocked() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// lookupLocked returns a live entry, deleting it when it has expired
+func (s *Server) lookupLocked(db int, key string) *entry {
+	e, ok := s.dbs[db][key]
+	if !ok {
+		return nil
+	}
+	if !e.expires.IsZero() && !s.nowLocked().Before(e.expires) {
+		delete(s.dbs[db], key)
+		return nil
+	}
+	return e
+}
+
+func (s *Server) liveKeysLocked(db int) []string {
+	keys := make([]string, 0, len(s.dbs[db]))
+	for key := range s.dbs[db] {
+		if s.lookupLocked(db, key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// compileGlob converts a Redis glob pattern (*, ?, [...] and \ escapes) to a regexp
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch ch := glob[i]; ch {
+		case '*':
+			b.WriteString("(?s:.*)")
+		case '?':
+			b.WriteString("(?s:.)")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings, or as an
+// inline command
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, nil
+	}
+	if line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid multibulk length")
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if header == "" || header[0] != '$' {
+			return nil, fmt.Errorf("expected '$', got '%s'", header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid bulk length")
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeSimple(w *bufio.Writer, s string) {
+	w.WriteString("+" + s + "\r\n")
+}
+
+func writeError(w *bufio.Writer, s string) {
+	w.WriteString("-" + s + "\r\n")
+}
+
+func writeWrongType(w *bufio.Writer) {
+	writeError(w, "WRONGTYPE Operation against a key holding the wrong kind of value")
+}
+
+func writeArity(w *bufio.Writer, name string) {
+	writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
+
+func writeInt(w *bufio.Writer, n int64) {
+	w.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+func writeBulk(w *bufio.Writer, s string) {
+	w.WriteString("$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n")
+}
+
+func writeNil(w *bufio.Writer) {
+	w.WriteString("$-1\r\n")
+}
+
+func writeStrings(w *bufio.Writer, items []string) {
+	w.WriteString("*" + strconv.Itoa(len(items)) + "\r\n")
+	for _, item := range items {
+		writeBulk(w, item)
+	}
+}
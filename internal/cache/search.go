@@ -17,6 +17,7 @@ type SearchCacheEntry struct {
 	Timestamp time.Time                  `json:"timestamp"`
 	JQL       string                     `json:"jql"`
 	Params    map[string]string          `json:"params"`
+	Tags      []string                   `json:"tags"`
 }
 
 // SearchCache manages cached search results
@@ -65,7 +66,8 @@ func (sc *SearchCache) Get(jql string, params map[string]string) (*jira.Extended
 	return entry.Result, true
 }
 
-// Set stores a search result in the cache
+// Set stores a search result in the cache, tagged with the issues it contains
+// and the projects its JQL is restricted to (see SearchTags)
 func (sc *SearchCache) Set(jql string, params map[string]string, result *jira.ExtendedSearchResult) {
 	sc.mutex.Lock()
 	defer sc.mutex.Unlock()
@@ -75,13 +77,36 @@ func (sc *SearchCache) Set(jql string, params map[string]string, result *jira.Ex
 		sc.evictOldest()
 	}
 
+	var issues []jira.Issue
+	if result != nil {
+		issues = result.Issues
+	}
+
 	key := sc.generateKey(jql, params)
 	sc.entries[key] = &SearchCacheEntry{
 		Result:    result,
 		Timestamp: time.Now(),
 		JQL:       jql,
 		Params:    params,
+		Tags:      SearchTags(jql, issues),
+	}
+}
+
+// InvalidateTags removes the entries carrying any of the tags
+func (sc *SearchCache) InvalidateTags(tags ...string) int {
+	wanted := tagSet(tags)
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	count := 0
+	for key, entry := range sc.entries {
+		if hasAnyTag(entry.Tags, wanted) {
+			delete(sc.entries, key)
+			count++
+		}
 	}
+	return count
 }
 
 // evictOldest removes the oldest cache entry
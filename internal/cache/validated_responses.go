@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// ValidatedResponseCache keeps Jira responses with their ETag and
+// Last-Modified validators, so the Jira client can revalidate them with a
+// conditional request instead of downloading them again. Entries carry the
+// dependency tags of the endpoint they were read from: they expire after the
+// TTL and are evicted by Jira writes like any other cached Jira data.
+type ValidatedResponseCache struct {
+	responses *ResponseCache
+}
+
+var _ jira.ResponseStore = (*ValidatedResponseCache)(nil)
+
+// NewValidatedResponseCache creates a validated response cache holding up to
+// maxSize responses for ttl each
+func NewValidatedResponseCache(maxSize int, ttl time.Duration) *ValidatedResponseCache {
+	return &ValidatedResponseCache{responses: NewResponseCache(maxSize, ttl)}
+}
+
+// Response returns the stored response of an endpoint
+func (c *ValidatedResponseCache) Response(endpoint string) (*jira.ValidatedResponse, bool) {
+	value, found := c.responses.Get(endpoint)
+	if !found {
+		return nil, false
+	}
+	resp, ok := value.(*jira.ValidatedResponse)
+	return resp, ok
+}
+
+// StoreResponse stores the response of an endpoint, tagged with the Jira
+// data it was read from
+func (c *ValidatedResponseCache) StoreResponse(endpoint string, resp *jira.ValidatedResponse) {
+	c.responses.Set(endpoint, resp, WithTags(EndpointTags(endpoint)...))
+}
+
+// DeleteResponse removes the stored response of an endpoint
+func (c *ValidatedResponseCache) DeleteResponse(endpoint string) {
+	c.responses.Delete(endpoint)
+}
+
+// Len returns the number of live stored responses
+func (c *ValidatedResponseCache) Len() int {
+	return len(c.responses.Entries(""))
+}
+
+// InvalidateTags evicts the responses changed by a Jira write
+func (c *ValidatedResponseCache) InvalidateTags(tags ...string) int {
+	return c.responses.InvalidateTags(tags...)
+}
+
+// Responses returns the underlying response cache, for the admin registry
+func (c *ValidatedResponseCache) Responses() *ResponseCache {
+	return c.responses
+}
+
+// Stop shuts down the cleanup routine
+func (c *ValidatedResponseCache) Stop() {
+	c.responses.Stop()
+}
+
+// EndpointTags returns the dependency tags of a Jira REST response. Single
+// issues and boards are tagged precisely; searches are tagged like cached
+// searches, and lists of issues, which any issue write may change, carry
+// TagAnyIssue as well.
+func EndpointTags(endpoint string) []string {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	// rest/api/2/<resource>/... and rest/agile/1.0/<resource>/...
+	if len(parts) < 4 || parts[0] != "rest" {
+		return []string{TagAllJiraData, TagAnyIssue}
+	}
+	resource, rest := parts[3], parts[4:]
+
+	switch resource {
+	case "issue":
+		if len(rest) == 1 {
+			if _, err := strconv.Atoi(rest[0]); err != nil {
+				return IssueTags([]jira.Issue{{Key: rest[0]}})
+			}
+		}
+	case "search":
+		query, _ := url.ParseQuery(rawQuery)
+		return SearchTags(query.Get("jql"), nil)
+	case "board":
+		if len(rest) > 0 {
+			if id, err := strconv.Atoi(rest[0]); err == nil {
+				if len(rest) == 1 || rest[1] == "configuration" {
+					return []string{TagAllJiraData, BoardTag(id)}
+				}
+				return []string{TagAllJiraData, TagAnyIssue, BoardTag(id)}
+			}
+		}
+	case "sprint":
+		if len(rest) > 0 {
+			if id, err := strconv.Atoi(rest[0]); err == nil {
+				return []string{TagAllJiraData, TagAnyIssue, SprintTag(id)}
+			}
+		}
+	}
+	return []string{TagAllJiraData, TagAnyIssue}
+}
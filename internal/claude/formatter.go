@@ -45,10 +45,11 @@ type ClaudeResponse struct {
 }
 
 type ResponseMetadata struct {
-	Timestamp    time.Time `json:"timestamp"`
-	Duration     string    `json:"duration,omitempty"`
-	ResultCount  int       `json:"resultCount,omitempty"`
-	JiraInstance string    `json:"jiraInstance,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
+	Duration     string     `json:"duration,omitempty"`
+	ResultCount  int        `json:"resultCount,omitempty"`
+	JiraInstance string     `json:"jiraInstance,omitempty"`
+	StaleAsOf    *time.Time `json:"staleAsOf,omitempty"` // set when answered from the local mirror
 }
 
 func NewResponseFormatter(config FormatterConfig) *ResponseFormatter {
@@ -549,6 +550,25 @@ func (rf *ResponseFormatter) FormatSearchResponse(result *jira.ExtendedSearchRes
 	return response
 }
 
+// MarkStale marks a response built from the local mirror while Jira was
+// unreachable, so the reader knows how old the data may be
+func (rf *ResponseFormatter) MarkStale(response *ClaudeResponse, asOf *time.Time) {
+	marker := "⚠️ Jira is unreachable; answered from the local mirror"
+	if asOf != nil {
+		marker += fmt.Sprintf(", stale as of %s", asOf.Format("2006-01-02 15:04 MST"))
+	}
+	response.Summary = marker + "\n\n" + response.Summary
+
+	if response.Context == nil {
+		response.Context = make(map[string]interface{})
+	}
+	response.Context["offline"] = true
+	response.Metadata.StaleAsOf = asOf
+	if response.Metadata.Timestamp.IsZero() {
+		response.Metadata.Timestamp = time.Now()
+	}
+}
+
 func (rf *ResponseFormatter) formatIssueDetails(issue *jira.Issue) interface{} {
 	details := map[string]interface{}{
 		"key":     issue.Key,
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ericfisherdev/GoJira/internal/cache"
 	"github.com/ericfisherdev/GoJira/internal/nlp"
+	"github.com/ericfisherdev/GoJira/internal/webhook"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,6 +19,8 @@ type IntegrationManager struct {
 	nlpParser         *nlp.Parser
 	config            *ClaudeConfig
 	responseCache     *cache.ResponseCache
+	unregisterCache   func()
+	unsubscribeWebhooks []func()
 	formatter         *ResponseFormatter
 	summarizer        *Summarizer
 }
@@ -89,10 +92,25 @@ func NewIntegrationManager(nlpParser *nlp.Parser, config *ClaudeConfig) *Integra
 	workflowEngine := NewWorkflowEngine(sessionManager, patternManager)
 	suggestionEngine := NewSuggestionEngine(patternManager, sessionManager)
 
+	// Changes made in Jira directly reach sessions and suggestions by webhook
+	unsubscribeWebhooks := []func(){
+		webhook.GlobalBus.Subscribe(sessionManager.HandleJiraEvent),
+		webhook.GlobalBus.Subscribe(suggestionEngine.HandleJiraEvent, webhook.CategoryIssue, webhook.CategoryComment),
+	}
+
 	// Initialize response optimization components
 	var responseCache *cache.ResponseCache
+	var unregisterCache func()
 	if config.EnableResponseCache {
 		responseCache = cache.NewResponseCache(config.ResponseCacheSize, config.ResponseCacheTTL)
+		// Jira writes evict the responses built from the data they change,
+		// and the admin API inspects and evicts its entries
+		unregisterBus := cache.GlobalInvalidationBus.Register(responseCache)
+		unregisterAdmin := cache.GlobalAdmin.RegisterResponseCache(responseCache)
+		unregisterCache = func() {
+			unregisterBus()
+			unregisterAdmin()
+		}
 	}
 
 	// Configure formatter for Claude Code optimization
@@ -117,6 +135,8 @@ func NewIntegrationManager(nlpParser *nlp.Parser, config *ClaudeConfig) *Integra
 		nlpParser:        nlpParser,
 		config:           config,
 		responseCache:    responseCache,
+		unregisterCache:  unregisterCache,
+		unsubscribeWebhooks: unsubscribeWebhooks,
 		formatter:        formatter,
 		summarizer:       summarizer,
 	}
@@ -588,6 +608,12 @@ func (m *IntegrationManager) GetStats() map[string]interface{} {
 func (m *IntegrationManager) Shutdown() {
 	log.Info().Msg("Shutting down Claude Code integration manager")
 	m.sessionManager.Stop()
+	if m.unregisterCache != nil {
+		m.unregisterCache()
+	}
+	for _, unsubscribe := range m.unsubscribeWebhooks {
+		unsubscribe()
+	}
 	if m.responseCache != nil {
 		m.responseCache.Stop()
 	}
@@ -630,6 +656,12 @@ func (m *IntegrationManager) optimizeResponse(response *IntegrationResponse, for
 		if response.Intent != nil {
 			tags = append(tags, string(response.Intent.Type))
 		}
+		// Dependency tags of the Jira data in the response
+		if response.Command != nil {
+			tags = append(tags, cache.DependencyTags(response.Command.Data)...)
+		} else {
+			tags = append(tags, cache.TagAnyIssue)
+		}
 		
 		m.responseCache.Set(cacheKey, response, 
 			cache.WithFormat(string(format)),
@@ -12,6 +12,7 @@ type ClaudeManager struct {
 	formatter     *ResponseFormatter
 	processor     *CommandProcessor
 	searchCache   *cache.SearchCache
+	unregisterSearchCache func()
 	mutex         sync.RWMutex
 	initialized   bool
 }
@@ -50,8 +51,10 @@ func (cm *ClaudeManager) Initialize() error {
 	// Initialize command processor
 	cm.processor = NewCommandProcessor()
 
-	// Initialize search cache (5 minute TTL, max 1000 entries)
+	// Initialize search cache (5 minute TTL, max 1000 entries); Jira writes
+	// evict the searches they affect
 	cm.searchCache = cache.NewSearchCache(5*time.Minute, 1000)
+	cm.unregisterSearchCache = cache.GlobalInvalidationBus.Register(cm.searchCache)
 
 	cm.initialized = true
 	return nil
@@ -102,6 +105,10 @@ func (cm *ClaudeManager) Shutdown() error {
 	if cm.searchCache != nil {
 		cm.searchCache.Clear()
 	}
+	if cm.unregisterSearchCache != nil {
+		cm.unregisterSearchCache()
+		cm.unregisterSearchCache = nil
+	}
 
 	cm.initialized = false
 	return nil
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/ericfisherdev/GoJira/internal/jql"
 )
 
 type CommandProcessor struct {
@@ -172,66 +174,71 @@ func (cp *CommandProcessor) calculateConfidence(action string, matches []string)
 func (cp *CommandProcessor) GenerateJQLFromNaturalLanguage(input string) (string, error) {
 	input = strings.ToLower(strings.TrimSpace(input))
 
-	var jqlParts []string
+	var conditions []jql.Condition
 
 	// Status patterns
 	if strings.Contains(input, "open") || strings.Contains(input, "to do") {
-		jqlParts = append(jqlParts, `status = "To Do"`)
+		conditions = append(conditions, jql.Field("status").Eq("To Do"))
 	} else if strings.Contains(input, "in progress") || strings.Contains(input, "working on") {
-		jqlParts = append(jqlParts, `status = "In Progress"`)
+		conditions = append(conditions, jql.Field("status").Eq("In Progress"))
 	} else if strings.Contains(input, "done") || strings.Contains(input, "completed") {
-		jqlParts = append(jqlParts, `status = Done`)
+		conditions = append(conditions, jql.Field("status").Eq("Done"))
 	}
 
 	// Priority patterns
 	if strings.Contains(input, "high priority") || strings.Contains(input, "urgent") {
-		jqlParts = append(jqlParts, `priority = High`)
+		conditions = append(conditions, jql.Field("priority").Eq("High"))
 	} else if strings.Contains(input, "low priority") {
-		jqlParts = append(jqlParts, `priority = Low`)
+		conditions = append(conditions, jql.Field("priority").Eq("Low"))
 	}
 
 	// Assignee patterns
 	if strings.Contains(input, "unassigned") {
-		jqlParts = append(jqlParts, `assignee is EMPTY`)
+		conditions = append(conditions, jql.Field("assignee").IsEmpty())
 	} else if strings.Contains(input, "assigned to me") || strings.Contains(input, "my issues") {
-		jqlParts = append(jqlParts, `assignee = currentUser()`)
+		conditions = append(conditions, jql.Field("assignee").Eq(jql.CurrentUser()))
 	}
 
 	// Issue type patterns
 	if strings.Contains(input, "bugs") {
-		jqlParts = append(jqlParts, `issuetype = Bug`)
+		conditions = append(conditions, jql.Field("issuetype").Eq("Bug"))
 	} else if strings.Contains(input, "tasks") {
-		jqlParts = append(jqlParts, `issuetype = Task`)
+		conditions = append(conditions, jql.Field("issuetype").Eq("Task"))
 	} else if strings.Contains(input, "stories") {
-		jqlParts = append(jqlParts, `issuetype = Story`)
+		conditions = append(conditions, jql.Field("issuetype").Eq("Story"))
 	}
 
 	// Time patterns
 	if strings.Contains(input, "created today") {
-		jqlParts = append(jqlParts, `created >= -1d`)
+		conditions = append(conditions, jql.Field("created").Gte("-1d"))
 	} else if strings.Contains(input, "created this week") {
-		jqlParts = append(jqlParts, `created >= -7d`)
+		conditions = append(conditions, jql.Field("created").Gte("-7d"))
 	} else if strings.Contains(input, "updated today") {
-		jqlParts = append(jqlParts, `updated >= -1d`)
+		conditions = append(conditions, jql.Field("updated").Gte("-1d"))
+	}
+
+	// Sprint patterns
+	if strings.Contains(input, "current sprint") || strings.Contains(input, "active sprint") {
+		conditions = append(conditions, jql.Field("sprint").In(jql.OpenSprints()))
 	}
 
 	// Project patterns
 	projectPattern := regexp.MustCompile(`(?:project\s+|in\s+)([A-Z]{2,10})`)
 	if projectMatch := projectPattern.FindStringSubmatch(input); len(projectMatch) > 1 {
-		jqlParts = append(jqlParts, fmt.Sprintf(`project = %s`, projectMatch[1]))
+		conditions = append(conditions, jql.Field("project").Eq(projectMatch[1]))
 	}
 
 	// Text search patterns
 	textPattern := regexp.MustCompile(`containing\s+"([^"]+)"`)
 	if textMatch := textPattern.FindStringSubmatch(input); len(textMatch) > 1 {
-		jqlParts = append(jqlParts, fmt.Sprintf(`text ~ "%s"`, textMatch[1]))
+		conditions = append(conditions, jql.Field("text").Contains(textMatch[1]))
 	}
 
-	if len(jqlParts) == 0 {
+	if len(conditions) == 0 {
 		return "", fmt.Errorf("unable to generate JQL from input: %s", input)
 	}
 
-	return strings.Join(jqlParts, " AND "), nil
+	return jql.And(conditions...).String(), nil
 }
 
 // SuggestCommands provides command suggestions based on partial input
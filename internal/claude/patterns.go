@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ericfisherdev/GoJira/internal/nlp"
+	"github.com/ericfisherdev/GoJira/internal/textindex"
 	"github.com/rs/zerolog/log"
 )
 
@@ -150,6 +151,19 @@ func InitializePatterns() []CommandPattern {
 			Handler:  handleQuickAssign,
 			Priority: 8,
 		},
+		{
+			Name:        "NotifyWatchers",
+			Description: "Send issue notifications to people, watchers or groups",
+			Category:    "assignment",
+			Examples: []string{
+				"Notify the watchers about PROJ-123: deployment is delayed",
+				"Tell alice and bob about PROJ-42",
+				"Email the reporter on PROJ-7 that the fix is released",
+			},
+			Pattern:  regexp.MustCompile(`(?i)(notify|email|tell|ping)\s+(.+?)\s+(about|on)\s+(\w+-\d+)`),
+			Handler:  handleNotifyWatchers,
+			Priority: 8,
+		},
 		{
 			Name:        "SprintManagement",
 			Description: "Manage sprint operations intelligently",
@@ -390,16 +404,19 @@ func handleCreateBugFromCode(ctx *CommandContext) (*CommandResult, error) {
 		description += fmt.Sprintf(" at line %d", lineNumber)
 	}
 
+	summary := fmt.Sprintf("Security issue in %s", filename)
+	data := map[string]interface{}{
+		"issueType":   issueType,
+		"summary":     summary,
+		"description": description,
+		"priority":    "High",
+		"labels":      []string{"security", "code-review"},
+	}
+
 	result := &CommandResult{
 		Success: true,
 		Message: fmt.Sprintf("Ready to create %s issue for code review finding", strings.ToLower(issueType)),
-		Data: map[string]interface{}{
-			"issueType":   issueType,
-			"summary":     fmt.Sprintf("Security issue in %s", filename),
-			"description": description,
-			"priority":    "High",
-			"labels":      []string{"security", "code-review"},
-		},
+		Data:    data,
 		NextSteps: []string{
 			"Specify the project where this issue should be created",
 			"Add more details about the vulnerability",
@@ -416,9 +433,56 @@ func handleCreateBugFromCode(ctx *CommandContext) (*CommandResult, error) {
 		},
 	}
 
+	// Warn about issues that likely report the same finding before filing
+	if duplicates := findLikelyDuplicates(ctx.Input, summary, description); len(duplicates) > 0 {
+		keys := make([]string, len(duplicates))
+		for i, duplicate := range duplicates {
+			keys[i] = duplicate.Key
+		}
+		result.Message = fmt.Sprintf("Found %d likely duplicate(s) of this %s: %s. Review them before filing",
+			len(duplicates), strings.ToLower(issueType), strings.Join(keys, ", "))
+		data["possibleDuplicates"] = duplicates
+		result.NextSteps = append([]string{fmt.Sprintf("Check whether %s already covers this finding", strings.Join(keys, ", "))}, result.NextSteps...)
+		result.Actions = append([]ActionItem{{
+			ID:          "review-duplicates",
+			Title:       "Review Possible Duplicates",
+			Description: "Open the existing issues that look like the same finding",
+			Command:     fmt.Sprintf("show issue %s", keys[0]),
+			Priority:    1,
+		}}, result.Actions...)
+	}
+
 	return result, nil
 }
 
+// bugCommandPrefix matches the command words in front of a reported finding
+var bugCommandPrefix = regexp.MustCompile(`(?i)^\s*(create|report|file)\s+(a|an)?\s*`)
+
+// findLikelyDuplicates returns the indexed issues that likely report the same
+// finding, comparing both the issue about to be filed and the finding as the
+// user described it
+func findLikelyDuplicates(input, summary, description string) []textindex.Match {
+	seen := make(map[string]int)
+	var duplicates []textindex.Match
+	for _, text := range []string{summary + "\n" + description, bugCommandPrefix.ReplaceAllString(input, "")} {
+		for _, match := range textindex.GlobalIndex.Duplicates(text, 5) {
+			if i, ok := seen[match.Key]; ok {
+				if match.Similarity > duplicates[i].Similarity {
+					duplicates[i] = match
+				}
+				continue
+			}
+			seen[match.Key] = len(duplicates)
+			duplicates = append(duplicates, match)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Similarity > duplicates[j].Similarity })
+	if len(duplicates) > 5 {
+		duplicates = duplicates[:5]
+	}
+	return duplicates
+}
+
 func handleBatchTransition(ctx *CommandContext) (*CommandResult, error) {
 	// This is a placeholder - in reality would perform the batch operation
 	return &CommandResult{
@@ -453,15 +517,140 @@ func handleSmartSearch(ctx *CommandContext) (*CommandResult, error) {
 }
 
 func handleQuickAssign(ctx *CommandContext) (*CommandResult, error) {
+	matches := regexp.MustCompile(`(?i)assign\s+(\w+-\d+)\s+to\s+(.+)`).FindStringSubmatch(ctx.Input)
+	if len(matches) < 3 {
+		return &CommandResult{
+			Success: true,
+			Message: "Quick assignment processed",
+			Data: map[string]interface{}{
+				"operation": "smart_assignment",
+			},
+		}, nil
+	}
+
+	issueKey := strings.ToUpper(matches[1])
+	assignee := strings.TrimSpace(matches[2])
+	notify := notifyPattern.MatchString(assignee)
+	assignee = strings.TrimSpace(notifyPattern.ReplaceAllString(assignee, ""))
+
+	actions := []ActionItem{
+		{
+			ID:          "assign-issue",
+			Title:       "Assign Issue",
+			Description: fmt.Sprintf("Assign %s to %s", issueKey, assignee),
+			Command:     fmt.Sprintf("PUT /api/v1/issues/%s {\"assignee\": %q}", issueKey, assignee),
+			Priority:    1,
+		},
+		{
+			ID:          "add-watcher",
+			Title:       "Add Watcher",
+			Description: fmt.Sprintf("Make sure %s follows updates on %s", assignee, issueKey),
+			Command:     fmt.Sprintf("POST /api/v1/issues/%s/watchers {\"user\": %q}", issueKey, assignee),
+			Priority:    2,
+		},
+	}
+	if notify {
+		actions = append(actions, ActionItem{
+			ID:          "notify-assignee",
+			Title:       "Notify Assignee",
+			Description: fmt.Sprintf("Email %s about the assignment", assignee),
+			Command:     fmt.Sprintf("POST /api/v1/issues/%s/notify {\"subject\": \"%s assigned to you\", \"to\": {\"assignee\": true}}", issueKey, issueKey),
+			Priority:    2,
+		})
+	}
+
 	return &CommandResult{
 		Success: true,
-		Message: "Quick assignment processed",
+		Message: fmt.Sprintf("Ready to assign %s to %s", issueKey, assignee),
 		Data: map[string]interface{}{
 			"operation": "smart_assignment",
+			"issueKey":  issueKey,
+			"assignee":  assignee,
+			"notify":    notify,
+		},
+		NextSteps: []string{
+			"Resolve the assignee to a Jira user if the name is ambiguous",
+			"Execute the assignment",
 		},
+		Actions: actions,
 	}, nil
 }
 
+// notifyPattern matches a trailing request to notify the assignee, e.g. "and let them know"
+var notifyPattern = regexp.MustCompile(`(?i)\s+(and\s+)?(notify|tell|let)\s+(them|him|her|the\s+assignee)(\s+know)?\s*$`)
+
+func handleNotifyWatchers(ctx *CommandContext) (*CommandResult, error) {
+	matches := regexp.MustCompile(`(?i)(notify|email|tell|ping)\s+(.+?)\s+(about|on)\s+(\w+-\d+)(.*)`).FindStringSubmatch(ctx.Input)
+	if len(matches) < 5 {
+		return &CommandResult{
+			Success: true,
+			Message: "Notification request identified",
+			Data: map[string]interface{}{
+				"operation": "notify",
+			},
+			NextSteps: []string{"Specify who to notify and which issue it is about"},
+		}, nil
+	}
+
+	recipients := strings.TrimSpace(matches[2])
+	issueKey := strings.ToUpper(matches[4])
+	message := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(matches[5]), ":-"))
+
+	to := map[string]interface{}{}
+	switch strings.ToLower(recipients) {
+	case "watchers", "the watchers", "everyone watching":
+		to["watchers"] = true
+	case "assignee", "the assignee":
+		to["assignee"] = true
+	case "reporter", "the reporter":
+		to["reporter"] = true
+	case "voters", "the voters":
+		to["voters"] = true
+	default:
+		to["users"] = splitRecipients(recipients)
+	}
+
+	payload := map[string]interface{}{
+		"subject":  fmt.Sprintf("Update on %s", issueKey),
+		"textBody": message,
+		"to":       to,
+	}
+
+	return &CommandResult{
+		Success: true,
+		Message: fmt.Sprintf("Ready to notify %s about %s", recipients, issueKey),
+		Data: map[string]interface{}{
+			"operation": "notify",
+			"issueKey":  issueKey,
+			"payload":   payload,
+		},
+		NextSteps: []string{
+			"Confirm the message body",
+			"Send the notification",
+		},
+		Actions: []ActionItem{
+			{
+				ID:          "notify-issue",
+				Title:       "Send Notification",
+				Description: fmt.Sprintf("Email %s about %s", recipients, issueKey),
+				Command:     fmt.Sprintf("POST /api/v1/issues/%s/notify", issueKey),
+				Priority:    1,
+			},
+		},
+	}, nil
+}
+
+// splitRecipients splits "alice, bob and carol" into individual user references
+func splitRecipients(s string) []string {
+	var recipients []string
+	for _, part := range regexp.MustCompile(`\s*(,|\band\b)\s*`).Split(s, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			recipients = append(recipients, part)
+		}
+	}
+	return recipients
+}
+
 func handleSprintManagement(ctx *CommandContext) (*CommandResult, error) {
 	return &CommandResult{
 		Success: true,
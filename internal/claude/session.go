@@ -2,9 +2,11 @@ package claude
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/webhook"
 	"github.com/rs/zerolog/log"
 )
 
@@ -55,6 +57,29 @@ type WorkingHours struct {
 	Timezone  string   `json:"timezone"`
 }
 
+// ExternalChange is a change made in Jira outside GoJira, reported by webhook
+type ExternalChange struct {
+	Event    string    `json:"event"`
+	IssueKey string    `json:"issueKey,omitempty"`
+	Project  string    `json:"project,omitempty"`
+	Summary  string    `json:"summary"`
+	Time     time.Time `json:"time"` // when the webhook was received
+}
+
+// maxExternalChanges bounds the Jira changes kept in a session context
+const maxExternalChanges = 10
+
+// newExternalChange summarizes a webhook event
+func newExternalChange(event *webhook.Event) ExternalChange {
+	return ExternalChange{
+		Event:    event.Type,
+		IssueKey: strings.ToUpper(event.IssueKey()),
+		Project:  event.ProjectKey(),
+		Summary:  event.Describe(),
+		Time:     time.Now(),
+	}
+}
+
 // SessionManager manages conversation sessions
 type SessionManager struct {
 	sessions    map[string]*Session
@@ -446,6 +471,39 @@ func (sm *SessionManager) getDefaultPreferences() *UserPreferences {
 	}
 }
 
+// HandleJiraEvent records a change made in Jira in the context of every
+// active session working on its project or issue, so that follow-up commands
+// know the state they saw is outdated
+func (sm *SessionManager) HandleJiraEvent(event *webhook.Event) {
+	change := newExternalChange(event)
+	if change.IssueKey == "" && change.Project == "" {
+		return
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	for _, session := range sm.sessions {
+		if !session.IsActive || sm.isSessionExpired(session) || !session.concerns(change) {
+			continue
+		}
+
+		changes, _ := session.Context["jiraChanges"].([]ExternalChange)
+		changes = append(changes, change)
+		if len(changes) > maxExternalChanges {
+			changes = changes[len(changes)-maxExternalChanges:]
+		}
+		session.Context["jiraChanges"] = changes
+		session.Context["lastJiraChange"] = change.Summary
+
+		log.Debug().
+			Str("sessionId", session.ID).
+			Str("event", change.Event).
+			Str("issue", change.IssueKey).
+			Msg("Recorded Jira change in session")
+	}
+}
+
 func (sm *SessionManager) Stop() {
 	close(sm.stopCh)
 }
@@ -488,6 +546,27 @@ func (s *Session) GetRecentCommands(count int) []Command {
 	return s.CommandHistory[len(s.CommandHistory)-count:]
 }
 
+// concerns reports whether a Jira change is about the session's project or
+// an issue of its recent commands
+func (s *Session) concerns(change ExternalChange) bool {
+	if change.Project != "" && strings.EqualFold(s.Project, change.Project) {
+		return true
+	}
+	if change.IssueKey == "" {
+		return false
+	}
+	for _, command := range s.GetRecentCommands(10) {
+		if command.Intent == nil {
+			continue
+		}
+		if entity, ok := command.Intent.Entities["issue_key"]; ok &&
+			strings.EqualFold(fmt.Sprint(entity.Value), change.IssueKey) {
+			return true
+		}
+	}
+	return false
+}
+
 func generateSessionID() string {
 	return fmt.Sprintf("session_%d", time.Now().UnixNano())
 }
\ No newline at end of file
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/jira"
 	"github.com/ericfisherdev/GoJira/internal/nlp"
+	"github.com/ericfisherdev/GoJira/internal/webhook"
 	"github.com/rs/zerolog/log"
 )
 
@@ -15,8 +18,14 @@ type SuggestionEngine struct {
 	patternManager *PatternManager
 	sessionManager *SessionManager
 	preferences    map[string]*UserPreferences
+
+	changesMu sync.RWMutex
+	changes   map[string][]ExternalChange // project -> recent Jira changes
 }
 
+// External changes older than externalChangeWindow are not suggested
+const externalChangeWindow = time.Hour
+
 // SuggestionContext provides context for generating suggestions
 type SuggestionContext struct {
 	UserID         string
@@ -45,9 +54,31 @@ func NewSuggestionEngine(patternManager *PatternManager, sessionManager *Session
 		patternManager: patternManager,
 		sessionManager: sessionManager,
 		preferences:    make(map[string]*UserPreferences),
+		changes:        make(map[string][]ExternalChange),
 	}
 }
 
+// HandleJiraEvent remembers an issue changed in Jira outside GoJira, so that
+// sessions on its project are offered to review it
+func (se *SuggestionEngine) HandleJiraEvent(event *webhook.Event) {
+	if event.Type == jira.WebhookIssueDeleted {
+		return
+	}
+	change := newExternalChange(event)
+	if change.IssueKey == "" || change.Project == "" {
+		return
+	}
+
+	se.changesMu.Lock()
+	defer se.changesMu.Unlock()
+
+	changes := append(se.changes[change.Project], change)
+	if len(changes) > maxExternalChanges {
+		changes = changes[len(changes)-maxExternalChanges:]
+	}
+	se.changes[change.Project] = changes
+}
+
 // GetSuggestions generates intelligent suggestions based on context
 func (se *SuggestionEngine) GetSuggestions(ctx *CommandContext) []Suggestion {
 	suggestions := make([]Suggestion, 0)
@@ -68,6 +99,7 @@ func (se *SuggestionEngine) GetSuggestions(ctx *CommandContext) []Suggestion {
 	// Add project-specific suggestions
 	if ctx.Session != nil && ctx.Session.Project != "" {
 		suggestions = append(suggestions, se.getProjectSuggestions(ctx)...)
+		suggestions = append(suggestions, se.getExternalChangeSuggestions(ctx)...)
 	}
 
 	// Add efficiency suggestions
@@ -419,6 +451,31 @@ func (se *SuggestionEngine) getProjectSuggestions(ctx *CommandContext) []Suggest
 	}
 }
 
+// getExternalChangeSuggestions offers to review the issues of the session's
+// project most recently changed in Jira, newest first
+func (se *SuggestionEngine) getExternalChangeSuggestions(ctx *CommandContext) []Suggestion {
+	se.changesMu.RLock()
+	changes := se.changes[strings.ToUpper(ctx.Session.Project)]
+	se.changesMu.RUnlock()
+
+	suggestions := make([]Suggestion, 0)
+	seen := make(map[string]bool)
+	for i := len(changes) - 1; i >= 0 && len(suggestions) < 3; i-- {
+		change := changes[i]
+		if time.Since(change.Time) > externalChangeWindow || seen[change.IssueKey] {
+			continue
+		}
+		seen[change.IssueKey] = true
+		suggestions = append(suggestions, Suggestion{
+			Command:     fmt.Sprintf("Show %s", change.IssueKey),
+			Description: "Changed in Jira: " + change.Summary,
+			Confidence:  0.75,
+			Category:    string(CategoryFollowUp),
+		})
+	}
+	return suggestions
+}
+
 // getEfficiencySuggestions provides efficiency improvement suggestions
 func (se *SuggestionEngine) getEfficiencySuggestions(ctx *CommandContext) []Suggestion {
 	return []Suggestion{
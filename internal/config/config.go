@@ -14,6 +14,10 @@ type Config struct {
 	Features FeatureConfig  `mapstructure:"features"`
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	Security SecurityConfig `mapstructure:"security"`
+	Webhooks WebhookConfig  `mapstructure:"webhooks"`
+	Warmup   WarmupConfig   `mapstructure:"warmup"`
+	Mirror   MirrorConfig   `mapstructure:"mirror"`
+	Cache    CacheConfig    `mapstructure:"cache"`
 }
 
 type ServerConfig struct {
@@ -65,6 +69,52 @@ type SecurityConfig struct {
 	JWTSecret      string   `mapstructure:"jwt_secret"`
 }
 
+// WebhookConfig holds the credentials Jira webhook deliveries must carry
+type WebhookConfig struct {
+	Secret    string `mapstructure:"secret"`     // shared secret, in the URL or as HMAC key
+	JWTSecret string `mapstructure:"jwt_secret"` // Connect app shared secret
+	JWTIssuer string `mapstructure:"jwt_issuer"` // expected Connect client key
+}
+
+// WarmupConfig holds the Jira data loaded into the cache after connecting
+type WarmupConfig struct {
+	Interval int                   `mapstructure:"interval"` // minutes between scheduled warm-ups, 0 disables them
+	Profiles []WarmupProfileConfig `mapstructure:"profiles"` // the default profile is used when empty
+}
+
+// WarmupProfileConfig names the targets of a warm-up profile: projects,
+// boards, activeSprints, workflows, fields and linkTypes
+type WarmupProfileConfig struct {
+	Name    string   `mapstructure:"name"`
+	Targets []string `mapstructure:"targets"`
+	Boards  []int    `mapstructure:"boards"` // boards whose active sprints are warmed, all scrum boards when empty
+}
+
+// MirrorConfig selects the projects mirrored to disk and served while Jira is
+// unreachable
+type MirrorConfig struct {
+	Dir           string   `mapstructure:"dir"` // mirror and write outbox directory, empty disables the mirror
+	Projects      []string `mapstructure:"projects"`
+	Interval      int      `mapstructure:"interval"`       // minutes between incremental syncs
+	ProbeInterval int      `mapstructure:"probe_interval"` // seconds between reachability checks while Jira is unreachable
+	PageSize      int      `mapstructure:"page_size"`      // issues fetched per search page
+}
+
+// CacheConfig holds the cache levels shared between GoJira replicas
+type CacheConfig struct {
+	L2 L2CacheConfig `mapstructure:"l2"`
+}
+
+// L2CacheConfig points the shared L2 cache at a Redis-compatible server
+type L2CacheConfig struct {
+	Addr     string `mapstructure:"addr"` // host:port, empty disables the shared cache
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	Prefix   string `mapstructure:"prefix"`    // key namespace shared by the replicas
+	PoolSize int    `mapstructure:"pool_size"` // pooled connections, also the cap on commands in flight
+	TTL      int    `mapstructure:"ttl"`       // minutes entries stay in the shared cache
+}
+
 // Load loads configuration from various sources
 func Load(configPath string) (*Config, error) {
 	// Set config name and type
@@ -150,6 +200,19 @@ func setDefaults() {
 	viper.SetDefault("features.caching", true)
 	viper.SetDefault("features.auto_retry", true)
 
+	// Warm-up defaults
+	viper.SetDefault("warmup.interval", 30)
+
+	// Offline mirror defaults
+	viper.SetDefault("mirror.interval", 15)
+	viper.SetDefault("mirror.probe_interval", 30)
+	viper.SetDefault("mirror.page_size", 100)
+
+	// Shared cache defaults
+	viper.SetDefault("cache.l2.prefix", "gojira:")
+	viper.SetDefault("cache.l2.pool_size", 10)
+	viper.SetDefault("cache.l2.ttl", 15)
+
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "json")
@@ -202,6 +265,16 @@ func validate(config *Config) error {
 		}
 	}
 
+	// Validate offline mirror config
+	if config.Mirror.Dir != "" && len(config.Mirror.Projects) == 0 {
+		return fmt.Errorf("mirror projects are required when a mirror directory is set")
+	}
+
+	// Validate shared cache config
+	if config.Cache.L2.PoolSize < 0 || config.Cache.L2.TTL < 0 {
+		return fmt.Errorf("cache l2 pool size and ttl must not be negative")
+	}
+
 	// Validate Jira auth config
 	if config.Jira.Auth.Type != "" {
 		validAuthTypes := map[string]bool{
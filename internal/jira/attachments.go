@@ -0,0 +1,52 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// DownloadAttachment downloads the content of an attachment
+func (c *Client) DownloadAttachment(attachment Attachment) ([]byte, error) {
+	if attachment.Content == "" {
+		return nil, fmt.Errorf("attachment %s has no content url", attachment.Filename)
+	}
+
+	resp, err := c.newRequest().
+		SetHeader("Accept", "*/*").
+		Get(attachment.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment %s: %w", attachment.Filename, err)
+	}
+
+	if resp.IsError() {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	return resp.Body(), nil
+}
+
+// AddAttachment uploads a file as an attachment to an issue
+func (c *Client) AddAttachment(issueKey, filename string, content []byte) ([]Attachment, error) {
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/attachments", c.baseURL, issueKey)
+
+	resp, err := c.newRequest().
+		SetHeader("X-Atlassian-Token", "no-check").
+		SetFileReader("file", filename, bytes.NewReader(content)).
+		Post(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload attachment %s: %w", filename, err)
+	}
+
+	if resp.IsError() {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var attachments []Attachment
+	if err := json.Unmarshal(resp.Body(), &attachments); err != nil {
+		return nil, fmt.Errorf("failed to parse attachment response: %w", err)
+	}
+
+	c.notifyMutation(Mutation{Operation: OpAttachment, Issues: []string{issueKey}})
+	return attachments, nil
+}
@@ -498,7 +498,7 @@ func (rl *RateLimiter) Wait() {
 
 	// Wait if no tokens available
 	if rl.tokens < 1.0 {
-		waitTime := time.Duration((1.0-rl.tokens)/rl.rate) * time.Second
+		waitTime := time.Duration((1.0 - rl.tokens) / rl.rate * float64(time.Second))
 		time.Sleep(waitTime)
 		rl.tokens = 0
 	} else {
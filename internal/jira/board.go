@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -101,22 +102,19 @@ type BoardIssueList struct {
 
 // GetBoards retrieves all boards accessible to the user
 func (c *Client) GetBoards() (*BoardList, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board", c.baseURL)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	endpoint := "/rest/agile/1.0/board"
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get boards: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var boardList BoardList
-	if err := json.Unmarshal(resp.Body(), &boardList); err != nil {
+	if err := json.Unmarshal(body, &boardList); err != nil {
 		return nil, fmt.Errorf("failed to parse board list: %w", err)
 	}
 	
@@ -125,46 +123,51 @@ func (c *Client) GetBoards() (*BoardList, error) {
 
 // GetBoard retrieves a specific board by ID
 func (c *Client) GetBoard(boardID int) (*Board, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d", c.baseURL, boardID)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	endpoint := fmt.Sprintf("/rest/agile/1.0/board/%d", boardID)
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get board: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var board Board
-	if err := json.Unmarshal(resp.Body(), &board); err != nil {
+	if err := json.Unmarshal(body, &board); err != nil {
 		return nil, fmt.Errorf("failed to parse board: %w", err)
 	}
 	
 	return &board, nil
 }
 
-// GetBoardConfiguration retrieves the configuration of a board
+// GetBoardConfiguration retrieves the configuration of a board. Concurrent
+// requests share one Jira call and the returned configuration.
 func (c *Client) GetBoardConfiguration(boardID int) (*BoardConfiguration, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/configuration", c.baseURL, boardID)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	v, err, _ := c.reads.Do(context.Background(), fmt.Sprintf("board:%d/configuration", boardID), func(context.Context) (interface{}, error) {
+		return c.getBoardConfiguration(boardID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*BoardConfiguration), nil
+}
+
+func (c *Client) getBoardConfiguration(boardID int) (*BoardConfiguration, error) {
+	endpoint := fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", boardID)
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get board configuration: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var config BoardConfiguration
-	if err := json.Unmarshal(resp.Body(), &config); err != nil {
+	if err := json.Unmarshal(body, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse board configuration: %w", err)
 	}
 	
@@ -173,22 +176,19 @@ func (c *Client) GetBoardConfiguration(boardID int) (*BoardConfiguration, error)
 
 // GetBoardIssues retrieves issues on a board
 func (c *Client) GetBoardIssues(boardID int) (*BoardIssueList, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/issue", c.baseURL, boardID)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	endpoint := fmt.Sprintf("/rest/agile/1.0/board/%d/issue", boardID)
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get board issues: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var issueList BoardIssueList
-	if err := json.Unmarshal(resp.Body(), &issueList); err != nil {
+	if err := json.Unmarshal(body, &issueList); err != nil {
 		return nil, fmt.Errorf("failed to parse board issues: %w", err)
 	}
 	
@@ -197,22 +197,19 @@ func (c *Client) GetBoardIssues(boardID int) (*BoardIssueList, error) {
 
 // GetBoardBacklog retrieves issues in the backlog of a board
 func (c *Client) GetBoardBacklog(boardID int) (*BoardIssueList, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/backlog", c.baseURL, boardID)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	endpoint := fmt.Sprintf("/rest/agile/1.0/board/%d/backlog", boardID)
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get board backlog: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var issueList BoardIssueList
-	if err := json.Unmarshal(resp.Body(), &issueList); err != nil {
+	if err := json.Unmarshal(body, &issueList); err != nil {
 		return nil, fmt.Errorf("failed to parse board backlog: %w", err)
 	}
 	
@@ -247,6 +244,7 @@ func (c *Client) MoveIssuesToBacklog(issueKeys []string) error {
 		return c.handleErrorResponse(resp)
 	}
 	
+	c.notifyMutation(Mutation{Operation: OpBacklog, Issues: issueKeys})
 	return nil
 }
 
@@ -281,5 +279,6 @@ func (c *Client) MoveIssuesToBoard(boardID int, issueKeys []string, position str
 		return c.handleErrorResponse(resp)
 	}
 	
+	c.notifyMutation(Mutation{Operation: OpBoardMove, Issues: issueKeys, Boards: []int{boardID}})
 	return nil
 }
\ No newline at end of file
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -34,10 +36,36 @@ type IssueCreate struct {
 	Fields map[string]interface{} `json:"fields"`
 }
 
-// BulkCreateResponse represents the response from bulk create
+// BulkCreateResponse represents the response from bulk create. Issues holds
+// the created issues in request order, skipping the elements listed in Errors.
 type BulkCreateResponse struct {
-	Issues []Issue              `json:"issues"`
-	Errors []map[string]string  `json:"errors"`
+	Issues []Issue            `json:"issues"`
+	Errors []BulkCreateError  `json:"errors"`
+}
+
+// BulkCreateError describes an element of a bulk create that failed
+type BulkCreateError struct {
+	Status              int           `json:"status"`
+	ElementErrors       ErrorResponse `json:"elementErrors"`
+	FailedElementNumber int           `json:"failedElementNumber"`
+}
+
+// Message returns the field and general errors of the failed element
+func (e BulkCreateError) Message() string {
+	var msgs []string
+	msgs = append(msgs, e.ElementErrors.ErrorMessages...)
+	fields := make([]string, 0, len(e.ElementErrors.Errors))
+	for field := range e.ElementErrors.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", field, e.ElementErrors.Errors[field]))
+	}
+	if len(msgs) == 0 {
+		return fmt.Sprintf("create failed with status %d", e.Status)
+	}
+	return strings.Join(msgs, "; ")
 }
 
 // BulkUpdateIssues updates multiple issues with the same fields
@@ -165,6 +193,7 @@ func (c *Client) BulkDeleteIssues(issueKeys []string, deleteSubtasks bool) (*Bul
 			
 			if resp.StatusCode() == http.StatusNoContent || resp.StatusCode() == http.StatusOK {
 				result.Successful = append(result.Successful, issueKey)
+				c.notifyMutation(Mutation{Operation: OpDeleteIssue, Issues: []string{issueKey}})
 			} else {
 				result.Failed[issueKey] = fmt.Sprintf("deletion failed with status: %d", resp.StatusCode())
 			}
@@ -194,15 +223,24 @@ func (c *Client) BulkCreateIssues(issues []map[string]interface{}) (*BulkCreateR
 	if err != nil {
 		return nil, fmt.Errorf("failed to bulk create issues: %w", err)
 	}
+
+	// Jira answers 400 when every element failed, still listing the element errors
+	var response BulkCreateResponse
+	if resp.StatusCode() == http.StatusBadRequest {
+		if err := json.Unmarshal(resp.Body(), &response); err == nil && len(response.Errors) > 0 {
+			c.notifyBulkCreate(&response)
+			return &response, nil
+		}
+	}
 	if resp.StatusCode() != http.StatusCreated && resp.StatusCode() != http.StatusOK {
 		return nil, fmt.Errorf("bulk create failed with status %d: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
-	var response BulkCreateResponse
 	if err := json.Unmarshal(resp.Body(), &response); err != nil {
 		return nil, fmt.Errorf("failed to decode bulk create response: %w", err)
 	}
 
+	c.notifyBulkCreate(&response)
 	return &response, nil
 }
 
@@ -290,4 +328,14 @@ func (c *Client) BulkAddLabels(issueKeys []string, labels []string) (*BulkOperat
 	result.TotalTime = time.Since(start)
 
 	return result, nil
-}
\ No newline at end of file
+}
+// notifyBulkCreate reports the issues created by a bulk create request
+func (c *Client) notifyBulkCreate(response *BulkCreateResponse) {
+	keys := make([]string, 0, len(response.Issues))
+	for _, issue := range response.Issues {
+		keys = append(keys, issue.Key)
+	}
+	if len(keys) > 0 {
+		c.notifyMutation(Mutation{Operation: OpCreateIssue, Issues: keys})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ericfisherdev/GoJira/internal/auth"
@@ -19,6 +20,15 @@ type Client struct {
 	baseURL       string
 	authenticator auth.Authenticator
 	httpClient    *resty.Client
+
+	mutationMu        sync.RWMutex
+	mutationListeners []MutationListener
+
+	reads       CallGroup        // coalesces concurrent identical reads
+	conditional conditionalReads // responses kept for conditional revalidation
+
+	locationMu sync.Mutex
+	location   *time.Location // time zone of the connected user, once looked up
 }
 
 // ClientOptions contains options for creating a new client
@@ -62,6 +72,12 @@ func NewClient(baseURL string, authenticator auth.Authenticator, opts *ClientOpt
 
 // doRequest executes an HTTP request with authentication
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, body interface{}) (*resty.Response, error) {
+	return c.doRequestWithHeaders(ctx, method, endpoint, body, nil)
+}
+
+// doRequestWithHeaders executes an HTTP request with authentication and
+// additional headers
+func (c *Client) doRequestWithHeaders(ctx context.Context, method, endpoint string, body interface{}, headers map[string]string) (*resty.Response, error) {
 	// Track API call
 	monitoring.GlobalMetrics.IncrementJiraAPICalls()
 	
@@ -74,6 +90,10 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 		}
 	}
 
+	for k, v := range headers {
+		req.SetHeader(k, v)
+	}
+
 	// Set request body if provided
 	if body != nil {
 		req.SetBody(body)
@@ -165,6 +185,28 @@ func (c *Client) GetMyself(ctx context.Context) (*User, error) {
 	return &user, nil
 }
 
+// UserLocation returns the time zone Jira reads JQL dates in for the connected
+// user. It is looked up once; when it cannot be determined, the local time
+// zone is returned and ok is false.
+func (c *Client) UserLocation(ctx context.Context) (loc *time.Location, ok bool) {
+	c.locationMu.Lock()
+	defer c.locationMu.Unlock()
+
+	if c.location != nil {
+		return c.location, true
+	}
+	user, err := c.GetMyself(ctx)
+	if err != nil || user.TimeZone == "" {
+		return time.Local, false
+	}
+	loc, err = time.LoadLocation(user.TimeZone)
+	if err != nil {
+		return time.Local, false
+	}
+	c.location = loc
+	return loc, true
+}
+
 // CreateIssue creates a new issue
 func (c *Client) CreateIssue(ctx context.Context, issue *CreateIssueRequest) (*Issue, error) {
 	resp, err := c.doRequest(ctx, "POST", "/rest/api/2/issue", issue)
@@ -181,11 +223,24 @@ func (c *Client) CreateIssue(ctx context.Context, issue *CreateIssueRequest) (*I
 		return nil, fmt.Errorf("failed to parse created issue: %w", err)
 	}
 
+	c.notifyMutation(Mutation{Operation: OpCreateIssue, Issues: []string{created.Key}})
 	return &created, nil
 }
 
-// GetIssue retrieves an issue by key or ID
+// GetIssue retrieves an issue by key or ID. Concurrent identical requests
+// share one Jira call and the returned issue, which must not be modified.
 func (c *Client) GetIssue(ctx context.Context, issueKey string, expand []string) (*Issue, error) {
+	key := "issue:" + issueKey + "?expand=" + strings.Join(expand, ",")
+	v, err, _ := c.reads.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.getIssue(ctx, issueKey, expand)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Issue), nil
+}
+
+func (c *Client) getIssue(ctx context.Context, issueKey string, expand []string) (*Issue, error) {
 	endpoint := fmt.Sprintf("/rest/api/2/issue/%s", issueKey)
 	
 	// Add expand parameter if provided
@@ -193,17 +248,18 @@ func (c *Client) GetIssue(ctx context.Context, issueKey string, expand []string)
 		endpoint += "?expand=" + strings.Join(expand, ",")
 	}
 
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	// Revalidated, so an unchanged issue is not downloaded again
+	body, resp, err := c.doValidatedGet(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue %s: %w", issueKey, err)
 	}
 
-	if err := c.handleErrorResponse(resp); err != nil {
-		return nil, err
+	if body == nil {
+		return nil, c.handleErrorResponse(resp)
 	}
 
 	var issue Issue
-	if err := json.Unmarshal(resp.Body(), &issue); err != nil {
+	if err := json.Unmarshal(body, &issue); err != nil {
 		return nil, fmt.Errorf("failed to parse issue: %w", err)
 	}
 
@@ -223,6 +279,7 @@ func (c *Client) UpdateIssue(ctx context.Context, issueKey string, update *Updat
 		return err
 	}
 
+	c.notifyMutation(Mutation{Operation: OpUpdateIssue, Issues: []string{issueKey}})
 	return nil
 }
 
@@ -243,11 +300,24 @@ func (c *Client) DeleteIssue(ctx context.Context, issueKey string, deleteSubtask
 		return err
 	}
 
+	c.notifyMutation(Mutation{Operation: OpDeleteIssue, Issues: []string{issueKey}})
 	return nil
 }
 
-// SearchIssues searches for issues using JQL
+// SearchIssues searches for issues using JQL. Concurrent identical searches
+// share one Jira call and the returned result, which must not be modified.
 func (c *Client) SearchIssues(ctx context.Context, jql string, startAt, maxResults int, expand []string) (*SearchResult, error) {
+	key := fmt.Sprintf("search:%s?startAt=%d&maxResults=%d&expand=%s", jql, startAt, maxResults, strings.Join(expand, ","))
+	v, err, _ := c.reads.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.searchIssues(ctx, jql, startAt, maxResults, expand)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*SearchResult), nil
+}
+
+func (c *Client) searchIssues(ctx context.Context, jql string, startAt, maxResults int, expand []string) (*SearchResult, error) {
 	params := url.Values{}
 	params.Add("jql", jql)
 	params.Add("startAt", strconv.Itoa(startAt))
@@ -259,17 +329,18 @@ func (c *Client) SearchIssues(ctx context.Context, jql string, startAt, maxResul
 
 	endpoint := "/rest/api/2/search?" + params.Encode()
 
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	// Revalidated, so an unchanged result is not downloaded again
+	body, resp, err := c.doValidatedGet(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
 
-	if err := c.handleErrorResponse(resp); err != nil {
-		return nil, err
+	if body == nil {
+		return nil, c.handleErrorResponse(resp)
 	}
 
 	var result SearchResult
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse search results: %w", err)
 	}
 
@@ -310,6 +381,7 @@ func (c *Client) TransitionIssue(ctx context.Context, issueKey string, transitio
 		return err
 	}
 
+	c.notifyMutation(Mutation{Operation: OpTransitionIssue, Issues: []string{issueKey}})
 	return nil
 }
 
@@ -331,6 +403,7 @@ func (c *Client) AddComment(ctx context.Context, issueKey string, comment *Creat
 		return nil, fmt.Errorf("failed to parse comment: %w", err)
 	}
 
+	c.notifyMutation(Mutation{Operation: OpComment, Issues: []string{issueKey}})
 	return &result, nil
 }
 
@@ -0,0 +1,517 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CloneOptions controls how an issue is cloned
+type CloneOptions struct {
+	TargetProject      string                 `json:"targetProject,omitempty"`   // defaults to the source project
+	TargetIssueType    string                 `json:"targetIssueType,omitempty"` // defaults to the source issue type
+	Fields             []string               `json:"fields,omitempty"`          // defaults to DefaultCloneFields
+	FieldOverrides     map[string]interface{} `json:"fieldOverrides,omitempty"`
+	SummaryPrefix      string                 `json:"summaryPrefix,omitempty"`
+	IncludeSubtasks    bool                   `json:"includeSubtasks,omitempty"`
+	IncludeLinks       bool                   `json:"includeLinks,omitempty"`
+	IncludeAttachments bool                   `json:"includeAttachments,omitempty"`
+	LinkToSource       bool                   `json:"linkToSource,omitempty"`
+	LinkType           string                 `json:"linkType,omitempty"` // defaults to "Cloners"
+}
+
+// CloneResult describes the outcome of a clone
+type CloneResult struct {
+	SourceKey     string         `json:"sourceKey"`
+	Key           string         `json:"key"`
+	ID            string         `json:"id,omitempty"`
+	Subtasks      []*CloneResult `json:"subtasks,omitempty"`
+	LinksCopied   int            `json:"linksCopied"`
+	Attachments   int            `json:"attachmentsCopied"`
+	DroppedFields []FieldIssue   `json:"droppedFields,omitempty"`
+	Warnings      []string       `json:"warnings,omitempty"`
+}
+
+// MoveOptions controls how an issue is moved to another project
+type MoveOptions struct {
+	TargetProject    string            `json:"targetProject"`
+	IssueTypeMapping map[string]string `json:"issueTypeMapping,omitempty"` // source type -> target type
+	StatusMapping    map[string]string `json:"statusMapping,omitempty"`    // source status -> target status
+	IncludeSubtasks  bool              `json:"includeSubtasks,omitempty"`
+	DeleteSource     bool              `json:"deleteSource,omitempty"`
+	SourceStatus     string            `json:"sourceStatus,omitempty"` // status to move the source to when it is kept
+}
+
+// MoveResult describes the outcome of a move
+type MoveResult struct {
+	CloneResult
+	Status        string `json:"status,omitempty"`
+	StatusMatched bool   `json:"statusMatched"`
+	SourceDeleted bool   `json:"sourceDeleted"`
+	SourceKept    string `json:"sourceKept,omitempty"` // why the source was kept although DeleteSource was set
+}
+
+// DefaultCloneFields are copied when no field list is given
+var DefaultCloneFields = []string{
+	"summary", "description", "priority", "labels", "components", "fixVersions",
+	"versions", "assignee", "duedate", "environment", "timetracking",
+}
+
+// cloneSkippedFields are never copied: they are set by the clone itself or maintained by Jira
+var cloneSkippedFields = map[string]bool{
+	"project": true, "issuetype": true, "parent": true, "subtasks": true, "issuelinks": true,
+	"attachment": true, "comment": true, "worklog": true, "status": true, "resolution": true,
+	"resolutiondate": true, "created": true, "updated": true, "creator": true, "votes": true,
+	"watches": true, "lastViewed": true, "aggregateprogress": true, "progress": true,
+	"statuscategorychangedate": true, "workratio": true,
+}
+
+// rawIssue is an issue with its fields kept as generic JSON values
+type rawIssue struct {
+	ID     string                 `json:"id"`
+	Key    string                 `json:"key"`
+	Fields map[string]interface{} `json:"fields"`
+	typed  IssueFields
+}
+
+func (c *Client) getRawIssue(ctx context.Context, issueKey string) (*rawIssue, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/rest/api/2/issue/%s", issueKey), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", issueKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var issue rawIssue
+	if err := json.Unmarshal(resp.Body(), &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+
+	var typed struct {
+		Fields IssueFields `json:"fields"`
+	}
+	if err := json.Unmarshal(resp.Body(), &typed); err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+	issue.typed = typed.Fields
+
+	return &issue, nil
+}
+
+// CloneIssue deep-clones an issue, optionally into another project. Field values
+// are remapped against the target create metadata: option values are matched by
+// name so components, versions and select options resolve to the target
+// project's ids. Optional fields that cannot be mapped are dropped and reported.
+func (c *Client) CloneIssue(ctx context.Context, sourceKey string, opts CloneOptions) (*CloneResult, error) {
+	source, err := c.getRawIssue(ctx, sourceKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.cloneRawIssue(ctx, source, opts, "")
+}
+
+func (c *Client) cloneRawIssue(ctx context.Context, source *rawIssue, opts CloneOptions, parentKey string) (*CloneResult, error) {
+	targetProject := opts.TargetProject
+	if targetProject == "" {
+		targetProject = source.typed.Project.Key
+	}
+	issueType := opts.TargetIssueType
+	if issueType == "" {
+		issueType = source.typed.IssueType.Name
+	}
+
+	meta, err := c.GetCreateMeta(targetProject, issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldList := opts.Fields
+	if len(fieldList) == 0 {
+		fieldList = DefaultCloneFields
+	}
+
+	fields := make(map[string]interface{})
+	for _, name := range fieldList {
+		if cloneSkippedFields[name] {
+			continue
+		}
+		value, ok := source.Fields[name]
+		if !ok || isEmptyFieldValue(value) {
+			continue
+		}
+		fields[name] = portableFieldValue(value)
+	}
+	for name, value := range opts.FieldOverrides {
+		fields[name] = value
+	}
+	if summary, ok := fields["summary"].(string); ok && opts.SummaryPrefix != "" {
+		fields["summary"] = opts.SummaryPrefix + summary
+	}
+	if _, ok := fields["summary"]; !ok {
+		fields["summary"] = opts.SummaryPrefix + source.typed.Summary
+	}
+
+	result := &CloneResult{SourceKey: source.Key}
+
+	// Fields the target does not accept are dropped when optional; anything
+	// required and missing is fatal
+	validation := meta.ValidateFields(fields, nil)
+	for _, issue := range validation.Invalid {
+		if field, ok := meta.Fields[issue.Field]; ok && field.Required && !field.HasDefaultValue {
+			return nil, fmt.Errorf("cannot clone %s: required field %s: %s", source.Key, issue.Name, issue.Message)
+		}
+		result.DroppedFields = append(result.DroppedFields, issue)
+	}
+	var missing []string
+	for _, issue := range validation.Missing {
+		if parentKey != "" && issue.Field == "parent" {
+			continue
+		}
+		missing = append(missing, issue.Name)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("cannot clone %s into %s/%s: missing required fields: %s",
+			source.Key, targetProject, meta.IssueType.Name, strings.Join(missing, ", "))
+	}
+
+	createFields := validation.Fields
+	createFields["project"] = map[string]interface{}{"key": targetProject}
+	createFields["issuetype"] = map[string]interface{}{"id": meta.IssueType.ID}
+	if parentKey != "" {
+		createFields["parent"] = map[string]interface{}{"key": parentKey}
+	}
+
+	created, err := c.CreateIssue(ctx, &CreateIssueRequest{Fields: createFields})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clone of %s: %w", source.Key, err)
+	}
+	result.Key = created.Key
+	result.ID = created.ID
+
+	if opts.LinkToSource {
+		linkType := opts.LinkType
+		if linkType == "" {
+			linkType = "Cloners"
+		}
+		if err := c.CreateIssueLink(created.Key, source.Key, linkType, ""); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to link clone to source: %v", err))
+		}
+	}
+
+	if opts.IncludeLinks {
+		c.copyIssueLinks(source, created.Key, result)
+	}
+
+	if opts.IncludeAttachments {
+		for _, attachment := range source.typed.Attachment {
+			content, err := c.DownloadAttachment(attachment)
+			if err == nil {
+				_, err = c.AddAttachment(created.Key, attachment.Filename, content)
+			}
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("attachment %s not copied: %v", attachment.Filename, err))
+				continue
+			}
+			result.Attachments++
+		}
+	}
+
+	if opts.IncludeSubtasks && parentKey == "" {
+		subtaskOpts := opts
+		subtaskOpts.TargetIssueType = ""
+		subtaskOpts.FieldOverrides = nil
+		subtaskOpts.LinkToSource = false
+
+		for _, ref := range source.typed.Subtasks {
+			subtask, err := c.getRawIssue(ctx, ref.Key)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("subtask %s not cloned: %v", ref.Key, err))
+				continue
+			}
+			subtaskOpts.TargetIssueType = c.subtaskIssueType(targetProject, subtask.typed.IssueType.Name)
+
+			cloned, err := c.cloneRawIssue(ctx, subtask, subtaskOpts, created.Key)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("subtask %s not cloned: %v", ref.Key, err))
+				continue
+			}
+			result.Subtasks = append(result.Subtasks, cloned)
+		}
+	}
+
+	return result, nil
+}
+
+// copyIssueLinks recreates the source's issue links on the clone
+func (c *Client) copyIssueLinks(source *rawIssue, cloneKey string, result *CloneResult) {
+	for _, link := range source.typed.IssueLinks {
+		var err error
+		switch {
+		case link.OutwardIssue != nil:
+			err = c.CreateIssueLink(cloneKey, link.OutwardIssue.Key, link.Type.Name, "")
+		case link.InwardIssue != nil:
+			err = c.CreateIssueLink(link.InwardIssue.Key, cloneKey, link.Type.Name, "")
+		default:
+			continue
+		}
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("link %s not copied: %v", link.Type.Name, err))
+			continue
+		}
+		result.LinksCopied++
+	}
+}
+
+// subtaskIssueType picks the target project's subtask type, preferring one with the same name
+func (c *Client) subtaskIssueType(projectKey, sourceType string) string {
+	issueTypes, err := c.GetCreateMetaIssueTypes(projectKey)
+	if err != nil {
+		return sourceType
+	}
+
+	fallback := sourceType
+	for _, it := range issueTypes {
+		if !it.Subtask {
+			continue
+		}
+		if strings.EqualFold(it.Name, sourceType) {
+			return it.Name
+		}
+		if fallback == sourceType {
+			fallback = it.Name
+		}
+	}
+	return fallback
+}
+
+// MoveIssue moves an issue to another project. The issue is recreated in the
+// target project with mapped issue type and fields, transitioned to the mapped
+// status, and the source is either deleted or linked to the new issue. The
+// source is only deleted when everything was copied, since deleting it also
+// deletes its subtasks.
+func (c *Client) MoveIssue(ctx context.Context, sourceKey string, opts MoveOptions) (*MoveResult, error) {
+	if opts.TargetProject == "" {
+		return nil, fmt.Errorf("target project is required")
+	}
+
+	source, err := c.getRawIssue(ctx, sourceKey)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(source.typed.Project.Key, opts.TargetProject) {
+		return nil, fmt.Errorf("%s is already in project %s", sourceKey, opts.TargetProject)
+	}
+
+	issueType := source.typed.IssueType.Name
+	if mapped, ok := lookupMapping(opts.IssueTypeMapping, issueType); ok {
+		issueType = mapped
+	}
+
+	fields := make([]string, 0, len(source.Fields))
+	for name := range source.Fields {
+		if !cloneSkippedFields[name] {
+			fields = append(fields, name)
+		}
+	}
+
+	clone, err := c.cloneRawIssue(ctx, source, CloneOptions{
+		TargetProject:      opts.TargetProject,
+		TargetIssueType:    issueType,
+		Fields:             fields,
+		IncludeSubtasks:    opts.IncludeSubtasks,
+		IncludeLinks:       true,
+		IncludeAttachments: true,
+		LinkToSource:       !opts.DeleteSource,
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MoveResult{CloneResult: *clone}
+
+	if source.typed.Status != nil {
+		target := source.typed.Status.Name
+		if mapped, ok := lookupMapping(opts.StatusMapping, target); ok {
+			target = mapped
+		}
+		result.Status = target
+
+		matched, err := c.transitionToStatus(ctx, clone.Key, target)
+		result.StatusMatched = matched
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+		for _, subtask := range clone.Subtasks {
+			c.transitionSubtask(ctx, subtask, opts.StatusMapping, result)
+		}
+	}
+
+	if opts.DeleteSource {
+		result.SourceKept = keepSourceReason(source, opts, clone)
+		if result.SourceKept == "" {
+			if err := c.DeleteIssue(ctx, sourceKey, true); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("source %s not deleted: %v", sourceKey, err))
+			} else {
+				result.SourceDeleted = true
+			}
+			return result, nil
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("source %s not deleted: %s", sourceKey, result.SourceKept))
+
+		// The kept source points to the new issue like on a move without delete
+		if err := c.CreateIssueLink(clone.Key, sourceKey, "Cloners", ""); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to link clone to source: %v", err))
+		}
+	}
+
+	comment := fmt.Sprintf("Moved to %s", clone.Key)
+	if _, err := c.AddComment(ctx, sourceKey, &CreateCommentRequest{Body: comment}); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to comment on source: %v", err))
+	}
+	if opts.SourceStatus != "" {
+		if _, err := c.transitionToStatus(ctx, sourceKey, opts.SourceStatus); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+// keepSourceReason explains why a moved issue must not be deleted: deleting it
+// would lose subtasks left behind, or links, attachments and subtasks that
+// failed to copy. It is empty when the source can be deleted.
+func keepSourceReason(source *rawIssue, opts MoveOptions, clone *CloneResult) string {
+	if !opts.IncludeSubtasks && len(source.typed.Subtasks) > 0 {
+		return fmt.Sprintf("its %d subtasks were not moved and would be deleted with it", len(source.typed.Subtasks))
+	}
+
+	failures := 0
+	var count func(result *CloneResult)
+	count = func(result *CloneResult) {
+		failures += len(result.Warnings)
+		for _, subtask := range result.Subtasks {
+			count(subtask)
+		}
+	}
+	count(clone)
+	if failures > 0 {
+		return fmt.Sprintf("%d links, attachments or subtasks were not copied", failures)
+	}
+	return ""
+}
+
+func (c *Client) transitionSubtask(ctx context.Context, subtask *CloneResult, mapping map[string]string, result *MoveResult) {
+	source, err := c.getRawIssue(ctx, subtask.SourceKey)
+	if err != nil || source.typed.Status == nil {
+		return
+	}
+	target := source.typed.Status.Name
+	if mapped, ok := lookupMapping(mapping, target); ok {
+		target = mapped
+	}
+	if _, err := c.transitionToStatus(ctx, subtask.Key, target); err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+}
+
+// transitionToStatus moves an issue to the named status using a single
+// available transition. It reports whether the issue ended in that status.
+func (c *Client) transitionToStatus(ctx context.Context, issueKey, status string) (bool, error) {
+	// The new issue may already be in the requested status
+	issue, err := c.GetIssue(ctx, issueKey, nil)
+	if err == nil && issue.Fields.Status != nil && strings.EqualFold(issue.Fields.Status.Name, status) {
+		return true, nil
+	}
+
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/rest/api/2/issue/%s/transitions", issueKey), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get transitions for %s: %w", issueKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return false, err
+	}
+
+	var result struct {
+		Transitions []TransitionDetail `json:"transitions"`
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return false, fmt.Errorf("failed to decode transitions: %w", err)
+	}
+
+	for _, transition := range result.Transitions {
+		if strings.EqualFold(transition.To.Name, status) {
+			if err := c.TransitionIssueAdvanced(issueKey, transition.ID, nil, ""); err != nil {
+				return false, fmt.Errorf("failed to transition %s to %s: %w", issueKey, status, err)
+			}
+			return true, nil
+		}
+	}
+
+	return false, fmt.Errorf("no transition from the initial status of %s leads to %s", issueKey, status)
+}
+
+func lookupMapping(mapping map[string]string, key string) (string, bool) {
+	if value, ok := mapping[key]; ok {
+		return value, true
+	}
+	for k, v := range mapping {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func isEmptyFieldValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// portableFieldValue strips project specific ids from option-like values so
+// they can be matched by name against another project's allowed values
+func portableFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = portableFieldValue(item)
+		}
+		return items
+	case map[string]interface{}:
+		// Users and rich text documents are kept as they are
+		if _, ok := v["accountId"]; ok {
+			return map[string]interface{}{"accountId": v["accountId"]}
+		}
+		if _, ok := v["type"].(string); ok {
+			if _, isDoc := v["content"]; isDoc {
+				return v
+			}
+		}
+		if name, ok := v["name"].(string); ok && name != "" {
+			if _, isUser := v["emailAddress"]; isUser {
+				return map[string]interface{}{"name": name}
+			}
+			return name
+		}
+		if val, ok := v["value"].(string); ok && val != "" {
+			return val
+		}
+		return v
+	}
+	return value
+}
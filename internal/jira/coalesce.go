@@ -0,0 +1,98 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/monitoring"
+)
+
+// CoalescingStats counts the calls made through a CallGroup
+type CoalescingStats struct {
+	Calls     int64 `json:"calls"`     // calls actually made
+	Coalesced int64 `json:"coalesced"` // requests that shared a call already in flight
+	InFlight  int   `json:"inFlight"`
+}
+
+type flight struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// CallGroup coalesces concurrent calls for the same key into one call whose
+// result every caller shares. Shared results must be treated as read-only.
+type CallGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+	stats   CoalescingStats
+}
+
+// Do runs fn once for all concurrent callers of key. fn runs detached from
+// the cancellation of the caller that started it, so that one caller giving
+// up does not fail the others; each caller stops waiting when its own
+// context is done. shared reports whether the caller joined a call started by
+// another.
+func (g *CallGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (value interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+	f, shared := g.flights[key]
+	if shared {
+		g.stats.Coalesced++
+		monitoring.GlobalMetrics.IncrementCoalesced()
+	} else {
+		f = &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		g.stats.Calls++
+		go g.run(context.WithoutCancel(ctx), key, f, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err, shared
+	case <-ctx.Done():
+		return nil, ctx.Err(), shared
+	}
+}
+
+func (g *CallGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) (interface{}, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.err = fmt.Errorf("coalesced call %s panicked: %v", key, r)
+		}
+		g.mu.Lock()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.value, f.err = fn(ctx)
+}
+
+// ForgetAll makes callers arriving from now on start new calls instead of
+// joining those in flight, whose results may predate a write
+func (g *CallGroup) ForgetAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.flights = make(map[string]*flight)
+}
+
+// GetStats returns the coalescing statistics
+func (g *CallGroup) GetStats() CoalescingStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	stats := g.stats
+	stats.InFlight = len(g.flights)
+	return stats
+}
+
+// GetCoalescingStats returns how many concurrent identical reads made
+// through the client shared a single Jira request
+func (c *Client) GetCoalescingStats() CoalescingStats {
+	return c.reads.GetStats()
+}
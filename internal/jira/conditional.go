@@ -0,0 +1,132 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ConditionalStats counts conditional requests made to Jira
+type ConditionalStats struct {
+	Requests    int64 `json:"requests"`    // requests sent with a validator
+	NotModified int64 `json:"notModified"` // answered 304 and served from the stored body
+	BytesSaved  int64 `json:"bytesSaved"`
+	Entries     int   `json:"entries"`
+}
+
+// ValidatedResponse is a Jira response body with the validators Jira sent
+type ValidatedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// ResponseStore keeps the last response of each endpoint that carried an ETag
+// or Last-Modified header. Stored responses are cache entries: they expire
+// and are invalidated by Jira writes together with the data read from them.
+type ResponseStore interface {
+	Response(endpoint string) (*ValidatedResponse, bool)
+	StoreResponse(endpoint string, resp *ValidatedResponse)
+	DeleteResponse(endpoint string)
+	Len() int
+}
+
+// conditionalReads holds the store of validated responses and counts the
+// requests revalidated against it
+type conditionalReads struct {
+	mu    sync.Mutex
+	store ResponseStore
+	stats ConditionalStats
+}
+
+func (v *conditionalReads) responseStore() ResponseStore {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.store
+}
+
+func (v *conditionalReads) record(notModified bool, saved int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.stats.Requests++
+	if notModified {
+		v.stats.NotModified++
+		v.stats.BytesSaved += int64(saved)
+	}
+}
+
+// SetResponseStore sets where validated responses are kept for conditional
+// revalidation; reads are not revalidated without a store
+func (c *Client) SetResponseStore(store ResponseStore) {
+	c.conditional.mu.Lock()
+	defer c.conditional.mu.Unlock()
+	c.conditional.store = store
+}
+
+// doValidatedGet performs a GET that revalidates the stored response of the
+// endpoint with If-None-Match and If-Modified-Since. On 304 Not Modified the
+// stored body is returned; body is nil when Jira answered with an error.
+func (c *Client) doValidatedGet(ctx context.Context, endpoint string) (body []byte, resp *resty.Response, err error) {
+	store := c.conditional.responseStore()
+
+	var stored *ValidatedResponse
+	var headers map[string]string
+	if store != nil {
+		if cached, ok := store.Response(endpoint); ok {
+			stored = cached
+			headers = make(map[string]string, 2)
+			if stored.ETag != "" {
+				headers["If-None-Match"] = stored.ETag
+			}
+			if stored.LastModified != "" {
+				headers["If-Modified-Since"] = stored.LastModified
+			}
+		}
+	}
+
+	resp, err = c.doRequestWithHeaders(ctx, "GET", endpoint, nil, headers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if stored != nil {
+		notModified := resp.StatusCode() == http.StatusNotModified
+		c.conditional.record(notModified, len(stored.Body))
+		if notModified {
+			return stored.Body, resp, nil
+		}
+	}
+
+	if !resp.IsSuccess() {
+		return nil, resp, nil
+	}
+
+	body = resp.Body()
+	if body == nil {
+		body = []byte{}
+	}
+	if store != nil {
+		etag := resp.Header().Get("ETag")
+		lastModified := resp.Header().Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			store.DeleteResponse(endpoint)
+		} else {
+			store.StoreResponse(endpoint, &ValidatedResponse{ETag: etag, LastModified: lastModified, Body: body})
+		}
+	}
+	return body, resp, nil
+}
+
+// GetConditionalStats returns how many Jira reads were revalidated and how
+// many of them were answered 304 Not Modified
+func (c *Client) GetConditionalStats() ConditionalStats {
+	c.conditional.mu.Lock()
+	defer c.conditional.mu.Unlock()
+	stats := c.conditional.stats
+	if c.conditional.store != nil {
+		stats.Entries = c.conditional.store.Len()
+	}
+	return stats
+}
@@ -0,0 +1,491 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CreateMeta describes the fields available when creating an issue of a given
+// type in a given project
+type CreateMeta struct {
+	ProjectKey string                     `json:"projectKey"`
+	IssueType  IssueType                  `json:"issueType"`
+	Fields     map[string]CreateMetaField `json:"fields"`
+}
+
+// CreateMetaField describes a single field from the create metadata
+type CreateMetaField struct {
+	FieldID         string              `json:"fieldId"`
+	Key             string              `json:"key,omitempty"`
+	Name            string              `json:"name"`
+	Required        bool                `json:"required"`
+	HasDefaultValue bool                `json:"hasDefaultValue"`
+	Schema          FieldSchema         `json:"schema"`
+	AllowedValues   []FieldAllowedValue `json:"allowedValues,omitempty"`
+	Operations      []string            `json:"operations,omitempty"`
+	AutoCompleteURL string              `json:"autoCompleteUrl,omitempty"`
+}
+
+// FieldAllowedValue represents one allowed value of an option-like field
+type FieldAllowedValue struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+	Key   string `json:"key,omitempty"`
+}
+
+// Label returns the human readable label of an allowed value
+func (v FieldAllowedValue) Label() string {
+	switch {
+	case v.Name != "":
+		return v.Name
+	case v.Value != "":
+		return v.Value
+	case v.Key != "":
+		return v.Key
+	}
+	return v.ID
+}
+
+// FieldIssue describes a missing or invalid field in an issue payload
+type FieldIssue struct {
+	Field         string      `json:"field"`
+	Name          string      `json:"name,omitempty"`
+	Code          string      `json:"code"` // missing, unknown_field, invalid_type, invalid_value
+	Message       string      `json:"message"`
+	Expected      string      `json:"expected,omitempty"`
+	AllowedValues []string    `json:"allowedValues,omitempty"`
+	Received      interface{} `json:"received,omitempty"`
+}
+
+// FieldValidationResult is the outcome of validating an issue payload against create metadata
+type FieldValidationResult struct {
+	Valid   bool                   `json:"valid"`
+	Missing []FieldIssue           `json:"missing,omitempty"`
+	Invalid []FieldIssue           `json:"invalid,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// UserFieldResolver converts a user reference into a Jira user field value
+type UserFieldResolver func(reference string) (map[string]interface{}, error)
+
+// Field issue codes
+const (
+	FieldIssueMissing      = "missing"
+	FieldIssueUnknownField = "unknown_field"
+	FieldIssueInvalidType  = "invalid_type"
+	FieldIssueInvalidValue = "invalid_value"
+)
+
+// jiraDateTimeLayout is the timestamp layout Jira expects for datetime fields
+const jiraDateTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// GetCreateMetaIssueTypes retrieves the issue types that can be created in a project
+func (c *Client) GetCreateMetaIssueTypes(projectKey string) ([]IssueType, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/createmeta/%s/issuetypes?maxResults=200", url.PathEscape(projectKey))
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get create metadata issue types: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	// Cloud returns "issueTypes", Data Center returns "values"
+	var result struct {
+		IssueTypes []IssueType `json:"issueTypes"`
+		Values     []IssueType `json:"values"`
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode create metadata issue types: %w", err)
+	}
+
+	if len(result.IssueTypes) > 0 {
+		return result.IssueTypes, nil
+	}
+	return result.Values, nil
+}
+
+// GetCreateMeta retrieves the create metadata for an issue type in a project.
+// The issue type can be given by ID or by name.
+func (c *Client) GetCreateMeta(projectKey, issueType string) (*CreateMeta, error) {
+	issueTypes, err := c.GetCreateMetaIssueTypes(projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected *IssueType
+	for i := range issueTypes {
+		if issueTypes[i].ID == issueType || strings.EqualFold(issueTypes[i].Name, issueType) {
+			selected = &issueTypes[i]
+			break
+		}
+	}
+	if selected == nil {
+		names := make([]string, len(issueTypes))
+		for i, it := range issueTypes {
+			names[i] = it.Name
+		}
+		return nil, fmt.Errorf("issue type '%s' is not available in project %s (available: %s)",
+			issueType, projectKey, strings.Join(names, ", "))
+	}
+
+	meta := &CreateMeta{
+		ProjectKey: projectKey,
+		IssueType:  *selected,
+		Fields:     make(map[string]CreateMetaField),
+	}
+
+	startAt := 0
+	for {
+		endpoint := fmt.Sprintf("/rest/api/2/issue/createmeta/%s/issuetypes/%s?startAt=%d&maxResults=100",
+			url.PathEscape(projectKey), url.PathEscape(selected.ID), startAt)
+
+		resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get create metadata fields: %w", err)
+		}
+
+		if err := c.handleErrorResponse(resp); err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Fields []CreateMetaField `json:"fields"`
+			Values []CreateMetaField `json:"values"`
+			Total  int               `json:"total"`
+			IsLast *bool             `json:"isLast"`
+		}
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return nil, fmt.Errorf("failed to decode create metadata fields: %w", err)
+		}
+
+		fields := page.Fields
+		if len(fields) == 0 {
+			fields = page.Values
+		}
+		for _, field := range fields {
+			if field.FieldID == "" {
+				field.FieldID = field.Key
+			}
+			meta.Fields[field.FieldID] = field
+		}
+
+		startAt += len(fields)
+		if len(fields) == 0 || (page.IsLast != nil && *page.IsLast) || (page.IsLast == nil && startAt >= page.Total) {
+			break
+		}
+	}
+
+	return meta, nil
+}
+
+// RequiredFields returns the required fields that have no default value
+func (m *CreateMeta) RequiredFields() []CreateMetaField {
+	var required []CreateMetaField
+	for _, field := range m.Fields {
+		if field.Required && !field.HasDefaultValue {
+			required = append(required, field)
+		}
+	}
+	sort.Slice(required, func(i, j int) bool { return required[i].FieldID < required[j].FieldID })
+	return required
+}
+
+// LookupField finds a field by ID, key or case-insensitive name
+func (m *CreateMeta) LookupField(nameOrID string) (CreateMetaField, bool) {
+	if field, ok := m.Fields[nameOrID]; ok {
+		return field, true
+	}
+	for _, field := range m.Fields {
+		if field.Key == nameOrID || strings.EqualFold(field.Name, nameOrID) {
+			return field, true
+		}
+	}
+	return CreateMetaField{}, false
+}
+
+// ValidateFields checks a create payload against the metadata and coerces
+// values into the shapes Jira expects: option names become ids, user
+// references are resolved through resolveUser, dates are normalised and
+// numbers parsed. Fields may be keyed by field ID or display name. The coerced
+// payload is returned in the result, keyed by field ID.
+func (m *CreateMeta) ValidateFields(fields map[string]interface{}, resolveUser UserFieldResolver) *FieldValidationResult {
+	result := &FieldValidationResult{
+		Fields: make(map[string]interface{}, len(fields)),
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := fields[key]
+
+		field, ok := m.LookupField(key)
+		if !ok {
+			result.Invalid = append(result.Invalid, FieldIssue{
+				Field:   key,
+				Code:    FieldIssueUnknownField,
+				Message: fmt.Sprintf("field '%s' is not available when creating a %s in %s", key, m.IssueType.Name, m.ProjectKey),
+			})
+			continue
+		}
+
+		// Project and issue type are fixed by the metadata itself
+		if field.FieldID == "project" || field.FieldID == "issuetype" {
+			result.Fields[field.FieldID] = value
+			continue
+		}
+
+		if value == nil {
+			if field.Required {
+				result.Missing = append(result.Missing, missingField(field))
+			}
+			continue
+		}
+
+		coerced, issue := coerceFieldValue(field, value, resolveUser)
+		if issue != nil {
+			result.Invalid = append(result.Invalid, *issue)
+			continue
+		}
+		result.Fields[field.FieldID] = coerced
+	}
+
+	for _, field := range m.RequiredFields() {
+		if field.FieldID == "project" || field.FieldID == "issuetype" {
+			continue
+		}
+		if _, provided := result.Fields[field.FieldID]; provided {
+			continue
+		}
+		if alreadyReported(result.Invalid, field.FieldID) || alreadyReported(result.Missing, field.FieldID) {
+			continue
+		}
+		result.Missing = append(result.Missing, missingField(field))
+	}
+
+	result.Valid = len(result.Missing) == 0 && len(result.Invalid) == 0
+	return result
+}
+
+func missingField(field CreateMetaField) FieldIssue {
+	issue := FieldIssue{
+		Field:    field.FieldID,
+		Name:     field.Name,
+		Code:     FieldIssueMissing,
+		Message:  fmt.Sprintf("%s is required", field.Name),
+		Expected: describeSchema(field.Schema),
+	}
+	for _, allowed := range field.AllowedValues {
+		issue.AllowedValues = append(issue.AllowedValues, allowed.Label())
+	}
+	return issue
+}
+
+func alreadyReported(issues []FieldIssue, fieldID string) bool {
+	for _, issue := range issues {
+		if issue.Field == fieldID {
+			return true
+		}
+	}
+	return false
+}
+
+func describeSchema(schema FieldSchema) string {
+	if schema.Type == "array" && schema.Items != "" {
+		return "array of " + schema.Items
+	}
+	return schema.Type
+}
+
+// coerceFieldValue converts a value to the representation required by the field schema
+func coerceFieldValue(field CreateMetaField, value interface{}, resolveUser UserFieldResolver) (interface{}, *FieldIssue) {
+	if field.Schema.Type == "array" {
+		items, ok := value.([]interface{})
+		if !ok {
+			switch v := value.(type) {
+			case []string:
+				for _, s := range v {
+					items = append(items, s)
+				}
+			case []map[string]interface{}:
+				for _, m := range v {
+					items = append(items, m)
+				}
+			default:
+				// Accept a single value for array fields
+				items = []interface{}{value}
+			}
+		}
+
+		itemSchema := FieldSchema{Type: field.Schema.Items, Custom: field.Schema.Custom}
+		coerced := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			itemField := field
+			itemField.Schema = itemSchema
+			converted, issue := coerceScalar(itemField, item, resolveUser)
+			if issue != nil {
+				return nil, issue
+			}
+			coerced = append(coerced, converted)
+		}
+		return coerced, nil
+	}
+
+	return coerceScalar(field, value, resolveUser)
+}
+
+func coerceScalar(field CreateMetaField, value interface{}, resolveUser UserFieldResolver) (interface{}, *FieldIssue) {
+	invalid := func(code, message string) *FieldIssue {
+		issue := &FieldIssue{
+			Field:    field.FieldID,
+			Name:     field.Name,
+			Code:     code,
+			Message:  message,
+			Expected: describeSchema(field.Schema),
+			Received: value,
+		}
+		for _, allowed := range field.AllowedValues {
+			issue.AllowedValues = append(issue.AllowedValues, allowed.Label())
+		}
+		return issue
+	}
+
+	switch field.Schema.Type {
+	case "string":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64, int, bool:
+			return fmt.Sprint(v), nil
+		case map[string]interface{}:
+			// Rich text (ADF) payloads are passed through untouched
+			if _, isDoc := v["type"]; isDoc {
+				return v, nil
+			}
+		}
+		return nil, invalid(FieldIssueInvalidType, fmt.Sprintf("%s must be text", field.Name))
+
+	case "number":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case string:
+			if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return n, nil
+			}
+		}
+		return nil, invalid(FieldIssueInvalidType, fmt.Sprintf("%s must be a number", field.Name))
+
+	case "date", "datetime":
+		s, ok := value.(string)
+		if !ok {
+			return nil, invalid(FieldIssueInvalidType, fmt.Sprintf("%s must be a date string", field.Name))
+		}
+		t, err := parseFlexibleDate(s)
+		if err != nil {
+			return nil, invalid(FieldIssueInvalidValue, fmt.Sprintf("%s: cannot parse date %q", field.Name, s))
+		}
+		if field.Schema.Type == "date" {
+			return t.Format("2006-01-02"), nil
+		}
+		return t.Format(jiraDateTimeLayout), nil
+
+	case "user":
+		switch v := value.(type) {
+		case map[string]interface{}:
+			return v, nil
+		case string:
+			if resolveUser == nil {
+				return map[string]interface{}{"accountId": v}, nil
+			}
+			resolved, err := resolveUser(v)
+			if err != nil {
+				return nil, invalid(FieldIssueInvalidValue, fmt.Sprintf("%s: %v", field.Name, err))
+			}
+			return resolved, nil
+		}
+		return nil, invalid(FieldIssueInvalidType, fmt.Sprintf("%s must be a user reference", field.Name))
+	}
+
+	if len(field.AllowedValues) > 0 {
+		return coerceAllowedValue(field, value, invalid)
+	}
+
+	// Unknown schema types (and option fields without allowed values) are passed through
+	return value, nil
+}
+
+// coerceAllowedValue maps a name, value, id or object onto one of the field's allowed values
+func coerceAllowedValue(field CreateMetaField, value interface{}, invalid func(code, message string) *FieldIssue) (interface{}, *FieldIssue) {
+	var candidate string
+	switch v := value.(type) {
+	case string:
+		candidate = v
+	case float64:
+		candidate = strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		for _, k := range []string{"id", "name", "value", "key"} {
+			if s, ok := v[k].(string); ok && s != "" {
+				candidate = s
+				break
+			}
+		}
+	}
+
+	if candidate == "" {
+		return nil, invalid(FieldIssueInvalidType, fmt.Sprintf("%s must be one of the allowed values", field.Name))
+	}
+
+	for _, allowed := range field.AllowedValues {
+		if allowed.ID == candidate ||
+			strings.EqualFold(allowed.Name, candidate) ||
+			strings.EqualFold(allowed.Value, candidate) ||
+			(allowed.Key != "" && strings.EqualFold(allowed.Key, candidate)) {
+			if allowed.ID != "" {
+				return map[string]interface{}{"id": allowed.ID}, nil
+			}
+			return map[string]interface{}{"name": allowed.Label()}, nil
+		}
+	}
+
+	return nil, invalid(FieldIssueInvalidValue, fmt.Sprintf("'%s' is not an allowed value for %s", candidate, field.Name))
+}
+
+// parseFlexibleDate parses the date formats commonly produced by people and tools
+func parseFlexibleDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	layouts := []string{
+		"2006-01-02",
+		jiraDateTimeLayout,
+		"2006-01-02T15:04:05-0700",
+		time.RFC3339Nano,
+		time.RFC3339,
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+		"2006/01/02",
+		"02 Jan 2006",
+		"Jan 2, 2006",
+		"January 2, 2006",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date format")
+}
@@ -108,43 +108,26 @@ func (c *Client) GetFieldConfiguration(projectKey string, issueType string) (*Fi
 	return &result, nil
 }
 
-// ValidateCustomFields validates custom field values before submission
+// ValidateCustomFields validates custom field values before submission against
+// the create metadata of the project and issue type
 func (c *Client) ValidateCustomFields(projectKey string, issueType string, customFields map[string]interface{}) error {
-	config, err := c.GetFieldConfiguration(projectKey, issueType)
+	meta, err := c.GetCreateMeta(projectKey, issueType)
 	if err != nil {
 		return err
 	}
 
-	for fieldID, value := range customFields {
-		if fieldConfig, exists := config.Fields[fieldID]; exists {
-			if fieldConfig.Required && value == nil {
-				return fmt.Errorf("custom field '%s' (%s) is required", fieldConfig.Name, fieldID)
-			}
-			
-			// Additional validation can be added here based on field type
-			if len(fieldConfig.AllowedValues) > 0 {
-				// Check if value is in allowed values
-				valid := false
-				for _, allowed := range fieldConfig.AllowedValues {
-					if value == allowed {
-						valid = true
-						break
-					}
-				}
-				if !valid {
-					return fmt.Errorf("value '%v' is not allowed for field '%s'", value, fieldConfig.Name)
-				}
-			}
+	result := meta.ValidateFields(customFields, nil)
+
+	var problems []string
+	for _, issue := range append(result.Invalid, result.Missing...) {
+		if !strings.HasPrefix(issue.Field, "customfield_") {
+			continue
 		}
+		problems = append(problems, fmt.Sprintf("%s (%s): %s", issue.Field, issue.Code, issue.Message))
 	}
 
-	// Check for required fields that are missing
-	for fieldID, fieldConfig := range config.Fields {
-		if fieldConfig.Required && strings.HasPrefix(fieldID, "customfield_") {
-			if _, exists := customFields[fieldID]; !exists {
-				return fmt.Errorf("required custom field '%s' (%s) is missing", fieldConfig.Name, fieldID)
-			}
-		}
+	if len(problems) > 0 {
+		return fmt.Errorf("custom field validation failed: %s", strings.Join(problems, "; "))
 	}
 
 	return nil
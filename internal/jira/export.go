@@ -16,6 +16,8 @@ const (
 	FormatJSON     ExportFormat = "json"
 	FormatCSV      ExportFormat = "csv" 
 	FormatMarkdown ExportFormat = "markdown"
+	FormatXLSX     ExportFormat = "xlsx"
+	FormatHTML     ExportFormat = "html"
 )
 
 // ExportRequest represents a request to export search results
@@ -24,6 +26,7 @@ type ExportRequest struct {
 	Fields             []string     `json:"fields,omitempty"`
 	IncludeComments    bool         `json:"includeComments,omitempty"`
 	IncludeAttachments bool         `json:"includeAttachments,omitempty"`
+	GroupBy            string       `json:"groupBy,omitempty"` // field whose values split XLSX sheets and HTML tables
 }
 
 // ExportResult represents the result of an export operation
@@ -43,6 +46,8 @@ func (c *Client) ExportSearchResults(result *ExtendedSearchResult, req ExportReq
 		return c.exportCSV(result, req)
 	case FormatMarkdown:
 		return c.exportMarkdown(result, req)
+	case FormatXLSX, FormatHTML:
+		return RenderExport(c.searchExportDocument(result, req), req.Format, "jira_search_results")
 	default:
 		return nil, fmt.Errorf("unsupported export format: %s", req.Format)
 	}
@@ -265,7 +270,7 @@ func (c *Client) getFieldValue(issue Issue, field string) string {
 
 // GetSupportedExportFormats returns all supported export formats
 func GetSupportedExportFormats() []ExportFormat {
-	return []ExportFormat{FormatJSON, FormatCSV, FormatMarkdown}
+	return []ExportFormat{FormatJSON, FormatCSV, FormatMarkdown, FormatXLSX, FormatHTML}
 }
 
 // ValidateExportRequest validates an export request
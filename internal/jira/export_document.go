@@ -0,0 +1,183 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportTable is a titled table of typed cells. Cells may be strings, numbers,
+// booleans, time.Time or nil; each table becomes one XLSX sheet or one HTML table.
+type ExportTable struct {
+	Title  string
+	Header []string
+	Rows   [][]interface{}
+}
+
+// ExportDocument is a format-independent report rendered by the XLSX and HTML writers
+type ExportDocument struct {
+	Title    string
+	Subtitle string
+	Tables   []ExportTable
+}
+
+// RenderExport renders a document as an XLSX workbook or a self-contained HTML
+// page, naming the file after name and the current time
+func RenderExport(doc *ExportDocument, format ExportFormat, name string) (*ExportResult, error) {
+	var (
+		data        []byte
+		err         error
+		contentType string
+		extension   string
+	)
+
+	switch format {
+	case FormatXLSX:
+		data, err = WriteXLSX(doc)
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		extension = "xlsx"
+	case FormatHTML:
+		data, err = WriteHTML(doc)
+		contentType = "text/html; charset=utf-8"
+		extension = "html"
+	default:
+		return nil, fmt.Errorf("unsupported document export format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportResult{
+		ContentType: contentType,
+		Filename:    fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102_150405"), extension),
+		Data:        data,
+		Size:        len(data),
+	}, nil
+}
+
+// searchExportDocument lays out search results as one table, or one table per
+// distinct value of req.GroupBy
+func (c *Client) searchExportDocument(result *ExtendedSearchResult, req ExportRequest) *ExportDocument {
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = []string{"key", "summary", "status", "assignee", "priority", "created", "updated"}
+	}
+
+	doc := &ExportDocument{
+		Title:    "Jira Search Results",
+		Subtitle: fmt.Sprintf("%d issues, exported %s", result.Total, time.Now().Format("January 2, 2006 at 3:04 PM")),
+	}
+
+	if req.GroupBy == "" {
+		table := ExportTable{Title: "Issues", Header: fields}
+		for _, issue := range result.Issues {
+			table.Rows = append(table.Rows, c.exportRow(issue, fields))
+		}
+		doc.Tables = append(doc.Tables, table)
+		return doc
+	}
+
+	groups := make(map[string]*ExportTable)
+	var order []string
+	for _, issue := range result.Issues {
+		group := c.getFieldValue(issue, req.GroupBy)
+		if group == "" {
+			group = "None"
+		}
+		table, ok := groups[group]
+		if !ok {
+			table = &ExportTable{Title: group, Header: fields}
+			groups[group] = table
+			order = append(order, group)
+		}
+		table.Rows = append(table.Rows, c.exportRow(issue, fields))
+	}
+
+	sort.Strings(order)
+	for _, group := range order {
+		doc.Tables = append(doc.Tables, *groups[group])
+	}
+	if len(doc.Tables) == 0 {
+		doc.Tables = append(doc.Tables, ExportTable{Title: "Issues", Header: fields})
+	}
+	return doc
+}
+
+// exportRow returns the typed cells of an issue: dates stay time.Time so that
+// spreadsheets can sort and filter them
+func (c *Client) exportRow(issue Issue, fields []string) []interface{} {
+	row := make([]interface{}, len(fields))
+	for i, field := range fields {
+		switch field {
+		case "created":
+			if issue.Fields.Created != nil {
+				row[i] = issue.Fields.Created.Time
+			}
+		case "updated":
+			if issue.Fields.Updated != nil {
+				row[i] = issue.Fields.Updated.Time
+			}
+		case "resolved":
+			if issue.Fields.Resolved != nil {
+				row[i] = issue.Fields.Resolved.Time
+			}
+		default:
+			row[i] = c.getFieldValue(issue, field)
+		}
+	}
+	return row
+}
+
+// SprintReportDocument lays out a sprint report as a summary, the burndown and
+// one table per issue group
+func SprintReportDocument(report *SprintReport) *ExportDocument {
+	doc := &ExportDocument{
+		Title:    "Sprint Report: " + report.Sprint.Name,
+		Subtitle: fmt.Sprintf("%s to %s", report.StartDate.Format("January 2, 2006"), report.EndDate.Format("January 2, 2006")),
+	}
+
+	doc.Tables = append(doc.Tables, ExportTable{
+		Title:  "Summary",
+		Header: []string{"Metric", "Value"},
+		Rows: [][]interface{}{
+			{"Sprint", report.Sprint.Name},
+			{"State", report.Sprint.State},
+			{"Goal", report.Sprint.Goal},
+			{"Start", report.StartDate},
+			{"End", report.EndDate},
+			{"Complete", report.IsComplete},
+			{"Committed points", report.Velocity.Committed},
+			{"Completed points", report.Velocity.Completed},
+			{"Committed issues", report.Velocity.CommittedIssues},
+			{"Completed issues", report.Velocity.CompletedIssues},
+			{"Incomplete issues", report.Velocity.IncompleteIssues},
+		},
+	})
+
+	burndown := ExportTable{Title: "Burndown", Header: []string{"Date", "Story Points", "Issues", "Ideal"}}
+	for _, point := range report.Burndown {
+		burndown.Rows = append(burndown.Rows, []interface{}{point.Date, point.StoryPoints, point.IssueCount, point.IdealProgress})
+	}
+	doc.Tables = append(doc.Tables, burndown)
+
+	groups := make([]string, 0, len(report.Issues))
+	for group := range report.Issues {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		doc.Tables = append(doc.Tables, SprintIssueTable(strings.Title(group), report.Issues[group]))
+	}
+
+	return doc
+}
+
+// SprintIssueTable lists sprint issues with their status
+func SprintIssueTable(title string, issues []SprintIssue) ExportTable {
+	table := ExportTable{Title: title, Header: []string{"Key", "Summary", "Status"}}
+	for _, issue := range issues {
+		table.Rows = append(table.Rows, []interface{}{issue.Key, issue.Fields.Summary, issue.Fields.Status.Name})
+	}
+	return table
+}
@@ -0,0 +1,134 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+)
+
+// htmlExportTemplate is a self-contained page: styles and the table sorting
+// script are inlined so the file can be attached to an email as is
+var htmlExportTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #172b4d; margin: 24px; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+h2 { font-size: 17px; margin-top: 28px; }
+.subtitle { color: #5e6c84; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; font-size: 14px; }
+th { background: #0052cc; color: #fff; text-align: left; padding: 6px 10px; cursor: pointer; user-select: none; white-space: nowrap; }
+th[aria-sort="ascending"]::after { content: " \25B2"; }
+th[aria-sort="descending"]::after { content: " \25BC"; }
+td { border-bottom: 1px solid #dfe1e6; padding: 6px 10px; vertical-align: top; }
+td.num { text-align: right; }
+tr:nth-child(even) td { background: #f4f5f7; }
+footer { color: #5e6c84; font-size: 12px; margin-top: 28px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Subtitle}}<p class="subtitle">{{.Subtitle}}</p>{{end}}
+{{range .Tables}}
+<h2>{{.Title}} ({{len .Rows}})</h2>
+<table class="sortable">
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td{{if .Numeric}} class="num"{{end}}{{if .Sort}} data-sort="{{.Sort}}"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{end}}
+<footer>Generated by GoJira on {{.Generated}}</footer>
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, col) {
+    th.addEventListener("click", function () {
+      var asc = th.getAttribute("aria-sort") !== "ascending";
+      table.querySelectorAll("th").forEach(function (h) { h.removeAttribute("aria-sort"); });
+      th.setAttribute("aria-sort", asc ? "ascending" : "descending");
+      var body = table.tBodies[0];
+      var key = function (row) {
+        var cell = row.cells[col];
+        if (!cell) return "";
+        return cell.hasAttribute("data-sort") ? cell.getAttribute("data-sort") : cell.textContent;
+      };
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = key(a), y = key(b), nx = parseFloat(x), ny = parseFloat(y);
+        var cmp = (!isNaN(nx) && !isNaN(ny) && isFinite(x) && isFinite(y)) ? nx - ny : x.localeCompare(y, undefined, { numeric: true });
+        return asc ? cmp : -cmp;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// htmlExportCell is a rendered cell with an optional machine-sortable key
+type htmlExportCell struct {
+	Text    string
+	Sort    string
+	Numeric bool
+}
+
+// WriteHTML renders a document as a self-contained HTML page with one sortable
+// table per document table; clicking a header sorts by that column
+func WriteHTML(doc *ExportDocument) ([]byte, error) {
+	type htmlTable struct {
+		Title  string
+		Header []string
+		Rows   [][]htmlExportCell
+	}
+
+	data := struct {
+		Title     string
+		Subtitle  string
+		Tables    []htmlTable
+		Generated string
+	}{
+		Title:     doc.Title,
+		Subtitle:  doc.Subtitle,
+		Generated: time.Now().Format("January 2, 2006 at 3:04 PM"),
+	}
+
+	for _, table := range doc.Tables {
+		rendered := htmlTable{Title: table.Title, Header: table.Header}
+		for _, row := range table.Rows {
+			cells := make([]htmlExportCell, len(row))
+			for i, value := range row {
+				cells[i] = htmlCell(value)
+			}
+			rendered.Rows = append(rendered.Rows, cells)
+		}
+		data.Tables = append(data.Tables, rendered)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlExportTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render HTML export: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// htmlCell renders a typed value; dates sort by their RFC 3339 form
+func htmlCell(value interface{}) htmlExportCell {
+	cell := htmlExportCell{Text: exportCellText(value)}
+	switch v := value.(type) {
+	case time.Time:
+		if !v.IsZero() {
+			cell.Sort = v.Format(time.RFC3339)
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		cell.Numeric = true
+	case bool:
+		cell.Sort = strconv.FormatBool(v)
+	}
+	return cell
+}
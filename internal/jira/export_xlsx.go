@@ -0,0 +1,282 @@
+package jira
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Cell styles defined in xlsxStyles, by index into cellXfs
+const (
+	xlsxStyleDefault = 0
+	xlsxStyleHeader  = 1
+	xlsxStyleDate    = 2
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="11"/><color rgb="FFFFFFFF"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FF0052CC"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+// xlsxEpoch is day zero of the 1900 date system as used by spreadsheet applications
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// WriteXLSX renders a document as a minimal OOXML workbook with one sheet per
+// table. Header rows are styled and frozen, numbers and booleans are stored as
+// typed cells and dates as date-formatted serial numbers.
+func WriteXLSX(doc *ExportDocument) ([]byte, error) {
+	tables := doc.Tables
+	if len(tables) == 0 {
+		tables = []ExportTable{{Title: "Sheet1"}}
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	names := xlsxSheetNames(tables)
+	var overrides, sheets, rels strings.Builder
+	for i := range tables {
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxEscape(names[i]), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(tables)+1)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels.String() + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, table := range tables {
+		parts = append(parts, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxSheet(table)})
+	}
+
+	for _, part := range parts {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create XLSX part %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(fw, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write XLSX part %s: %w", part.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish XLSX archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// xlsxSheet renders the worksheet XML of a table
+func xlsxSheet(table ExportTable) string {
+	columns := len(table.Header)
+	for _, row := range table.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	// Column widths follow the longest rendered value, within sensible bounds
+	widths := make([]int, columns)
+	for i, title := range table.Header {
+		widths[i] = utf8.RuneCountInString(title)
+	}
+	for _, row := range table.Rows {
+		for i, value := range row {
+			if n := utf8.RuneCountInString(exportCellText(value)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(table.Header) > 0 {
+		sb.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	if columns > 0 {
+		sb.WriteString(`<cols>`)
+		for i, width := range widths {
+			width = min(max(width+2, 8), 80)
+			fmt.Fprintf(&sb, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		sb.WriteString(`</cols>`)
+	}
+
+	sb.WriteString(`<sheetData>`)
+	rowNum := 0
+	if len(table.Header) > 0 {
+		rowNum++
+		fmt.Fprintf(&sb, `<row r="%d">`, rowNum)
+		for i, title := range table.Header {
+			sb.WriteString(xlsxCell(i, rowNum, title, xlsxStyleHeader))
+		}
+		sb.WriteString(`</row>`)
+	}
+	for _, row := range table.Rows {
+		rowNum++
+		fmt.Fprintf(&sb, `<row r="%d">`, rowNum)
+		for i, value := range row {
+			sb.WriteString(xlsxCell(i, rowNum, value, xlsxStyleDefault))
+		}
+		sb.WriteString(`</row>`)
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	return sb.String()
+}
+
+// xlsxCell renders one typed cell; empty values produce no cell at all
+func xlsxCell(col, row int, value interface{}, style int) string {
+	ref := xlsxColumn(col) + strconv.Itoa(row)
+	styleAttr := ""
+	if style != xlsxStyleDefault {
+		styleAttr = fmt.Sprintf(` s="%d"`, style)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if v == "" && style == xlsxStyleDefault {
+			return ""
+		}
+		return fmt.Sprintf(`<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleAttr, xlsxEscape(v))
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		return fmt.Sprintf(`<c r="%s"%s t="b"><v>%d</v></c>`, ref, styleAttr, b)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf(`<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+	case float32:
+		return fmt.Sprintf(`<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case float64:
+		return fmt.Sprintf(`<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64))
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		if style == xlsxStyleDefault {
+			styleAttr = fmt.Sprintf(` s="%d"`, xlsxStyleDate)
+		}
+		// Serial dates carry no zone, so the wall clock time is stored as shown
+		wall := time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), 0, time.UTC)
+		serial := wall.Sub(xlsxEpoch).Hours() / 24
+		return fmt.Sprintf(`<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(serial, 'f', -1, 64))
+	default:
+		return xlsxCell(col, row, exportCellText(v), style)
+	}
+}
+
+// xlsxColumn converts a zero-based column index to its letter name (0 = A, 26 = AA)
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetNames returns valid, unique sheet names: at most 31 characters and
+// none of the characters spreadsheet applications reject
+func xlsxSheetNames(tables []ExportTable) []string {
+	replacer := strings.NewReplacer("[", "(", "]", ")", ":", "-", "*", "-", "?", "", "/", "-", "\\", "-")
+	used := make(map[string]bool, len(tables))
+	names := make([]string, len(tables))
+
+	for i, table := range tables {
+		base := strings.Trim(replacer.Replace(table.Title), "' ")
+		if base == "" {
+			base = fmt.Sprintf("Sheet%d", i+1)
+		}
+		base = truncateRunes(base, 31)
+
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncateRunes(base, 31-len(suffix)) + suffix
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// xlsxEscape escapes text for XML, replacing characters XML cannot carry
+func xlsxEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// exportCellText renders a cell value as text, as shown by the HTML export
+func exportCellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
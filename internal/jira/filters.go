@@ -0,0 +1,329 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Share permission types accepted by Jira
+const (
+	ShareGlobal        = "global"
+	ShareAuthenticated = "authenticated" // any logged-in user (Cloud)
+	ShareLoggedIn      = "loggedin"      // any logged-in user (Server/Data Center)
+	ShareProject       = "project"
+	ShareProjectRole   = "projectRole"
+	ShareGroup         = "group"
+	ShareUser          = "user"
+)
+
+// GroupRef identifies a Jira group
+type GroupRef struct {
+	Name    string `json:"name"`
+	GroupID string `json:"groupId,omitempty"`
+	Self    string `json:"self,omitempty"`
+}
+
+// FilterRequest represents a request to create or update a saved filter
+type FilterRequest struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	JQL         string                   `json:"jql,omitempty"`
+	Favourite   *bool                    `json:"favourite,omitempty"`
+	Share       []SharePermissionRequest `json:"-"` // only applied on create
+}
+
+// SharePermissionRequest grants a filter to an audience. Projects and roles
+// are referenced by ID; users by account ID on Cloud.
+type SharePermissionRequest struct {
+	Type          string `json:"type"`
+	ProjectID     string `json:"projectId,omitempty"`
+	ProjectRoleID string `json:"projectRoleId,omitempty"`
+	GroupName     string `json:"groupname,omitempty"`
+	AccountID     string `json:"accountId,omitempty"`
+}
+
+// FilterColumn represents a column of a filter's issue navigator view
+type FilterColumn struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// FilterSubscription represents a scheduled email subscription to a filter
+type FilterSubscription struct {
+	ID    int       `json:"id"`
+	User  *User     `json:"user,omitempty"`
+	Group *GroupRef `json:"group,omitempty"`
+}
+
+// permission converts the request into the share permission shape used in
+// filter bodies, which nests references instead of flattening them
+func (r SharePermissionRequest) permission() map[string]interface{} {
+	permission := map[string]interface{}{"type": r.Type}
+	if r.ProjectID != "" {
+		permission["project"] = map[string]interface{}{"id": r.ProjectID}
+	}
+	if r.ProjectRoleID != "" {
+		permission["role"] = map[string]interface{}{"id": r.ProjectRoleID}
+	}
+	if r.GroupName != "" {
+		permission["group"] = map[string]interface{}{"name": r.GroupName}
+	}
+	if r.AccountID != "" {
+		permission["user"] = map[string]interface{}{"accountId": r.AccountID}
+	}
+	return permission
+}
+
+// body builds the filter body; share permissions are only sent when creating
+func (r *FilterRequest) body(withShare bool) map[string]interface{} {
+	body := map[string]interface{}{"name": r.Name}
+	if r.Description != "" {
+		body["description"] = r.Description
+	}
+	if r.JQL != "" {
+		body["jql"] = r.JQL
+	}
+	if r.Favourite != nil {
+		body["favourite"] = *r.Favourite
+	}
+	if withShare && len(r.Share) > 0 {
+		permissions := make([]map[string]interface{}, len(r.Share))
+		for i, share := range r.Share {
+			permissions[i] = share.permission()
+		}
+		body["sharePermissions"] = permissions
+	}
+	return body
+}
+
+// CreateFilter saves a new filter owned by the current user
+func (c *Client) CreateFilter(req *FilterRequest) (*SearchFilter, error) {
+	resp, err := c.doRequest(context.Background(), "POST", "/rest/api/2/filter", req.body(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filter: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var filter SearchFilter
+	if err := json.Unmarshal(resp.Body(), &filter); err != nil {
+		return nil, fmt.Errorf("failed to decode filter: %w", err)
+	}
+
+	return &filter, nil
+}
+
+// UpdateFilter updates the name, description, JQL or favourite flag of a
+// filter. Jira requires the name on every update.
+func (c *Client) UpdateFilter(filterID string, req *FilterRequest) (*SearchFilter, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s", filterID)
+
+	resp, err := c.doRequest(context.Background(), "PUT", endpoint, req.body(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update filter %s: %w", filterID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var filter SearchFilter
+	if err := json.Unmarshal(resp.Body(), &filter); err != nil {
+		return nil, fmt.Errorf("failed to decode filter: %w", err)
+	}
+
+	return &filter, nil
+}
+
+// DeleteFilter deletes a filter
+func (c *Client) DeleteFilter(filterID string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s", filterID)
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete filter %s: %w", filterID, err)
+	}
+
+	return c.handleErrorResponse(resp)
+}
+
+// GetFavouriteFilters retrieves the filters the current user has marked as favourite
+func (c *Client) GetFavouriteFilters() ([]SearchFilter, error) {
+	resp, err := c.doRequest(context.Background(), "GET", "/rest/api/2/filter/favourite", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favourite filters: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var filters []SearchFilter
+	if err := json.Unmarshal(resp.Body(), &filters); err != nil {
+		return nil, fmt.Errorf("failed to decode filters: %w", err)
+	}
+
+	return filters, nil
+}
+
+// SetFilterFavourite adds a filter to, or removes it from, the current user's favourites
+func (c *Client) SetFilterFavourite(filterID string, favourite bool) (*SearchFilter, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/favourite", filterID)
+	method := "PUT"
+	if !favourite {
+		method = "DELETE"
+	}
+
+	resp, err := c.doRequest(context.Background(), method, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update favourite of filter %s: %w", filterID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var filter SearchFilter
+	if err := json.Unmarshal(resp.Body(), &filter); err != nil {
+		return nil, fmt.Errorf("failed to decode filter: %w", err)
+	}
+
+	return &filter, nil
+}
+
+// GetFilterSharePermissions retrieves the share permissions of a filter
+func (c *Client) GetFilterSharePermissions(filterID string) ([]SharePermission, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/permission", filterID)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share permissions of filter %s: %w", filterID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var permissions []SharePermission
+	if err := json.Unmarshal(resp.Body(), &permissions); err != nil {
+		return nil, fmt.Errorf("failed to decode share permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// AddFilterSharePermission shares a filter and returns all of its share permissions
+func (c *Client) AddFilterSharePermission(filterID string, req SharePermissionRequest) ([]SharePermission, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/permission", filterID)
+
+	resp, err := c.doRequest(context.Background(), "POST", endpoint, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to share filter %s: %w", filterID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var permissions []SharePermission
+	if err := json.Unmarshal(resp.Body(), &permissions); err != nil {
+		return nil, fmt.Errorf("failed to decode share permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// DeleteFilterSharePermission removes a share permission from a filter
+func (c *Client) DeleteFilterSharePermission(filterID string, permissionID int) error {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/permission/%d", filterID, permissionID)
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete share permission %d of filter %s: %w", permissionID, filterID, err)
+	}
+
+	return c.handleErrorResponse(resp)
+}
+
+// GetFilterColumns retrieves the columns shown when the filter is opened in
+// Jira. A filter without its own configuration returns the user's defaults.
+func (c *Client) GetFilterColumns(filterID string) ([]FilterColumn, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/columns", filterID)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns of filter %s: %w", filterID, err)
+	}
+
+	if resp.StatusCode() == http.StatusNoContent {
+		return []FilterColumn{}, nil
+	}
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var columns []FilterColumn
+	if err := json.Unmarshal(resp.Body(), &columns); err != nil {
+		return nil, fmt.Errorf("failed to decode filter columns: %w", err)
+	}
+
+	return columns, nil
+}
+
+// SetFilterColumns sets the columns of a filter, given as field IDs in display order
+func (c *Client) SetFilterColumns(filterID string, columns []string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/columns", filterID)
+
+	resp, err := c.doRequest(context.Background(), "PUT", endpoint, columns)
+	if err != nil {
+		return fmt.Errorf("failed to set columns of filter %s: %w", filterID, err)
+	}
+
+	return c.handleErrorResponse(resp)
+}
+
+// ResetFilterColumns resets the columns of a filter to the user's defaults
+func (c *Client) ResetFilterColumns(filterID string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s/columns", filterID)
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to reset columns of filter %s: %w", filterID, err)
+	}
+
+	return c.handleErrorResponse(resp)
+}
+
+// GetFilterSubscriptions retrieves the email subscriptions of a filter. Jira
+// does not offer a REST API to create subscriptions; they are managed in its UI.
+func (c *Client) GetFilterSubscriptions(filterID string) ([]FilterSubscription, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/filter/%s?expand=subscriptions", filterID)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions of filter %s: %w", filterID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var filter struct {
+		Subscriptions struct {
+			Size  int                  `json:"size"`
+			Items []FilterSubscription `json:"items"`
+		} `json:"subscriptions"`
+	}
+	if err := json.Unmarshal(resp.Body(), &filter); err != nil {
+		return nil, fmt.Errorf("failed to decode filter subscriptions: %w", err)
+	}
+
+	if filter.Subscriptions.Items == nil {
+		return []FilterSubscription{}, nil
+	}
+	return filter.Subscriptions.Items, nil
+}
@@ -0,0 +1,477 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ericfisherdev/GoJira/internal/jql"
+)
+
+// HierarchyFields identifies the instance specific fields used for issue hierarchy
+type HierarchyFields struct {
+	EpicLinkField    string `json:"epicLinkField,omitempty"`
+	StoryPointsField string `json:"storyPointsField,omitempty"`
+}
+
+// SubtaskRequest represents a request to create a subtask
+type SubtaskRequest struct {
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description,omitempty"`
+	IssueType   string                 `json:"issueType,omitempty"` // defaults to the project's subtask type
+	Assignee    map[string]interface{} `json:"assignee,omitempty"`
+	Priority    string                 `json:"priority,omitempty"`
+	Labels      []string               `json:"labels,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+}
+
+// HierarchyNode is an issue together with its children
+type HierarchyNode struct {
+	Key            string           `json:"key"`
+	ID             string           `json:"id,omitempty"`
+	Summary        string           `json:"summary"`
+	IssueType      string           `json:"issueType"`
+	Status         string           `json:"status,omitempty"`
+	StatusCategory string           `json:"statusCategory,omitempty"`
+	Assignee       string           `json:"assignee,omitempty"`
+	StoryPoints    *float64         `json:"storyPoints,omitempty"`
+	ParentKey      string           `json:"parentKey,omitempty"`
+	Children       []*HierarchyNode `json:"children,omitempty"`
+
+	epicKey string // value of the Epic Link field
+}
+
+// HierarchyRollup aggregates progress over all descendants of an issue
+type HierarchyRollup struct {
+	Total                int                `json:"total"`
+	Done                 int                `json:"done"`
+	ByStatus             map[string]int     `json:"byStatus"`
+	ByStatusCategory     map[string]int     `json:"byStatusCategory"`
+	ByIssueType          map[string]int     `json:"byIssueType"`
+	StoryPoints          float64            `json:"storyPoints"`
+	CompletedStoryPoints float64            `json:"completedStoryPoints"`
+	Unestimated          int                `json:"unestimated"`
+	PercentDone          float64            `json:"percentDone"`
+	PercentDoneBy        string             `json:"percentDoneBy"` // storyPoints or count
+	PointsByStatus       map[string]float64 `json:"pointsByStatus,omitempty"`
+}
+
+const (
+	epicLinkSchema        = "com.pyxis.greenhopper.jira:gh-epic-link"
+	maxHierarchyDepth     = 5
+	hierarchySearchPage   = 100
+	statusCategoryDoneKey = "done"
+)
+
+// maxParentsPerSearch bounds the length of the JQL searching the children of
+// a hierarchy level
+const maxParentsPerSearch = 50
+
+// storyPointsFieldNames are the names Jira uses for the story points field
+var storyPointsFieldNames = []string{"story points", "story point estimate"}
+
+// GetHierarchyFields discovers the Epic Link and story points fields of the instance
+func (c *Client) GetHierarchyFields() (*HierarchyFields, error) {
+	fields, err := c.GetCustomFields("")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HierarchyFields{}
+	for _, field := range fields {
+		if field.Schema.Custom == epicLinkSchema || strings.EqualFold(field.Name, "Epic Link") {
+			if result.EpicLinkField == "" {
+				result.EpicLinkField = field.ID
+			}
+			continue
+		}
+		for _, name := range storyPointsFieldNames {
+			// Prefer "Story Points" over "Story point estimate" when both exist
+			if strings.EqualFold(field.Name, name) && (result.StoryPointsField == "" || name == storyPointsFieldNames[0]) {
+				result.StoryPointsField = field.ID
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// CreateSubtask creates a subtask under the given parent issue
+func (c *Client) CreateSubtask(parentKey string, req SubtaskRequest) (*Issue, error) {
+	parent, err := c.GetIssue(context.Background(), parentKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent issue %s: %w", parentKey, err)
+	}
+	if parent.Fields.IssueType.Subtask {
+		return nil, fmt.Errorf("cannot create a subtask under %s: it is already a subtask", parentKey)
+	}
+
+	projectKey := parent.Fields.Project.Key
+	issueType := map[string]interface{}{"name": req.IssueType}
+	if req.IssueType == "" {
+		issueTypes, err := c.GetCreateMetaIssueTypes(projectKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range issueTypes {
+			if it.Subtask {
+				issueType = map[string]interface{}{"id": it.ID}
+				break
+			}
+		}
+		if _, ok := issueType["id"]; !ok {
+			return nil, fmt.Errorf("project %s has no subtask issue type", projectKey)
+		}
+	}
+
+	fields := map[string]interface{}{
+		"project":   map[string]interface{}{"key": projectKey},
+		"parent":    map[string]interface{}{"key": parent.Key},
+		"summary":   req.Summary,
+		"issuetype": issueType,
+	}
+	if req.Description != "" {
+		fields["description"] = req.Description
+	}
+	if req.Priority != "" {
+		fields["priority"] = map[string]interface{}{"name": req.Priority}
+	}
+	if req.Assignee != nil {
+		fields["assignee"] = req.Assignee
+	}
+	if len(req.Labels) > 0 {
+		fields["labels"] = req.Labels
+	}
+	for k, v := range req.Fields {
+		fields[k] = v
+	}
+
+	return c.CreateIssue(context.Background(), &CreateIssueRequest{Fields: fields})
+}
+
+// SetParent sets or changes the parent of an issue. The parent field is tried
+// first; when it is rejected and the parent is an epic, the legacy Epic Link
+// field is used instead. An empty parentKey removes the parent.
+func (c *Client) SetParent(issueKey, parentKey string, hf *HierarchyFields) error {
+	if parentKey == "" {
+		return c.clearParent(issueKey, hf)
+	}
+
+	parent, err := c.GetIssue(context.Background(), parentKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get parent issue %s: %w", parentKey, err)
+	}
+
+	err = c.UpdateIssue(context.Background(), issueKey, &UpdateIssueRequest{
+		Fields: map[string]interface{}{
+			"parent": map[string]interface{}{"key": parent.Key},
+		},
+	})
+	if err == nil {
+		return nil
+	}
+
+	if hf == nil || hf.EpicLinkField == "" || !strings.EqualFold(parent.Fields.IssueType.Name, "Epic") {
+		return fmt.Errorf("failed to set parent of %s to %s: %w", issueKey, parentKey, err)
+	}
+
+	if epicErr := c.UpdateIssue(context.Background(), issueKey, &UpdateIssueRequest{
+		Fields: map[string]interface{}{
+			hf.EpicLinkField: parent.Key,
+		},
+	}); epicErr != nil {
+		return fmt.Errorf("failed to set epic of %s to %s: %w", issueKey, parentKey, epicErr)
+	}
+
+	return nil
+}
+
+func (c *Client) clearParent(issueKey string, hf *HierarchyFields) error {
+	err := c.UpdateIssue(context.Background(), issueKey, &UpdateIssueRequest{
+		Fields: map[string]interface{}{"parent": nil},
+	})
+	if err == nil || hf == nil || hf.EpicLinkField == "" {
+		return err
+	}
+
+	if epicErr := c.UpdateIssue(context.Background(), issueKey, &UpdateIssueRequest{
+		Fields: map[string]interface{}{hf.EpicLinkField: nil},
+	}); epicErr != nil {
+		return fmt.Errorf("failed to remove parent of %s: %w", issueKey, epicErr)
+	}
+
+	return nil
+}
+
+// GetChildren returns the direct children of an issue: issues whose parent is
+// the issue, issues linked to it through Epic Link, and its subtasks
+func (c *Client) GetChildren(issueKey string, hf *HierarchyFields) ([]*HierarchyNode, error) {
+	children, err := c.searchChildren([]string{issueKey}, hf)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		child.ParentKey = issueKey
+	}
+	return children, nil
+}
+
+// searchChildren returns the direct children of a set of issues in rank order,
+// with a single search. The parent of each child is the issue named by its
+// parent field, or else by its Epic Link field.
+func (c *Client) searchChildren(parentKeys []string, hf *HierarchyFields) ([]*HierarchyNode, error) {
+	keys := make([]interface{}, len(parentKeys))
+	parents := make(map[string]bool, len(parentKeys))
+	for i, key := range parentKeys {
+		keys[i] = key
+		parents[key] = true
+	}
+	condition := jql.Field("parent").In(keys...)
+	if hf != nil && hf.EpicLinkField != "" {
+		condition = condition.Or(jql.CustomField(hf.EpicLinkField).In(keys...))
+	}
+	query := condition.OrderBy("rank", jql.Asc).String()
+	described := strings.Join(parentKeys, ", ")
+
+	fieldList := []string{"summary", "status", "issuetype", "assignee", "parent"}
+	if hf != nil {
+		if hf.EpicLinkField != "" {
+			fieldList = append(fieldList, hf.EpicLinkField)
+		}
+		if hf.StoryPointsField != "" {
+			fieldList = append(fieldList, hf.StoryPointsField)
+		}
+	}
+
+	var children []*HierarchyNode
+	for startAt := 0; ; {
+		resp, err := c.doRequest(context.Background(), "POST", "/rest/api/2/search", SearchRequest{
+			JQL:        query,
+			StartAt:    startAt,
+			MaxResults: hierarchySearchPage,
+			Fields:     fieldList,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search children of %s: %w", described, err)
+		}
+
+		if err := c.handleErrorResponse(resp); err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Total  int `json:"total"`
+			Issues []struct {
+				ID     string          `json:"id"`
+				Key    string          `json:"key"`
+				Fields json.RawMessage `json:"fields"`
+			} `json:"issues"`
+		}
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return nil, fmt.Errorf("failed to decode children of %s: %w", described, err)
+		}
+
+		for _, raw := range page.Issues {
+			node, err := newHierarchyNode(raw.ID, raw.Key, raw.Fields, hf)
+			if err != nil {
+				return nil, err
+			}
+			if !parents[node.ParentKey] && parents[node.epicKey] {
+				node.ParentKey = node.epicKey
+			}
+			children = append(children, node)
+		}
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	return children, nil
+}
+
+// GetHierarchy returns an issue with all of its descendants, down to maxDepth
+// levels (epic -> story -> subtask is depth 2)
+func (c *Client) GetHierarchy(issueKey string, hf *HierarchyFields, maxDepth int) (*HierarchyNode, error) {
+	if maxDepth <= 0 || maxDepth > maxHierarchyDepth {
+		maxDepth = maxHierarchyDepth
+	}
+
+	fieldList := []string{"summary", "status", "issuetype", "assignee", "parent"}
+	if hf != nil && hf.StoryPointsField != "" {
+		fieldList = append(fieldList, hf.StoryPointsField)
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET",
+		fmt.Sprintf("/rest/api/2/issue/%s?fields=%s", issueKey, strings.Join(fieldList, ",")), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", issueKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		ID     string          `json:"id"`
+		Key    string          `json:"key"`
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(resp.Body(), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+
+	root, err := newHierarchyNode(raw.ID, raw.Key, raw.Fields, hf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.expandHierarchy(root, hf, maxDepth); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// expandHierarchy fills in the descendants of root level by level, searching
+// the children of a whole level at once
+func (c *Client) expandHierarchy(root *HierarchyNode, hf *HierarchyFields, depth int) error {
+	visited := map[string]bool{root.Key: true}
+	level := []*HierarchyNode{root}
+
+	for ; depth > 0 && len(level) > 0; depth-- {
+		var next []*HierarchyNode
+		for start := 0; start < len(level); start += maxParentsPerSearch {
+			batch := level[start:min(start+maxParentsPerSearch, len(level))]
+			byKey := make(map[string]*HierarchyNode, len(batch))
+			keys := make([]string, len(batch))
+			for i, node := range batch {
+				byKey[node.Key] = node
+				keys[i] = node.Key
+			}
+
+			children, err := c.searchChildren(keys, hf)
+			if err != nil {
+				return err
+			}
+
+			for _, child := range children {
+				// Guard against cycles and issues reachable through both parent and Epic Link
+				parent := byKey[child.ParentKey]
+				if parent == nil || visited[child.Key] {
+					continue
+				}
+				visited[child.Key] = true
+				parent.Children = append(parent.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	return nil
+}
+
+func newHierarchyNode(id, key string, rawFields json.RawMessage, hf *HierarchyFields) (*HierarchyNode, error) {
+	var fields IssueFields
+	if err := json.Unmarshal(rawFields, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse fields of %s: %w", key, err)
+	}
+
+	node := &HierarchyNode{
+		Key:       key,
+		ID:        id,
+		Summary:   fields.Summary,
+		IssueType: fields.IssueType.Name,
+	}
+	if fields.Status != nil {
+		node.Status = fields.Status.Name
+		node.StatusCategory = fields.Status.StatusCategory.Key
+	}
+	if fields.Assignee != nil {
+		node.Assignee = fields.Assignee.DisplayName
+	}
+	if fields.Parent != nil {
+		node.ParentKey = fields.Parent.Key
+	}
+
+	if hf != nil && (hf.StoryPointsField != "" || hf.EpicLinkField != "") {
+		var custom map[string]interface{}
+		if err := json.Unmarshal(rawFields, &custom); err == nil {
+			if points, ok := custom[hf.StoryPointsField].(float64); ok && hf.StoryPointsField != "" {
+				node.StoryPoints = &points
+			}
+			if epic, ok := custom[hf.EpicLinkField].(string); ok && hf.EpicLinkField != "" {
+				node.epicKey = epic
+			}
+		}
+	}
+
+	return node, nil
+}
+
+// Flatten returns all descendants of the node, depth first
+func (n *HierarchyNode) Flatten() []*HierarchyNode {
+	var nodes []*HierarchyNode
+	for _, child := range n.Children {
+		nodes = append(nodes, child)
+		nodes = append(nodes, child.Flatten()...)
+	}
+	return nodes
+}
+
+// Rollup aggregates status counts, story points and progress over all
+// descendants of the node. Subtasks are counted but their story points are
+// ignored when their parent is estimated, to avoid double counting.
+func (n *HierarchyNode) Rollup() *HierarchyRollup {
+	rollup := &HierarchyRollup{
+		ByStatus:         make(map[string]int),
+		ByStatusCategory: make(map[string]int),
+		ByIssueType:      make(map[string]int),
+		PointsByStatus:   make(map[string]float64),
+	}
+
+	var walk func(node *HierarchyNode, parentEstimated bool)
+	walk = func(node *HierarchyNode, parentEstimated bool) {
+		for _, child := range node.Children {
+			rollup.Total++
+			rollup.ByStatus[child.Status]++
+			rollup.ByStatusCategory[child.StatusCategory]++
+			rollup.ByIssueType[child.IssueType]++
+
+			done := child.StatusCategory == statusCategoryDoneKey
+			if done {
+				rollup.Done++
+			}
+
+			estimated := child.StoryPoints != nil
+			if estimated && !parentEstimated {
+				rollup.StoryPoints += *child.StoryPoints
+				rollup.PointsByStatus[child.Status] += *child.StoryPoints
+				if done {
+					rollup.CompletedStoryPoints += *child.StoryPoints
+				}
+			} else if !estimated && !parentEstimated && len(child.Children) == 0 {
+				rollup.Unestimated++
+			}
+
+			walk(child, parentEstimated || estimated)
+		}
+	}
+	walk(n, false)
+
+	switch {
+	case rollup.StoryPoints > 0:
+		rollup.PercentDoneBy = "storyPoints"
+		rollup.PercentDone = rollup.CompletedStoryPoints / rollup.StoryPoints * 100
+	case rollup.Total > 0:
+		rollup.PercentDoneBy = "count"
+		rollup.PercentDone = float64(rollup.Done) / float64(rollup.Total) * 100
+	default:
+		rollup.PercentDoneBy = "count"
+	}
+
+	return rollup
+}
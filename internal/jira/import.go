@@ -0,0 +1,617 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportFormat is the format of an issue import file
+type ImportFormat string
+
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportJSON ImportFormat = "json"
+)
+
+// Import mapping targets with a special meaning; every other target is a field
+// name or ID validated against the create metadata
+const (
+	ImportTargetKey       = "key"       // existing issue to update instead of creating one
+	ImportTargetRef       = "ref"       // identifier other rows use to reference this row
+	ImportTargetParent    = "parent"    // parent issue: a row reference or an issue key
+	ImportTargetEpic      = "epic"      // epic: a row reference or an issue key
+	ImportTargetProject   = "project"   // project key, overriding the default
+	ImportTargetIssueType = "issuetype" // issue type name or ID, overriding the default
+)
+
+// Row statuses of an import report
+const (
+	ImportRowValid   = "valid"
+	ImportRowInvalid = "invalid"
+	ImportRowBlocked = "blocked" // a row it references is invalid
+	ImportRowPending = "pending"
+	ImportRowCreated = "created"
+	ImportRowUpdated = "updated"
+	ImportRowFailed  = "failed"
+	ImportRowSkipped = "skipped"
+)
+
+// MaxImportChunkSize is the number of issues Jira accepts in one bulk create
+const MaxImportChunkSize = 50
+
+// importMultiValueFields are split on commas when they come from CSV text
+var importMultiValueFields = map[string]bool{
+	"labels": true, "components": true, "fixversions": true, "versions": true,
+}
+
+var (
+	importRowNumberPattern = regexp.MustCompile(`^#?(\d+)$`)
+	importIssueKeyPattern  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-\d+$`)
+)
+
+// ImportRequest describes an issue import. CSV data must start with a header
+// row; JSON data is an array of objects and may be given as Records instead.
+// Mapping maps columns (CSV headers or JSON keys) to fields or to one of the
+// ImportTarget values; when a mapping is given, unmapped columns are ignored,
+// otherwise every column maps to the field of the same name.
+type ImportRequest struct {
+	Format      ImportFormat             `json:"format"`
+	Data        string                   `json:"data,omitempty"`
+	Records     []map[string]interface{} `json:"records,omitempty"`
+	Mapping     map[string]string        `json:"mapping,omitempty"`
+	Project     string                   `json:"project,omitempty"`
+	IssueType   string                   `json:"issueType,omitempty"`
+	ChunkSize   int                      `json:"chunkSize,omitempty"`
+	DryRun      bool                     `json:"dryRun,omitempty"`
+	SkipInvalid bool                     `json:"skipInvalid,omitempty"` // import the valid rows even when others are invalid
+}
+
+// ImportRow is one parsed row of an import. ParentRow and EpicRow are 1-based
+// row numbers within the import, set when the reference points at another row.
+type ImportRow struct {
+	Row       int                    `json:"row"`
+	Ref       string                 `json:"ref,omitempty"`
+	Key       string                 `json:"key,omitempty"`
+	Project   string                 `json:"project,omitempty"`
+	IssueType string                 `json:"issueType,omitempty"`
+	ParentRef string                 `json:"parentRef,omitempty"`
+	EpicRef   string                 `json:"epicRef,omitempty"`
+	ParentRow int                    `json:"parentRow,omitempty"`
+	EpicRow   int                    `json:"epicRow,omitempty"`
+	Parent    string                 `json:"parent,omitempty"`
+	Epic      string                 `json:"epic,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// IsUpdate reports whether the row updates an existing issue
+func (r *ImportRow) IsUpdate() bool {
+	return r.Key != ""
+}
+
+// ImportRowReport is the validation or execution outcome of a row
+type ImportRowReport struct {
+	Row       int                    `json:"row"`
+	Ref       string                 `json:"ref,omitempty"`
+	Action    string                 `json:"action"` // create or update
+	Key       string                 `json:"key,omitempty"`
+	Project   string                 `json:"project,omitempty"`
+	IssueType string                 `json:"issueType,omitempty"`
+	ParentRow int                    `json:"parentRow,omitempty"`
+	EpicRow   int                    `json:"epicRow,omitempty"`
+	Status    string                 `json:"status"`
+	Missing   []FieldIssue           `json:"missing,omitempty"`
+	Invalid   []FieldIssue           `json:"invalid,omitempty"`
+	Errors    []string               `json:"errors,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ImportSummary counts the rows of an import plan
+type ImportSummary struct {
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	Blocked int `json:"blocked"`
+	Creates int `json:"creates"`
+	Updates int `json:"updates"`
+}
+
+// ImportPlan is a validated import. Levels groups the indexes of importable
+// rows so that every row comes after the rows it references.
+type ImportPlan struct {
+	Rows    []ImportRow       `json:"-"`
+	Reports []ImportRowReport `json:"rows"`
+	Levels  [][]int           `json:"-"`
+	Summary ImportSummary     `json:"summary"`
+	Valid   bool              `json:"valid"`
+}
+
+// ImportValidator validates and coerces the fields of a row against the create
+// metadata of its project and issue type
+type ImportValidator func(projectKey, issueType string, fields map[string]interface{}) (*FieldValidationResult, error)
+
+// ParseImport reads the rows of an import request
+func ParseImport(req ImportRequest) ([]ImportRow, error) {
+	switch req.Format {
+	case ImportCSV:
+		return parseImportCSV(req)
+	case ImportJSON:
+		records := req.Records
+		if records == nil {
+			if err := json.Unmarshal([]byte(req.Data), &records); err != nil {
+				return nil, fmt.Errorf("invalid JSON import data: %w", err)
+			}
+		}
+		rows := make([]ImportRow, 0, len(records))
+		for i, record := range records {
+			row, err := mapImportRecord(i+1, record, req.Mapping, false)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format '%s', supported formats: csv, json", req.Format)
+	}
+}
+
+func parseImportCSV(req ImportRequest) ([]ImportRow, error) {
+	data := bytes.TrimPrefix([]byte(req.Data), []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV import data is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV data: %w", err)
+		}
+		if len(record) > len(header) {
+			return nil, fmt.Errorf("CSV row %d has %d columns but the header has %d", len(rows)+1, len(record), len(header))
+		}
+
+		values := make(map[string]interface{}, len(record))
+		blank := true
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				values[header[i]] = value
+				blank = false
+			}
+		}
+		if blank {
+			continue
+		}
+
+		row, err := mapImportRecord(len(rows)+1, values, req.Mapping, true)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// mapImportRecord applies the column mapping to one record
+func mapImportRecord(number int, record map[string]interface{}, mapping map[string]string, fromCSV bool) (ImportRow, error) {
+	row := ImportRow{Row: number, Fields: make(map[string]interface{})}
+
+	columns := make([]string, 0, len(record))
+	for column := range record {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		value := record[column]
+		if value == nil {
+			continue
+		}
+
+		target := column
+		if mapping != nil {
+			mapped, ok := mapping[column]
+			if !ok || mapped == "" || mapped == "-" {
+				continue
+			}
+			target = mapped
+		}
+
+		switch normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(target), " ", "")); normalized {
+		case ImportTargetKey, ImportTargetRef, ImportTargetParent, ImportTargetEpic, ImportTargetProject, ImportTargetIssueType:
+			text := strings.TrimSpace(importText(value))
+			if text == "" {
+				continue
+			}
+			switch normalized {
+			case ImportTargetKey:
+				row.Key = text
+			case ImportTargetRef:
+				row.Ref = text
+			case ImportTargetParent:
+				row.ParentRef = text
+			case ImportTargetEpic:
+				row.EpicRef = text
+			case ImportTargetProject:
+				row.Project = text
+			case ImportTargetIssueType:
+				row.IssueType = text
+			}
+		default:
+			if _, duplicate := row.Fields[target]; duplicate {
+				return row, fmt.Errorf("row %d: more than one column maps to field '%s'", number, target)
+			}
+			if s, ok := value.(string); ok && fromCSV && importMultiValueFields[normalized] {
+				var items []interface{}
+				for _, item := range strings.Split(s, ",") {
+					if item = strings.TrimSpace(item); item != "" {
+						items = append(items, item)
+					}
+				}
+				value = items
+			}
+			row.Fields[target] = value
+		}
+	}
+	return row, nil
+}
+
+// importText renders a JSON scalar as text; numbers keep their integer form
+func importText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// PlanImport resolves references between rows, validates every row against
+// its create metadata and orders the importable rows so that parents and
+// epics are created before the rows that reference them. Defaults for the
+// project and issue type come from req.
+func PlanImport(rows []ImportRow, req ImportRequest, validate ImportValidator) *ImportPlan {
+	plan := &ImportPlan{
+		Rows:    rows,
+		Reports: make([]ImportRowReport, len(rows)),
+	}
+
+	refs := make(map[string]int, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		if row.Project == "" {
+			row.Project = req.Project
+		}
+		if row.IssueType == "" {
+			row.IssueType = req.IssueType
+		}
+
+		report := &plan.Reports[i]
+		*report = ImportRowReport{
+			Row:       row.Row,
+			Ref:       row.Ref,
+			Action:    "create",
+			Key:       row.Key,
+			Project:   row.Project,
+			IssueType: row.IssueType,
+			Status:    ImportRowValid,
+		}
+		if row.IsUpdate() {
+			report.Action = "update"
+		}
+
+		if row.Ref != "" {
+			if other, taken := refs[row.Ref]; taken {
+				report.Errors = append(report.Errors, fmt.Sprintf("ref '%s' is already used by row %d", row.Ref, rows[other].Row))
+			} else {
+				refs[row.Ref] = i
+			}
+		}
+	}
+
+	// Resolve parent and epic references to rows or issue keys
+	for i := range rows {
+		row, report := &rows[i], &plan.Reports[i]
+		var err error
+		if row.ParentRow, row.Parent, err = resolveImportRef(rows, refs, i, row.ParentRef); err != nil {
+			report.Errors = append(report.Errors, "parent: "+err.Error())
+		}
+		if row.EpicRow, row.Epic, err = resolveImportRef(rows, refs, i, row.EpicRef); err != nil {
+			report.Errors = append(report.Errors, "epic: "+err.Error())
+		}
+		report.ParentRow, report.EpicRow = row.ParentRow, row.EpicRow
+	}
+
+	// Validate fields against the create metadata
+	for i := range rows {
+		row, report := &rows[i], &plan.Reports[i]
+		if !row.IsUpdate() && (row.Project == "" || row.IssueType == "") {
+			report.Errors = append(report.Errors, "project and issue type are required to create an issue")
+			continue
+		}
+		if row.IsUpdate() && (row.Project == "" || row.IssueType == "") {
+			// Without a project and type there is no metadata to check updates against
+			continue
+		}
+
+		result, err := validate(row.Project, row.IssueType, row.Fields)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		report.Invalid = result.Invalid
+		if !row.IsUpdate() {
+			report.Missing = result.Missing
+		}
+		row.Fields = result.Fields
+		report.Fields = result.Fields
+	}
+
+	for i := range plan.Reports {
+		report := &plan.Reports[i]
+		if len(report.Errors) > 0 || len(report.Missing) > 0 || len(report.Invalid) > 0 {
+			report.Status = ImportRowInvalid
+		}
+	}
+
+	depths := importRowDepths(rows, plan.Reports)
+	for i := range rows {
+		report := &plan.Reports[i]
+		plan.Summary.Total++
+		switch report.Status {
+		case ImportRowValid:
+			plan.Summary.Valid++
+			if rows[i].IsUpdate() {
+				plan.Summary.Updates++
+			} else {
+				plan.Summary.Creates++
+			}
+			for len(plan.Levels) <= depths[i] {
+				plan.Levels = append(plan.Levels, nil)
+			}
+			plan.Levels[depths[i]] = append(plan.Levels[depths[i]], i)
+		case ImportRowInvalid:
+			plan.Summary.Invalid++
+		case ImportRowBlocked:
+			plan.Summary.Blocked++
+		}
+	}
+
+	plan.Valid = plan.Summary.Total > 0 && plan.Summary.Valid == plan.Summary.Total
+	return plan
+}
+
+// resolveImportRef resolves a reference to another row (by #number, ref or
+// bare row number) or to an issue key. Rows that update an existing issue
+// resolve to that issue's key directly.
+func resolveImportRef(rows []ImportRow, refs map[string]int, self int, ref string) (int, string, error) {
+	if ref == "" {
+		return 0, "", nil
+	}
+
+	target := -1
+	if i, ok := refs[ref]; ok {
+		target = i
+	} else if m := importRowNumberPattern.FindStringSubmatch(ref); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		for i := range rows {
+			if rows[i].Row == n {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			return 0, "", fmt.Errorf("row %d does not exist", n)
+		}
+	} else if importIssueKeyPattern.MatchString(ref) {
+		return 0, strings.ToUpper(ref), nil
+	} else {
+		return 0, "", fmt.Errorf("'%s' is neither a row reference nor an issue key", ref)
+	}
+
+	if target == self {
+		return 0, "", fmt.Errorf("row references itself")
+	}
+	if rows[target].IsUpdate() {
+		return 0, strings.ToUpper(rows[target].Key), nil
+	}
+	return rows[target].Row, "", nil
+}
+
+// importRowDepths returns how many referenced rows must be created before each
+// row. Rows in a reference cycle are marked invalid, and rows that reference
+// an invalid row are marked blocked.
+func importRowDepths(rows []ImportRow, reports []ImportRowReport) []int {
+	index := make(map[int]int, len(rows))
+	for i := range rows {
+		index[rows[i].Row] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(rows))
+	depths := make([]int, len(rows))
+	cyclic := make([]bool, len(rows))
+	var stack []int
+
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != unvisited {
+			return
+		}
+		state[i] = visiting
+		stack = append(stack, i)
+
+		for _, ref := range []int{rows[i].ParentRow, rows[i].EpicRow} {
+			if ref == 0 {
+				continue
+			}
+			j := index[ref]
+			if state[j] == visiting {
+				// Every row on the stack from j onwards is part of the cycle
+				for k := len(stack) - 1; k >= 0; k-- {
+					cyclic[stack[k]] = true
+					if stack[k] == j {
+						break
+					}
+				}
+				continue
+			}
+			visit(j)
+			if depths[j]+1 > depths[i] {
+				depths[i] = depths[j] + 1
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[i] = done
+	}
+
+	for i := range rows {
+		visit(i)
+	}
+
+	for i := range rows {
+		if cyclic[i] {
+			reports[i].Status = ImportRowInvalid
+			reports[i].Errors = append(reports[i].Errors, "circular parent or epic reference")
+		}
+	}
+
+	// Referenced rows have a lower depth, so visiting by depth settles blocking transitively
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return depths[order[a]] < depths[order[b]] })
+	for _, i := range order {
+		if reports[i].Status != ImportRowValid {
+			continue
+		}
+		for _, ref := range []int{rows[i].ParentRow, rows[i].EpicRow} {
+			if ref != 0 && reports[index[ref]].Status != ImportRowValid {
+				reports[i].Status = ImportRowBlocked
+				reports[i].Errors = append(reports[i].Errors, fmt.Sprintf("row %d it references cannot be imported", ref))
+				break
+			}
+		}
+	}
+	return depths
+}
+
+// ImportChunk creates the new issues of a chunk with one bulk create and
+// applies the updates one by one. Row.Parent and Row.Epic must already hold
+// issue keys. The epic is set through epicLinkField when given, or as the
+// parent otherwise. A report is returned for every row, in order.
+func (c *Client) ImportChunk(ctx context.Context, rows []ImportRow, epicLinkField string) []ImportRowReport {
+	reports := make([]ImportRowReport, len(rows))
+
+	var creates []map[string]interface{}
+	var createIndexes []int
+	for i := range rows {
+		row := &rows[i]
+		reports[i] = ImportRowReport{Row: row.Row, Ref: row.Ref, Key: row.Key, Project: row.Project,
+			IssueType: row.IssueType, ParentRow: row.ParentRow, EpicRow: row.EpicRow}
+
+		fields := make(map[string]interface{}, len(row.Fields)+4)
+		for k, v := range row.Fields {
+			fields[k] = v
+		}
+		if row.Parent != "" {
+			fields["parent"] = map[string]interface{}{"key": row.Parent}
+		}
+		if row.Epic != "" {
+			if epicLinkField != "" {
+				fields[epicLinkField] = row.Epic
+			} else if row.Parent == "" {
+				fields["parent"] = map[string]interface{}{"key": row.Epic}
+			}
+		}
+
+		if row.IsUpdate() {
+			reports[i].Action = "update"
+			if err := c.UpdateIssue(ctx, row.Key, &UpdateIssueRequest{Fields: fields}); err != nil {
+				reports[i].Status = ImportRowFailed
+				reports[i].Errors = []string{err.Error()}
+			} else {
+				reports[i].Status = ImportRowUpdated
+			}
+			continue
+		}
+
+		reports[i].Action = "create"
+		fields["project"] = map[string]interface{}{"key": row.Project}
+		fields["issuetype"] = importIssueTypeField(row.IssueType)
+		creates = append(creates, fields)
+		createIndexes = append(createIndexes, i)
+	}
+
+	if len(creates) == 0 {
+		return reports
+	}
+
+	response, err := c.BulkCreateIssues(creates)
+	if err != nil {
+		for _, i := range createIndexes {
+			reports[i].Status = ImportRowFailed
+			reports[i].Errors = []string{err.Error()}
+		}
+		return reports
+	}
+
+	failed := make(map[int]string, len(response.Errors))
+	for _, e := range response.Errors {
+		failed[e.FailedElementNumber] = e.Message()
+	}
+	created := 0
+	for element, i := range createIndexes {
+		if message, ok := failed[element]; ok {
+			reports[i].Status = ImportRowFailed
+			reports[i].Errors = []string{message}
+			continue
+		}
+		if created >= len(response.Issues) {
+			reports[i].Status = ImportRowFailed
+			reports[i].Errors = []string{"Jira did not return the created issue"}
+			continue
+		}
+		reports[i].Status = ImportRowCreated
+		reports[i].Key = response.Issues[created].Key
+		created++
+	}
+	return reports
+}
+
+// importIssueTypeField references an issue type by ID when numeric, by name otherwise
+func importIssueTypeField(issueType string) map[string]interface{} {
+	if _, err := strconv.Atoi(issueType); err == nil {
+		return map[string]interface{}{"id": issueType}
+	}
+	return map[string]interface{}{"name": issueType}
+}
@@ -0,0 +1,795 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ericfisherdev/GoJira/internal/jql"
+)
+
+// JQLPos is a position in a JQL string. Offset is a byte offset; Line and
+// Column are 1-based, with Column counted in characters.
+type JQLPos struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p JQLPos) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// JQLError is a syntax or validation error at a position in a JQL string
+type JQLError struct {
+	Message string `json:"message"`
+	Pos     JQLPos `json:"position"`
+}
+
+func (e *JQLError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// JQLQuery is a parsed JQL query. Where is nil for queries without a condition.
+type JQLQuery struct {
+	Where   JQLExpr           `json:"where,omitempty"`
+	OrderBy []JQLOrderByField `json:"orderBy,omitempty"`
+}
+
+// JQLOrderByField is a field of the ORDER BY clause
+type JQLOrderByField struct {
+	Field     JQLField `json:"field"`
+	Direction string   `json:"direction,omitempty"` // ASC, DESC or empty
+}
+
+// JQLExpr is a node of a JQL condition: *JQLBinaryExpr, *JQLNotExpr or *JQLClause
+type JQLExpr interface {
+	Position() JQLPos
+	String() string
+	jqlExpr()
+}
+
+// JQLBinaryExpr joins two conditions with AND or OR
+type JQLBinaryExpr struct {
+	Op    string  `json:"op"` // AND or OR
+	Left  JQLExpr `json:"left"`
+	Right JQLExpr `json:"right"`
+	Pos   JQLPos  `json:"position"`
+}
+
+// JQLNotExpr negates a condition
+type JQLNotExpr struct {
+	Expr JQLExpr `json:"expr"`
+	Pos  JQLPos  `json:"position"`
+}
+
+// JQLClause is a single field condition such as `status IN (Open, "In Progress")`
+type JQLClause struct {
+	Field      JQLField       `json:"field"`
+	Operator   string         `json:"operator"`             // canonical upper-case operator
+	Value      JQLOperand     `json:"value,omitempty"`      // nil for CHANGED
+	Predicates []JQLPredicate `json:"predicates,omitempty"` // history predicates of WAS and CHANGED
+	Pos        JQLPos         `json:"position"`
+	OpPos      JQLPos         `json:"operatorPosition"`
+}
+
+// JQLPredicate is a history predicate such as `AFTER -1w` or `BY currentUser()`
+type JQLPredicate struct {
+	Keyword string     `json:"keyword"` // AFTER, BEFORE, BY, DURING, FROM, ON or TO
+	Value   JQLOperand `json:"value"`
+	Pos     JQLPos     `json:"position"`
+}
+
+// JQLField is a field reference: a name, a quoted name or a cf[id] reference
+type JQLField struct {
+	Name   string `json:"name"`
+	Quoted bool   `json:"quoted,omitempty"`
+	Pos    JQLPos `json:"position"`
+}
+
+// JQLOperand is the right-hand side of a clause: *JQLValue, *JQLList or *JQLFunctionCall
+type JQLOperand interface {
+	Position() JQLPos
+	String() string
+	jqlOperand()
+}
+
+// JQLValue is a literal value. EMPTY and NULL are values with Empty set.
+type JQLValue struct {
+	Text   string `json:"text"`
+	Quoted bool   `json:"quoted,omitempty"`
+	Empty  bool   `json:"empty,omitempty"`
+	Pos    JQLPos `json:"position"`
+}
+
+// JQLList is a parenthesised list of operands
+type JQLList struct {
+	Values []JQLOperand `json:"values"`
+	Pos    JQLPos       `json:"position"`
+}
+
+// JQLFunctionCall is a function call such as membersOf("developers")
+type JQLFunctionCall struct {
+	Name string     `json:"name"`
+	Args []JQLValue `json:"args"`
+	Pos  JQLPos     `json:"position"`
+}
+
+func (e *JQLBinaryExpr) Position() JQLPos   { return e.Pos }
+func (e *JQLNotExpr) Position() JQLPos      { return e.Pos }
+func (c *JQLClause) Position() JQLPos       { return c.Pos }
+func (v *JQLValue) Position() JQLPos        { return v.Pos }
+func (l *JQLList) Position() JQLPos         { return l.Pos }
+func (f *JQLFunctionCall) Position() JQLPos { return f.Pos }
+
+func (*JQLBinaryExpr) jqlExpr()      {}
+func (*JQLNotExpr) jqlExpr()         {}
+func (*JQLClause) jqlExpr()          {}
+func (*JQLValue) jqlOperand()        {}
+func (*JQLList) jqlOperand()         {}
+func (*JQLFunctionCall) jqlOperand() {}
+
+// String returns the canonical JQL for the query: upper-case keywords,
+// normalised spacing, minimal parentheses and consistent quoting
+func (q *JQLQuery) String() string {
+	var parts []string
+	if q.Where != nil {
+		parts = append(parts, q.Where.String())
+	}
+	if len(q.OrderBy) > 0 {
+		fields := make([]string, len(q.OrderBy))
+		for i, field := range q.OrderBy {
+			fields[i] = field.Field.String()
+			if field.Direction != "" {
+				fields[i] += " " + field.Direction
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(fields, ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (e *JQLBinaryExpr) String() string {
+	return jqlOperandString(e.Left, e.Op) + " " + e.Op + " " + jqlOperandString(e.Right, e.Op)
+}
+
+// jqlOperandString parenthesises a child of a binary expression when it binds
+// more loosely than its parent
+func jqlOperandString(expr JQLExpr, parentOp string) string {
+	if child, ok := expr.(*JQLBinaryExpr); ok && child.Op == "OR" && parentOp == "AND" {
+		return "(" + child.String() + ")"
+	}
+	return expr.String()
+}
+
+func (e *JQLNotExpr) String() string {
+	if _, ok := e.Expr.(*JQLBinaryExpr); ok {
+		return "NOT (" + e.Expr.String() + ")"
+	}
+	return "NOT " + e.Expr.String()
+}
+
+func (c *JQLClause) String() string {
+	var b strings.Builder
+	b.WriteString(c.Field.String())
+	b.WriteString(" ")
+	b.WriteString(c.Operator)
+	if c.Value != nil {
+		b.WriteString(" ")
+		b.WriteString(c.Value.String())
+	}
+	for _, predicate := range c.Predicates {
+		b.WriteString(" ")
+		b.WriteString(predicate.Keyword)
+		b.WriteString(" ")
+		b.WriteString(predicate.Value.String())
+	}
+	return b.String()
+}
+
+func (f JQLField) String() string {
+	return jql.FieldName(f.Name)
+}
+
+func (v *JQLValue) String() string {
+	if v.Empty {
+		return "EMPTY"
+	}
+	if v.Quoted {
+		return jql.Quote(v.Text)
+	}
+	return jql.QuoteIfNeeded(v.Text)
+}
+
+func (l *JQLList) String() string {
+	values := make([]string, len(l.Values))
+	for i, value := range l.Values {
+		values[i] = value.String()
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+func (f *JQLFunctionCall) String() string {
+	args := make([]string, len(f.Args))
+	for i := range f.Args {
+		args[i] = f.Args[i].String()
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Walk calls fn for every clause of the query in source order
+func (q *JQLQuery) Walk(fn func(clause *JQLClause)) {
+	var walk func(expr JQLExpr)
+	walk = func(expr JQLExpr) {
+		switch e := expr.(type) {
+		case *JQLBinaryExpr:
+			walk(e.Left)
+			walk(e.Right)
+		case *JQLNotExpr:
+			walk(e.Expr)
+		case *JQLClause:
+			fn(e)
+		}
+	}
+	if q.Where != nil {
+		walk(q.Where)
+	}
+}
+
+// Lexer
+
+type jqlTokenKind int
+
+const (
+	jqlEOF jqlTokenKind = iota
+	jqlWord
+	jqlString
+	jqlOperator
+	jqlLParen
+	jqlRParen
+	jqlComma
+)
+
+type jqlToken struct {
+	kind jqlTokenKind
+	text string // unquoted text for strings, upper-case for operators
+	pos  JQLPos
+}
+
+func (t jqlToken) describe() string {
+	switch t.kind {
+	case jqlEOF:
+		return "end of query"
+	case jqlString:
+		return jql.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+// is reports whether the token is the given keyword, case-insensitively
+func (t jqlToken) is(keyword string) bool {
+	return t.kind == jqlWord && strings.EqualFold(t.text, keyword)
+}
+
+type jqlLexer struct {
+	input  string
+	offset int
+	line   int
+	column int
+}
+
+func (l *jqlLexer) pos() JQLPos {
+	return JQLPos{Offset: l.offset, Line: l.line, Column: l.column}
+}
+
+func (l *jqlLexer) peekRune() rune {
+	if l.offset >= len(l.input) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[l.offset:])
+	return r
+}
+
+func (l *jqlLexer) nextRune() rune {
+	r, size := utf8.DecodeRuneInString(l.input[l.offset:])
+	l.offset += size
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+func isJQLWordRune(r rune) bool {
+	return r != 0 && !unicode.IsSpace(r) && !strings.ContainsRune(`=!<>~(),"'&|[]{}`, r)
+}
+
+func (l *jqlLexer) tokenize() ([]jqlToken, error) {
+	var tokens []jqlToken
+	for {
+		for unicode.IsSpace(l.peekRune()) {
+			l.nextRune()
+		}
+
+		start := l.pos()
+		if l.offset >= len(l.input) {
+			return append(tokens, jqlToken{kind: jqlEOF, pos: start}), nil
+		}
+
+		r := l.peekRune()
+		switch {
+		case r == '(':
+			l.nextRune()
+			tokens = append(tokens, jqlToken{kind: jqlLParen, text: "(", pos: start})
+		case r == ')':
+			l.nextRune()
+			tokens = append(tokens, jqlToken{kind: jqlRParen, text: ")", pos: start})
+		case r == ',':
+			l.nextRune()
+			tokens = append(tokens, jqlToken{kind: jqlComma, text: ",", pos: start})
+		case r == '"' || r == '\'':
+			text, err := l.readString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, jqlToken{kind: jqlString, text: text, pos: start})
+		case r == '&' || r == '|':
+			l.nextRune()
+			if l.peekRune() != r {
+				return nil, &JQLError{Message: fmt.Sprintf("unexpected character '%c'", r), Pos: start}
+			}
+			l.nextRune()
+			keyword := "AND"
+			if r == '|' {
+				keyword = "OR"
+			}
+			tokens = append(tokens, jqlToken{kind: jqlWord, text: keyword, pos: start})
+		case strings.ContainsRune("=!<>~", r):
+			l.nextRune()
+			op := string(r)
+			if next := l.peekRune(); (r == '!' && (next == '=' || next == '~')) || ((r == '<' || r == '>') && next == '=') {
+				l.nextRune()
+				op += string(next)
+			}
+			if op == "!" {
+				tokens = append(tokens, jqlToken{kind: jqlWord, text: "NOT", pos: start})
+				continue
+			}
+			tokens = append(tokens, jqlToken{kind: jqlOperator, text: op, pos: start})
+		case isJQLWordRune(r):
+			tokens = append(tokens, jqlToken{kind: jqlWord, text: l.readWord(), pos: start})
+		default:
+			return nil, &JQLError{Message: fmt.Sprintf("unexpected character '%c'", r), Pos: start}
+		}
+	}
+}
+
+func (l *jqlLexer) readString() (string, error) {
+	start := l.pos()
+	quote := l.nextRune()
+
+	var b strings.Builder
+	for {
+		if l.offset >= len(l.input) {
+			return "", &JQLError{Message: "unterminated string", Pos: start}
+		}
+		r := l.nextRune()
+		switch r {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if l.offset >= len(l.input) {
+				return "", &JQLError{Message: "unterminated string", Pos: start}
+			}
+			escaped := l.nextRune()
+			switch escaped {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case 'r':
+				b.WriteRune('\r')
+			default:
+				b.WriteRune(escaped)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+func (l *jqlLexer) readWord() string {
+	start := l.offset
+	for isJQLWordRune(l.peekRune()) {
+		l.nextRune()
+	}
+	// cf[12345] custom field references
+	if strings.EqualFold(l.input[start:l.offset], "cf") && l.peekRune() == '[' {
+		for l.offset < len(l.input) && l.peekRune() != ']' {
+			l.nextRune()
+		}
+		if l.offset < len(l.input) {
+			l.nextRune()
+		}
+	}
+	return l.input[start:l.offset]
+}
+
+// Parser
+
+type jqlParser struct {
+	tokens []jqlToken
+	index  int
+}
+
+// ParseJQL parses a JQL query into an AST. Syntax errors are returned as
+// *JQLError with the position of the offending token.
+func ParseJQL(input string) (*JQLQuery, error) {
+	lexer := &jqlLexer{input: input, line: 1, column: 1}
+	tokens, err := lexer.tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &jqlParser{tokens: tokens}
+	query := &JQLQuery{}
+
+	if p.peek().kind != jqlEOF && !p.atOrderBy() {
+		query.Where, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.atOrderBy() {
+		p.next()
+		p.next()
+		query.OrderBy, err = p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tok := p.peek(); tok.kind != jqlEOF {
+		return nil, p.unexpected(tok, "AND, OR or ORDER BY")
+	}
+
+	return query, nil
+}
+
+// FormatJQL returns the canonical form of a JQL query
+func FormatJQL(input string) (string, error) {
+	query, err := ParseJQL(input)
+	if err != nil {
+		return "", err
+	}
+	return query.String(), nil
+}
+
+func (p *jqlParser) peek() jqlToken {
+	return p.tokens[p.index]
+}
+
+func (p *jqlParser) peekAt(n int) jqlToken {
+	if p.index+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.index+n]
+}
+
+func (p *jqlParser) next() jqlToken {
+	tok := p.tokens[p.index]
+	if tok.kind != jqlEOF {
+		p.index++
+	}
+	return tok
+}
+
+func (p *jqlParser) atOrderBy() bool {
+	return p.peek().is("order") && p.peekAt(1).is("by")
+}
+
+func (p *jqlParser) unexpected(tok jqlToken, expected string) error {
+	return &JQLError{Message: fmt.Sprintf("expected %s but found %s", expected, tok.describe()), Pos: tok.pos}
+}
+
+func (p *jqlParser) parseOr() (JQLExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("or") {
+		tok := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &JQLBinaryExpr{Op: "OR", Left: left, Right: right, Pos: tok.pos}
+	}
+	return left, nil
+}
+
+func (p *jqlParser) parseAnd() (JQLExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("and") {
+		tok := p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &JQLBinaryExpr{Op: "AND", Left: left, Right: right, Pos: tok.pos}
+	}
+	return left, nil
+}
+
+func (p *jqlParser) parseNot() (JQLExpr, error) {
+	tok := p.peek()
+	if tok.is("not") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &JQLNotExpr{Expr: expr, Pos: tok.pos}, nil
+	}
+
+	if tok.kind == jqlLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != jqlRParen {
+			return nil, p.unexpected(closing, "')'")
+		}
+		return expr, nil
+	}
+
+	return p.parseClause()
+}
+
+func (p *jqlParser) parseField(expected string) (JQLField, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == jqlString:
+		return JQLField{Name: tok.text, Quoted: true, Pos: tok.pos}, nil
+	case tok.kind == jqlWord && !jql.IsReserved(tok.text):
+		return JQLField{Name: tok.text, Pos: tok.pos}, nil
+	default:
+		return JQLField{}, p.unexpected(tok, expected)
+	}
+}
+
+func (p *jqlParser) parseClause() (JQLExpr, error) {
+	field, err := p.parseField("a field name")
+	if err != nil {
+		return nil, err
+	}
+
+	clause := &JQLClause{Field: field, Pos: field.Pos, OpPos: p.peek().pos}
+	if clause.Operator, err = p.parseOperator(); err != nil {
+		return nil, err
+	}
+
+	switch clause.Operator {
+	case "CHANGED":
+		// CHANGED takes predicates only
+	case "IS", "IS NOT":
+		tok := p.next()
+		if !tok.is("empty") && !tok.is("null") {
+			return nil, p.unexpected(tok, "EMPTY or NULL")
+		}
+		clause.Value = &JQLValue{Text: "EMPTY", Empty: true, Pos: tok.pos}
+	case "IN", "NOT IN", "WAS IN", "WAS NOT IN":
+		if p.peek().kind == jqlLParen {
+			clause.Value, err = p.parseList()
+		} else {
+			clause.Value, err = p.parseOperand()
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		if clause.Value, err = p.parseOperand(); err != nil {
+			return nil, err
+		}
+	}
+
+	if clause.Operator == "CHANGED" || strings.HasPrefix(clause.Operator, "WAS") {
+		if clause.Predicates, err = p.parsePredicates(); err != nil {
+			return nil, err
+		}
+	}
+
+	return clause, nil
+}
+
+func (p *jqlParser) parseOperator() (string, error) {
+	tok := p.next()
+	if tok.kind == jqlOperator {
+		return tok.text, nil
+	}
+
+	switch {
+	case tok.is("in"):
+		return "IN", nil
+	case tok.is("changed"):
+		return "CHANGED", nil
+	case tok.is("not"):
+		if next := p.next(); !next.is("in") {
+			return "", p.unexpected(next, "IN after NOT")
+		}
+		return "NOT IN", nil
+	case tok.is("is"):
+		if p.peek().is("not") {
+			p.next()
+			return "IS NOT", nil
+		}
+		return "IS", nil
+	case tok.is("was"):
+		op := "WAS"
+		if p.peek().is("not") {
+			p.next()
+			op += " NOT"
+		}
+		if p.peek().is("in") {
+			p.next()
+			op += " IN"
+		}
+		return op, nil
+	}
+
+	return "", p.unexpected(tok, "an operator")
+}
+
+func (p *jqlParser) parseOperand() (JQLOperand, error) {
+	tok := p.peek()
+	if tok.kind == jqlLParen {
+		return p.parseList()
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if !value.Quoted && !value.Empty && p.peek().kind == jqlLParen {
+		return p.parseFunctionArgs(value)
+	}
+	return value, nil
+}
+
+func (p *jqlParser) parseValue() (*JQLValue, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == jqlString:
+		return &JQLValue{Text: tok.text, Quoted: true, Pos: tok.pos}, nil
+	case tok.is("empty") || tok.is("null"):
+		return &JQLValue{Text: "EMPTY", Empty: true, Pos: tok.pos}, nil
+	case tok.kind == jqlWord && !jql.IsReserved(tok.text):
+		return &JQLValue{Text: tok.text, Pos: tok.pos}, nil
+	}
+	return nil, p.unexpected(tok, "a value")
+}
+
+func (p *jqlParser) parseFunctionArgs(name *JQLValue) (*JQLFunctionCall, error) {
+	call := &JQLFunctionCall{Name: name.Text, Args: []JQLValue{}, Pos: name.Pos}
+	p.next()
+
+	if p.peek().kind == jqlRParen {
+		p.next()
+		return call, nil
+	}
+
+	for {
+		tok := p.next()
+		switch tok.kind {
+		case jqlString:
+			call.Args = append(call.Args, JQLValue{Text: tok.text, Quoted: true, Pos: tok.pos})
+		case jqlWord:
+			call.Args = append(call.Args, JQLValue{Text: tok.text, Pos: tok.pos})
+		default:
+			return nil, p.unexpected(tok, "a function argument")
+		}
+
+		switch sep := p.next(); sep.kind {
+		case jqlComma:
+			continue
+		case jqlRParen:
+			return call, nil
+		default:
+			return nil, p.unexpected(sep, "',' or ')'")
+		}
+	}
+}
+
+func (p *jqlParser) parseList() (*JQLList, error) {
+	open := p.next()
+	list := &JQLList{Pos: open.pos}
+
+	for {
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if _, nested := operand.(*JQLList); nested {
+			return nil, &JQLError{Message: "lists cannot be nested", Pos: operand.Position()}
+		}
+		list.Values = append(list.Values, operand)
+
+		switch sep := p.next(); sep.kind {
+		case jqlComma:
+			continue
+		case jqlRParen:
+			return list, nil
+		default:
+			return nil, p.unexpected(sep, "',' or ')'")
+		}
+	}
+}
+
+// jqlPredicateKeywords are the history predicates accepted after WAS and CHANGED
+var jqlPredicateKeywords = []string{"AFTER", "BEFORE", "BY", "DURING", "FROM", "ON", "TO"}
+
+func (p *jqlParser) parsePredicates() ([]JQLPredicate, error) {
+	var predicates []JQLPredicate
+	for {
+		tok := p.peek()
+		keyword := ""
+		for _, candidate := range jqlPredicateKeywords {
+			if tok.is(candidate) {
+				keyword = candidate
+				break
+			}
+		}
+		if keyword == "" {
+			return predicates, nil
+		}
+		p.next()
+
+		var value JQLOperand
+		var err error
+		if keyword == "DURING" {
+			value, err = p.parseList()
+			if err == nil && len(value.(*JQLList).Values) != 2 {
+				err = &JQLError{Message: "DURING requires a (start, end) pair", Pos: value.Position()}
+			}
+		} else {
+			value, err = p.parseOperand()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		predicates = append(predicates, JQLPredicate{Keyword: keyword, Value: value, Pos: tok.pos})
+	}
+}
+
+func (p *jqlParser) parseOrderBy() ([]JQLOrderByField, error) {
+	var fields []JQLOrderByField
+	for {
+		field, err := p.parseField("a field to order by")
+		if err != nil {
+			return nil, err
+		}
+
+		orderField := JQLOrderByField{Field: field}
+		if tok := p.peek(); tok.is("asc") || tok.is("desc") {
+			p.next()
+			orderField.Direction = strings.ToUpper(tok.text)
+		}
+		fields = append(fields, orderField)
+
+		if p.peek().kind != jqlComma {
+			return fields, nil
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,334 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// JQLFieldInfo describes a field that can be used in JQL
+type JQLFieldInfo struct {
+	Value       string   `json:"value"`
+	DisplayName string   `json:"displayName"`
+	Orderable   string   `json:"orderable,omitempty"`
+	Searchable  string   `json:"searchable,omitempty"`
+	Operators   []string `json:"operators,omitempty"`
+	Types       []string `json:"types,omitempty"`
+	CfID        string   `json:"cfid,omitempty"`
+}
+
+// JQLFunctionInfo describes a function that can be used in JQL
+type JQLFunctionInfo struct {
+	Value       string   `json:"value"`
+	DisplayName string   `json:"displayName"`
+	IsList      string   `json:"isList,omitempty"`
+	Types       []string `json:"types,omitempty"`
+}
+
+// JQLAutocompleteData is the field, function and reserved word reference data used by the JQL editor
+type JQLAutocompleteData struct {
+	VisibleFieldNames    []JQLFieldInfo    `json:"visibleFieldNames"`
+	VisibleFunctionNames []JQLFunctionInfo `json:"visibleFunctionNames"`
+	JQLReservedWords     []string          `json:"jqlReservedWords"`
+}
+
+// GetJQLAutocompleteData retrieves the JQL reference data: fields with their
+// operators and types, and functions
+func (c *Client) GetJQLAutocompleteData() (*JQLAutocompleteData, error) {
+	resp, err := c.doRequest(context.Background(), "GET", "/rest/api/2/jql/autocompletedata", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get JQL autocomplete data: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("failed to get JQL autocomplete data, status: %d", resp.StatusCode())
+	}
+
+	var data JQLAutocompleteData
+	if err := json.Unmarshal(resp.Body(), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode JQL autocomplete data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// jqlFunctionArity holds the argument counts of built-in JQL functions. A max
+// of -1 means any number of arguments. Functions missing from the table (for
+// example ones added by apps) are not arity checked.
+var jqlFunctionArity = map[string][2]int{
+	"approved":                        {0, 0},
+	"approver":                        {1, -1},
+	"cascadeoption":                   {1, 2},
+	"closedsprints":                   {0, 0},
+	"componentsleadbyuser":            {0, 1},
+	"currentlogin":                    {0, 0},
+	"currentuser":                     {0, 0},
+	"earliestunreleasedversion":       {1, 1},
+	"endofday":                        {0, 1},
+	"endofmonth":                      {0, 1},
+	"endofweek":                       {0, 1},
+	"endofyear":                       {0, 1},
+	"futuresprints":                   {0, 0},
+	"issuehistory":                    {0, 0},
+	"issueswithremotelinksbyglobalid": {1, 100},
+	"lastlogin":                       {0, 0},
+	"latestreleasedversion":           {1, 1},
+	"linkedissues":                    {1, 2},
+	"membersof":                       {1, 1},
+	"now":                             {0, 0},
+	"opensprints":                     {0, 0},
+	"projectsleadbyuser":              {0, 1},
+	"projectswhereuserhaspermission":  {1, 1},
+	"projectswhereuserhasrole":        {1, 1},
+	"releasedversions":                {0, 1},
+	"standardissuetypes":              {0, 0},
+	"startofday":                      {0, 1},
+	"startofmonth":                    {0, 1},
+	"startofweek":                     {0, 1},
+	"startofyear":                     {0, 1},
+	"subtaskissuetypes":               {0, 0},
+	"unreleasedversions":              {0, 1},
+	"updatedby":                       {1, 3},
+	"votedissues":                     {0, 0},
+	"watchedissues":                   {0, 0},
+}
+
+// jqlFieldAliases maps alternative field names to the name Jira reports
+var jqlFieldAliases = map[string]string{
+	"type":   "issuetype",
+	"key":    "issuekey",
+	"id":     "issuekey",
+	"issue":  "issuekey",
+	"filter": "savedfilter",
+}
+
+// JQLValidator statically checks parsed JQL against the fields and functions known to Jira
+type JQLValidator struct {
+	fields    map[string]*JQLFieldInfo
+	functions map[string]*JQLFunctionInfo
+}
+
+// NewJQLValidator creates a validator from JQL autocomplete data
+func NewJQLValidator(data *JQLAutocompleteData) *JQLValidator {
+	v := &JQLValidator{
+		fields:    make(map[string]*JQLFieldInfo),
+		functions: make(map[string]*JQLFunctionInfo),
+	}
+
+	for i := range data.VisibleFieldNames {
+		field := &data.VisibleFieldNames[i]
+		v.fields[strings.ToLower(strings.Trim(field.Value, `"`))] = field
+		if field.CfID != "" {
+			v.fields[strings.ToLower(field.CfID)] = field
+		}
+		// Custom fields are displayed as "Story Points - cf[10016]"
+		if name, _, found := strings.Cut(field.DisplayName, " - cf["); found {
+			if _, exists := v.fields[strings.ToLower(name)]; !exists {
+				v.fields[strings.ToLower(name)] = field
+			}
+		}
+	}
+
+	for i := range data.VisibleFunctionNames {
+		function := &data.VisibleFunctionNames[i]
+		name, _, _ := strings.Cut(function.Value, "(")
+		v.functions[strings.ToLower(name)] = function
+	}
+
+	return v
+}
+
+// Field looks up a JQL field by name, quoted name or cf[id] reference
+func (v *JQLValidator) Field(name string) (*JQLFieldInfo, bool) {
+	key := strings.ToLower(name)
+	if field, ok := v.fields[key]; ok {
+		return field, true
+	}
+	if alias, ok := jqlFieldAliases[key]; ok {
+		field, ok := v.fields[alias]
+		return field, ok
+	}
+	return nil, false
+}
+
+// Validate parses a JQL query and checks it statically. The query is nil when
+// it has a syntax error; validation errors are sorted by position.
+func (v *JQLValidator) Validate(jql string) (*JQLQuery, []JQLError) {
+	query, err := ParseJQL(jql)
+	if err != nil {
+		if jqlErr, ok := err.(*JQLError); ok {
+			return nil, []JQLError{*jqlErr}
+		}
+		return nil, []JQLError{{Message: err.Error()}}
+	}
+	return query, v.ValidateQuery(query)
+}
+
+// ValidateQuery checks field names, operators against field types, value
+// shapes and function arity of a parsed query
+func (v *JQLValidator) ValidateQuery(query *JQLQuery) []JQLError {
+	var errs []JQLError
+	report := func(pos JQLPos, format string, args ...interface{}) {
+		errs = append(errs, JQLError{Message: fmt.Sprintf(format, args...), Pos: pos})
+	}
+
+	query.Walk(func(clause *JQLClause) {
+		field, ok := v.Field(clause.Field.Name)
+		if !ok {
+			report(clause.Field.Pos, "field '%s' does not exist or you do not have permission to view it", clause.Field.Name)
+		} else {
+			if field.Searchable == "false" {
+				report(clause.Field.Pos, "field '%s' is not searchable", clause.Field.Name)
+			}
+			if allowed := jqlFieldOperators(field); !containsString(allowed, clause.Operator) {
+				report(clause.OpPos, "operator '%s' cannot be used with field '%s'; allowed operators are %s",
+					clause.Operator, clause.Field.Name, strings.Join(allowed, ", "))
+			}
+		}
+
+		v.checkOperand(clause, clause.Value, &errs)
+		for _, predicate := range clause.Predicates {
+			v.checkFunctions(predicate.Value, &errs)
+		}
+	})
+
+	for _, orderField := range query.OrderBy {
+		field, ok := v.Field(orderField.Field.Name)
+		if !ok {
+			report(orderField.Field.Pos, "field '%s' does not exist or you do not have permission to view it", orderField.Field.Name)
+		} else if field.Orderable == "false" {
+			report(orderField.Field.Pos, "field '%s' cannot be used in ORDER BY", orderField.Field.Name)
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Pos.Offset < errs[j].Pos.Offset })
+	return errs
+}
+
+// checkOperand verifies that the value of a clause has the shape its operator needs
+func (v *JQLValidator) checkOperand(clause *JQLClause, value JQLOperand, errs *[]JQLError) {
+	if value == nil {
+		return
+	}
+
+	listOperator := strings.HasSuffix(clause.Operator, "IN")
+	switch operand := value.(type) {
+	case *JQLList:
+		if !listOperator {
+			*errs = append(*errs, JQLError{
+				Message: fmt.Sprintf("operator '%s' does not accept a list of values", clause.Operator),
+				Pos:     operand.Pos,
+			})
+		}
+	case *JQLFunctionCall:
+		if function, ok := v.functions[strings.ToLower(operand.Name)]; ok {
+			isList := function.IsList == "true"
+			if listOperator && !isList {
+				*errs = append(*errs, JQLError{
+					Message: fmt.Sprintf("operator '%s' requires a list, but %s() returns a single value", clause.Operator, operand.Name),
+					Pos:     operand.Pos,
+				})
+			} else if !listOperator && isList {
+				*errs = append(*errs, JQLError{
+					Message: fmt.Sprintf("operator '%s' cannot be used with the list function %s()", clause.Operator, operand.Name),
+					Pos:     operand.Pos,
+				})
+			}
+		}
+	case *JQLValue:
+		if listOperator {
+			*errs = append(*errs, JQLError{
+				Message: fmt.Sprintf("operator '%s' requires a list of values in parentheses", clause.Operator),
+				Pos:     operand.Pos,
+			})
+		} else if operand.Empty && clause.Operator != "=" && clause.Operator != "!=" && !strings.HasPrefix(clause.Operator, "IS") && !strings.HasPrefix(clause.Operator, "WAS") {
+			*errs = append(*errs, JQLError{
+				Message: fmt.Sprintf("EMPTY cannot be used with operator '%s'", clause.Operator),
+				Pos:     operand.Pos,
+			})
+		}
+	}
+
+	v.checkFunctions(value, errs)
+}
+
+// checkFunctions verifies that every function in an operand exists and gets the right number of arguments
+func (v *JQLValidator) checkFunctions(value JQLOperand, errs *[]JQLError) {
+	switch operand := value.(type) {
+	case *JQLList:
+		for _, item := range operand.Values {
+			v.checkFunctions(item, errs)
+		}
+	case *JQLFunctionCall:
+		name := strings.ToLower(operand.Name)
+		if _, ok := v.functions[name]; !ok && len(v.functions) > 0 {
+			*errs = append(*errs, JQLError{
+				Message: fmt.Sprintf("function '%s' does not exist", operand.Name),
+				Pos:     operand.Pos,
+			})
+			return
+		}
+
+		arity, ok := jqlFunctionArity[name]
+		if !ok {
+			return
+		}
+		count := len(operand.Args)
+		if count < arity[0] || (arity[1] >= 0 && count > arity[1]) {
+			*errs = append(*errs, JQLError{
+				Message: fmt.Sprintf("function '%s' expects %s but got %d", operand.Name, describeArity(arity), count),
+				Pos:     operand.Pos,
+			})
+		}
+	}
+}
+
+func describeArity(arity [2]int) string {
+	switch {
+	case arity[0] == arity[1] && arity[0] == 1:
+		return "1 argument"
+	case arity[0] == arity[1]:
+		return fmt.Sprintf("%d arguments", arity[0])
+	case arity[1] < 0:
+		return fmt.Sprintf("at least %d arguments", arity[0])
+	default:
+		return fmt.Sprintf("%d to %d arguments", arity[0], arity[1])
+	}
+}
+
+// jqlFieldOperators returns the canonical operators a field supports. Jira
+// reports them per field; when it does not, they are derived from the field type.
+func jqlFieldOperators(field *JQLFieldInfo) []string {
+	if len(field.Operators) > 0 {
+		operators := make([]string, len(field.Operators))
+		for i, op := range field.Operators {
+			operators[i] = strings.ToUpper(strings.Join(strings.Fields(op), " "))
+		}
+		return operators
+	}
+
+	for _, fieldType := range field.Types {
+		switch fieldType {
+		case "java.lang.String", "com.atlassian.jira.issue.fields.TextField":
+			return []string{"~", "!~", "IS", "IS NOT"}
+		case "java.util.Date", "java.lang.Number", "java.lang.Long", "java.lang.Double":
+			return []string{"=", "!=", ">", ">=", "<", "<=", "IN", "NOT IN", "IS", "IS NOT",
+				"WAS", "WAS NOT", "WAS IN", "WAS NOT IN", "CHANGED"}
+		}
+	}
+
+	return []string{"=", "!=", "IN", "NOT IN", "IS", "IS NOT",
+		"WAS", "WAS NOT", "WAS IN", "WAS NOT IN", "CHANGED"}
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -95,6 +95,7 @@ func (c *Client) CreateIssueLink(inwardIssueKey, outwardIssueKey, linkTypeName s
 		return fmt.Errorf("link creation failed with status %d: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
+	c.notifyMutation(Mutation{Operation: OpIssueLink, Issues: []string{inwardIssueKey, outwardIssueKey}})
 	return nil
 }
 
@@ -111,6 +112,8 @@ func (c *Client) DeleteIssueLink(linkID string) error {
 		return fmt.Errorf("link deletion failed with status: %d", resp.StatusCode())
 	}
 
+	// The link ID does not tell which issues were linked
+	c.notifyMutation(Mutation{Operation: OpIssueLink})
 	return nil
 }
 
@@ -27,6 +27,7 @@ func (jt *JiraTime) UnmarshalJSON(data []byte) error {
 		"2006-01-02T15:04:05Z",          // ISO format
 		time.RFC3339Nano,                // Standard RFC3339 with nanoseconds
 		time.RFC3339,                    // Standard RFC3339
+		"2006-01-02",                    // Date-only format (version release dates)
 	}
 	
 	for _, format := range formats {
@@ -104,6 +105,7 @@ type Project struct {
 	Components  []Component      `json:"components,omitempty"`
 	Versions    []Version        `json:"versions,omitempty"`
 	Roles       map[string]string `json:"roles,omitempty"`
+	IssueTypes  []IssueType      `json:"issueTypes,omitempty"`
 }
 
 // IssueType represents a Jira issue type
@@ -153,10 +155,15 @@ type User struct {
 
 // Component represents a project component
 type Component struct {
-	ID          string `json:"id,omitempty"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Lead        *User  `json:"lead,omitempty"`
+	ID           string `json:"id,omitempty"`
+	Self         string `json:"self,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Lead         *User  `json:"lead,omitempty"`
+	AssigneeType string `json:"assigneeType,omitempty"`
+	Project      string `json:"project,omitempty"`
+	ProjectID    int    `json:"projectId,omitempty"`
+	Archived     bool   `json:"archived,omitempty"`
 }
 
 // Version represents a project version
@@ -168,6 +175,8 @@ type Version struct {
 	Released       bool       `json:"released,omitempty"`
 	ReleaseDate    *JiraTime  `json:"releaseDate,omitempty"`
 	UserReleaseDate string    `json:"userReleaseDate,omitempty"`
+	StartDate      *JiraTime  `json:"startDate,omitempty"`
+	Overdue        bool       `json:"overdue,omitempty"`
 	ProjectID      int        `json:"projectId,omitempty"`
 }
 
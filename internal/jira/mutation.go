@@ -0,0 +1,115 @@
+package jira
+
+import (
+	"sort"
+	"strings"
+)
+
+// Mutation operations reported to mutation listeners
+const (
+	OpCreateIssue     = "issue.create"
+	OpUpdateIssue     = "issue.update"
+	OpDeleteIssue     = "issue.delete"
+	OpTransitionIssue = "issue.transition"
+	OpComment         = "issue.comment"
+	OpAttachment      = "issue.attachment"
+	OpWatchers        = "issue.watchers"
+	OpVotes           = "issue.votes"
+	OpRemoteLink      = "issue.remotelink"
+	OpIssueLink       = "issue.link"
+	OpRank            = "issue.rank"
+	OpBacklog         = "board.backlog"
+	OpBoardMove       = "board.move"
+	OpSprint          = "sprint.update"
+	OpSprintIssues    = "sprint.issues"
+	OpComponent       = "project.component"
+	OpVersion         = "project.version"
+)
+
+// Mutation describes the Jira data changed by a successful write made through
+// a Client. Projects holds project keys; the projects of Issues are added
+// automatically. A mutation naming no entity at all, such as deleting an issue
+// link by ID, may have changed any issue.
+type Mutation struct {
+	Operation string   `json:"operation"`
+	Issues    []string `json:"issues,omitempty"`
+	Projects  []string `json:"projects,omitempty"`
+	Sprints   []int    `json:"sprints,omitempty"`
+	Boards    []int    `json:"boards,omitempty"`
+}
+
+// IsEmpty reports whether the mutation names no issue, project, sprint or board
+func (m Mutation) IsEmpty() bool {
+	return len(m.Issues) == 0 && len(m.Projects) == 0 && len(m.Sprints) == 0 && len(m.Boards) == 0
+}
+
+// MutationListener is called after every successful write made through a Client
+type MutationListener func(Mutation)
+
+// OnMutation registers a listener for successful writes. Listeners run
+// synchronously before the write method returns, so that caches are
+// invalidated before the caller can read the changed data again.
+func (c *Client) OnMutation(listener MutationListener) {
+	c.mutationMu.Lock()
+	defer c.mutationMu.Unlock()
+	c.mutationListeners = append(c.mutationListeners, listener)
+}
+
+// notifyMutation normalizes a mutation and passes it to the listeners
+func (c *Client) notifyMutation(m Mutation) {
+	// Reads already in flight may have been answered before the write
+	c.reads.ForgetAll()
+
+	c.mutationMu.RLock()
+	listeners := c.mutationListeners
+	c.mutationMu.RUnlock()
+	if len(listeners) == 0 {
+		return
+	}
+
+	projects := append([]string{}, m.Projects...)
+	for _, key := range m.Issues {
+		if project := ProjectKeyFromIssueKey(key); project != "" {
+			projects = append(projects, project)
+		}
+	}
+	m.Issues = uniqueUpper(m.Issues)
+	m.Projects = uniqueUpper(projects)
+
+	for _, listener := range listeners {
+		listener(m)
+	}
+}
+
+// ProjectKeyFromIssueKey returns the project key of an issue key such as
+// PROJ-123, or an empty string for numeric issue IDs
+func ProjectKeyFromIssueKey(issueKey string) string {
+	i := strings.LastIndex(issueKey, "-")
+	if i <= 0 || i == len(issueKey)-1 {
+		return ""
+	}
+	for _, r := range issueKey[i+1:] {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return strings.ToUpper(issueKey[:i])
+}
+
+// uniqueUpper returns the non-empty values upper-cased, sorted and without duplicates
+func uniqueUpper(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
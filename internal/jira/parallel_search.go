@@ -0,0 +1,214 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jql"
+)
+
+// ParallelSearchOptions controls a bounded-parallel fetch of every search page
+type ParallelSearchOptions struct {
+	MaxPages    int            // pages to fetch including the first; defaults to 100
+	Concurrency int            // pages fetched at the same time; defaults to 4
+	Snapshot    bool           // pin results to issues updated before the search started
+	Location    *time.Location // time zone of the snapshot bound; defaults to the Jira user's
+	RateLimiter *RateLimiter   // shared limiter every page request waits on; nil disables limiting
+}
+
+// ParallelSearchResult is the ordered merge of every fetched page
+type ParallelSearchResult struct {
+	*ExtendedSearchResult
+	Pages      int        `json:"pages"`
+	Duplicates int        `json:"duplicates"`
+	SnapshotAt *time.Time `json:"snapshotAt,omitempty"`
+	JQL        string     `json:"jql"`
+}
+
+// SearchAllPagesParallel fetches the first page to learn the total, then fetches
+// the remaining pages concurrently and merges them back in page order. Issues
+// that shift between pages while paginating are returned only once. With
+// Snapshot set the query is restricted to issues updated no later than the
+// start of the search, so edits made during the fetch cannot reorder results.
+func (c *Client) SearchAllPagesParallel(ctx context.Context, req SearchRequest, opts ParallelSearchOptions) (*ParallelSearchResult, error) {
+	if req.MaxResults <= 0 {
+		req.MaxResults = 50
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = 100
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	var snapshotAt *time.Time
+	var warnings []string
+	if opts.Snapshot {
+		loc := opts.Location
+		if loc == nil {
+			var known bool
+			if loc, known = c.UserLocation(ctx); !known {
+				warnings = append(warnings, "the Jira user's time zone is unknown, so the snapshot bound uses the server's time zone")
+			}
+		}
+
+		// JQL dates have minute precision; rounding up to the next minute
+		// keeps issues updated earlier in the current one
+		now := time.Now()
+		at := now.Truncate(time.Minute)
+		if at.Before(now) {
+			at = at.Add(time.Minute)
+		}
+		at = at.In(loc)
+		pinned, err := snapshotJQL(req.JQL, at)
+		if err != nil {
+			return nil, err
+		}
+		req.JQL = pinned
+		snapshotAt = &at
+	}
+
+	fetch := func(ctx context.Context, startAt int) (*ExtendedSearchResult, error) {
+		if opts.RateLimiter != nil {
+			opts.RateLimiter.Wait()
+		}
+		pageReq := req
+		pageReq.StartAt = startAt
+		return c.SearchIssuesAdvancedContext(ctx, pageReq)
+	}
+
+	first, err := fetch(ctx, req.StartAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page 1: %w", err)
+	}
+
+	pageCount := 1
+	if remaining := first.Total - req.StartAt; remaining > req.MaxResults {
+		pageCount = (remaining + req.MaxResults - 1) / req.MaxResults
+	}
+	if pageCount > opts.MaxPages {
+		pageCount = opts.MaxPages
+	}
+
+	pages := make([]*ExtendedSearchResult, pageCount)
+	pages[0] = first
+
+	// cancel stops the remaining pages once one fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		errPage  int
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+	for i := 1; i < pageCount; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := fetch(ctx, req.StartAt+page*req.MaxResults)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// Pages stopped by the cancellation did not fail themselves
+				if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+					return
+				}
+				// Report the earliest failing page rather than whichever failed first
+				if firstErr == nil || page < errPage {
+					firstErr, errPage = err, page
+				}
+				cancel()
+				return
+			}
+			pages[page] = result
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, fmt.Errorf("failed to fetch page %d: %w", errPage+1, firstErr)
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("parallel search cancelled: %w", ctx.Err())
+	}
+
+	merged, duplicates := mergeSearchPages(pages)
+	merged.WarningMessages = append(merged.WarningMessages, warnings...)
+	if duplicates > 0 {
+		merged.WarningMessages = append(merged.WarningMessages,
+			fmt.Sprintf("%d issues moved between pages during the search and were returned once", duplicates))
+	}
+
+	return &ParallelSearchResult{
+		ExtendedSearchResult: merged,
+		Pages:                pageCount,
+		Duplicates:           duplicates,
+		SnapshotAt:           snapshotAt,
+		JQL:                  req.JQL,
+	}, nil
+}
+
+// mergeSearchPages concatenates pages in order, keeping the first occurrence of
+// each issue, and returns the number of duplicates dropped
+func mergeSearchPages(pages []*ExtendedSearchResult) (*ExtendedSearchResult, int) {
+	merged := CombineSearchResults(pages)
+
+	seen := make(map[string]bool, len(merged.Issues))
+	issues := merged.Issues[:0]
+	for _, issue := range merged.Issues {
+		id := issue.ID
+		if id == "" {
+			id = issue.Key
+		}
+		if id != "" && seen[id] {
+			continue
+		}
+		seen[id] = true
+		issues = append(issues, issue)
+	}
+
+	duplicates := len(merged.Issues) - len(issues)
+	merged.Issues = issues
+	merged.MaxResults = len(issues)
+	return merged, duplicates
+}
+
+// snapshotJQL restricts a query to issues updated no later than at, keeping its
+// ORDER BY clause. JQL dates are read in the Jira user's time zone, so at must
+// be in that zone; it is formatted in its own location.
+func snapshotJQL(query string, at time.Time) (string, error) {
+	parsed, err := ParseJQL(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to pin query to snapshot: %w", err)
+	}
+
+	bound := at.Format("2006-01-02 15:04")
+	pin, err := ParseJQL("updated <= " + jql.Quote(bound))
+	if err != nil {
+		return "", fmt.Errorf("failed to pin query to snapshot: %w", err)
+	}
+
+	if parsed.Where == nil {
+		parsed.Where = pin.Where
+	} else {
+		parsed.Where = &JQLBinaryExpr{Op: "AND", Left: parsed.Where, Right: pin.Where}
+	}
+	return parsed.String(), nil
+}
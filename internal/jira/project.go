@@ -0,0 +1,449 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CreateComponentRequest represents a request to create or update a project component
+type CreateComponentRequest struct {
+	Name          string `json:"name,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Project       string `json:"project,omitempty"`
+	LeadAccountID string `json:"leadAccountId,omitempty"`
+	AssigneeType  string `json:"assigneeType,omitempty"` // PROJECT_DEFAULT, COMPONENT_LEAD, PROJECT_LEAD, UNASSIGNED
+	Archived      *bool  `json:"archived,omitempty"`
+}
+
+// CreateVersionRequest represents a request to create or update a project version
+type CreateVersionRequest struct {
+	Name                string `json:"name,omitempty"`
+	Description         string `json:"description,omitempty"`
+	Project             string `json:"project,omitempty"`
+	StartDate           string `json:"startDate,omitempty"`   // yyyy-MM-dd
+	ReleaseDate         string `json:"releaseDate,omitempty"` // yyyy-MM-dd
+	Released            *bool  `json:"released,omitempty"`
+	Archived            *bool  `json:"archived,omitempty"`
+	MoveUnfixedIssuesTo string `json:"moveUnfixedIssuesTo,omitempty"`
+}
+
+// ReleaseVersionOptions controls how a version is released
+type ReleaseVersionOptions struct {
+	ReleaseDate time.Time `json:"releaseDate,omitempty"`
+	// MoveUnfixedIssuesTo is the ID of the version that unresolved issues are moved to.
+	// When empty, releasing a version with unresolved issues fails unless Force is set.
+	MoveUnfixedIssuesTo string `json:"moveUnfixedIssuesTo,omitempty"`
+	Force               bool   `json:"force,omitempty"`
+}
+
+// VersionIssueCounts represents issue counts related to a version
+type VersionIssueCounts struct {
+	Self                       string `json:"self,omitempty"`
+	IssuesFixedCount           int    `json:"issuesFixedCount"`
+	IssuesAffectedCount        int    `json:"issuesAffectedCount"`
+	IssueCountWithCustomFields int    `json:"issueCountWithCustomFieldsShowingVersion"`
+	IssuesUnresolvedCount      int    `json:"issuesUnresolvedCount"`
+}
+
+// ProjectRole represents a project role and its members
+type ProjectRole struct {
+	ID          int         `json:"id"`
+	Self        string      `json:"self,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Actors      []RoleActor `json:"actors,omitempty"`
+}
+
+// RoleActor represents a user or group assigned to a project role
+type RoleActor struct {
+	ID          int    `json:"id"`
+	DisplayName string `json:"displayName"`
+	Type        string `json:"type"` // atlassian-user-role-actor, atlassian-group-role-actor
+	Name        string `json:"name,omitempty"`
+	ActorUser   *struct {
+		AccountID string `json:"accountId"`
+	} `json:"actorUser,omitempty"`
+	ActorGroup *struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName,omitempty"`
+	} `json:"actorGroup,omitempty"`
+}
+
+// ProjectRoleSummary represents a project role reference returned by the roles listing
+type ProjectRoleSummary struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Self string `json:"self"`
+}
+
+// GetProjectComponents retrieves all components of a project
+func (c *Client) GetProjectComponents(ctx context.Context, projectKey string) ([]Component, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/project/%s/components", projectKey)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get components for project %s: %w", projectKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var components []Component
+	if err := json.Unmarshal(resp.Body(), &components); err != nil {
+		return nil, fmt.Errorf("failed to decode components: %w", err)
+	}
+
+	return components, nil
+}
+
+// GetComponent retrieves a component by ID
+func (c *Client) GetComponent(ctx context.Context, componentID string) (*Component, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/component/%s", componentID)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get component %s: %w", componentID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var component Component
+	if err := json.Unmarshal(resp.Body(), &component); err != nil {
+		return nil, fmt.Errorf("failed to decode component: %w", err)
+	}
+
+	return &component, nil
+}
+
+// CreateComponent creates a new project component
+func (c *Client) CreateComponent(ctx context.Context, req *CreateComponentRequest) (*Component, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("component name is required")
+	}
+	if req.Project == "" {
+		return nil, fmt.Errorf("component project is required")
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/rest/api/2/component", req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create component: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var component Component
+	if err := json.Unmarshal(resp.Body(), &component); err != nil {
+		return nil, fmt.Errorf("failed to decode created component: %w", err)
+	}
+
+	c.notifyMutation(Mutation{Operation: OpComponent, Projects: []string{req.Project}})
+	return &component, nil
+}
+
+// UpdateComponent updates an existing component
+func (c *Client) UpdateComponent(ctx context.Context, componentID string, req *CreateComponentRequest) (*Component, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/component/%s", componentID)
+
+	resp, err := c.doRequest(ctx, "PUT", endpoint, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update component %s: %w", componentID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var component Component
+	if err := json.Unmarshal(resp.Body(), &component); err != nil {
+		return nil, fmt.Errorf("failed to decode updated component: %w", err)
+	}
+
+	c.notifyMutation(Mutation{Operation: OpComponent, Projects: []string{component.Project}})
+	return &component, nil
+}
+
+// ArchiveComponent archives a component so it can no longer be used on new issues
+func (c *Client) ArchiveComponent(ctx context.Context, componentID string) (*Component, error) {
+	archived := true
+	return c.UpdateComponent(ctx, componentID, &CreateComponentRequest{Archived: &archived})
+}
+
+// DeleteComponent deletes a component, optionally moving its issues to another component
+func (c *Client) DeleteComponent(ctx context.Context, componentID string, moveIssuesTo string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/component/%s", componentID)
+	if moveIssuesTo != "" {
+		endpoint += "?moveIssuesTo=" + url.QueryEscape(moveIssuesTo)
+	}
+
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete component %s: %w", componentID, err)
+	}
+
+	if resp.StatusCode() != http.StatusNoContent && resp.StatusCode() != http.StatusOK {
+		return c.handleErrorResponse(resp)
+	}
+
+	// Deleting a component changes the issues that used it, in a project the ID does not tell
+	c.notifyMutation(Mutation{Operation: OpComponent})
+	return nil
+}
+
+// GetProjectVersions retrieves all versions of a project
+func (c *Client) GetProjectVersions(ctx context.Context, projectKey string) ([]Version, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/project/%s/versions", projectKey)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions for project %s: %w", projectKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var versions []Version
+	if err := json.Unmarshal(resp.Body(), &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves a version by ID
+func (c *Client) GetVersion(ctx context.Context, versionID string) (*Version, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/version/%s", versionID)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s: %w", versionID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var version Version
+	if err := json.Unmarshal(resp.Body(), &version); err != nil {
+		return nil, fmt.Errorf("failed to decode version: %w", err)
+	}
+
+	return &version, nil
+}
+
+// CreateVersion creates a new project version
+func (c *Client) CreateVersion(ctx context.Context, req *CreateVersionRequest) (*Version, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("version name is required")
+	}
+	if req.Project == "" {
+		return nil, fmt.Errorf("version project is required")
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/rest/api/2/version", req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create version: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var version Version
+	if err := json.Unmarshal(resp.Body(), &version); err != nil {
+		return nil, fmt.Errorf("failed to decode created version: %w", err)
+	}
+
+	c.notifyMutation(Mutation{Operation: OpVersion, Projects: []string{req.Project}})
+	return &version, nil
+}
+
+// UpdateVersion updates an existing version
+func (c *Client) UpdateVersion(ctx context.Context, versionID string, req *CreateVersionRequest) (*Version, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/version/%s", versionID)
+
+	resp, err := c.doRequest(ctx, "PUT", endpoint, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update version %s: %w", versionID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var version Version
+	if err := json.Unmarshal(resp.Body(), &version); err != nil {
+		return nil, fmt.Errorf("failed to decode updated version: %w", err)
+	}
+
+	// Versions only carry the numeric project ID, so any project may be affected
+	c.notifyMutation(Mutation{Operation: OpVersion})
+	return &version, nil
+}
+
+// ArchiveVersion archives a version
+func (c *Client) ArchiveVersion(ctx context.Context, versionID string) (*Version, error) {
+	archived := true
+	return c.UpdateVersion(ctx, versionID, &CreateVersionRequest{Archived: &archived})
+}
+
+// GetVersionIssueCounts retrieves fixed, affected and unresolved issue counts for a version
+func (c *Client) GetVersionIssueCounts(ctx context.Context, versionID string) (*VersionIssueCounts, error) {
+	counts := &VersionIssueCounts{}
+
+	endpoint := fmt.Sprintf("/rest/api/2/version/%s/relatedIssueCounts", versionID)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue counts for version %s: %w", versionID, err)
+	}
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(resp.Body(), counts); err != nil {
+		return nil, fmt.Errorf("failed to decode version issue counts: %w", err)
+	}
+
+	endpoint = fmt.Sprintf("/rest/api/2/version/%s/unresolvedIssueCount", versionID)
+	resp, err = c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unresolved issue count for version %s: %w", versionID, err)
+	}
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var unresolved struct {
+		IssuesUnresolvedCount int `json:"issuesUnresolvedCount"`
+	}
+	if err := json.Unmarshal(resp.Body(), &unresolved); err != nil {
+		return nil, fmt.Errorf("failed to decode unresolved issue count: %w", err)
+	}
+	counts.IssuesUnresolvedCount = unresolved.IssuesUnresolvedCount
+
+	return counts, nil
+}
+
+// ReleaseVersion marks a version as released. Unresolved issues are moved to
+// opts.MoveUnfixedIssuesTo when set; otherwise the release is refused while
+// unresolved issues remain, unless opts.Force is true.
+func (c *Client) ReleaseVersion(ctx context.Context, versionID string, opts ReleaseVersionOptions) (*Version, error) {
+	if opts.MoveUnfixedIssuesTo == versionID && versionID != "" {
+		return nil, fmt.Errorf("cannot move unresolved issues to the version being released")
+	}
+
+	req := &CreateVersionRequest{}
+	released := true
+	req.Released = &released
+
+	releaseDate := opts.ReleaseDate
+	if releaseDate.IsZero() {
+		releaseDate = time.Now()
+	}
+	req.ReleaseDate = releaseDate.Format("2006-01-02")
+
+	if opts.MoveUnfixedIssuesTo != "" {
+		target, err := c.GetVersion(ctx, opts.MoveUnfixedIssuesTo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve target version: %w", err)
+		}
+		if target.Released || target.Archived {
+			return nil, fmt.Errorf("target version %s is already released or archived", target.Name)
+		}
+		req.MoveUnfixedIssuesTo = c.baseURL + "/rest/api/2/version/" + opts.MoveUnfixedIssuesTo
+	} else if !opts.Force {
+		counts, err := c.GetVersionIssueCounts(ctx, versionID)
+		if err != nil {
+			return nil, err
+		}
+		if counts.IssuesUnresolvedCount > 0 {
+			return nil, fmt.Errorf("version %s has %d unresolved issues; specify a version to move them to or force the release",
+				versionID, counts.IssuesUnresolvedCount)
+		}
+	}
+
+	return c.UpdateVersion(ctx, versionID, req)
+}
+
+// GetProjectRoles retrieves the roles defined for a project
+func (c *Client) GetProjectRoles(ctx context.Context, projectKey string) ([]ProjectRoleSummary, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/project/%s/role", projectKey)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles for project %s: %w", projectKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	// Jira returns a map of role name to role URL
+	var roleURLs map[string]string
+	if err := json.Unmarshal(resp.Body(), &roleURLs); err != nil {
+		return nil, fmt.Errorf("failed to decode project roles: %w", err)
+	}
+
+	roles := make([]ProjectRoleSummary, 0, len(roleURLs))
+	for name, self := range roleURLs {
+		summary := ProjectRoleSummary{Name: name, Self: self}
+		if idx := strings.LastIndex(self, "/"); idx >= 0 {
+			if id, err := strconv.Atoi(self[idx+1:]); err == nil {
+				summary.ID = id
+			}
+		}
+		roles = append(roles, summary)
+	}
+
+	// Map order is random; keep responses stable between calls
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].ID != roles[j].ID {
+			return roles[i].ID < roles[j].ID
+		}
+		return roles[i].Name < roles[j].Name
+	})
+
+	return roles, nil
+}
+
+// GetProjectRole retrieves a project role along with its members
+func (c *Client) GetProjectRole(ctx context.Context, projectKey string, roleID int) (*ProjectRole, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/project/%s/role/%d", projectKey, roleID)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role %d for project %s: %w", roleID, projectKey, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var role ProjectRole
+	if err := json.Unmarshal(resp.Body(), &role); err != nil {
+		return nil, fmt.Errorf("failed to decode project role: %w", err)
+	}
+
+	return &role, nil
+}
+
+// GetProjectIssueTypes retrieves the issue types available in a project's issue type scheme
+func (c *Client) GetProjectIssueTypes(ctx context.Context, projectKey string) ([]IssueType, error) {
+	project, err := c.GetProject(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return project.IssueTypes, nil
+}
@@ -0,0 +1,217 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// maxRankBatch is the maximum number of issues the agile rank endpoint accepts per call
+const maxRankBatch = 50
+
+// RankIssuesRequest represents a request to the agile rank endpoint
+type RankIssuesRequest struct {
+	Issues            []string `json:"issues"`
+	RankBeforeIssue   string   `json:"rankBeforeIssue,omitempty"`
+	RankAfterIssue    string   `json:"rankAfterIssue,omitempty"`
+	RankCustomFieldID int      `json:"rankCustomFieldId,omitempty"`
+}
+
+// RankEntry is the outcome of ranking a single issue
+type RankEntry struct {
+	IssueID  int      `json:"issueId,omitempty"`
+	IssueKey string   `json:"issueKey"`
+	Status   int      `json:"status"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// RankResult summarises a (possibly batched) rank operation
+type RankResult struct {
+	Ranked  int         `json:"ranked"`
+	Failed  int         `json:"failed"`
+	Batches int         `json:"batches"`
+	Entries []RankEntry `json:"entries,omitempty"`
+}
+
+// FailedIssues returns the keys of the issues that could not be ranked
+func (r *RankResult) FailedIssues() []string {
+	var keys []string
+	for _, entry := range r.Entries {
+		if entry.Status < http.StatusBadRequest {
+			continue
+		}
+		key := entry.IssueKey
+		if key == "" {
+			key = strconv.Itoa(entry.IssueID)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// RankIssues ranks issues before or after another issue, keeping their relative
+// order. Requests larger than the 50 issue limit are split into batches; each
+// batch after the first is ranked after the last issue of the previous batch.
+func (c *Client) RankIssues(issueKeys []string, rankBefore, rankAfter string, rankCustomFieldID int) (*RankResult, error) {
+	if len(issueKeys) == 0 {
+		return nil, fmt.Errorf("at least one issue is required")
+	}
+	if (rankBefore == "") == (rankAfter == "") {
+		return nil, fmt.Errorf("exactly one of rankBeforeIssue or rankAfterIssue is required")
+	}
+
+	result := &RankResult{}
+	for start := 0; start < len(issueKeys); start += maxRankBatch {
+		end := start + maxRankBatch
+		if end > len(issueKeys) {
+			end = len(issueKeys)
+		}
+
+		req := &RankIssuesRequest{
+			Issues:            issueKeys[start:end],
+			RankCustomFieldID: rankCustomFieldID,
+		}
+		if start == 0 {
+			req.RankBeforeIssue = rankBefore
+			req.RankAfterIssue = rankAfter
+		} else {
+			req.RankAfterIssue = issueKeys[start-1]
+		}
+
+		entries, err := c.rankBatch(req)
+		if err != nil {
+			return result, fmt.Errorf("failed to rank issues %d-%d: %w", start+1, end, err)
+		}
+
+		result.Batches++
+		if entries == nil {
+			result.Ranked += len(req.Issues)
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Status >= http.StatusBadRequest {
+				result.Failed++
+			} else {
+				result.Ranked++
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	return result, nil
+}
+
+// RankBefore ranks issues directly before another issue
+func (c *Client) RankBefore(issueKeys []string, issueKey string) (*RankResult, error) {
+	return c.RankIssues(issueKeys, issueKey, "", 0)
+}
+
+// RankAfter ranks issues directly after another issue
+func (c *Client) RankAfter(issueKeys []string, issueKey string) (*RankResult, error) {
+	return c.RankIssues(issueKeys, "", issueKey, 0)
+}
+
+// rankBatch sends one rank request. Entries are only returned for a partial
+// success (207); a 204 means every issue was ranked.
+func (c *Client) rankBatch(req *RankIssuesRequest) ([]RankEntry, error) {
+	resp, err := c.doRequest(context.Background(), "PUT", "/rest/agile/1.0/issue/rank", req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	c.notifyMutation(Mutation{Operation: OpRank, Issues: req.Issues})
+
+	if resp.StatusCode() != http.StatusMultiStatus || len(resp.Body()) == 0 {
+		return nil, nil
+	}
+
+	var result struct {
+		Entries []RankEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode rank result: %w", err)
+	}
+
+	return result.Entries, nil
+}
+
+// GetBacklogIssueKeys returns the keys of all backlog issues of a board in rank order
+func (c *Client) GetBacklogIssueKeys(boardID int) ([]string, error) {
+	var keys []string
+	for startAt := 0; ; {
+		endpoint := fmt.Sprintf("/rest/agile/1.0/board/%d/backlog?fields=key&startAt=%d&maxResults=100", boardID, startAt)
+
+		resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get board backlog: %w", err)
+		}
+
+		if err := c.handleErrorResponse(resp); err != nil {
+			return nil, err
+		}
+
+		var page BoardIssueList
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return nil, fmt.Errorf("failed to parse board backlog: %w", err)
+		}
+
+		for _, issue := range page.Issues {
+			keys = append(keys, issue.Key)
+		}
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
+// RankInBacklog moves issues to an explicit position in a board's backlog.
+// An index of 0 moves them to the top; an index at or beyond the end of the
+// backlog (or a negative index) moves them to the bottom.
+func (c *Client) RankInBacklog(boardID int, issueKeys []string, index int) (*RankResult, error) {
+	backlog, err := c.GetBacklogIssueKeys(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	moving := make(map[string]bool, len(issueKeys))
+	for _, key := range issueKeys {
+		moving[key] = true
+	}
+
+	others := make([]string, 0, len(backlog))
+	for _, key := range backlog {
+		if !moving[key] {
+			others = append(others, key)
+		}
+	}
+
+	if len(others) == 0 {
+		// Nothing to rank against: the issues already make up the whole backlog
+		return &RankResult{}, nil
+	}
+
+	if index < 0 || index >= len(others) {
+		return c.RankAfter(issueKeys, others[len(others)-1])
+	}
+	return c.RankBefore(issueKeys, others[index])
+}
+
+// RankToTop moves issues to the top of a board's backlog
+func (c *Client) RankToTop(boardID int, issueKeys []string) (*RankResult, error) {
+	return c.RankInBacklog(boardID, issueKeys, 0)
+}
+
+// RankToBottom moves issues to the bottom of a board's backlog
+func (c *Client) RankToBottom(boardID int, issueKeys []string) (*RankResult, error) {
+	return c.RankInBacklog(boardID, issueKeys, -1)
+}
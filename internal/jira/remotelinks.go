@@ -0,0 +1,385 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RemoteLink represents a link from an issue to an external resource
+type RemoteLink struct {
+	ID           int                    `json:"id,omitempty"`
+	Self         string                 `json:"self,omitempty"`
+	GlobalID     string                 `json:"globalId,omitempty"`
+	Application  *RemoteLinkApplication `json:"application,omitempty"`
+	Relationship string                 `json:"relationship,omitempty"`
+	Object       RemoteLinkObject       `json:"object"`
+}
+
+// RemoteLinkApplication identifies the application that owns the linked resource
+type RemoteLinkApplication struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// RemoteLinkObject describes the linked resource
+type RemoteLinkObject struct {
+	URL     string            `json:"url"`
+	Title   string            `json:"title"`
+	Summary string            `json:"summary,omitempty"`
+	Icon    *RemoteLinkIcon   `json:"icon,omitempty"`
+	Status  *RemoteLinkStatus `json:"status,omitempty"`
+}
+
+// RemoteLinkIcon is an icon shown next to a remote link
+type RemoteLinkIcon struct {
+	URL16x16 string `json:"url16x16,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
+// RemoteLinkStatus describes the state of the linked resource. Resolved links are shown struck through.
+type RemoteLinkStatus struct {
+	Resolved bool            `json:"resolved"`
+	Icon     *RemoteLinkIcon `json:"icon,omitempty"`
+}
+
+// RemoteLinkResult is returned when a remote link is created or updated
+type RemoteLinkResult struct {
+	ID      int    `json:"id"`
+	Self    string `json:"self"`
+	Created bool   `json:"created"`
+}
+
+// Global ID prefixes used by the remote link helpers
+const (
+	remoteLinkCommitPrefix = "git-commit="
+	remoteLinkPRPrefix     = "pull-request="
+	remoteLinkCIPrefix     = "ci-run="
+	remoteLinkTitleLimit   = 255
+)
+
+// GetRemoteLinks retrieves all remote links of an issue
+func (c *Client) GetRemoteLinks(issueKey string) ([]RemoteLink, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink", issueKey)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote links: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var links []RemoteLink
+	if err := json.Unmarshal(resp.Body(), &links); err != nil {
+		return nil, fmt.Errorf("failed to decode remote links: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetRemoteLink retrieves a remote link by ID
+func (c *Client) GetRemoteLink(issueKey string, linkID int) (*RemoteLink, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink/%d", issueKey, linkID)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote link: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var link RemoteLink
+	if err := json.Unmarshal(resp.Body(), &link); err != nil {
+		return nil, fmt.Errorf("failed to decode remote link: %w", err)
+	}
+
+	return &link, nil
+}
+
+// GetRemoteLinkByGlobalID retrieves the remote link with the given global ID.
+// It returns nil without an error when the issue has no such link.
+func (c *Client) GetRemoteLinkByGlobalID(issueKey, globalID string) (*RemoteLink, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink?globalId=%s", issueKey, url.QueryEscape(globalID))
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote link: %w", err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	// Depending on the deployment a single object or a list is returned
+	body := strings.TrimSpace(string(resp.Body()))
+	if strings.HasPrefix(body, "[") {
+		var links []RemoteLink
+		if err := json.Unmarshal(resp.Body(), &links); err != nil {
+			return nil, fmt.Errorf("failed to decode remote link: %w", err)
+		}
+		for i := range links {
+			if links[i].GlobalID == globalID {
+				return &links[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	var link RemoteLink
+	if err := json.Unmarshal(resp.Body(), &link); err != nil {
+		return nil, fmt.Errorf("failed to decode remote link: %w", err)
+	}
+	return &link, nil
+}
+
+// UpsertRemoteLink creates a remote link, or updates the existing link with
+// the same global ID. Links without a global ID are keyed by their URL so that
+// repeated calls never create duplicates.
+func (c *Client) UpsertRemoteLink(issueKey string, link *RemoteLink) (*RemoteLinkResult, error) {
+	if link.Object.URL == "" {
+		return nil, fmt.Errorf("remote link url is required")
+	}
+	if link.Object.Title == "" {
+		link.Object.Title = link.Object.URL
+	}
+	if len(link.Object.Title) > remoteLinkTitleLimit {
+		link.Object.Title = link.Object.Title[:remoteLinkTitleLimit-3] + "..."
+	}
+	if link.GlobalID == "" {
+		link.GlobalID = link.Object.URL
+	}
+
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink", issueKey)
+
+	// Jira updates the existing link when the global ID already exists
+	resp, err := c.doRequest(context.Background(), "POST", endpoint, link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save remote link: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result RemoteLinkResult
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode remote link result: %w", err)
+	}
+	result.Created = resp.StatusCode() == http.StatusCreated
+
+	c.notifyMutation(Mutation{Operation: OpRemoteLink, Issues: []string{issueKey}})
+	return &result, nil
+}
+
+// UpdateRemoteLink replaces a remote link by ID
+func (c *Client) UpdateRemoteLink(issueKey string, linkID int, link *RemoteLink) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink/%d", issueKey, linkID)
+
+	resp, err := c.doRequest(context.Background(), "PUT", endpoint, link)
+	if err != nil {
+		return fmt.Errorf("failed to update remote link: %w", err)
+	}
+
+	return c.remoteLinkChanged(issueKey, resp)
+}
+
+// DeleteRemoteLink deletes a remote link by ID
+func (c *Client) DeleteRemoteLink(issueKey string, linkID int) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink/%d", issueKey, linkID)
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete remote link: %w", err)
+	}
+
+	return c.remoteLinkChanged(issueKey, resp)
+}
+
+// DeleteRemoteLinkByGlobalID deletes the remote link with the given global ID
+func (c *Client) DeleteRemoteLinkByGlobalID(issueKey, globalID string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/remotelink?globalId=%s", issueKey, url.QueryEscape(globalID))
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete remote link: %w", err)
+	}
+
+	return c.remoteLinkChanged(issueKey, resp)
+}
+
+// remoteLinkChanged checks the response of a remote link write and reports the change
+func (c *Client) remoteLinkChanged(issueKey string, resp *resty.Response) error {
+	if err := c.handleErrorResponse(resp); err != nil {
+		return err
+	}
+	c.notifyMutation(Mutation{Operation: OpRemoteLink, Issues: []string{issueKey}})
+	return nil
+}
+
+// CommitLinkOptions describes a git commit to link to an issue
+type CommitLinkOptions struct {
+	RepositoryURL string `json:"repositoryUrl"`
+	SHA           string `json:"sha"`
+	Message       string `json:"message,omitempty"`
+	Author        string `json:"author,omitempty"`
+	URL           string `json:"url,omitempty"` // defaults to <repositoryUrl>/commit/<sha>
+}
+
+// PullRequestLinkOptions describes a pull request to link to an issue
+type PullRequestLinkOptions struct {
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	Number     int    `json:"number,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	State      string `json:"state,omitempty"` // open, merged, closed, declined
+	Author     string `json:"author,omitempty"`
+}
+
+// CIRunLinkOptions describes a CI run to link to an issue
+type CIRunLinkOptions struct {
+	URL      string `json:"url"`
+	Pipeline string `json:"pipeline,omitempty"`
+	RunID    string `json:"runId,omitempty"`
+	Status   string `json:"status,omitempty"` // pending, running, success, failed, cancelled
+	Provider string `json:"provider,omitempty"`
+}
+
+// NewCommitRemoteLink builds a remote link for a git commit. The global ID is
+// derived from the repository and SHA so relinking the same commit is idempotent.
+func NewCommitRemoteLink(opts CommitLinkOptions) (*RemoteLink, error) {
+	if opts.RepositoryURL == "" || opts.SHA == "" {
+		return nil, fmt.Errorf("repositoryUrl and sha are required")
+	}
+
+	repo := strings.TrimSuffix(strings.TrimSuffix(opts.RepositoryURL, "/"), ".git")
+	linkURL := opts.URL
+	if linkURL == "" {
+		linkURL = repo + "/commit/" + opts.SHA
+	}
+
+	shortSHA := opts.SHA
+	if len(shortSHA) > 7 {
+		shortSHA = shortSHA[:7]
+	}
+
+	title := shortSHA
+	if subject := firstLine(opts.Message); subject != "" {
+		title = shortSHA + " " + subject
+	}
+
+	summary := opts.Message
+	if opts.Author != "" {
+		summary = strings.TrimSpace(fmt.Sprintf("%s (by %s)", firstLine(opts.Message), opts.Author))
+	}
+
+	return &RemoteLink{
+		GlobalID:     remoteLinkCommitPrefix + repo + "@" + opts.SHA,
+		Application:  &RemoteLinkApplication{Type: "com.git", Name: "Git"},
+		Relationship: "commit",
+		Object: RemoteLinkObject{
+			URL:     linkURL,
+			Title:   title,
+			Summary: summary,
+		},
+	}, nil
+}
+
+// NewPullRequestRemoteLink builds a remote link for a pull request. Merged,
+// closed and declined pull requests are marked as resolved.
+func NewPullRequestRemoteLink(opts PullRequestLinkOptions) (*RemoteLink, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+
+	title := opts.Title
+	if opts.Number > 0 {
+		title = strings.TrimSpace(fmt.Sprintf("#%d %s", opts.Number, opts.Title))
+	}
+	if title == "" {
+		title = opts.URL
+	}
+
+	var details []string
+	if opts.Repository != "" {
+		details = append(details, opts.Repository)
+	}
+	if opts.State != "" {
+		details = append(details, strings.ToLower(opts.State))
+	}
+	if opts.Author != "" {
+		details = append(details, "by "+opts.Author)
+	}
+
+	state := strings.ToLower(opts.State)
+	return &RemoteLink{
+		GlobalID:     remoteLinkPRPrefix + opts.URL,
+		Application:  &RemoteLinkApplication{Type: "com.git.pullrequest", Name: "Pull Request"},
+		Relationship: "pull request",
+		Object: RemoteLinkObject{
+			URL:     opts.URL,
+			Title:   title,
+			Summary: strings.Join(details, " · "),
+			Status: &RemoteLinkStatus{
+				Resolved: state == "merged" || state == "closed" || state == "declined",
+			},
+		},
+	}, nil
+}
+
+// NewCIRunRemoteLink builds a remote link for a CI run. Finished runs are marked as resolved.
+func NewCIRunRemoteLink(opts CIRunLinkOptions) (*RemoteLink, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+
+	title := opts.Pipeline
+	if title == "" {
+		title = "CI run"
+	}
+	if opts.RunID != "" {
+		title += " #" + opts.RunID
+	}
+	if opts.Status != "" {
+		title += " (" + strings.ToLower(opts.Status) + ")"
+	}
+
+	provider := opts.Provider
+	if provider == "" {
+		provider = "CI"
+	}
+
+	status := strings.ToLower(opts.Status)
+	return &RemoteLink{
+		GlobalID:     remoteLinkCIPrefix + opts.URL,
+		Application:  &RemoteLinkApplication{Type: "com.ci", Name: provider},
+		Relationship: "build",
+		Object: RemoteLinkObject{
+			URL:   opts.URL,
+			Title: title,
+			Status: &RemoteLinkStatus{
+				Resolved: status == "success" || status == "failed" || status == "cancelled",
+			},
+		},
+	}, nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
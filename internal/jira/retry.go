@@ -106,6 +106,26 @@ func isNetworkError(err error) bool {
 	return false
 }
 
+// IsUnreachable reports whether err means Jira could not be reached: a
+// network failure, or a gateway or availability error returned by a proxy in
+// front of Jira. Requests failing this way were not processed by Jira.
+func IsUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isNetworkError(err) {
+		return true
+	}
+
+	errMsg := err.Error()
+	for _, code := range []string{"502", "503", "504"} {
+		if strings.Contains(errMsg, "jira API error: "+code) || strings.Contains(errMsg, "status "+code) {
+			return true
+		}
+	}
+	return false
+}
+
 // WithRetry executes a function with retry logic
 func WithRetry(ctx context.Context, config *RetryConfig, operation func() (interface{}, int, error)) (interface{}, error) {
 	if config == nil {
@@ -56,16 +56,25 @@ type SearchFilter struct {
 
 // SharePermission represents filter sharing permissions
 type SharePermission struct {
-	ID    int    `json:"id"`
-	Type  string `json:"type"` // global, project, group, user
-	Value string `json:"value,omitempty"`
+	ID      int          `json:"id"`
+	Type    string       `json:"type"` // global, authenticated, loggedin, project, projectRole, group, user
+	Value   string       `json:"value,omitempty"`
+	Project *Project     `json:"project,omitempty"`
+	Role    *ProjectRole `json:"role,omitempty"`
+	Group   *GroupRef    `json:"group,omitempty"`
+	User    *User        `json:"user,omitempty"`
 }
 
 // SearchIssuesAdvanced performs an advanced search using POST method with full request body
 func (c *Client) SearchIssuesAdvanced(req SearchRequest) (*ExtendedSearchResult, error) {
+	return c.SearchIssuesAdvancedContext(context.Background(), req)
+}
+
+// SearchIssuesAdvancedContext performs an advanced search that is aborted when ctx is cancelled
+func (c *Client) SearchIssuesAdvancedContext(ctx context.Context, req SearchRequest) (*ExtendedSearchResult, error) {
 	endpoint := "/rest/api/2/search"
 	
-	resp, err := c.doRequest(context.Background(), "POST", endpoint, req)
+	resp, err := c.doRequest(ctx, "POST", endpoint, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
@@ -231,30 +240,13 @@ func (c *Client) SearchWithFilter(filterID string, startAt, maxResults int) (*Ex
 
 // GetJQLFields retrieves all available JQL fields for autocomplete
 func (c *Client) GetJQLFields() ([]string, error) {
-	endpoint := "/rest/api/2/jql/autocompletedata"
-	
-	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	data, err := c.GetJQLAutocompleteData()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get JQL fields: %w", err)
 	}
 
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("failed to get JQL fields, status: %d", resp.StatusCode())
-	}
-
-	var result struct {
-		VisibleFieldNames []struct {
-			Value       string `json:"value"`
-			DisplayName string `json:"displayName"`
-		} `json:"visibleFieldNames"`
-	}
-	
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
-		return nil, fmt.Errorf("failed to decode JQL fields: %w", err)
-	}
-
 	var fields []string
-	for _, field := range result.VisibleFieldNames {
+	for _, field := range data.VisibleFieldNames {
 		fields = append(fields, field.Value)
 	}
 
@@ -263,30 +255,13 @@ func (c *Client) GetJQLFields() ([]string, error) {
 
 // GetJQLFunctions retrieves all available JQL functions
 func (c *Client) GetJQLFunctions() ([]string, error) {
-	endpoint := "/rest/api/2/jql/autocompletedata"
-	
-	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	data, err := c.GetJQLAutocompleteData()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get JQL functions: %w", err)
 	}
 
-	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("failed to get JQL functions, status: %d", resp.StatusCode())
-	}
-
-	var result struct {
-		VisibleFunctionNames []struct {
-			Value       string `json:"value"`
-			DisplayName string `json:"displayName"`
-		} `json:"visibleFunctionNames"`
-	}
-	
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
-		return nil, fmt.Errorf("failed to decode JQL functions: %w", err)
-	}
-
 	var functions []string
-	for _, function := range result.VisibleFunctionNames {
+	for _, function := range data.VisibleFunctionNames {
 		functions = append(functions, function.Value)
 	}
 
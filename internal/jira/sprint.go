@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -113,22 +114,19 @@ func (c *Client) newRequest() *resty.Request {
 
 // GetSprints retrieves all sprints for a board
 func (c *Client) GetSprints(boardID int) (*SprintList, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint", c.baseURL, boardID)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	endpoint := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint", boardID)
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sprints: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var sprintList SprintList
-	if err := json.Unmarshal(resp.Body(), &sprintList); err != nil {
+	if err := json.Unmarshal(body, &sprintList); err != nil {
 		return nil, fmt.Errorf("failed to parse sprint list: %w", err)
 	}
 	
@@ -182,6 +180,7 @@ func (c *Client) CreateSprint(req *CreateSprintRequest) (*Sprint, error) {
 		return nil, fmt.Errorf("failed to parse created sprint: %w", err)
 	}
 	
+	c.notifyMutation(Mutation{Operation: OpSprint, Sprints: []int{sprint.ID}, Boards: []int{req.OriginBoardID}})
 	return &sprint, nil
 }
 
@@ -208,6 +207,11 @@ func (c *Client) UpdateSprint(sprintID int, req *UpdateSprintRequest) (*Sprint,
 		return nil, fmt.Errorf("failed to parse updated sprint: %w", err)
 	}
 	
+	mutation := Mutation{Operation: OpSprint, Sprints: []int{sprintID}}
+	if sprint.OriginBoardID != 0 {
+		mutation.Boards = []int{sprint.OriginBoardID}
+	}
+	c.notifyMutation(mutation)
 	return &sprint, nil
 }
 
@@ -233,24 +237,32 @@ func (c *Client) CloseSprint(sprintID int) error {
 	return err
 }
 
-// GetSprintIssues retrieves issues in a sprint
+// GetSprintIssues retrieves issues in a sprint. Concurrent requests share one
+// Jira call and the returned list.
 func (c *Client) GetSprintIssues(sprintID int) (*SprintIssueList, error) {
-	url := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue", c.baseURL, sprintID)
-	
-	resp, err := c.newRequest().
-		SetHeader("Accept", "application/json").
-		Get(url)
+	v, err, _ := c.reads.Do(context.Background(), fmt.Sprintf("sprint:%d/issue", sprintID), func(context.Context) (interface{}, error) {
+		return c.getSprintIssues(sprintID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*SprintIssueList), nil
+}
+
+func (c *Client) getSprintIssues(sprintID int) (*SprintIssueList, error) {
+	endpoint := fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID)
 	
+	body, resp, err := c.doValidatedGet(context.Background(), endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sprint issues: %w", err)
 	}
 	
-	if resp.IsError() {
+	if body == nil {
 		return nil, c.handleErrorResponse(resp)
 	}
 	
 	var issueList SprintIssueList
-	if err := json.Unmarshal(resp.Body(), &issueList); err != nil {
+	if err := json.Unmarshal(body, &issueList); err != nil {
 		return nil, fmt.Errorf("failed to parse sprint issues: %w", err)
 	}
 	
@@ -278,6 +290,7 @@ func (c *Client) MoveIssuesToSprint(sprintID int, issueKeys []string) error {
 		return c.handleErrorResponse(resp)
 	}
 	
+	c.notifyMutation(Mutation{Operation: OpSprintIssues, Issues: issueKeys, Sprints: []int{sprintID}})
 	return nil
 }
 
@@ -0,0 +1,149 @@
+package jira
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// SearchStreamOptions controls a streaming search
+type SearchStreamOptions struct {
+	MaxIssues int // stop after this many issues; 0 streams every match
+	Prefetch  int // pages fetched ahead of a slow consumer; defaults to 1
+}
+
+// SearchStreamSummary reports what a streaming search delivered
+type SearchStreamSummary struct {
+	Total     int      `json:"total"`
+	Delivered int      `json:"delivered"`
+	Pages     int      `json:"pages"`
+	Truncated bool     `json:"truncated"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+type searchStreamPage struct {
+	result *ExtendedSearchResult
+	err    error
+}
+
+// StreamSearch runs a search page by page and hands each page to fn as soon as
+// it arrives, instead of buffering the whole result set. At most Prefetch pages
+// are fetched ahead of fn, so a slow consumer slows down the requests to Jira.
+// The stream stops when every match has been delivered, MaxIssues is reached,
+// fn returns an error or ctx is cancelled.
+func (c *Client) StreamSearch(ctx context.Context, req SearchRequest, opts SearchStreamOptions, fn func(page *ExtendedSearchResult) error) (*SearchStreamSummary, error) {
+	if req.MaxResults <= 0 {
+		req.MaxResults = 100
+	}
+	prefetch := opts.Prefetch
+	if prefetch <= 0 {
+		prefetch = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make(chan searchStreamPage, prefetch)
+	go func() {
+		defer close(pages)
+
+		for startAt, fetched := req.StartAt, 0; ; {
+			pageReq := req
+			pageReq.StartAt = startAt
+			if opts.MaxIssues > 0 && opts.MaxIssues-fetched < pageReq.MaxResults {
+				pageReq.MaxResults = opts.MaxIssues - fetched
+			}
+
+			result, err := c.SearchIssuesAdvancedContext(ctx, pageReq)
+			if err == nil && ctx.Err() != nil {
+				err = ctx.Err()
+			}
+
+			select {
+			case pages <- searchStreamPage{result: result, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || len(result.Issues) == 0 {
+				return
+			}
+
+			fetched += len(result.Issues)
+			startAt += len(result.Issues)
+			if startAt >= result.Total || (opts.MaxIssues > 0 && fetched >= opts.MaxIssues) {
+				return
+			}
+		}
+	}()
+
+	summary := &SearchStreamSummary{}
+	for page := range pages {
+		if page.err != nil {
+			if ctx.Err() != nil {
+				return summary, fmt.Errorf("search stream cancelled after %d issues: %w", summary.Delivered, ctx.Err())
+			}
+			return summary, fmt.Errorf("failed to fetch page %d: %w", summary.Pages+1, page.err)
+		}
+
+		summary.Total = page.result.Total
+		summary.Warnings = append(summary.Warnings, page.result.WarningMessages...)
+		if len(page.result.Issues) == 0 {
+			break
+		}
+
+		if err := fn(page.result); err != nil {
+			return summary, err
+		}
+		summary.Pages++
+		summary.Delivered += len(page.result.Issues)
+	}
+
+	if ctx.Err() != nil {
+		return summary, fmt.Errorf("search stream cancelled after %d issues: %w", summary.Delivered, ctx.Err())
+	}
+
+	summary.Truncated = summary.Delivered < summary.Total-req.StartAt
+	return summary, nil
+}
+
+// CSVStreamWriter writes issues as CSV rows as pages arrive
+type CSVStreamWriter struct {
+	client      *Client
+	writer      *csv.Writer
+	fields      []string
+	wroteHeader bool
+}
+
+// NewCSVStreamWriter creates a CSV writer for the given export fields, using
+// the default CSV export fields when none are given
+func (c *Client) NewCSVStreamWriter(w io.Writer, fields []string) *CSVStreamWriter {
+	if len(fields) == 0 {
+		fields = []string{"key", "summary", "status", "assignee", "priority", "created", "updated"}
+	}
+	return &CSVStreamWriter{client: c, writer: csv.NewWriter(w), fields: fields}
+}
+
+// WriteIssues writes a batch of rows, preceded by the header on the first
+// call, and flushes them to the underlying writer
+func (sw *CSVStreamWriter) WriteIssues(issues []Issue) error {
+	if !sw.wroteHeader {
+		if err := sw.writer.Write(sw.fields); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		sw.wroteHeader = true
+	}
+
+	row := make([]string, len(sw.fields))
+	for _, issue := range issues {
+		for i, field := range sw.fields {
+			row[i] = sw.client.getFieldValue(issue, field)
+		}
+		if err := sw.writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	sw.writer.Flush()
+	return sw.writer.Error()
+}
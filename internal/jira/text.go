@@ -0,0 +1,36 @@
+package jira
+
+import "strings"
+
+// PlainText returns the text of a description or comment body, which is a
+// string in API v2 and an Atlassian Document Format tree in API v3
+func PlainText(value interface{}) string {
+	var buf strings.Builder
+	writePlainText(&buf, value)
+	return strings.TrimSpace(buf.String())
+}
+
+func writePlainText(buf *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		buf.WriteString(v)
+	case map[string]interface{}:
+		if text, ok := v["text"].(string); ok {
+			buf.WriteString(text)
+		}
+		if content, ok := v["content"].([]interface{}); ok {
+			for _, node := range content {
+				writePlainText(buf, node)
+			}
+		}
+		// Block nodes end a line of text
+		switch v["type"] {
+		case "paragraph", "heading", "listItem", "codeBlock", "blockquote", "tableCell":
+			buf.WriteString("\n")
+		}
+	case []interface{}:
+		for _, node := range v {
+			writePlainText(buf, node)
+		}
+	}
+}
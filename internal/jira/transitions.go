@@ -26,8 +26,11 @@ type FieldMeta struct {
 
 // FieldSchema represents the schema of a field
 type FieldSchema struct {
-	Type   string `json:"type"`
-	System string `json:"system,omitempty"`
+	Type     string `json:"type"`
+	Items    string `json:"items,omitempty"`
+	System   string `json:"system,omitempty"`
+	Custom   string `json:"custom,omitempty"`
+	CustomID int    `json:"customId,omitempty"`
 }
 
 // TransitionIssueRequest represents a request to transition an issue with extended options
@@ -97,6 +100,7 @@ func (c *Client) TransitionIssueAdvanced(issueKey string, transitionID string, f
 		return fmt.Errorf("transition failed with status %d: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
+	c.notifyMutation(Mutation{Operation: OpTransitionIssue, Issues: []string{issueKey}})
 	return nil
 }
 
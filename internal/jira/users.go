@@ -0,0 +1,143 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// UserPickerResult represents the result of a user picker query
+type UserPickerResult struct {
+	Users  []UserPickerUser `json:"users"`
+	Total  int              `json:"total"`
+	Header string           `json:"header,omitempty"`
+}
+
+// UserPickerUser represents a user suggestion returned by the user picker
+type UserPickerUser struct {
+	AccountID   string `json:"accountId,omitempty"`
+	Name        string `json:"name,omitempty"` // Server/Data Center only
+	Key         string `json:"key,omitempty"`  // Server/Data Center only
+	DisplayName string `json:"displayName"`
+	HTML        string `json:"html,omitempty"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+}
+
+// AssignableUserQuery scopes an assignable user search to a project or issue
+type AssignableUserQuery struct {
+	Query      string `json:"query"`
+	ProjectKey string `json:"project,omitempty"`
+	IssueKey   string `json:"issueKey,omitempty"`
+	MaxResults int    `json:"maxResults,omitempty"`
+}
+
+// SearchUsers finds active users matching a query against display name and email
+func (c *Client) SearchUsers(query string, maxResults int) ([]User, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	// Server/Data Center deployments still expect the username parameter
+	params.Add("username", query)
+	if maxResults > 0 {
+		params.Add("maxResults", strconv.Itoa(maxResults))
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", "/rest/api/2/user/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var users []User
+	if err := json.Unmarshal(resp.Body(), &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	return users, nil
+}
+
+// FindAssignableUsers finds users that can be assigned issues in a project or to a specific issue
+func (c *Client) FindAssignableUsers(q AssignableUserQuery) ([]User, error) {
+	if q.ProjectKey == "" && q.IssueKey == "" {
+		return nil, fmt.Errorf("project or issue key is required")
+	}
+
+	params := url.Values{}
+	params.Add("query", q.Query)
+	params.Add("username", q.Query)
+	if q.IssueKey != "" {
+		params.Add("issueKey", q.IssueKey)
+	} else {
+		params.Add("project", q.ProjectKey)
+	}
+	if q.MaxResults > 0 {
+		params.Add("maxResults", strconv.Itoa(q.MaxResults))
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", "/rest/api/2/user/assignable/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find assignable users: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var users []User
+	if err := json.Unmarshal(resp.Body(), &users); err != nil {
+		return nil, fmt.Errorf("failed to decode assignable users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UserPicker returns user suggestions for a partial name, as used by Jira's user picker
+func (c *Client) UserPicker(query string, maxResults int) (*UserPickerResult, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	if maxResults > 0 {
+		params.Add("maxResults", strconv.Itoa(maxResults))
+	}
+
+	resp, err := c.doRequest(context.Background(), "GET", "/rest/api/2/user/picker?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user picker: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var result UserPickerResult
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode user picker result: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetUser retrieves a user by account ID
+func (c *Client) GetUser(accountID string) (*User, error) {
+	params := url.Values{}
+	params.Add("accountId", accountID)
+
+	resp, err := c.doRequest(context.Background(), "GET", "/rest/api/2/user?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", accountID, err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := json.Unmarshal(resp.Body(), &user); err != nil {
+		return nil, fmt.Errorf("failed to decode user: %w", err)
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,214 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Watchers represents the watchers of an issue
+type Watchers struct {
+	Self       string `json:"self,omitempty"`
+	IsWatching bool   `json:"isWatching"`
+	WatchCount int    `json:"watchCount"`
+	Watchers   []User `json:"watchers"`
+}
+
+// Votes represents the votes on an issue
+type Votes struct {
+	Self     string `json:"self,omitempty"`
+	Votes    int    `json:"votes"`
+	HasVoted bool   `json:"hasVoted"`
+	Voters   []User `json:"voters,omitempty"`
+}
+
+// NotifyRequest represents an email notification about an issue
+type NotifyRequest struct {
+	Subject  string             `json:"subject,omitempty"`
+	TextBody string             `json:"textBody,omitempty"`
+	HTMLBody string             `json:"htmlBody,omitempty"`
+	To       NotifyRecipients   `json:"to"`
+	Restrict *NotifyRestriction `json:"restrict,omitempty"`
+}
+
+// NotifyRecipients lists who should receive a notification
+type NotifyRecipients struct {
+	Reporter bool             `json:"reporter"`
+	Assignee bool             `json:"assignee"`
+	Watchers bool             `json:"watchers"`
+	Voters   bool             `json:"voters"`
+	Users    []NotifyUser     `json:"users,omitempty"`
+	Groups   []NotifyGroupRef `json:"groups,omitempty"`
+}
+
+// NotifyRestriction limits a notification to users in the given groups or with the given permissions
+type NotifyRestriction struct {
+	Groups      []NotifyGroupRef `json:"groups,omitempty"`
+	Permissions []NotifyPermRef  `json:"permissions,omitempty"`
+}
+
+// NotifyUser identifies a notification recipient by account ID (Cloud) or name (Server/Data Center)
+type NotifyUser struct {
+	AccountID string `json:"accountId,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// NotifyGroupRef identifies a group by name
+type NotifyGroupRef struct {
+	Name string `json:"name"`
+}
+
+// NotifyPermRef identifies a permission by key
+type NotifyPermRef struct {
+	Key string `json:"key"`
+}
+
+// HasRecipients reports whether the notification is addressed to anyone
+func (r NotifyRecipients) HasRecipients() bool {
+	return r.Reporter || r.Assignee || r.Watchers || r.Voters || len(r.Users) > 0 || len(r.Groups) > 0
+}
+
+// GetWatchers retrieves the watchers of an issue
+func (c *Client) GetWatchers(issueKey string) (*Watchers, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/watchers", issueKey)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchers: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var watchers Watchers
+	if err := json.Unmarshal(resp.Body(), &watchers); err != nil {
+		return nil, fmt.Errorf("failed to decode watchers: %w", err)
+	}
+
+	return &watchers, nil
+}
+
+// AddWatcher adds a user to the watchers of an issue. The user is an account
+// ID on Cloud or a username on Server/Data Center.
+func (c *Client) AddWatcher(issueKey, user string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/watchers", issueKey)
+
+	// The endpoint expects a bare JSON string as the request body
+	body, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to encode watcher: %w", err)
+	}
+
+	resp, err := c.doRequest(context.Background(), "POST", endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to add watcher: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return err
+	}
+
+	c.notifyMutation(Mutation{Operation: OpWatchers, Issues: []string{issueKey}})
+	return nil
+}
+
+// RemoveWatcher removes a user, identified by account ID, from the watchers of an issue
+func (c *Client) RemoveWatcher(issueKey, accountID string) error {
+	return c.removeWatcher(issueKey, "accountId", accountID)
+}
+
+// RemoveWatcherByUsername removes a user, identified by username, from the
+// watchers of an issue on Server/Data Center
+func (c *Client) RemoveWatcherByUsername(issueKey, username string) error {
+	return c.removeWatcher(issueKey, "username", username)
+}
+
+func (c *Client) removeWatcher(issueKey, param, value string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/watchers?%s=%s", issueKey, param, url.QueryEscape(value))
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove watcher: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return err
+	}
+
+	c.notifyMutation(Mutation{Operation: OpWatchers, Issues: []string{issueKey}})
+	return nil
+}
+
+// GetVotes retrieves the votes on an issue
+func (c *Client) GetVotes(issueKey string) (*Votes, error) {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/votes", issueKey)
+
+	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var votes Votes
+	if err := json.Unmarshal(resp.Body(), &votes); err != nil {
+		return nil, fmt.Errorf("failed to decode votes: %w", err)
+	}
+
+	return &votes, nil
+}
+
+// AddVote casts the current user's vote for an issue
+func (c *Client) AddVote(issueKey string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/votes", issueKey)
+
+	resp, err := c.doRequest(context.Background(), "POST", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to add vote: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return err
+	}
+
+	c.notifyMutation(Mutation{Operation: OpVotes, Issues: []string{issueKey}})
+	return nil
+}
+
+// RemoveVote withdraws the current user's vote for an issue
+func (c *Client) RemoveVote(issueKey string) error {
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/votes", issueKey)
+
+	resp, err := c.doRequest(context.Background(), "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove vote: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return err
+	}
+
+	c.notifyMutation(Mutation{Operation: OpVotes, Issues: []string{issueKey}})
+	return nil
+}
+
+// NotifyIssue sends an email notification about an issue. Jira queues the
+// notification; a successful call does not mean the email has been delivered.
+func (c *Client) NotifyIssue(issueKey string, req *NotifyRequest) error {
+	if !req.To.HasRecipients() {
+		return fmt.Errorf("notification has no recipients")
+	}
+
+	endpoint := fmt.Sprintf("/rest/api/2/issue/%s/notify", issueKey)
+
+	resp, err := c.doRequest(context.Background(), "POST", endpoint, req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return c.handleErrorResponse(resp)
+}
@@ -0,0 +1,138 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Webhook event names accepted when registering a webhook
+const (
+	WebhookIssueCreated   = "jira:issue_created"
+	WebhookIssueUpdated   = "jira:issue_updated"
+	WebhookIssueDeleted   = "jira:issue_deleted"
+	WebhookCommentCreated = "comment_created"
+	WebhookCommentUpdated = "comment_updated"
+	WebhookCommentDeleted = "comment_deleted"
+	WebhookWorklogCreated = "worklog_created"
+	WebhookWorklogUpdated = "worklog_updated"
+	WebhookWorklogDeleted = "worklog_deleted"
+	WebhookSprintCreated  = "sprint_created"
+	WebhookSprintUpdated  = "sprint_updated"
+	WebhookSprintDeleted  = "sprint_deleted"
+	WebhookSprintStarted  = "sprint_started"
+	WebhookSprintClosed   = "sprint_closed"
+	WebhookBoardCreated   = "board_created"
+	WebhookBoardUpdated   = "board_updated"
+	WebhookBoardDeleted   = "board_deleted"
+	WebhookBoardConfig    = "board_configuration_changed"
+)
+
+// DefaultWebhookEvents lists the events GoJira needs to keep its caches and
+// sessions in sync with Jira
+var DefaultWebhookEvents = []string{
+	WebhookIssueCreated, WebhookIssueUpdated, WebhookIssueDeleted,
+	WebhookCommentCreated, WebhookCommentUpdated, WebhookCommentDeleted,
+	WebhookWorklogCreated, WebhookWorklogUpdated, WebhookWorklogDeleted,
+	WebhookSprintCreated, WebhookSprintUpdated, WebhookSprintDeleted, WebhookSprintStarted, WebhookSprintClosed,
+	WebhookBoardCreated, WebhookBoardUpdated, WebhookBoardDeleted, WebhookBoardConfig,
+}
+
+// Webhook represents a webhook registered in Jira
+type Webhook struct {
+	Self        string            `json:"self,omitempty"`
+	Name        string            `json:"name"`
+	URL         string            `json:"url"`
+	Events      []string          `json:"events"`
+	Filters     map[string]string `json:"filters,omitempty"`
+	ExcludeBody bool              `json:"excludeBody"`
+	Enabled     bool              `json:"enabled"`
+}
+
+// ID returns the webhook ID, which Jira only exposes as the last segment of
+// the self URL
+func (w *Webhook) ID() string {
+	return w.Self[strings.LastIndex(w.Self, "/")+1:]
+}
+
+// WebhookRequest represents a request to register a webhook. JQL restricts
+// issue-related events to matching issues.
+type WebhookRequest struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+	JQL    string   `json:"jql,omitempty"`
+	Secret string   `json:"secret,omitempty"` // Cloud signs deliveries with it (X-Hub-Signature)
+}
+
+// body builds the webhook body, defaulting to DefaultWebhookEvents
+func (r *WebhookRequest) body() map[string]interface{} {
+	events := r.Events
+	if len(events) == 0 {
+		events = DefaultWebhookEvents
+	}
+	body := map[string]interface{}{
+		"name":        r.Name,
+		"url":         r.URL,
+		"events":      events,
+		"excludeBody": false,
+	}
+	if r.JQL != "" {
+		body["filters"] = map[string]string{"issue-related-events-section": r.JQL}
+	}
+	if r.Secret != "" {
+		body["secret"] = r.Secret
+	}
+	return body
+}
+
+// RegisterWebhook registers a webhook. It requires Jira administrator permission.
+func (c *Client) RegisterWebhook(ctx context.Context, req *WebhookRequest) (*Webhook, error) {
+	resp, err := c.doRequest(ctx, "POST", "/rest/webhooks/1.0/webhook", req.body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to register webhook: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var webhook Webhook
+	if err := json.Unmarshal(resp.Body(), &webhook); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// GetWebhooks lists the registered webhooks
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	resp, err := c.doRequest(ctx, "GET", "/rest/webhooks/1.0/webhook", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	if err := c.handleErrorResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var webhooks []Webhook
+	if err := json.Unmarshal(resp.Body(), &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a registered webhook
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	endpoint := fmt.Sprintf("/rest/webhooks/1.0/webhook/%s", webhookID)
+
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", webhookID, err)
+	}
+
+	return c.handleErrorResponse(resp)
+}
@@ -276,8 +276,19 @@ func (c *Client) GetWorkflows() (*WorkflowList, error) {
 	return &result, nil
 }
 
-// GetWorkflow retrieves a specific workflow by name
+// GetWorkflow retrieves a specific workflow by name. Concurrent requests
+// share one Jira call and the returned workflow.
 func (c *Client) GetWorkflow(workflowName string) (*Workflow, error) {
+	v, err, _ := c.reads.Do(context.Background(), "workflow:"+workflowName, func(context.Context) (interface{}, error) {
+		return c.getWorkflow(workflowName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Workflow), nil
+}
+
+func (c *Client) getWorkflow(workflowName string) (*Workflow, error) {
 	endpoint := fmt.Sprintf("/rest/api/2/workflow/%s", workflowName)
 	
 	resp, err := c.doRequest(context.Background(), "GET", endpoint, nil)
@@ -0,0 +1,305 @@
+package jql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Condition is a JQL condition. The zero value is an empty condition that is
+// ignored by And and Or.
+type Condition struct {
+	text string
+	op   string // AND or OR for compound conditions, empty otherwise
+}
+
+// String returns the condition as JQL
+func (c Condition) String() string {
+	return c.text
+}
+
+// IsZero reports whether the condition is empty
+func (c Condition) IsZero() bool {
+	return c.text == ""
+}
+
+// And combines the condition with others using AND
+func (c Condition) And(others ...Condition) Condition {
+	return And(append([]Condition{c}, others...)...)
+}
+
+// Or combines the condition with others using OR
+func (c Condition) Or(others ...Condition) Condition {
+	return Or(append([]Condition{c}, others...)...)
+}
+
+// OrderBy starts a query with this condition ordered by a field
+func (c Condition) OrderBy(field string, direction ...Direction) *Query {
+	return Where(c).OrderBy(field, direction...)
+}
+
+// And joins conditions with AND, parenthesising OR groups. Empty conditions are skipped.
+func And(conditions ...Condition) Condition {
+	return join("AND", conditions)
+}
+
+// Or joins conditions with OR. Empty conditions are skipped.
+func Or(conditions ...Condition) Condition {
+	return join("OR", conditions)
+}
+
+func join(op string, conditions []Condition) Condition {
+	var parts []string
+	for _, condition := range conditions {
+		if condition.IsZero() {
+			continue
+		}
+		if op == "AND" && condition.op == "OR" {
+			parts = append(parts, "("+condition.text+")")
+		} else {
+			parts = append(parts, condition.text)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return Condition{}
+	case 1:
+		// A single condition keeps its own precedence
+		for _, condition := range conditions {
+			if !condition.IsZero() {
+				return condition
+			}
+		}
+	}
+	return Condition{text: strings.Join(parts, " "+op+" "), op: op}
+}
+
+// Not negates a condition
+func Not(condition Condition) Condition {
+	if condition.IsZero() {
+		return condition
+	}
+	if condition.op != "" {
+		return Condition{text: "NOT (" + condition.text + ")"}
+	}
+	return Condition{text: "NOT " + condition.text}
+}
+
+// FieldRef is a field that conditions can be built on
+type FieldRef struct {
+	name string
+}
+
+// Field references a field by name. Names with spaces or reserved words are quoted.
+func Field(name string) FieldRef {
+	return FieldRef{name: name}
+}
+
+// CustomField references a custom field by ID, e.g. CustomField("customfield_10016") or CustomField("10016")
+func CustomField(id string) FieldRef {
+	return FieldRef{name: "cf[" + strings.TrimPrefix(id, "customfield_") + "]"}
+}
+
+// String returns the field as it is written in JQL
+func (f FieldRef) String() string {
+	return FieldName(f.name)
+}
+
+func (f FieldRef) compare(op string, value interface{}) Condition {
+	return Condition{text: f.String() + " " + op + " " + formatValue(value)}
+}
+
+func (f FieldRef) list(op string, values []interface{}) Condition {
+	// A single list function such as membersOf() is used without parentheses
+	if len(values) == 1 {
+		if function, ok := values[0].(Function); ok {
+			return Condition{text: f.String() + " " + op + " " + function.String()}
+		}
+	}
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(value)
+	}
+	return Condition{text: f.String() + " " + op + " (" + strings.Join(formatted, ", ") + ")"}
+}
+
+// Eq builds `field = value`
+func (f FieldRef) Eq(value interface{}) Condition { return f.compare("=", value) }
+
+// NotEq builds `field != value`
+func (f FieldRef) NotEq(value interface{}) Condition { return f.compare("!=", value) }
+
+// Gt builds `field > value`
+func (f FieldRef) Gt(value interface{}) Condition { return f.compare(">", value) }
+
+// Gte builds `field >= value`
+func (f FieldRef) Gte(value interface{}) Condition { return f.compare(">=", value) }
+
+// Lt builds `field < value`
+func (f FieldRef) Lt(value interface{}) Condition { return f.compare("<", value) }
+
+// Lte builds `field <= value`
+func (f FieldRef) Lte(value interface{}) Condition { return f.compare("<=", value) }
+
+// Contains builds the text search `field ~ value`
+func (f FieldRef) Contains(value interface{}) Condition { return f.compare("~", value) }
+
+// NotContains builds `field !~ value`
+func (f FieldRef) NotContains(value interface{}) Condition { return f.compare("!~", value) }
+
+// In builds `field IN (values...)`
+func (f FieldRef) In(values ...interface{}) Condition { return f.list("IN", values) }
+
+// NotIn builds `field NOT IN (values...)`
+func (f FieldRef) NotIn(values ...interface{}) Condition { return f.list("NOT IN", values) }
+
+// IsEmpty builds `field IS EMPTY`
+func (f FieldRef) IsEmpty() Condition { return Condition{text: f.String() + " IS EMPTY"} }
+
+// IsNotEmpty builds `field IS NOT EMPTY`
+func (f FieldRef) IsNotEmpty() Condition { return Condition{text: f.String() + " IS NOT EMPTY"} }
+
+// Was builds `field WAS value`
+func (f FieldRef) Was(value interface{}) Condition { return f.compare("WAS", value) }
+
+// WasNot builds `field WAS NOT value`
+func (f FieldRef) WasNot(value interface{}) Condition { return f.compare("WAS NOT", value) }
+
+// WasIn builds `field WAS IN (values...)`
+func (f FieldRef) WasIn(values ...interface{}) Condition { return f.list("WAS IN", values) }
+
+// WasNotIn builds `field WAS NOT IN (values...)`
+func (f FieldRef) WasNotIn(values ...interface{}) Condition { return f.list("WAS NOT IN", values) }
+
+// Changed builds `field CHANGED`; use History to add predicates
+func (f FieldRef) Changed() Condition { return Condition{text: f.String() + " CHANGED"} }
+
+// History appends a WAS/CHANGED predicate such as AFTER, BEFORE, BY, FROM, ON or TO
+func (c Condition) History(keyword string, value interface{}) Condition {
+	return Condition{text: c.text + " " + strings.ToUpper(keyword) + " " + formatValue(value), op: c.op}
+}
+
+// During appends a `DURING (start, end)` predicate
+func (c Condition) During(start, end interface{}) Condition {
+	return Condition{text: fmt.Sprintf("%s DURING (%s, %s)", c.text, formatValue(start), formatValue(end)), op: c.op}
+}
+
+// Function is a JQL function call
+type Function struct {
+	name string
+	args []string
+}
+
+// Func builds a call to any JQL function
+func Func(name string, args ...string) Function {
+	return Function{name: name, args: args}
+}
+
+// String returns the function call as JQL
+func (f Function) String() string {
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = QuoteIfNeeded(arg)
+	}
+	return f.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// CurrentUser builds currentUser()
+func CurrentUser() Function { return Func("currentUser") }
+
+// OpenSprints builds openSprints()
+func OpenSprints() Function { return Func("openSprints") }
+
+// ClosedSprints builds closedSprints()
+func ClosedSprints() Function { return Func("closedSprints") }
+
+// FutureSprints builds futureSprints()
+func FutureSprints() Function { return Func("futureSprints") }
+
+// MembersOf builds membersOf(group)
+func MembersOf(group string) Function { return Func("membersOf", group) }
+
+// Now builds now()
+func Now() Function { return Func("now") }
+
+// StartOfDay builds startOfDay() with an optional offset such as "-1d"
+func StartOfDay(offset ...string) Function { return Func("startOfDay", offset...) }
+
+// StartOfWeek builds startOfWeek() with an optional offset such as "-1w"
+func StartOfWeek(offset ...string) Function { return Func("startOfWeek", offset...) }
+
+// StartOfMonth builds startOfMonth() with an optional offset such as "-1M"
+func StartOfMonth(offset ...string) Function { return Func("startOfMonth", offset...) }
+
+// Empty is the EMPTY value for use in lists and comparisons
+var Empty = emptyValue{}
+
+type emptyValue struct{}
+
+// formatValue renders a value: strings are quoted when needed, numbers are
+// written as-is, times as "yyyy-MM-dd HH:mm" and functions as calls
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return QuoteIfNeeded(v)
+	case Function:
+		return v.String()
+	case emptyValue:
+		return "EMPTY"
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return Quote(v.Format("2006-01-02 15:04"))
+	case fmt.Stringer:
+		return QuoteIfNeeded(v.String())
+	default:
+		return QuoteIfNeeded(fmt.Sprint(v))
+	}
+}
+
+// Direction is an ORDER BY direction
+type Direction string
+
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+// Query is a JQL query with an optional ORDER BY clause
+type Query struct {
+	where   Condition
+	orderBy []string
+}
+
+// Where starts a query with a condition
+func Where(condition Condition) *Query {
+	return &Query{where: condition}
+}
+
+// OrderBy adds a field to the ORDER BY clause
+func (q *Query) OrderBy(field string, direction ...Direction) *Query {
+	order := FieldName(field)
+	if len(direction) > 0 && direction[0] != "" {
+		order += " " + string(direction[0])
+	}
+	q.orderBy = append(q.orderBy, order)
+	return q
+}
+
+// String returns the query as JQL
+func (q *Query) String() string {
+	var parts []string
+	if !q.where.IsZero() {
+		parts = append(parts, q.where.String())
+	}
+	if len(q.orderBy) > 0 {
+		parts = append(parts, "ORDER BY "+strings.Join(q.orderBy, ", "))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,60 @@
+// Package jql builds well-formed JQL queries. Values and field names are
+// quoted and escaped as needed, and the output uses the same canonical form
+// as the parser in the jira package.
+package jql
+
+import (
+	"strings"
+	"unicode"
+)
+
+// reservedWords are JQL keywords that must be quoted when used as values or field names
+var reservedWords = map[string]bool{
+	"and": true, "or": true, "not": true, "empty": true, "null": true, "order": true,
+	"by": true, "asc": true, "desc": true, "in": true, "is": true, "was": true,
+	"changed": true, "after": true, "before": true, "during": true, "on": true,
+	"from": true, "to": true,
+}
+
+// IsReserved reports whether word is a JQL keyword, case-insensitively
+func IsReserved(word string) bool {
+	return reservedWords[strings.ToLower(word)]
+}
+
+// Quote returns s as a double-quoted JQL string with quotes and backslashes escaped
+func Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// QuoteIfNeeded quotes s unless it can be written as an unquoted JQL word
+func QuoteIfNeeded(s string) string {
+	if s == "" || IsReserved(s) {
+		return Quote(s)
+	}
+	for _, r := range s {
+		if !isSafeRune(r) {
+			return Quote(s)
+		}
+	}
+	return s
+}
+
+func isSafeRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-@", r)
+}
+
+// IsCustomFieldRef reports whether name is a cf[12345] custom field reference
+func IsCustomFieldRef(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "cf[") && strings.HasSuffix(lower, "]")
+}
+
+// FieldName returns a field name as it must be written in JQL
+func FieldName(name string) string {
+	if IsCustomFieldRef(name) {
+		return name
+	}
+	return QuoteIfNeeded(name)
+}
@@ -0,0 +1,514 @@
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// ErrUnsupportedQuery is returned for JQL the mirror cannot answer, such as
+// history searches or functions like currentUser()
+var ErrUnsupportedQuery = errors.New("query cannot be answered from the local mirror")
+
+// Query is JQL compiled for evaluation against mirrored issues. Only the
+// fields and operators the mirror holds the data for are supported; anything
+// else is an error rather than a wrong answer.
+type Query struct {
+	match   func(issue *jira.Issue) bool
+	orderBy []jira.JQLOrderByField
+}
+
+// Compile parses JQL for evaluation. Relative dates and date functions are
+// resolved against now.
+func Compile(jql string, now time.Time) (*Query, error) {
+	parsed, err := jira.ParseJQL(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{
+		match:   func(*jira.Issue) bool { return true },
+		orderBy: parsed.OrderBy,
+	}
+	if parsed.Where != nil {
+		if query.match, err = compileExpr(parsed.Where, now); err != nil {
+			return nil, err
+		}
+	}
+	for _, order := range parsed.OrderBy {
+		if _, ok := orderFields[strings.ToLower(order.Field.Name)]; !ok {
+			return nil, unsupported("ordering by %s", order.Field.Name)
+		}
+	}
+	return query, nil
+}
+
+// Match reports whether an issue satisfies the query
+func (q *Query) Match(issue *jira.Issue) bool {
+	return q.match(issue)
+}
+
+// Sort orders issues by the ORDER BY clause, or by last update like Jira
+// when there is none
+func (q *Query) Sort(issues []jira.Issue) {
+	orderBy := q.orderBy
+	if len(orderBy) == 0 {
+		orderBy = []jira.JQLOrderByField{{Field: jira.JQLField{Name: "updated"}, Direction: "DESC"}}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		for _, order := range orderBy {
+			c := orderFields[strings.ToLower(order.Field.Name)](&issues[i], &issues[j])
+			if strings.EqualFold(order.Direction, "DESC") {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return compareKeys(issues[i].Key, issues[j].Key) > 0
+	})
+}
+
+func unsupported(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedQuery, fmt.Sprintf(format, args...))
+}
+
+func compileExpr(expr jira.JQLExpr, now time.Time) (func(*jira.Issue) bool, error) {
+	switch e := expr.(type) {
+	case *jira.JQLBinaryExpr:
+		left, err := compileExpr(e.Left, now)
+		if err != nil {
+			return nil, err
+		}
+		right, err := compileExpr(e.Right, now)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == "OR" {
+			return func(issue *jira.Issue) bool { return left(issue) || right(issue) }, nil
+		}
+		return func(issue *jira.Issue) bool { return left(issue) && right(issue) }, nil
+
+	case *jira.JQLNotExpr:
+		inner, err := compileExpr(e.Expr, now)
+		if err != nil {
+			return nil, err
+		}
+		return func(issue *jira.Issue) bool { return !inner(issue) }, nil
+
+	case *jira.JQLClause:
+		return compileClause(e, now)
+	}
+	return nil, unsupported("condition %s", expr)
+}
+
+func compileClause(clause *jira.JQLClause, now time.Time) (func(*jira.Issue) bool, error) {
+	name := strings.ToLower(clause.Field.Name)
+
+	if dateField, ok := dateFields[name]; ok {
+		return compileDateClause(clause, dateField, now)
+	}
+
+	field, ok := valueFields[name]
+	textField, isText := textFields[name]
+	if !ok && !isText {
+		return nil, unsupported("field %s", clause.Field.Name)
+	}
+
+	switch clause.Operator {
+	case "~", "!~":
+		if !isText {
+			return nil, unsupported("operator %s on %s", clause.Operator, clause.Field.Name)
+		}
+		value, ok := clause.Value.(*jira.JQLValue)
+		if !ok || value.Empty {
+			return nil, unsupported("%s", clause)
+		}
+		terms := textTerms(value.Text)
+		contains := func(issue *jira.Issue) bool { return containsTerms(textField(issue), terms) }
+		if clause.Operator == "!~" {
+			return func(issue *jira.Issue) bool { return !contains(issue) }, nil
+		}
+		return contains, nil
+	}
+
+	if !ok {
+		return nil, unsupported("operator %s on %s", clause.Operator, clause.Field.Name)
+	}
+
+	switch clause.Operator {
+	case "=", "!=", "IS", "IS NOT":
+		value, ok := clause.Value.(*jira.JQLValue)
+		if !ok {
+			return nil, unsupported("%s", clause)
+		}
+		var match func(*jira.Issue) bool
+		if value.Empty {
+			match = func(issue *jira.Issue) bool { return len(field(issue)) == 0 }
+		} else if clause.Operator == "IS" || clause.Operator == "IS NOT" {
+			return nil, unsupported("%s", clause)
+		} else {
+			match = func(issue *jira.Issue) bool { return hasValue(field(issue), value.Text) }
+		}
+		if clause.Operator == "!=" || clause.Operator == "IS NOT" {
+			return func(issue *jira.Issue) bool { return !match(issue) }, nil
+		}
+		return match, nil
+
+	case "IN", "NOT IN":
+		list, ok := clause.Value.(*jira.JQLList)
+		if !ok {
+			return nil, unsupported("%s", clause)
+		}
+		var values []string
+		matchEmpty := false
+		for _, operand := range list.Values {
+			value, ok := operand.(*jira.JQLValue)
+			if !ok {
+				return nil, unsupported("%s", operand)
+			}
+			if value.Empty {
+				matchEmpty = true
+			} else {
+				values = append(values, value.Text)
+			}
+		}
+		match := func(issue *jira.Issue) bool {
+			actual := field(issue)
+			if len(actual) == 0 {
+				return matchEmpty
+			}
+			for _, value := range values {
+				if hasValue(actual, value) {
+					return true
+				}
+			}
+			return false
+		}
+		if clause.Operator == "NOT IN" {
+			return func(issue *jira.Issue) bool { return !match(issue) }, nil
+		}
+		return match, nil
+	}
+	return nil, unsupported("operator %s on %s", clause.Operator, clause.Field.Name)
+}
+
+func compileDateClause(clause *jira.JQLClause, field func(*jira.Issue) *time.Time, now time.Time) (func(*jira.Issue) bool, error) {
+	if value, ok := clause.Value.(*jira.JQLValue); ok && value.Empty {
+		switch clause.Operator {
+		case "=", "IS":
+			return func(issue *jira.Issue) bool { return field(issue) == nil }, nil
+		case "!=", "IS NOT":
+			return func(issue *jira.Issue) bool { return field(issue) != nil }, nil
+		}
+	}
+
+	at, err := resolveDate(clause.Value, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var compare func(t time.Time) bool
+	switch clause.Operator {
+	case "=":
+		compare = func(t time.Time) bool { return t.Equal(at) }
+	case "!=":
+		compare = func(t time.Time) bool { return !t.Equal(at) }
+	case ">":
+		compare = func(t time.Time) bool { return t.After(at) }
+	case ">=":
+		compare = func(t time.Time) bool { return !t.Before(at) }
+	case "<":
+		compare = func(t time.Time) bool { return t.Before(at) }
+	case "<=":
+		compare = func(t time.Time) bool { return !t.After(at) }
+	default:
+		return nil, unsupported("operator %s on %s", clause.Operator, clause.Field.Name)
+	}
+
+	return func(issue *jira.Issue) bool {
+		t := field(issue)
+		return t != nil && compare(*t)
+	}, nil
+}
+
+var relativeDate = regexp.MustCompile(`^([+-]?\d+)([wdhm])$`)
+
+// resolveDate resolves a date value: an absolute date, a relative offset
+// such as -1w, or one of the date functions without arguments
+func resolveDate(operand jira.JQLOperand, now time.Time) (time.Time, error) {
+	switch v := operand.(type) {
+	case *jira.JQLValue:
+		text := strings.TrimSpace(v.Text)
+		if m := relativeDate.FindStringSubmatch(strings.ToLower(text)); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			unit := map[string]time.Duration{"w": 7 * 24 * time.Hour, "d": 24 * time.Hour, "h": time.Hour, "m": time.Minute}[m[2]]
+			return now.Add(time.Duration(n) * unit), nil
+		}
+		for _, layout := range []string{"2006-01-02 15:04", "2006/01/02 15:04", "2006-01-02", "2006/01/02"} {
+			if t, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, unsupported("date %q", v.Text)
+
+	case *jira.JQLFunctionCall:
+		if len(v.Args) > 0 {
+			return time.Time{}, unsupported("%s", v)
+		}
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		switch strings.ToLower(v.Name) {
+		case "now":
+			return now, nil
+		case "startofday":
+			return day, nil
+		case "endofday":
+			return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		case "startofweek":
+			return day.AddDate(0, 0, -int(day.Weekday())), nil
+		case "startofmonth":
+			return day.AddDate(0, 0, 1-day.Day()), nil
+		}
+		return time.Time{}, unsupported("%s", v)
+	}
+	return time.Time{}, unsupported("%s", operand)
+}
+
+// valueFields return the values an issue has for a field, compared without
+// case by =, != and IN
+var valueFields = map[string]func(*jira.Issue) []string{
+	"project": func(i *jira.Issue) []string {
+		return nonEmpty(issueProject(i), i.Fields.Project.Name, i.Fields.Project.ID)
+	},
+	"key":       func(i *jira.Issue) []string { return nonEmpty(i.Key, i.ID) },
+	"issuekey":  func(i *jira.Issue) []string { return nonEmpty(i.Key, i.ID) },
+	"id":        func(i *jira.Issue) []string { return nonEmpty(i.ID, i.Key) },
+	"issuetype": func(i *jira.Issue) []string { return nonEmpty(i.Fields.IssueType.Name, i.Fields.IssueType.ID) },
+	"type":      func(i *jira.Issue) []string { return nonEmpty(i.Fields.IssueType.Name, i.Fields.IssueType.ID) },
+	"status": func(i *jira.Issue) []string {
+		if i.Fields.Status == nil {
+			return nil
+		}
+		return nonEmpty(i.Fields.Status.Name, i.Fields.Status.ID)
+	},
+	"statuscategory": func(i *jira.Issue) []string {
+		if i.Fields.Status == nil {
+			return nil
+		}
+		category := i.Fields.Status.StatusCategory
+		return nonEmpty(category.Key, category.Name)
+	},
+	"priority": func(i *jira.Issue) []string {
+		if i.Fields.Priority == nil {
+			return nil
+		}
+		return nonEmpty(i.Fields.Priority.Name, i.Fields.Priority.ID)
+	},
+	"assignee": func(i *jira.Issue) []string { return userValues(i.Fields.Assignee) },
+	"reporter": func(i *jira.Issue) []string { return userValues(i.Fields.Reporter) },
+	"creator":  func(i *jira.Issue) []string { return userValues(i.Fields.Creator) },
+	"labels":   func(i *jira.Issue) []string { return i.Fields.Labels },
+	"component": func(i *jira.Issue) []string {
+		var values []string
+		for _, c := range i.Fields.Components {
+			values = append(values, nonEmpty(c.Name, c.ID)...)
+		}
+		return values
+	},
+	"fixversion":      func(i *jira.Issue) []string { return versionValues(i.Fields.FixVersions) },
+	"affectedversion": func(i *jira.Issue) []string { return versionValues(i.Fields.Versions) },
+	"parent": func(i *jira.Issue) []string {
+		if i.Fields.Parent == nil {
+			return nil
+		}
+		return nonEmpty(i.Fields.Parent.Key, i.Fields.Parent.ID)
+	},
+}
+
+// textFields return the text searched by ~ and !~
+var textFields = map[string]func(*jira.Issue) string{
+	"summary":     func(i *jira.Issue) string { return i.Fields.Summary },
+	"description": func(i *jira.Issue) string { return jira.PlainText(i.Fields.Description) },
+	"comment":     commentText,
+	"text": func(i *jira.Issue) string {
+		return i.Fields.Summary + "\n" + jira.PlainText(i.Fields.Description) + "\n" + commentText(i)
+	},
+}
+
+var dateFields = map[string]func(*jira.Issue) *time.Time{
+	"created":        func(i *jira.Issue) *time.Time { return jiraTime(i.Fields.Created) },
+	"createddate":    func(i *jira.Issue) *time.Time { return jiraTime(i.Fields.Created) },
+	"updated":        func(i *jira.Issue) *time.Time { return jiraTime(i.Fields.Updated) },
+	"updateddate":    func(i *jira.Issue) *time.Time { return jiraTime(i.Fields.Updated) },
+	"resolved":       func(i *jira.Issue) *time.Time { return jiraTime(i.Fields.Resolved) },
+	"resolutiondate": func(i *jira.Issue) *time.Time { return jiraTime(i.Fields.Resolved) },
+}
+
+// orderFields compare two issues by an ORDER BY field
+var orderFields = map[string]func(a, b *jira.Issue) int{
+	"key":      func(a, b *jira.Issue) int { return compareKeys(a.Key, b.Key) },
+	"issuekey": func(a, b *jira.Issue) int { return compareKeys(a.Key, b.Key) },
+	"summary":  func(a, b *jira.Issue) int { return strings.Compare(a.Fields.Summary, b.Fields.Summary) },
+	"created": func(a, b *jira.Issue) int {
+		return compareTimes(jiraTime(a.Fields.Created), jiraTime(b.Fields.Created))
+	},
+	"updated": func(a, b *jira.Issue) int {
+		return compareTimes(jiraTime(a.Fields.Updated), jiraTime(b.Fields.Updated))
+	},
+	"resolved": func(a, b *jira.Issue) int {
+		return compareTimes(jiraTime(a.Fields.Resolved), jiraTime(b.Fields.Resolved))
+	},
+	"status": func(a, b *jira.Issue) int {
+		return strings.Compare(firstValue(valueFields["status"](a)), firstValue(valueFields["status"](b)))
+	},
+	"priority": func(a, b *jira.Issue) int {
+		// Jira orders priorities by rank; the mirror only knows their IDs,
+		// which follow the default ranking
+		return strings.Compare(priorityID(a), priorityID(b))
+	},
+	"assignee": func(a, b *jira.Issue) int {
+		return strings.Compare(userName(a.Fields.Assignee), userName(b.Fields.Assignee))
+	},
+}
+
+func commentText(i *jira.Issue) string {
+	if i.Fields.Comment == nil {
+		return ""
+	}
+	var buf strings.Builder
+	for _, comment := range i.Fields.Comment.Comments {
+		buf.WriteString(jira.PlainText(comment.Body))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func userValues(user *jira.User) []string {
+	if user == nil {
+		return nil
+	}
+	return nonEmpty(user.AccountID, user.Name, user.Key, user.DisplayName, user.EmailAddress)
+}
+
+func userName(user *jira.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.DisplayName
+}
+
+func priorityID(issue *jira.Issue) string {
+	if issue.Fields.Priority == nil {
+		return ""
+	}
+	return issue.Fields.Priority.ID
+}
+
+func versionValues(versions []jira.Version) []string {
+	var values []string
+	for _, v := range versions {
+		values = append(values, nonEmpty(v.Name, v.ID)...)
+	}
+	return values
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func hasValue(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func jiraTime(t *jira.JiraTime) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return &t.Time
+}
+
+// compareTimes orders times, with missing times first
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(*b)
+}
+
+// compareKeys orders issue keys by project and then by number
+func compareKeys(a, b string) int {
+	ap, an := splitKey(a)
+	bp, bn := splitKey(b)
+	if c := strings.Compare(ap, bp); c != 0 {
+		return c
+	}
+	switch {
+	case an < bn:
+		return -1
+	case an > bn:
+		return 1
+	}
+	return 0
+}
+
+func splitKey(key string) (string, int) {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return key, 0
+	}
+	n, _ := strconv.Atoi(key[i+1:])
+	return key[:i], n
+}
+
+// textTerms splits the value of ~ into lower-case terms. A trailing * marks
+// a prefix search, which substring matching covers.
+func textTerms(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !(r == '-' || r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r > 127)
+	})
+}
+
+// containsTerms reports whether the text contains every term, approximating
+// Jira's word matching, which also finds inflected forms
+func containsTerms(text string, terms []string) bool {
+	if len(terms) == 0 {
+		return false
+	}
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,359 @@
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+const (
+	issuesDir   = "issues"
+	projectsDir = "projects"
+)
+
+// Project is the mirrored state of a Jira project
+type Project struct {
+	Key      string        `json:"key"`
+	LastSync *time.Time    `json:"lastSync,omitempty"` // start of the last complete sync
+	Boards   []jira.Board  `json:"boards,omitempty"`
+	Sprints  []jira.Sprint `json:"sprints,omitempty"` // sprints of the project's boards
+}
+
+// StoreStats describes the contents of a store
+type StoreStats struct {
+	Projects int `json:"projects"`
+	Issues   int `json:"issues"`
+	Comments int `json:"comments"`
+}
+
+// Store is an embedded store of mirrored Jira projects. The mirror is held
+// in memory and every change is written to a directory, one JSON file per
+// issue and per project, so it survives restarts.
+type Store struct {
+	dir string
+
+	mu        sync.RWMutex
+	projects  map[string]*Project
+	issues    map[string]*jira.Issue
+	listeners []func(stored []jira.Issue, deleted []string)
+}
+
+// Open opens the store in dir, creating it when needed and loading the
+// issues and projects mirrored before
+func Open(dir string) (*Store, error) {
+	for _, sub := range []string{issuesDir, projectsDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create mirror directory: %w", err)
+		}
+	}
+
+	s := &Store{
+		dir:      dir,
+		projects: make(map[string]*Project),
+		issues:   make(map[string]*jira.Issue),
+	}
+
+	err := loadJSONFiles(filepath.Join(dir, projectsDir), func() interface{} { return &Project{} }, func(v interface{}) {
+		project := v.(*Project)
+		s.projects[project.Key] = project
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = loadJSONFiles(filepath.Join(dir, issuesDir), func() interface{} { return &jira.Issue{} }, func(v interface{}) {
+		issue := v.(*jira.Issue)
+		s.issues[issue.Key] = issue
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Dir returns the directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// OnChange registers fn to be called after issues are stored or deleted
+func (s *Store) OnChange(fn func(stored []jira.Issue, deleted []string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// PutIssues stores issues, replacing the mirrored versions
+func (s *Store) PutIssues(issues []jira.Issue) error {
+	s.mu.Lock()
+	stored := make([]jira.Issue, 0, len(issues))
+	var err error
+	for i := range issues {
+		issue := issues[i]
+		if issue.Key == "" {
+			continue
+		}
+		if err = writeJSONFile(filepath.Join(s.dir, issuesDir, issue.Key+".json"), &issue); err != nil {
+			break
+		}
+		s.issues[issue.Key] = &issue
+		stored = append(stored, issue)
+	}
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	if len(stored) > 0 {
+		for _, fn := range listeners {
+			fn(stored, nil)
+		}
+	}
+	return err
+}
+
+// DeleteIssue removes an issue from the mirror
+func (s *Store) DeleteIssue(key string) error {
+	s.mu.Lock()
+	delete(s.issues, key)
+	listeners := s.listeners
+	err := os.Remove(filepath.Join(s.dir, issuesDir, key+".json"))
+	s.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(nil, []string{key})
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete mirrored issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// Issue returns a copy of a mirrored issue
+func (s *Store) Issue(key string) (*jira.Issue, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	issue, ok := s.issues[strings.ToUpper(key)]
+	if !ok {
+		return nil, false
+	}
+	copied := *issue
+	return &copied, true
+}
+
+// Issues returns copies of the mirrored issues of a project, or of every
+// project when project is empty
+func (s *Store) Issues(project string) []jira.Issue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	issues := make([]jira.Issue, 0, len(s.issues))
+	for _, issue := range s.issues {
+		if project == "" || strings.EqualFold(issueProject(issue), project) {
+			issues = append(issues, *issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return compareKeys(issues[i].Key, issues[j].Key) < 0 })
+	return issues
+}
+
+// PutProject stores the state of a project
+func (s *Store) PutProject(project Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := writeJSONFile(filepath.Join(s.dir, projectsDir, project.Key+".json"), &project); err != nil {
+		return err
+	}
+	s.projects[project.Key] = &project
+	return nil
+}
+
+// Project returns the state of a mirrored project
+func (s *Store) Project(key string) (Project, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	project, ok := s.projects[key]
+	if !ok {
+		return Project{Key: key}, false
+	}
+	return *project, true
+}
+
+// Projects returns the mirrored projects ordered by key
+func (s *Store) Projects() []Project {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	projects := make([]Project, 0, len(s.projects))
+	for _, project := range s.projects {
+		projects = append(projects, *project)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Key < projects[j].Key })
+	return projects
+}
+
+// StaleAsOf returns the oldest last sync of the projects, or of every
+// mirrored project when none are given. It is nil when one of them has never
+// been synced.
+func (s *Store) StaleAsOf(projects ...string) *time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(projects) == 0 {
+		for key := range s.projects {
+			projects = append(projects, key)
+		}
+	}
+
+	var oldest *time.Time
+	for _, key := range projects {
+		project, ok := s.projects[strings.ToUpper(key)]
+		if !ok || project.LastSync == nil {
+			return nil
+		}
+		if oldest == nil || project.LastSync.Before(*oldest) {
+			synced := *project.LastSync
+			oldest = &synced
+		}
+	}
+	return oldest
+}
+
+// IssueStaleAsOf returns the last sync of the project of a mirrored issue
+func (s *Store) IssueStaleAsOf(issue *jira.Issue) *time.Time {
+	return s.StaleAsOf(issueProject(issue))
+}
+
+// Search answers JQL from the mirror. Queries the mirror cannot evaluate
+// fail with ErrUnsupportedQuery.
+func (s *Store) Search(jql string, startAt, maxResults int) (*jira.SearchResult, error) {
+	query, err := Compile(jql, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var matches []jira.Issue
+	for _, issue := range s.issues {
+		if query.Match(issue) {
+			matches = append(matches, *issue)
+		}
+	}
+	s.mu.RUnlock()
+
+	query.Sort(matches)
+
+	result := &jira.SearchResult{
+		StartAt:    startAt,
+		MaxResults: maxResults,
+		Total:      len(matches),
+		Issues:     []jira.Issue{},
+	}
+	if startAt < len(matches) {
+		end := len(matches)
+		if maxResults > 0 && startAt+maxResults < end {
+			end = startAt + maxResults
+		}
+		result.Issues = matches[startAt:end]
+	}
+	return result, nil
+}
+
+// ResultStaleAsOf returns the oldest last sync of the projects of search
+// results, or of every project when there are no results
+func (s *Store) ResultStaleAsOf(issues []jira.Issue) *time.Time {
+	seen := make(map[string]bool)
+	var projects []string
+	for i := range issues {
+		project := issueProject(&issues[i])
+		if !seen[project] {
+			seen[project] = true
+			projects = append(projects, project)
+		}
+	}
+	return s.StaleAsOf(projects...)
+}
+
+// Stats counts the mirrored projects, issues and comments
+func (s *Store) Stats() StoreStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := StoreStats{Projects: len(s.projects), Issues: len(s.issues)}
+	for _, issue := range s.issues {
+		if issue.Fields.Comment != nil {
+			stats.Comments += len(issue.Fields.Comment.Comments)
+		}
+	}
+	return stats
+}
+
+// issueProject returns the project key of an issue
+func issueProject(issue *jira.Issue) string {
+	if issue.Fields.Project.Key != "" {
+		return strings.ToUpper(issue.Fields.Project.Key)
+	}
+	if i := strings.LastIndex(issue.Key, "-"); i > 0 {
+		return strings.ToUpper(issue.Key[:i])
+	}
+	return ""
+}
+
+// writeJSONFile replaces a file atomically, so a crash never leaves a
+// partially written entry behind
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// loadJSONFiles decodes every JSON file of a directory. Unreadable files are
+// skipped; they are replaced by the next sync.
+func loadJSONFiles(dir string, newValue func() interface{}, add func(interface{})) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read mirror directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		value := newValue()
+		if err := json.Unmarshal(data, value); err != nil {
+			continue
+		}
+		add(value)
+	}
+	return nil
+}
@@ -0,0 +1,463 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/jql"
+	"github.com/rs/zerolog/log"
+)
+
+// Client is the subset of the Jira client a Syncer reads from
+type Client interface {
+	SearchIssuesAdvancedContext(ctx context.Context, req jira.SearchRequest) (*jira.ExtendedSearchResult, error)
+	GetComments(ctx context.Context, issueKey string, startAt, maxResults int) (*jira.CommentResult, error)
+	GetBoards() (*jira.BoardList, error)
+	GetSprints(boardID int) (*jira.SprintList, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// Options configures which projects a Syncer mirrors and how often
+type Options struct {
+	Projects []string `json:"projects"`
+	// Interval between incremental syncs; defaults to 15 minutes
+	Interval time.Duration `json:"interval"`
+	// ProbeInterval between reachability checks while Jira is unreachable;
+	// defaults to 30 seconds
+	ProbeInterval time.Duration `json:"probeInterval"`
+	// Overlap is subtracted from the last sync when asking for updated
+	// issues, covering clock skew and Jira's minute-precision dates;
+	// defaults to 5 minutes
+	Overlap time.Duration `json:"overlap"`
+	// Location is the time zone Jira evaluates JQL dates in for the
+	// connected user; defaults to the local time zone
+	Location *time.Location `json:"-"`
+	// PageSize of issue searches; defaults to 100
+	PageSize int `json:"pageSize"`
+}
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = 15 * time.Minute
+	}
+	if o.ProbeInterval <= 0 {
+		o.ProbeInterval = 30 * time.Second
+	}
+	if o.Overlap <= 0 {
+		o.Overlap = 5 * time.Minute
+	}
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = 100
+	}
+	projects := make([]string, 0, len(o.Projects))
+	for _, project := range o.Projects {
+		if project = strings.ToUpper(strings.TrimSpace(project)); project != "" {
+			projects = append(projects, project)
+		}
+	}
+	o.Projects = projects
+	return o
+}
+
+// ProjectSync is the sync state of a mirrored project
+type ProjectSync struct {
+	Project  string     `json:"project"`
+	LastSync *time.Time `json:"lastSync,omitempty"`
+	LastRun  *time.Time `json:"lastRun,omitempty"`
+	Updated  int        `json:"updated"` // issues fetched by the last run
+	Issues   int        `json:"issues"`
+	Boards   int        `json:"boards"`
+	Sprints  int        `json:"sprints"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Status describes the mirror and the reachability of Jira
+type Status struct {
+	Online       bool          `json:"online"`
+	OfflineSince *time.Time    `json:"offlineSince,omitempty"`
+	LastError    string        `json:"lastError,omitempty"`
+	Syncing      bool          `json:"syncing"`
+	NextRun      *time.Time    `json:"nextRun,omitempty"`
+	StaleAsOf    *time.Time    `json:"staleAsOf,omitempty"`
+	Projects     []ProjectSync `json:"projects"`
+	Store        StoreStats    `json:"store"`
+}
+
+// Syncer incrementally mirrors Jira projects into a Store, asking only for
+// the issues updated since the last sync. It also tracks whether Jira is
+// reachable: while it is not, it probes Jira instead of syncing, and calls
+// the reconnect hook once Jira answers again.
+type Syncer struct {
+	client Client
+	store  *Store
+	opts   Options
+
+	mu           sync.Mutex
+	runs         map[string]*ProjectSync
+	syncing      bool
+	online       bool
+	offlineSince *time.Time
+	lastError    string
+	nextRun      *time.Time
+	stop         chan struct{}
+	wake         chan struct{}
+	onReconnect  func(ctx context.Context)
+}
+
+// NewSyncer creates a syncer mirroring the projects of opts into store
+func NewSyncer(client Client, store *Store, opts Options) *Syncer {
+	return &Syncer{
+		client: client,
+		store:  store,
+		opts:   opts.withDefaults(),
+		runs:   make(map[string]*ProjectSync),
+		online: true,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Store returns the store the syncer mirrors into
+func (s *Syncer) Store() *Store {
+	return s.store
+}
+
+// OnReconnect sets the function called when Jira is reachable again, before
+// the mirror is synced
+func (s *Syncer) OnReconnect(fn func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReconnect = fn
+}
+
+// Online reports whether Jira was reachable when last contacted
+func (s *Syncer) Online() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.online
+}
+
+// MarkOffline records that Jira could not be reached. Until a probe
+// succeeds, reads are answered from the mirror.
+func (s *Syncer) MarkOffline(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.online {
+		now := time.Now()
+		s.offlineSince = &now
+		log.Warn().Err(err).Msg("Jira is unreachable, answering reads from the local mirror")
+	}
+	s.online = false
+	if err != nil {
+		s.lastError = err.Error()
+	}
+}
+
+// markOnline records that Jira answered again
+func (s *Syncer) markOnline() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.online {
+		log.Info().Msg("Jira is reachable again")
+	}
+	s.online = true
+	s.offlineSince = nil
+	s.lastError = ""
+}
+
+// Start syncs every interval, and probes Jira every probe interval while it
+// is unreachable, until Stop is called. The first sync starts immediately.
+func (s *Syncer) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+	}
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			s.tick()
+
+			s.mu.Lock()
+			wait := s.opts.Interval
+			if !s.online {
+				wait = s.opts.ProbeInterval
+			}
+			next := time.Now().Add(wait)
+			s.nextRun = &next
+			s.mu.Unlock()
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-s.wake:
+				timer.Stop()
+			case <-stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends scheduled syncs
+func (s *Syncer) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	s.nextRun = nil
+}
+
+// Trigger runs a sync now instead of waiting for the next one. It returns
+// false when scheduled syncs are not running.
+func (s *Syncer) Trigger() bool {
+	s.mu.Lock()
+	running := s.stop != nil
+	s.mu.Unlock()
+
+	if !running {
+		return false
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// tick runs a scheduled sync, which only probes Jira while it is unreachable
+func (s *Syncer) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Interval)
+	defer cancel()
+	s.Sync(ctx)
+}
+
+// Sync brings every project up to date. When Jira was unreachable, it is
+// probed first, and the reconnect hook runs once it answers. Syncing stops at the first project for which
+// Jira cannot be reached.
+func (s *Syncer) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	if s.syncing {
+		s.mu.Unlock()
+		return fmt.Errorf("a sync is already running")
+	}
+	s.syncing = true
+	wasOffline := !s.online
+	onReconnect := s.onReconnect
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.syncing = false
+		s.mu.Unlock()
+	}()
+
+	if wasOffline {
+		if err := s.client.HealthCheck(ctx); err != nil {
+			s.MarkOffline(err)
+			return err
+		}
+		s.markOnline()
+		if onReconnect != nil {
+			onReconnect(ctx)
+		}
+	}
+
+	var failures []string
+	for _, project := range s.opts.Projects {
+		if _, err := s.SyncProject(ctx, project); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", project, err))
+			if jira.IsUnreachable(err) {
+				s.MarkOffline(err)
+				break
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// SyncProject fetches the issues of a project updated since its last sync,
+// with their comments, and refreshes its boards and sprints. It returns the
+// number of issues fetched.
+func (s *Syncer) SyncProject(ctx context.Context, key string) (int, error) {
+	key = strings.ToUpper(key)
+	started := time.Now()
+	project, _ := s.store.Project(key)
+
+	updated, err := s.syncIssues(ctx, key, project.LastSync)
+	if err == nil {
+		err = s.syncBoards(key, &project)
+	}
+	if err == nil {
+		project.Key = key
+		project.LastSync = &started
+		err = s.store.PutProject(project)
+	}
+
+	run := &ProjectSync{Project: key, LastRun: &started, Updated: updated}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	s.mu.Lock()
+	s.runs[key] = run
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Warn().Err(err).Str("project", key).Int("updated", updated).Msg("Mirror sync failed")
+		return updated, err
+	}
+
+	log.Info().
+		Str("project", key).
+		Int("updated", updated).
+		Dur("duration", time.Since(started)).
+		Msg("Mirror synced")
+	return updated, nil
+}
+
+// syncIssues stores the issues of a project updated since the last sync, or
+// all of them on the first sync
+func (s *Syncer) syncIssues(ctx context.Context, project string, lastSync *time.Time) (int, error) {
+	condition := jql.Field("project").Eq(project)
+	if lastSync != nil {
+		since := lastSync.Add(-s.opts.Overlap).In(s.opts.Location)
+		condition = condition.And(jql.Field("updated").Gte(since))
+	}
+	query := condition.OrderBy("updated", jql.Asc).String()
+
+	fetched := 0
+	for startAt := 0; ; {
+		page, err := s.client.SearchIssuesAdvancedContext(ctx, jira.SearchRequest{
+			JQL:        query,
+			StartAt:    startAt,
+			MaxResults: s.opts.PageSize,
+			Fields:     []string{"*navigable", "comment"},
+		})
+		if err != nil {
+			return fetched, err
+		}
+
+		for i := range page.Issues {
+			if err := s.completeComments(ctx, &page.Issues[i]); err != nil {
+				return fetched, err
+			}
+		}
+		if err := s.store.PutIssues(page.Issues); err != nil {
+			return fetched, err
+		}
+		fetched += len(page.Issues)
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return fetched, nil
+		}
+	}
+}
+
+// completeComments fetches the comments a search left out
+func (s *Syncer) completeComments(ctx context.Context, issue *jira.Issue) error {
+	comments := issue.Fields.Comment
+	if comments != nil && len(comments.Comments) >= comments.Total {
+		return nil
+	}
+
+	all := &jira.CommentResult{}
+	for startAt := 0; ; {
+		page, err := s.client.GetComments(ctx, issue.Key, startAt, 100)
+		if err != nil {
+			return fmt.Errorf("comments of %s: %w", issue.Key, err)
+		}
+		all.Comments = append(all.Comments, page.Comments...)
+		all.Total = page.Total
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+	all.MaxResults = len(all.Comments)
+	issue.Fields.Comment = all
+	return nil
+}
+
+// syncBoards refreshes the boards of a project and their sprints
+func (s *Syncer) syncBoards(key string, project *Project) error {
+	boards, err := s.client.GetBoards()
+	if err != nil {
+		return fmt.Errorf("boards: %w", err)
+	}
+
+	project.Boards = nil
+	project.Sprints = nil
+	seen := make(map[int]bool)
+	for _, board := range boards.Values {
+		if !strings.EqualFold(board.Location.ProjectKey, key) {
+			continue
+		}
+		project.Boards = append(project.Boards, board)
+		if board.Type != "scrum" {
+			continue
+		}
+
+		sprints, err := s.client.GetSprints(board.ID)
+		if err != nil {
+			return fmt.Errorf("sprints of board %d: %w", board.ID, err)
+		}
+		for _, sprint := range sprints.Values {
+			if !seen[sprint.ID] {
+				seen[sprint.ID] = true
+				project.Sprints = append(project.Sprints, sprint)
+			}
+		}
+	}
+	return nil
+}
+
+// Status returns the state of the mirror
+func (s *Syncer) Status() Status {
+	s.mu.Lock()
+	status := Status{
+		Online:       s.online,
+		OfflineSince: s.offlineSince,
+		LastError:    s.lastError,
+		Syncing:      s.syncing,
+		NextRun:      s.nextRun,
+	}
+	runs := make(map[string]ProjectSync, len(s.runs))
+	for key, run := range s.runs {
+		runs[key] = *run
+	}
+	s.mu.Unlock()
+
+	for _, key := range s.opts.Projects {
+		entry := runs[key]
+		entry.Project = key
+		if project, ok := s.store.Project(key); ok {
+			entry.LastSync = project.LastSync
+			entry.Boards = len(project.Boards)
+			entry.Sprints = len(project.Sprints)
+		}
+		entry.Issues = len(s.store.Issues(key))
+		status.Projects = append(status.Projects, entry)
+	}
+	if len(s.opts.Projects) > 0 {
+		status.StaleAsOf = s.store.StaleAsOf(s.opts.Projects...)
+	}
+	status.Store = s.store.Stats()
+	return status
+}
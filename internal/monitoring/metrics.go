@@ -16,6 +16,9 @@ type Metrics struct {
 	jiraAPIErrorCount  int64
 	cacheHitCount      int64
 	cacheMissCount     int64
+	coalescedCount     int64
+	staleHitCount      int64
+	refreshCount       int64
 	startTime          time.Time
 }
 
@@ -65,6 +68,24 @@ func (m *Metrics) IncrementCacheMisses() {
 	m.cacheMissCount++
 }
 
+func (m *Metrics) IncrementCoalesced() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.coalescedCount++
+}
+
+func (m *Metrics) IncrementStaleHits() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.staleHitCount++
+}
+
+func (m *Metrics) IncrementRefreshes() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.refreshCount++
+}
+
 func (m *Metrics) GetStats() map[string]interface{} {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
@@ -100,9 +121,12 @@ func (m *Metrics) GetStats() map[string]interface{} {
 			"errorRate": jiraAPIErrorRate,
 		},
 		"cache": map[string]interface{}{
-			"hits":     m.cacheHitCount,
-			"misses":   m.cacheMissCount,
-			"hitRatio": cacheHitRatio,
+			"hits":      m.cacheHitCount,
+			"misses":    m.cacheMissCount,
+			"hitRatio":  cacheHitRatio,
+			"coalesced": m.coalescedCount,
+			"staleHits": m.staleHitCount,
+			"refreshes": m.refreshCount,
 		},
 		"system": map[string]interface{}{
 			"uptime":    uptime.String(),
@@ -122,6 +146,9 @@ func (m *Metrics) Reset() {
 	m.jiraAPIErrorCount = 0
 	m.cacheHitCount = 0
 	m.cacheMissCount = 0
+	m.coalescedCount = 0
+	m.staleHitCount = 0
+	m.refreshCount = 0
 	m.startTime = time.Now()
 }
 
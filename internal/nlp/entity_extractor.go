@@ -401,6 +401,10 @@ func (p *Parser) calculateEntityConfidence(entityType EntityType, value interfac
 				confidence = 0.95
 			}
 		}
+	case EntityComponent:
+		confidence = lookupConfidence(p.componentCache, value)
+	case EntityFixVersion:
+		confidence = lookupConfidence(p.versionCache, value)
 	case EntityDate:
 		// Dates are usually clear
 		confidence = 0.9
@@ -426,10 +430,35 @@ func (p *Parser) normalizeEntityValue(entityType EntityType, value interface{})
 		if issueType, ok := value.(string); ok {
 			return normalizeIssueType(issueType)
 		}
+	case EntityComponent:
+		if name, ok := value.(string); ok {
+			if canonical, exists := p.componentCache[strings.ToLower(name)]; exists {
+				return canonical
+			}
+		}
+	case EntityFixVersion:
+		if name, ok := value.(string); ok {
+			if canonical, exists := p.versionCache[strings.ToLower(name)]; exists {
+				return canonical
+			}
+		}
 	}
 	return nil
 }
 
+// lookupConfidence scores a name against a cache of known values. When the
+// cache has not been populated the default confidence is kept.
+func lookupConfidence(cache map[string]string, value interface{}) float64 {
+	name, ok := value.(string)
+	if !ok || len(cache) == 0 {
+		return 0.8
+	}
+	if _, exists := cache[strings.ToLower(name)]; exists {
+		return 0.95
+	}
+	return 0.5
+}
+
 func (p *Parser) extractProjectFromContext(input string) *Project {
 	upperInput := strings.ToUpper(input)
 	
@@ -17,6 +17,8 @@ type Parser struct {
 	userCache     map[string]*User
 	statusCache   map[string]*Status
 	priorityCache map[string]*Priority
+	componentCache map[string]string
+	versionCache   map[string]string
 	config        *ParseConfig
 	context       *Context
 }
@@ -41,6 +43,8 @@ func NewParser(config *ParseConfig) *Parser {
 		userCache:     make(map[string]*User),
 		statusCache:   make(map[string]*Status),
 		priorityCache: make(map[string]*Priority),
+		componentCache: make(map[string]string),
+		versionCache:   make(map[string]string),
 		config:        config,
 		context: &Context{
 			UserPreferences: make(map[string]string),
@@ -500,6 +504,22 @@ func (p *Parser) SetUserCache(users map[string]*User) {
 	p.userCache = users
 }
 
+// SetComponentCache updates the known component names used to validate component entities
+func (p *Parser) SetComponentCache(names []string) {
+	p.componentCache = make(map[string]string, len(names))
+	for _, name := range names {
+		p.componentCache[strings.ToLower(name)] = name
+	}
+}
+
+// SetVersionCache updates the known version names used to validate fix version entities
+func (p *Parser) SetVersionCache(names []string) {
+	p.versionCache = make(map[string]string, len(names))
+	for _, name := range names {
+		p.versionCache[strings.ToLower(name)] = name
+	}
+}
+
 // SetContext sets a new context
 func (p *Parser) SetContext(context *Context) {
 	p.context = context
@@ -520,7 +540,9 @@ func (p *Parser) GetCacheStats() map[string]int {
 	return map[string]int{
 		"projects": len(p.projectCache),
 		"users":    len(p.userCache),
-		"statuses": len(p.statusCache),
+		"statuses":   len(p.statusCache),
+		"components": len(p.componentCache),
+		"versions":   len(p.versionCache),
 	}
 }
 
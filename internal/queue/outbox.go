@@ -0,0 +1,295 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// OutboxEntry is a job held in an outbox
+type OutboxEntry struct {
+	Seq       int64     `json:"seq"`
+	Job       Job       `json:"job"`
+	Queued    time.Time `json:"queued"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	Failed    bool      `json:"failed,omitempty"` // failed permanently; kept until discarded
+}
+
+// OutboxReplay is the outcome of replaying an outbox
+type OutboxReplay struct {
+	Replayed  int    `json:"replayed"`
+	Failed    int    `json:"failed"`
+	Remaining int    `json:"remaining"`
+	Error     string `json:"error,omitempty"` // why the replay stopped early
+}
+
+// Outbox durably holds jobs that cannot run yet, such as Jira writes made
+// while Jira is unreachable. Each job is kept as a file until it has run, and
+// jobs are replayed one at a time in the order they were added, so later
+// writes never overtake earlier ones.
+type Outbox struct {
+	dir string
+
+	mu        sync.Mutex
+	entries   []*OutboxEntry
+	nextSeq   int64
+	replaying bool
+}
+
+// NewOutbox opens the outbox in dir, loading the jobs left by a previous run
+func NewOutbox(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox directory: %w", err)
+	}
+
+	o := &Outbox{dir: dir, nextSeq: 1}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outbox entry %s: %w", file.Name(), err)
+		}
+		var entry OutboxEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			log.Warn().Err(err).Str("file", file.Name()).Msg("Skipping unreadable outbox entry")
+			continue
+		}
+		o.entries = append(o.entries, &entry)
+		if entry.Seq >= o.nextSeq {
+			o.nextSeq = entry.Seq + 1
+		}
+	}
+	sort.Slice(o.entries, func(i, j int) bool { return o.entries[i].Seq < o.entries[j].Seq })
+	return o, nil
+}
+
+// Add stores a job at the end of the outbox
+func (o *Outbox) Add(job Job) (OutboxEntry, error) {
+	if job.ID == "" {
+		job.ID = NewJobID(job.Type)
+	}
+	if job.Created.IsZero() {
+		job.Created = time.Now()
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry := &OutboxEntry{Seq: o.nextSeq, Job: job, Queued: time.Now()}
+	if err := o.write(entry); err != nil {
+		return OutboxEntry{}, err
+	}
+	o.nextSeq++
+	o.entries = append(o.entries, entry)
+
+	log.Info().
+		Str("jobId", job.ID).
+		Str("type", string(job.Type)).
+		Msg("Job held in outbox")
+	return *entry, nil
+}
+
+// Entries returns the held jobs in replay order
+func (o *Outbox) Entries() []OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries := make([]OutboxEntry, len(o.entries))
+	for i, entry := range o.entries {
+		entries[i] = *entry
+	}
+	return entries
+}
+
+// Pending counts the jobs waiting to be replayed
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	pending := 0
+	for _, entry := range o.entries {
+		if !entry.Failed {
+			pending++
+		}
+	}
+	return pending
+}
+
+// Discard removes a job from the outbox without running it
+func (o *Outbox) Discard(jobID string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, entry := range o.entries {
+		if entry.Job.ID == jobID {
+			if err := o.remove(entry); err != nil {
+				return err
+			}
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("job %s is not in the outbox", jobID)
+}
+
+// Replay runs the held jobs in order with the handlers registered on the
+// queue. A job that fails permanently is kept as failed and skipped; any
+// other failure stops the replay, leaving it and the jobs after it for the
+// next replay. Results are delivered to the queue like those of submitted jobs.
+func (o *Outbox) Replay(ctx context.Context, q *JobQueue) OutboxReplay {
+	o.mu.Lock()
+	if o.replaying {
+		o.mu.Unlock()
+		return OutboxReplay{Remaining: o.Pending(), Error: "replay already in progress"}
+	}
+	o.replaying = true
+	o.mu.Unlock()
+
+	defer func() {
+		o.mu.Lock()
+		o.replaying = false
+		o.mu.Unlock()
+	}()
+
+	var replay OutboxReplay
+	for {
+		entry := o.next()
+		if entry == nil {
+			break
+		}
+
+		start := time.Now()
+		result, err := o.run(ctx, q, entry.Job)
+		var permanent *PermanentError
+		if err == nil || errors.As(err, &permanent) {
+			q.deliver(JobResult{
+				JobID:    entry.Job.ID,
+				Success:  err == nil,
+				Result:   result,
+				Error:    err,
+				Duration: time.Since(start),
+			})
+		}
+
+		o.mu.Lock()
+		entry.Attempts++
+		if err == nil {
+			if removeErr := o.remove(entry); removeErr != nil {
+				log.Error().Err(removeErr).Str("jobId", entry.Job.ID).Msg("Failed to remove replayed outbox entry")
+			}
+			o.drop(entry)
+			replay.Replayed++
+			o.mu.Unlock()
+			continue
+		}
+
+		entry.LastError = err.Error()
+		if permanent != nil {
+			entry.Failed = true
+			replay.Failed++
+		}
+		if writeErr := o.write(entry); writeErr != nil {
+			log.Error().Err(writeErr).Str("jobId", entry.Job.ID).Msg("Failed to update outbox entry")
+		}
+		o.mu.Unlock()
+
+		log.Warn().Err(err).
+			Str("jobId", entry.Job.ID).
+			Bool("permanent", entry.Failed).
+			Msg("Outbox job failed")
+
+		if !entry.Failed {
+			replay.Error = err.Error()
+			break
+		}
+	}
+
+	replay.Remaining = o.Pending()
+	return replay
+}
+
+// next returns the first job still to be replayed
+func (o *Outbox) next() *OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, entry := range o.entries {
+		if !entry.Failed {
+			return entry
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) run(ctx context.Context, q *JobQueue, job Job) (result interface{}, err error) {
+	handler := q.handlerFor(job.Type)
+	if handler == nil {
+		return nil, Permanent(fmt.Errorf("no handler registered for job type %s", job.Type))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = Permanent(fmt.Errorf("job panicked: %v", r))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return handler(ctx, job)
+}
+
+// drop removes an entry from the in-memory list; o.mu must be held
+func (o *Outbox) drop(target *OutboxEntry) {
+	for i, entry := range o.entries {
+		if entry == target {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (o *Outbox) path(entry *OutboxEntry) string {
+	id := strings.NewReplacer("/", "_", "\\", "_").Replace(entry.Job.ID)
+	return filepath.Join(o.dir, fmt.Sprintf("%012d-%s.json", entry.Seq, id))
+}
+
+// write persists an entry atomically; o.mu must be held
+func (o *Outbox) write(entry *OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox entry: %w", err)
+	}
+	tmp := o.path(entry) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+	if err := os.Rename(tmp, o.path(entry)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+	return nil
+}
+
+// remove deletes the file of an entry; o.mu must be held
+func (o *Outbox) remove(entry *OutboxEntry) error {
+	if err := os.Remove(o.path(entry)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove outbox entry: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -17,8 +18,15 @@ const (
 	JobTypeBulkUpdate     JobType = "BULK_UPDATE"
 	JobTypeSprintMove     JobType = "SPRINT_MOVE"
 	JobTypeWorkflowChange JobType = "WORKFLOW_CHANGE"
+	JobTypeCloneIssue     JobType = "CLONE_ISSUE"
+	JobTypeMoveIssue      JobType = "MOVE_ISSUE"
+	JobTypeImportIssues   JobType = "IMPORT_ISSUES"
+	JobTypeJiraWrite      JobType = "JIRA_WRITE" // a write held while Jira was unreachable
 )
 
+// JobHandler executes jobs of a registered type
+type JobHandler func(ctx context.Context, job Job) (interface{}, error)
+
 type Job struct {
 	ID       string
 	Type     JobType
@@ -55,6 +63,7 @@ type JobQueue struct {
 	mu            sync.RWMutex
 	metrics       *QueueMetrics
 	usePriority   bool
+	handlers      map[JobType]JobHandler
 }
 
 type QueueMetrics struct {
@@ -87,7 +96,8 @@ func NewJobQueue(config QueueConfig) *JobQueue {
 		results: make(chan JobResult, config.MaxQueueSize),
 		stopCh:  make(chan struct{}),
 		config:  config,
-		metrics: &QueueMetrics{},
+		metrics:  &QueueMetrics{},
+		handlers: make(map[JobType]JobHandler),
 	}
 
 	// Create workers
@@ -125,9 +135,33 @@ func (q *JobQueue) Start() {
 	}()
 }
 
+// RegisterHandler installs the executor for a job type, replacing any built-in handling
+func (q *JobQueue) RegisterHandler(jobType JobType, handler JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// HasHandler reports whether an executor is registered for a job type
+func (q *JobQueue) HasHandler(jobType JobType) bool {
+	return q.handlerFor(jobType) != nil
+}
+
+func (q *JobQueue) handlerFor(jobType JobType) JobHandler {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.handlers[jobType]
+}
+
+// NewJobID generates an ID for a job. Callers that need to report the ID
+// should set it on the job before submitting, since Submit takes a copy.
+func NewJobID(jobType JobType) string {
+	return fmt.Sprintf("%s-%d", jobType, time.Now().UnixNano())
+}
+
 func (q *JobQueue) Submit(job Job) error {
 	if job.ID == "" {
-		job.ID = fmt.Sprintf("%s-%d", job.Type, time.Now().UnixNano())
+		job.ID = NewJobID(job.Type)
 	}
 	if job.Created.IsZero() {
 		job.Created = time.Now()
@@ -178,6 +212,30 @@ func (q *JobQueue) updateMetrics(fn func(*QueueMetrics)) {
 	fn(q.metrics)
 }
 
+// deliver records the result of a job run outside the workers, such as a
+// job replayed from an outbox
+func (q *JobQueue) deliver(result JobResult) {
+	q.updateMetrics(func(m *QueueMetrics) {
+		m.TotalJobs++
+		if result.Success {
+			m.SuccessfulJobs++
+		} else {
+			m.FailedJobs++
+		}
+	})
+
+	select {
+	case <-q.stopCh:
+		return
+	default:
+	}
+	select {
+	case q.results <- result:
+	default:
+		log.Warn().Str("jobId", result.JobID).Msg("Failed to send job result - results channel full")
+	}
+}
+
 func (q *JobQueue) reportMetrics() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
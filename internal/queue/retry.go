@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -25,6 +26,28 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
 }
 
+// PermanentError marks an error that must not be retried, e.g. because the
+// failed job may already have had side effects
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that it is not retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
 func NewRetryManager(maxRetries int, baseDelay, maxDelay time.Duration) *RetryManager {
 	return &RetryManager{
 		maxRetries: maxRetries,
@@ -123,6 +146,11 @@ func isRetryableError(err error) bool {
 		return false
 	}
 
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
 	// Check for retryable HTTP status codes
 	if httpErr, ok := err.(*HTTPError); ok {
 		switch httpErr.StatusCode {
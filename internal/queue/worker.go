@@ -121,6 +121,11 @@ func (w *Worker) processJob(job Job) {
 }
 
 func (w *Worker) executeJob(ctx context.Context, job Job) (interface{}, error) {
+	// Registered handlers take precedence over the built-in job types
+	if handler := w.queue.handlerFor(job.Type); handler != nil {
+		return handler(ctx, job)
+	}
+
 	// Job execution logic based on job type
 	switch job.Type {
 	case JobTypeCreateIssue:
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// CreateMetaClient defines the Jira operations needed by the create metadata service
+type CreateMetaClient interface {
+	GetCreateMeta(projectKey, issueType string) (*jira.CreateMeta, error)
+}
+
+// CreateMetaService caches create metadata and validates issue payloads against it
+type CreateMetaService struct {
+	client CreateMetaClient
+	cache  *CreateMetaCache
+}
+
+// CreateMetaCache provides in-memory caching for create metadata
+type CreateMetaCache struct {
+	mu         sync.RWMutex
+	metas      map[string]*jira.CreateMeta // project:issueType -> metadata
+	lastUpdate map[string]time.Time
+	ttl        time.Duration
+}
+
+// NewCreateMetaService creates a new create metadata service
+func NewCreateMetaService(client CreateMetaClient) *CreateMetaService {
+	return &CreateMetaService{
+		client: client,
+		cache: &CreateMetaCache{
+			metas:      make(map[string]*jira.CreateMeta),
+			lastUpdate: make(map[string]time.Time),
+			ttl:        30 * time.Minute,
+		},
+	}
+}
+
+// Client returns the Jira client backing the service
+func (s *CreateMetaService) Client() CreateMetaClient {
+	return s.client
+}
+
+// Get returns the create metadata for an issue type in a project
+func (s *CreateMetaService) Get(projectKey, issueType string) (*jira.CreateMeta, error) {
+	key := strings.ToUpper(projectKey) + ":" + strings.ToLower(issueType)
+
+	s.cache.mu.RLock()
+	meta, ok := s.cache.metas[key]
+	fresh := ok && time.Since(s.cache.lastUpdate[key]) < s.cache.ttl
+	s.cache.mu.RUnlock()
+	if fresh {
+		return meta, nil
+	}
+
+	meta, err := s.client.GetCreateMeta(projectKey, issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.mu.Lock()
+	s.cache.metas[key] = meta
+	s.cache.lastUpdate[key] = time.Now()
+	s.cache.mu.Unlock()
+
+	return meta, nil
+}
+
+// Validate validates and coerces an issue payload for the given project and issue type
+func (s *CreateMetaService) Validate(projectKey, issueType string, fields map[string]interface{}, resolveUser jira.UserFieldResolver) (*jira.FieldValidationResult, error) {
+	meta, err := s.Get(projectKey, issueType)
+	if err != nil {
+		return nil, err
+	}
+	return meta.ValidateFields(fields, resolveUser), nil
+}
+
+// Invalidate drops cached metadata for a project, or everything when projectKey is empty
+func (s *CreateMetaService) Invalidate(projectKey string) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	prefix := strings.ToUpper(projectKey) + ":"
+	for key := range s.cache.metas {
+		if projectKey == "" || strings.HasPrefix(key, prefix) {
+			delete(s.cache.metas, key)
+			delete(s.cache.lastUpdate, key)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// HierarchyClient defines the Jira operations needed by the hierarchy service
+type HierarchyClient interface {
+	GetHierarchyFields() (*jira.HierarchyFields, error)
+	CreateSubtask(parentKey string, req jira.SubtaskRequest) (*jira.Issue, error)
+	SetParent(issueKey, parentKey string, hf *jira.HierarchyFields) error
+	GetChildren(issueKey string, hf *jira.HierarchyFields) ([]*jira.HierarchyNode, error)
+	GetHierarchy(issueKey string, hf *jira.HierarchyFields, maxDepth int) (*jira.HierarchyNode, error)
+}
+
+// HierarchyService manages epic, parent/child and subtask relationships
+type HierarchyService struct {
+	client HierarchyClient
+
+	mu          sync.RWMutex
+	fields      *jira.HierarchyFields
+	fieldsFetch time.Time
+	ttl         time.Duration
+}
+
+// NewHierarchyService creates a new hierarchy service
+func NewHierarchyService(client HierarchyClient) *HierarchyService {
+	return &HierarchyService{
+		client: client,
+		ttl:    time.Hour,
+	}
+}
+
+// Client returns the Jira client backing the service
+func (s *HierarchyService) Client() HierarchyClient {
+	return s.client
+}
+
+// Fields returns the Epic Link and story points fields, discovering them on first use.
+// A discovery failure is not fatal: the parent field alone is used instead.
+func (s *HierarchyService) Fields() *jira.HierarchyFields {
+	s.mu.RLock()
+	fields := s.fields
+	fresh := fields != nil && time.Since(s.fieldsFetch) < s.ttl
+	s.mu.RUnlock()
+	if fresh {
+		return fields
+	}
+
+	discovered, err := s.client.GetHierarchyFields()
+	if err != nil {
+		if fields != nil {
+			return fields
+		}
+		return &jira.HierarchyFields{}
+	}
+
+	s.mu.Lock()
+	s.fields = discovered
+	s.fieldsFetch = time.Now()
+	s.mu.Unlock()
+
+	return discovered
+}
+
+// CreateSubtask creates a subtask under the given parent issue
+func (s *HierarchyService) CreateSubtask(parentKey string, req jira.SubtaskRequest) (*jira.Issue, error) {
+	return s.client.CreateSubtask(parentKey, req)
+}
+
+// SetParent sets, changes or (with an empty parentKey) removes the parent of an issue
+func (s *HierarchyService) SetParent(issueKey, parentKey string) error {
+	return s.client.SetParent(issueKey, parentKey, s.Fields())
+}
+
+// Children returns the direct children of an issue
+func (s *HierarchyService) Children(issueKey string) ([]*jira.HierarchyNode, error) {
+	return s.client.GetChildren(issueKey, s.Fields())
+}
+
+// Tree returns an issue with its descendants down to maxDepth levels
+func (s *HierarchyService) Tree(issueKey string, maxDepth int) (*jira.HierarchyNode, error) {
+	return s.client.GetHierarchy(issueKey, s.Fields(), maxDepth)
+}
+
+// Rollup returns the descendants of an issue together with their aggregated progress
+func (s *HierarchyService) Rollup(issueKey string, maxDepth int) (*jira.HierarchyNode, *jira.HierarchyRollup, error) {
+	tree, err := s.Tree(issueKey, maxDepth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tree, tree.Rollup(), nil
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/rs/zerolog/log"
+)
+
+// ImportClient defines the Jira operations needed by the import service
+type ImportClient interface {
+	ImportChunk(ctx context.Context, rows []jira.ImportRow, epicLinkField string) []jira.ImportRowReport
+}
+
+// ImportChunkSubmitter hands a chunk of an import to the job queue. The queued
+// job must call ImportService.RunChunk with the same arguments.
+type ImportChunkSubmitter func(importID string, rows []int) error
+
+// Import run statuses
+const (
+	ImportRunning             = "running"
+	ImportCompleted           = "completed"
+	ImportCompletedWithErrors = "completed_with_errors"
+)
+
+// ImportRun is the progress of an import being executed
+type ImportRun struct {
+	ID        string                 `json:"id"`
+	Status    string                 `json:"status"`
+	Created   time.Time              `json:"created"`
+	Finished  *time.Time             `json:"finished,omitempty"`
+	Summary   ImportRunSummary       `json:"summary"`
+	Rows      []jira.ImportRowReport `json:"rows"`
+	Level     int                    `json:"level"`
+	Levels    int                    `json:"levels"`
+	ChunkSize int                    `json:"chunkSize"`
+	EpicField string                 `json:"epicLinkField,omitempty"`
+	rows      []jira.ImportRow
+	levels    [][]int
+	pending   int
+	keys      map[int]string // row number -> created or updated key
+}
+
+// ImportRunSummary counts the outcomes of an import run
+type ImportRunSummary struct {
+	Total   int `json:"total"`
+	Pending int `json:"pending"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// ImportService executes validated import plans chunk by chunk through the job
+// queue. Rows are imported level by level so that parents and epics exist
+// before the rows that reference them.
+type ImportService struct {
+	client ImportClient
+	submit ImportChunkSubmitter
+	mu     sync.Mutex
+	runs   map[string]*ImportRun
+	ttl    time.Duration
+}
+
+// NewImportService creates a new import service
+func NewImportService(client ImportClient, submit ImportChunkSubmitter) *ImportService {
+	return &ImportService{
+		client: client,
+		submit: submit,
+		runs:   make(map[string]*ImportRun),
+		ttl:    24 * time.Hour,
+	}
+}
+
+// Client returns the Jira client backing the service
+func (s *ImportService) Client() ImportClient {
+	return s.client
+}
+
+// Start begins executing a plan. Rows that are not valid are reported as
+// skipped; the caller decides whether an invalid plan may be started.
+func (s *ImportService) Start(plan *jira.ImportPlan, chunkSize int, epicLinkField string) (*ImportRun, error) {
+	if chunkSize <= 0 || chunkSize > jira.MaxImportChunkSize {
+		chunkSize = jira.MaxImportChunkSize
+	}
+
+	now := time.Now()
+	run := &ImportRun{
+		ID:        fmt.Sprintf("import_%d", now.UnixNano()),
+		Status:    ImportRunning,
+		Created:   now,
+		Rows:      make([]jira.ImportRowReport, len(plan.Reports)),
+		Levels:    len(plan.Levels),
+		ChunkSize: chunkSize,
+		EpicField: epicLinkField,
+		rows:      plan.Rows,
+		levels:    plan.Levels,
+		keys:      make(map[int]string),
+	}
+	for i, report := range plan.Reports {
+		report.Fields = nil
+		if report.Status == jira.ImportRowValid {
+			report.Status = jira.ImportRowPending
+		} else {
+			report.Status = jira.ImportRowSkipped
+		}
+		run.Rows[i] = report
+	}
+
+	s.mu.Lock()
+	s.pruneLocked()
+	s.runs[run.ID] = run
+	s.mu.Unlock()
+
+	if err := s.dispatch(run, 0); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return run.snapshot(), nil
+}
+
+// Get returns a snapshot of an import run
+func (s *ImportService) Get(id string) (*ImportRun, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok {
+		return nil, false
+	}
+	return run.snapshot(), true
+}
+
+// RunChunk executes one queued chunk of an import and, once every chunk of the
+// current level has finished, queues the next level
+func (s *ImportService) RunChunk(ctx context.Context, importID string, indexes []int) error {
+	s.mu.Lock()
+	run, ok := s.runs[importID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("import %s not found", importID)
+	}
+
+	// Resolve references to rows of earlier levels into the keys they received
+	var rows []jira.ImportRow
+	var positions []int
+	for _, i := range indexes {
+		row := run.rows[i]
+		blockedBy := 0
+		if row.ParentRow != 0 {
+			if row.Parent = run.keys[row.ParentRow]; row.Parent == "" {
+				blockedBy = row.ParentRow
+			}
+		}
+		if row.EpicRow != 0 {
+			if row.Epic = run.keys[row.EpicRow]; row.Epic == "" {
+				blockedBy = row.EpicRow
+			}
+		}
+		if blockedBy != 0 {
+			run.Rows[i].Status = jira.ImportRowSkipped
+			run.Rows[i].Errors = append(run.Rows[i].Errors, fmt.Sprintf("row %d it references was not imported", blockedBy))
+			continue
+		}
+		rows = append(rows, row)
+		positions = append(positions, i)
+	}
+	epicField := run.EpicField
+	s.mu.Unlock()
+
+	var reports []jira.ImportRowReport
+	if len(rows) > 0 {
+		reports = s.client.ImportChunk(ctx, rows, epicField)
+	}
+
+	s.mu.Lock()
+	for n, i := range positions {
+		run.Rows[i] = reports[n]
+		if reports[n].Key != "" && reports[n].Status != jira.ImportRowFailed {
+			run.keys[reports[n].Row] = reports[n].Key
+		}
+	}
+
+	run.pending--
+	if run.pending > 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	next := run.Level + 1
+	s.mu.Unlock()
+
+	return s.dispatch(run, next)
+}
+
+// dispatch queues the chunks of the first non-empty level from level onwards,
+// or finishes the run when no level is left. Chunks are submitted without
+// holding the lock, since a queue may run them straight away.
+func (s *ImportService) dispatch(run *ImportRun, level int) error {
+	s.mu.Lock()
+	for level < len(run.levels) && len(run.levels[level]) == 0 {
+		level++
+	}
+	if level >= len(run.levels) {
+		s.finishLocked(run)
+		s.mu.Unlock()
+		return nil
+	}
+
+	indexes := run.levels[level]
+	var chunks [][]int
+	for start := 0; start < len(indexes); start += run.ChunkSize {
+		end := start + run.ChunkSize
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		chunks = append(chunks, indexes[start:end])
+	}
+	run.Level = level
+	run.pending = len(chunks)
+	s.mu.Unlock()
+
+	var submitErr error
+	for _, chunk := range chunks {
+		err := s.submit(run.ID, chunk)
+		if err == nil {
+			continue
+		}
+		submitErr = err
+
+		// A chunk that cannot be queued fails its rows so that the run still completes
+		s.mu.Lock()
+		for _, i := range chunk {
+			run.Rows[i].Status = jira.ImportRowFailed
+			run.Rows[i].Errors = append(run.Rows[i].Errors, fmt.Sprintf("failed to queue import chunk: %v", err))
+		}
+		run.pending--
+		done := run.pending == 0
+		s.mu.Unlock()
+
+		if done {
+			if dispatchErr := s.dispatch(run, level+1); dispatchErr != nil {
+				return dispatchErr
+			}
+		}
+	}
+	return submitErr
+}
+
+func (s *ImportService) finishLocked(run *ImportRun) {
+	now := time.Now()
+	run.Finished = &now
+	run.Status = ImportCompleted
+	for _, row := range run.Rows {
+		if row.Status == jira.ImportRowFailed || row.Status == jira.ImportRowSkipped {
+			run.Status = ImportCompletedWithErrors
+			break
+		}
+	}
+	log.Info().Str("importId", run.ID).Str("status", run.Status).Dur("duration", now.Sub(run.Created)).Msg("Import finished")
+}
+
+// pruneLocked drops finished runs older than the retention period
+func (s *ImportService) pruneLocked() {
+	for id, run := range s.runs {
+		if run.Finished != nil && time.Since(*run.Finished) > s.ttl {
+			delete(s.runs, id)
+		}
+	}
+}
+
+// snapshot copies the public state of a run and computes its summary
+func (r *ImportRun) snapshot() *ImportRun {
+	copied := &ImportRun{
+		ID:        r.ID,
+		Status:    r.Status,
+		Created:   r.Created,
+		Finished:  r.Finished,
+		Rows:      append([]jira.ImportRowReport(nil), r.Rows...),
+		Level:     r.Level,
+		Levels:    r.Levels,
+		ChunkSize: r.ChunkSize,
+		EpicField: r.EpicField,
+	}
+
+	copied.Summary.Total = len(r.Rows)
+	for _, row := range r.Rows {
+		switch row.Status {
+		case jira.ImportRowPending:
+			copied.Summary.Pending++
+		case jira.ImportRowCreated:
+			copied.Summary.Created++
+		case jira.ImportRowUpdated:
+			copied.Summary.Updated++
+		case jira.ImportRowFailed:
+			copied.Summary.Failed++
+		case jira.ImportRowSkipped:
+			copied.Summary.Skipped++
+		}
+	}
+	return copied
+}
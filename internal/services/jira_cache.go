@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/rs/zerolog/log"
+)
+
+// JiraCacheClient is the subset of the Jira client read through JiraCache
+type JiraCacheClient interface {
+	GetProjects(ctx context.Context) ([]jira.Project, error)
+	GetBoards() (*jira.BoardList, error)
+	GetSprints(boardID int) (*jira.SprintList, error)
+	GetSprintIssues(sprintID int) (*jira.SprintIssueList, error)
+	GetWorkflows() (*jira.WorkflowList, error)
+	GetCustomFields(projectKey string) ([]jira.CustomField, error)
+	GetIssueLinkTypes() ([]jira.IssueLinkType, error)
+	GetIssue(ctx context.Context, issueKey string, expand []string) (*jira.Issue, error)
+	GetIssueTransitions(ctx context.Context, issueKey string) (*jira.TransitionsResult, error)
+}
+
+const (
+	// maxPrefetches bounds the prefetches running at once; further
+	// prefetches are dropped, since they only anticipate reads
+	maxPrefetches = 4
+	// maxPrefetchedLinks bounds the linked issues fetched after an issue read
+	maxPrefetchedLinks = 10
+	prefetchTimeout    = 30 * time.Second
+)
+
+// JiraCacheStats tracks the Jira read cache and its prefetcher
+type JiraCacheStats struct {
+	Metadata          cache.ReadThroughStats `json:"metadata"`
+	Issues            cache.ReadThroughStats `json:"issues"`
+	Prefetches        int64                  `json:"prefetches"`
+	PrefetchesDropped int64                  `json:"prefetchesDropped"`
+}
+
+// JiraCache caches Jira reads shared by the API handlers, warm-up and
+// prefetch. Metadata such as projects, boards and workflows is kept long and
+// served stale while it refreshes; issue data is kept briefly. Entries carry
+// dependency tags and are evicted by Jira writes.
+type JiraCache struct {
+	client   JiraCacheClient
+	metadata *cache.ReadThroughCache
+	issues   *cache.ReadThroughCache
+
+	prefetchSlots chan struct{}
+	statsMu       sync.Mutex
+	stats         JiraCacheStats
+}
+
+// NewJiraCache creates a Jira read cache
+func NewJiraCache(client JiraCacheClient) *JiraCache {
+	return &JiraCache{
+		client:        client,
+		metadata:      cache.NewReadThroughCache(30*time.Minute, 30*time.Minute, 1000),
+		issues:        cache.NewReadThroughCache(2*time.Minute, 0, 2000),
+		prefetchSlots: make(chan struct{}, maxPrefetches),
+	}
+}
+
+// InvalidateTags evicts cached reads changed by a Jira write
+func (c *JiraCache) InvalidateTags(tags ...string) int {
+	return c.metadata.InvalidateTags(tags...) + c.issues.InvalidateTags(tags...)
+}
+
+// Clear removes every cached read
+func (c *JiraCache) Clear() {
+	c.metadata.Clear()
+	c.issues.Clear()
+}
+
+// GetStats returns the cache and prefetch statistics
+func (c *JiraCache) GetStats() JiraCacheStats {
+	c.statsMu.Lock()
+	stats := c.stats
+	c.statsMu.Unlock()
+	stats.Metadata = c.metadata.GetStats()
+	stats.Issues = c.issues.GetStats()
+	return stats
+}
+
+// Projects returns the visible projects
+func (c *JiraCache) Projects(ctx context.Context) ([]jira.Project, error) {
+	value, err := c.metadata.Get(ctx, "projects", func(ctx context.Context) (interface{}, []string, error) {
+		projects, err := c.client.GetProjects(ctx)
+		return projects, []string{cache.TagAllJiraData}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]jira.Project), nil
+}
+
+// Boards returns the visible boards
+func (c *JiraCache) Boards(ctx context.Context) (*jira.BoardList, error) {
+	value, err := c.metadata.Get(ctx, "boards", func(ctx context.Context) (interface{}, []string, error) {
+		boards, err := c.client.GetBoards()
+		return boards, []string{cache.TagAllJiraData}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.BoardList), nil
+}
+
+// Sprints returns the sprints of a board
+func (c *JiraCache) Sprints(ctx context.Context, boardID int) (*jira.SprintList, error) {
+	value, err := c.metadata.Get(ctx, cache.BoardTag(boardID)+":sprints", func(ctx context.Context) (interface{}, []string, error) {
+		sprints, err := c.client.GetSprints(boardID)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags := []string{cache.TagAllJiraData, cache.BoardTag(boardID)}
+		for _, sprint := range sprints.Values {
+			tags = append(tags, cache.SprintTag(sprint.ID))
+		}
+		return sprints, tags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.SprintList), nil
+}
+
+// SprintIssues returns the issues of a sprint. Like other issue lists it is
+// evicted by any issue write.
+func (c *JiraCache) SprintIssues(ctx context.Context, sprintID int) (*jira.SprintIssueList, error) {
+	value, err := c.issues.Get(ctx, cache.SprintTag(sprintID)+":issues", func(ctx context.Context) (interface{}, []string, error) {
+		issues, err := c.client.GetSprintIssues(sprintID)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags := []string{cache.TagAnyIssue, cache.TagAllJiraData, cache.SprintTag(sprintID)}
+		for _, issue := range issues.Issues {
+			tags = append(tags, cache.IssueTag(issue.Key))
+		}
+		return issues, tags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.SprintIssueList), nil
+}
+
+// Workflows returns all workflows
+func (c *JiraCache) Workflows(ctx context.Context) (*jira.WorkflowList, error) {
+	value, err := c.metadata.Get(ctx, "workflows", func(ctx context.Context) (interface{}, []string, error) {
+		workflows, err := c.client.GetWorkflows()
+		return workflows, []string{cache.TagAllJiraData}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.WorkflowList), nil
+}
+
+// CustomFields returns the custom fields, which Jira does not scope by project
+func (c *JiraCache) CustomFields(ctx context.Context) ([]jira.CustomField, error) {
+	value, err := c.metadata.Get(ctx, "fields", func(ctx context.Context) (interface{}, []string, error) {
+		fields, err := c.client.GetCustomFields("")
+		return fields, []string{cache.TagAllJiraData}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]jira.CustomField), nil
+}
+
+// LinkTypes returns the issue link types
+func (c *JiraCache) LinkTypes(ctx context.Context) ([]jira.IssueLinkType, error) {
+	value, err := c.metadata.Get(ctx, "linkTypes", func(ctx context.Context) (interface{}, []string, error) {
+		linkTypes, err := c.client.GetIssueLinkTypes()
+		return linkTypes, []string{cache.TagAllJiraData}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]jira.IssueLinkType), nil
+}
+
+// Issue returns an issue without expansions
+func (c *JiraCache) Issue(ctx context.Context, issueKey string) (*jira.Issue, error) {
+	issueKey = strings.ToUpper(issueKey)
+	value, err := c.issues.Get(ctx, cache.IssueTag(issueKey), func(ctx context.Context) (interface{}, []string, error) {
+		issue, err := c.client.GetIssue(ctx, issueKey, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return issue, cache.DependencyTags(issue), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.Issue), nil
+}
+
+// Transitions returns the transitions available on an issue
+func (c *JiraCache) Transitions(ctx context.Context, issueKey string) (*jira.TransitionsResult, error) {
+	issueKey = strings.ToUpper(issueKey)
+	value, err := c.issues.Get(ctx, cache.IssueTag(issueKey)+":transitions", func(ctx context.Context) (interface{}, []string, error) {
+		transitions, err := c.client.GetIssueTransitions(ctx, issueKey)
+		return transitions, issueKeyTags(issueKey), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.TransitionsResult), nil
+}
+
+// Links returns the links of an issue, which are part of the cached issue
+func (c *JiraCache) Links(ctx context.Context, issueKey string) ([]jira.IssueLink, error) {
+	issue, err := c.Issue(ctx, issueKey)
+	if err != nil {
+		return nil, err
+	}
+	return issue.Fields.IssueLinks, nil
+}
+
+// issueKeyTags returns the tags of data belonging to a single issue
+func issueKeyTags(issueKey string) []string {
+	return cache.IssueTags([]jira.Issue{{Key: issueKey}})
+}
+
+// PrefetchIssue fetches in the background what is usually read after an
+// issue: its transitions and the issues it links to
+func (c *JiraCache) PrefetchIssue(issue *jira.Issue) {
+	if issue == nil || issue.Key == "" {
+		return
+	}
+
+	linked := make([]string, 0, maxPrefetchedLinks)
+	for _, link := range issue.Fields.IssueLinks {
+		for _, ref := range []*jira.IssueRef{link.OutwardIssue, link.InwardIssue} {
+			if ref != nil && ref.Key != "" && len(linked) < maxPrefetchedLinks {
+				linked = append(linked, ref.Key)
+			}
+		}
+	}
+
+	c.prefetch("issue "+issue.Key, func(ctx context.Context) error {
+		if _, err := c.Transitions(ctx, issue.Key); err != nil {
+			return err
+		}
+		for _, key := range linked {
+			if _, err := c.Issue(ctx, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PrefetchSprint fetches the issues of a sprint in the background
+func (c *JiraCache) PrefetchSprint(sprintID int) {
+	c.prefetch(fmt.Sprintf("sprint %d", sprintID), func(ctx context.Context) error {
+		_, err := c.SprintIssues(ctx, sprintID)
+		return err
+	})
+}
+
+// prefetch runs fn in the background if a prefetch slot is free
+func (c *JiraCache) prefetch(subject string, fn func(ctx context.Context) error) {
+	select {
+	case c.prefetchSlots <- struct{}{}:
+	default:
+		c.statsMu.Lock()
+		c.stats.PrefetchesDropped++
+		c.statsMu.Unlock()
+		return
+	}
+
+	c.statsMu.Lock()
+	c.stats.Prefetches++
+	c.statsMu.Unlock()
+
+	go func() {
+		defer func() { <-c.prefetchSlots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), prefetchTimeout)
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			log.Debug().Err(err).Str("subject", subject).Msg("Prefetch failed")
+		}
+	}()
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// JQLClient defines the Jira operations needed by the JQL service
+type JQLClient interface {
+	GetJQLAutocompleteData() (*jira.JQLAutocompleteData, error)
+}
+
+// JQLService validates JQL locally against cached field and function metadata
+type JQLService struct {
+	client JQLClient
+	cache  *JQLCache
+}
+
+// JQLCache provides in-memory caching for the JQL validator
+type JQLCache struct {
+	mu         sync.RWMutex
+	validator  *jira.JQLValidator
+	lastUpdate time.Time
+	ttl        time.Duration
+}
+
+// NewJQLService creates a new JQL service
+func NewJQLService(client JQLClient) *JQLService {
+	return &JQLService{
+		client: client,
+		cache: &JQLCache{
+			ttl: 1 * time.Hour,
+		},
+	}
+}
+
+// Client returns the Jira client backing the service
+func (s *JQLService) Client() JQLClient {
+	return s.client
+}
+
+// Validator returns a validator built from the instance's JQL autocomplete data
+func (s *JQLService) Validator() (*jira.JQLValidator, error) {
+	s.cache.mu.RLock()
+	validator := s.cache.validator
+	fresh := validator != nil && time.Since(s.cache.lastUpdate) < s.cache.ttl
+	s.cache.mu.RUnlock()
+	if fresh {
+		return validator, nil
+	}
+
+	data, err := s.client.GetJQLAutocompleteData()
+	if err != nil {
+		return nil, err
+	}
+	validator = jira.NewJQLValidator(data)
+
+	s.cache.mu.Lock()
+	s.cache.validator = validator
+	s.cache.lastUpdate = time.Now()
+	s.cache.mu.Unlock()
+
+	return validator, nil
+}
+
+// Validate parses and statically validates a JQL query. The query is nil when
+// the JQL has a syntax error.
+func (s *JQLService) Validate(jql string) (*jira.JQLQuery, []jira.JQLError, error) {
+	validator, err := s.Validator()
+	if err != nil {
+		return nil, nil, err
+	}
+	query, errs := validator.Validate(jql)
+	return query, errs, nil
+}
+
+// Invalidate drops the cached field and function metadata
+func (s *JQLService) Invalidate() {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	s.cache.validator = nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/cache"
 	"github.com/ericfisherdev/GoJira/internal/jira"
 	"github.com/rs/zerolog/log"
 )
@@ -14,10 +16,12 @@ import (
 type SprintService struct {
 	jiraClient jira.ClientInterface
 	cache      *SprintCache
+	issues     *cache.ReadThroughCache // sprint issue lists used for metrics
 }
 
 // SprintCache provides in-memory caching for sprint data
 type SprintCache struct {
+	mu         sync.RWMutex
 	sprints    map[int]*jira.Sprint
 	boardCache map[int][]int // boardID -> []sprintIDs
 	lastUpdate map[string]time.Time
@@ -34,9 +38,71 @@ func NewSprintService(jiraClient jira.ClientInterface) *SprintService {
 			lastUpdate: make(map[string]time.Time),
 			ttl:        5 * time.Minute,
 		},
+		issues: cache.NewReadThroughCache(time.Minute, 5*time.Minute, 200),
 	}
 }
 
+// put caches a sprint
+func (c *SprintCache) put(sprint *jira.Sprint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sprints[sprint.ID] = sprint
+	c.lastUpdate[cache.SprintTag(sprint.ID)] = time.Now()
+}
+
+// InvalidateTags evicts the sprints named by sprint tags and the sprints and
+// sprint lists of boards named by board tags
+func (c *SprintCache) InvalidateTags(tags ...string) int {
+	wanted := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		wanted[tag] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+	for id, sprint := range c.sprints {
+		if wanted[cache.SprintTag(id)] || wanted[cache.BoardTag(sprint.OriginBoardID)] {
+			delete(c.sprints, id)
+			delete(c.lastUpdate, cache.SprintTag(id))
+			count++
+		}
+	}
+	for boardID := range c.boardCache {
+		if wanted[cache.BoardTag(boardID)] {
+			delete(c.boardCache, boardID)
+			count++
+		}
+	}
+	return count
+}
+
+// InvalidateTags evicts cached sprint data changed by a Jira write
+func (s *SprintService) InvalidateTags(tags ...string) int {
+	return s.cache.InvalidateTags(tags...) + s.issues.InvalidateTags(tags...)
+}
+
+// getSprintIssues returns the issues of a sprint through the read-through
+// cache, tagged with the sprint and each of its issues
+func (s *SprintService) getSprintIssues(ctx context.Context, sprintID int) (*jira.SprintIssueList, error) {
+	value, err := s.issues.Get(ctx, cache.SprintTag(sprintID), func(ctx context.Context) (interface{}, []string, error) {
+		issues, err := s.jiraClient.GetSprintIssues(sprintID)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags := []string{cache.TagAllJiraData, cache.SprintTag(sprintID)}
+		for _, issue := range issues.Issues {
+			tags = append(tags, cache.IssueTag(issue.Key))
+		}
+		return issues, tags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*jira.SprintIssueList), nil
+}
+
 // SprintValidation provides sprint validation rules
 type SprintValidation struct {
 	MinDuration   time.Duration
@@ -116,7 +182,7 @@ func (s *SprintService) GetActiveSprints(ctx context.Context) ([]*jira.Sprint, e
 				activeSprint := sprint
 				activeSprints = append(activeSprints, &activeSprint)
 				// Cache the sprint
-				s.cache.sprints[sprint.ID] = &activeSprint
+				s.cache.put(&activeSprint)
 			}
 		}
 	}
@@ -282,6 +348,39 @@ type SprintCompletionReport struct {
 	Duration         time.Duration
 }
 
+// ExportDocument lays out the completion report for the XLSX and HTML exports
+func (r *SprintCompletionReport) ExportDocument() *jira.ExportDocument {
+	doc := &jira.ExportDocument{
+		Title:    "Sprint Completion Report",
+		Subtitle: fmt.Sprintf("Completed %s", r.CompletedAt.Format("January 2, 2006 at 3:04 PM")),
+	}
+	
+	summary := jira.ExportTable{Title: "Summary", Header: []string{"Metric", "Value"}}
+	if r.Sprint != nil {
+		doc.Title = "Sprint Completion Report: " + r.Sprint.Name
+		summary.Rows = append(summary.Rows,
+			[]interface{}{"Sprint", r.Sprint.Name},
+			[]interface{}{"Goal", r.Sprint.Goal},
+		)
+	}
+	summary.Rows = append(summary.Rows,
+		[]interface{}{"Total issues", r.TotalIssues},
+		[]interface{}{"Completed issues", len(r.CompletedIssues)},
+		[]interface{}{"Incomplete issues", len(r.IncompleteIssues)},
+		[]interface{}{"Completion rate (%)", r.CompletionRate},
+		[]interface{}{"Velocity", r.Velocity},
+		[]interface{}{"Duration (days)", r.Duration.Hours() / 24},
+		[]interface{}{"Completed at", r.CompletedAt},
+	)
+	
+	doc.Tables = append(doc.Tables,
+		summary,
+		jira.SprintIssueTable("Completed", r.CompletedIssues),
+		jira.SprintIssueTable("Incomplete", r.IncompleteIssues),
+	)
+	return doc
+}
+
 func (s *SprintService) analyzeSprintCompletion(sprint *jira.Sprint, issues *jira.SprintIssueList) *SprintCompletionReport {
 	report := &SprintCompletionReport{
 		Sprint:      sprint,
@@ -334,7 +433,7 @@ func (s *SprintService) GetSprintMetrics(ctx context.Context, sprintID int) (*Sp
 		return nil, fmt.Errorf("failed to get sprint: %w", err)
 	}
 	
-	issues, err := s.jiraClient.GetSprintIssues(sprintID)
+	issues, err := s.getSprintIssues(ctx, sprintID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sprint issues: %w", err)
 	}
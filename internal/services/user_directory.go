@@ -0,0 +1,498 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/nlp"
+	"github.com/rs/zerolog/log"
+)
+
+// UserClient defines the Jira operations needed by the user directory
+type UserClient interface {
+	SearchUsers(query string, maxResults int) ([]jira.User, error)
+	FindAssignableUsers(q jira.AssignableUserQuery) ([]jira.User, error)
+	GetUser(accountID string) (*jira.User, error)
+}
+
+// UserDirectory looks up Jira users and resolves free-form references to account IDs
+type UserDirectory struct {
+	client  UserClient
+	cache   *UserCache
+	version uint64
+}
+
+// UserCache provides in-memory caching for user lookups
+type UserCache struct {
+	mu         sync.RWMutex
+	users      map[string]*jira.User // accountId -> user
+	seen       map[string]time.Time  // accountId -> last time Jira returned the user
+	queries    map[string][]string   // query key -> accountIds
+	lastUpdate map[string]time.Time
+	ttl        time.Duration
+}
+
+// UserCandidate is a user that matched a reference, with a match score
+type UserCandidate struct {
+	User      jira.User `json:"user"`
+	Score     float64   `json:"score"`
+	MatchedOn string    `json:"matchedOn"`
+}
+
+// UserResolution is the result of resolving a user reference
+type UserResolution struct {
+	Input      string          `json:"input"`
+	Resolved   bool            `json:"resolved"`
+	Ambiguous  bool            `json:"ambiguous"`
+	AccountID  string          `json:"accountId,omitempty"`
+	User       *jira.User      `json:"user,omitempty"`
+	Candidates []UserCandidate `json:"candidates,omitempty"`
+	Message    string          `json:"message,omitempty"`
+}
+
+const (
+	userSearchLimit = 50
+	// ambiguityMargin is the minimum score lead the best candidate needs over the runner-up
+	ambiguityMargin = 0.1
+	minMatchScore   = 0.5
+	// Users not returned by Jira for userTTL are dropped, as are the least
+	// recently seen ones beyond maxCachedUsers
+	userTTL        = 24 * time.Hour
+	maxCachedUsers = 5000
+)
+
+var accountIDPattern = regexp.MustCompile(`^([0-9a-f]{24}|\d+:[0-9a-f-]{36})$`)
+
+// NewUserDirectory creates a new user directory
+func NewUserDirectory(client UserClient) *UserDirectory {
+	return &UserDirectory{
+		client: client,
+		cache: &UserCache{
+			users:      make(map[string]*jira.User),
+			seen:       make(map[string]time.Time),
+			queries:    make(map[string][]string),
+			lastUpdate: make(map[string]time.Time),
+			ttl:        15 * time.Minute,
+		},
+	}
+}
+
+// Client returns the Jira client backing the directory
+func (d *UserDirectory) Client() UserClient {
+	return d.client
+}
+
+// Version increases every time users are added to or dropped from the directory
+func (d *UserDirectory) Version() uint64 {
+	return atomic.LoadUint64(&d.version)
+}
+
+// Search finds users matching a query
+func (d *UserDirectory) Search(query string, maxResults int) ([]jira.User, error) {
+	if maxResults <= 0 {
+		maxResults = userSearchLimit
+	}
+	key := "search:" + strings.ToLower(strings.TrimSpace(query))
+
+	if users, ok := d.cached(key); ok {
+		return limitUsers(users, maxResults), nil
+	}
+
+	users, err := d.client.SearchUsers(query, userSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	d.store(key, users)
+
+	return limitUsers(users, maxResults), nil
+}
+
+// FindAssignable finds users that can be assigned issues in the given project or issue
+func (d *UserDirectory) FindAssignable(q jira.AssignableUserQuery) ([]jira.User, error) {
+	maxResults := q.MaxResults
+	if maxResults <= 0 {
+		maxResults = userSearchLimit
+	}
+	key := fmt.Sprintf("assignable:%s:%s:%s", q.ProjectKey, q.IssueKey, strings.ToLower(strings.TrimSpace(q.Query)))
+
+	if users, ok := d.cached(key); ok {
+		return limitUsers(users, maxResults), nil
+	}
+
+	q.MaxResults = userSearchLimit
+	users, err := d.client.FindAssignableUsers(q)
+	if err != nil {
+		return nil, err
+	}
+	d.store(key, users)
+
+	return limitUsers(users, maxResults), nil
+}
+
+// Get retrieves a user by account ID
+func (d *UserDirectory) Get(accountID string) (*jira.User, error) {
+	d.cache.mu.RLock()
+	user, exists := d.cache.users[accountID]
+	fresh := time.Since(d.cache.seen[accountID]) < userTTL
+	d.cache.mu.RUnlock()
+	if exists && fresh {
+		return user, nil
+	}
+
+	user, err := d.client.GetUser(accountID)
+	if err != nil {
+		return nil, err
+	}
+	d.store("", []jira.User{*user})
+
+	return user, nil
+}
+
+// Resolve maps a user reference such as "@sam", "Sam P", "sam.park" or an
+// email address to a single account ID. When the scope names a project or
+// issue, only assignable users are considered. If several users match
+// equally well the resolution is marked ambiguous and lists the candidates.
+func (d *UserDirectory) Resolve(input string, scope jira.AssignableUserQuery) (*UserResolution, error) {
+	query := strings.TrimPrefix(strings.TrimSpace(input), "@")
+	result := &UserResolution{Input: input}
+
+	if query == "" {
+		result.Message = "empty user reference"
+		return result, nil
+	}
+
+	if IsAccountID(query) {
+		user, err := d.Get(query)
+		if err != nil {
+			return nil, err
+		}
+		return resolvedTo(result, *user, "accountId"), nil
+	}
+
+	users, err := d.lookup(query, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handles like "sam.park" rarely match Jira's search directly, so retry
+	// with the first name part before giving up
+	if len(users) == 0 {
+		if parts := splitHandle(query); len(parts) > 1 {
+			users, err = d.lookup(parts[0], scope)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	candidates := rankUsers(query, users)
+	if len(candidates) == 0 {
+		result.Message = fmt.Sprintf("no users match %q", input)
+		return result, nil
+	}
+
+	best := candidates[0]
+	if len(candidates) == 1 || best.Score-candidates[1].Score >= ambiguityMargin {
+		return resolvedTo(result, best.User, best.MatchedOn), nil
+	}
+
+	result.Ambiguous = true
+	for _, candidate := range candidates {
+		if best.Score-candidate.Score < ambiguityMargin {
+			result.Candidates = append(result.Candidates, candidate)
+		}
+	}
+	result.Message = fmt.Sprintf("%q matches %d users; specify an email or account ID", input, len(result.Candidates))
+
+	return result, nil
+}
+
+// IsAccountID reports whether s looks like a Jira Cloud account ID
+func IsAccountID(s string) bool {
+	return accountIDPattern.MatchString(s)
+}
+
+// Users returns every user currently held in the directory
+func (d *UserDirectory) Users() []jira.User {
+	d.cache.mu.RLock()
+	defer d.cache.mu.RUnlock()
+
+	users := make([]jira.User, 0, len(d.cache.users))
+	for id, user := range d.cache.users {
+		if time.Since(d.cache.seen[id]) < userTTL {
+			users = append(users, *user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].DisplayName < users[j].DisplayName })
+
+	return users
+}
+
+// NLPUsers converts the directory into the user cache format used by the NLP parser.
+// Each user is registered under its email, email local part and dotted display name.
+func (d *UserDirectory) NLPUsers() map[string]*nlp.User {
+	users := d.Users()
+	result := make(map[string]*nlp.User, len(users)*2)
+
+	for i := range users {
+		user := users[i]
+		entry := &nlp.User{
+			Username:    userID(user),
+			DisplayName: user.DisplayName,
+			Email:       user.EmailAddress,
+			Active:      user.Active,
+		}
+
+		for _, handle := range userHandles(user) {
+			if _, taken := result[handle]; !taken {
+				result[handle] = entry
+			}
+		}
+	}
+
+	return result
+}
+
+// Invalidate clears all cached lookups
+func (d *UserDirectory) Invalidate() {
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+
+	d.cache.queries = make(map[string][]string)
+	d.cache.lastUpdate = make(map[string]time.Time)
+}
+
+func (d *UserDirectory) lookup(query string, scope jira.AssignableUserQuery) ([]jira.User, error) {
+	if scope.ProjectKey != "" || scope.IssueKey != "" {
+		scope.Query = query
+		return d.FindAssignable(scope)
+	}
+	return d.Search(query, userSearchLimit)
+}
+
+func (d *UserDirectory) cached(key string) ([]jira.User, bool) {
+	d.cache.mu.RLock()
+	defer d.cache.mu.RUnlock()
+
+	ids, exists := d.cache.queries[key]
+	if !exists || time.Since(d.cache.lastUpdate[key]) >= d.cache.ttl {
+		return nil, false
+	}
+
+	users := make([]jira.User, 0, len(ids))
+	for _, id := range ids {
+		user, ok := d.cache.users[id]
+		if !ok {
+			// The user was dropped since, so the lookup is incomplete
+			return nil, false
+		}
+		users = append(users, *user)
+	}
+
+	log.Debug().Str("key", key).Int("users", len(users)).Msg("Returning cached user lookup")
+	return users, true
+}
+
+func (d *UserDirectory) store(key string, users []jira.User) {
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+
+	now := time.Now()
+	ids := make([]string, 0, len(users))
+	added := false
+	for i := range users {
+		user := users[i]
+		id := userID(user)
+		if id == "" {
+			continue
+		}
+		if _, exists := d.cache.users[id]; !exists {
+			added = true
+		}
+		d.cache.users[id] = &user
+		d.cache.seen[id] = now
+		ids = append(ids, id)
+	}
+
+	if key != "" {
+		d.cache.queries[key] = ids
+		d.cache.lastUpdate[key] = now
+	}
+
+	if d.evict(now) || added {
+		atomic.AddUint64(&d.version, 1)
+	}
+}
+
+// evict drops expired lookups and users, then the least recently seen users
+// beyond maxCachedUsers. It reports whether any user was dropped; d.cache.mu
+// must be held.
+func (d *UserDirectory) evict(now time.Time) bool {
+	for key, updated := range d.cache.lastUpdate {
+		if now.Sub(updated) >= d.cache.ttl {
+			delete(d.cache.queries, key)
+			delete(d.cache.lastUpdate, key)
+		}
+	}
+
+	dropped := false
+	for id, seen := range d.cache.seen {
+		if now.Sub(seen) >= userTTL {
+			delete(d.cache.users, id)
+			delete(d.cache.seen, id)
+			dropped = true
+		}
+	}
+
+	excess := len(d.cache.users) - maxCachedUsers
+	if excess <= 0 {
+		return dropped
+	}
+	ids := make([]string, 0, len(d.cache.users))
+	for id := range d.cache.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return d.cache.seen[ids[i]].Before(d.cache.seen[ids[j]]) })
+	for _, id := range ids[:excess] {
+		delete(d.cache.users, id)
+		delete(d.cache.seen, id)
+	}
+	return true
+}
+
+func resolvedTo(result *UserResolution, user jira.User, matchedOn string) *UserResolution {
+	result.Resolved = true
+	result.AccountID = userID(user)
+	result.User = &user
+	result.Candidates = []UserCandidate{{User: user, Score: 1, MatchedOn: matchedOn}}
+	return result
+}
+
+// rankUsers scores users against a query and returns matches best first
+func rankUsers(query string, users []jira.User) []UserCandidate {
+	seen := make(map[string]bool)
+	var candidates []UserCandidate
+
+	for _, user := range users {
+		id := userID(user)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		score, matchedOn := scoreUser(query, user)
+		if !user.Active {
+			score *= 0.5
+		}
+		if score >= minMatchScore {
+			candidates = append(candidates, UserCandidate{User: user, Score: score, MatchedOn: matchedOn})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
+
+// scoreUser rates how well a query identifies a user, between 0 and 1
+func scoreUser(query string, user jira.User) (float64, string) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	displayName := strings.ToLower(user.DisplayName)
+	email := strings.ToLower(user.EmailAddress)
+
+	switch {
+	case q == strings.ToLower(user.AccountID):
+		return 1.0, "accountId"
+	case email != "" && q == email:
+		return 1.0, "email"
+	case user.Name != "" && q == strings.ToLower(user.Name):
+		return 1.0, "username"
+	case q == displayName:
+		return 0.95, "displayName"
+	case email != "" && q == strings.SplitN(email, "@", 2)[0]:
+		return 0.9, "email"
+	}
+
+	queryTokens := splitHandle(q)
+	nameTokens := strings.Fields(displayName)
+	if len(queryTokens) > 1 && strings.Join(queryTokens, " ") == displayName {
+		return 0.9, "displayName"
+	}
+
+	if len(queryTokens) > 0 && len(nameTokens) > 0 && tokensPrefixMatch(queryTokens, nameTokens) {
+		if len(queryTokens) > 1 {
+			return 0.8, "displayName"
+		}
+		if queryTokens[0] == nameTokens[0] {
+			return 0.75, "firstName"
+		}
+		return 0.6, "displayName"
+	}
+
+	if strings.Contains(displayName, q) || (email != "" && strings.Contains(email, q)) {
+		return 0.5, "partial"
+	}
+
+	return 0, ""
+}
+
+// tokensPrefixMatch reports whether each query token prefixes a name token, in order
+func tokensPrefixMatch(queryTokens, nameTokens []string) bool {
+	n := 0
+	for _, token := range queryTokens {
+		for n < len(nameTokens) && !strings.HasPrefix(nameTokens[n], token) {
+			n++
+		}
+		if n == len(nameTokens) {
+			return false
+		}
+		n++
+	}
+	return true
+}
+
+func splitHandle(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == ' ' || r == '.' || r == '_' || r == '-'
+	})
+}
+
+func userHandles(user jira.User) []string {
+	var handles []string
+	if user.Name != "" {
+		handles = append(handles, strings.ToLower(user.Name))
+	}
+	if user.EmailAddress != "" {
+		email := strings.ToLower(user.EmailAddress)
+		handles = append(handles, email, strings.SplitN(email, "@", 2)[0])
+	}
+	if tokens := splitHandle(user.DisplayName); len(tokens) > 0 {
+		handles = append(handles, strings.Join(tokens, "."))
+	}
+	return handles
+}
+
+func userID(user jira.User) string {
+	if user.AccountID != "" {
+		return user.AccountID
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Key
+}
+
+func limitUsers(users []jira.User, maxResults int) []jira.User {
+	if len(users) > maxResults {
+		return users[:maxResults]
+	}
+	return users
+}
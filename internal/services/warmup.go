@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Warm-up targets, the Jira data a profile loads into the JiraCache
+const (
+	WarmupProjects      = "projects"
+	WarmupBoards        = "boards"
+	WarmupActiveSprints = "activeSprints" // active sprints of the profile's boards and their issues
+	WarmupWorkflows     = "workflows"
+	WarmupFields        = "fields"
+	WarmupLinkTypes     = "linkTypes"
+)
+
+// Warm-up triggers
+const (
+	WarmupOnConnect  = "connect"
+	WarmupOnSchedule = "schedule"
+)
+
+// Warm-up statuses
+const (
+	WarmupIdle                = "idle"
+	WarmupRunning             = "running"
+	WarmupCompleted           = "completed"
+	WarmupCompletedWithErrors = "completed_with_errors"
+)
+
+const warmupTimeout = 5 * time.Minute
+
+// WarmupProfile names the Jira data to load before users ask for it
+type WarmupProfile struct {
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+	Boards  []int    `json:"boards,omitempty"` // boards whose active sprints are loaded; all scrum boards when empty
+}
+
+// DefaultWarmupProfile loads everything the first commands of a session need
+var DefaultWarmupProfile = WarmupProfile{
+	Name:    "default",
+	Targets: []string{WarmupProjects, WarmupBoards, WarmupActiveSprints, WarmupWorkflows, WarmupFields, WarmupLinkTypes},
+}
+
+// Validate checks the profile targets
+func (p WarmupProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("warm-up profile name is required")
+	}
+	if len(p.Targets) == 0 {
+		return fmt.Errorf("warm-up profile %s has no targets", p.Name)
+	}
+	for _, target := range p.Targets {
+		switch target {
+		case WarmupProjects, WarmupBoards, WarmupActiveSprints, WarmupWorkflows, WarmupFields, WarmupLinkTypes:
+		default:
+			return fmt.Errorf("warm-up profile %s has unknown target %q", p.Name, target)
+		}
+	}
+	return nil
+}
+
+// WarmupRun is the progress of a profile being loaded
+type WarmupRun struct {
+	Profile   string     `json:"profile"`
+	Trigger   string     `json:"trigger"`
+	Status    string     `json:"status"`
+	Started   time.Time  `json:"started"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Total     int        `json:"total"`
+	Completed int        `json:"completed"`
+	Failed    int        `json:"failed"`
+	Errors    []string   `json:"errors,omitempty"`
+}
+
+// WarmupProgress is reported by the health endpoint
+type WarmupProgress struct {
+	Status  string      `json:"status"`
+	Percent int         `json:"percent"`
+	NextRun *time.Time  `json:"nextRun,omitempty"`
+	Runs    []WarmupRun `json:"runs,omitempty"` // latest run of each profile
+}
+
+// Warmer loads warm-up profiles into a JiraCache after connecting and on a
+// schedule. Only one warm-up runs at a time.
+type Warmer struct {
+	cache *JiraCache
+
+	mu       sync.Mutex
+	profiles []WarmupProfile
+	runs     map[string]*WarmupRun
+	running  bool
+	nextRun  *time.Time
+	stop     chan struct{}
+}
+
+// NewWarmer creates a warmer for the profiles, or the default profile when
+// none are given
+func NewWarmer(cache *JiraCache, profiles []WarmupProfile) (*Warmer, error) {
+	if len(profiles) == 0 {
+		profiles = []WarmupProfile{DefaultWarmupProfile}
+	}
+	for _, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &Warmer{
+		cache:    cache,
+		profiles: profiles,
+		runs:     make(map[string]*WarmupRun),
+	}, nil
+}
+
+// Start runs every profile in the background. It returns false when a
+// warm-up is already running.
+func (w *Warmer) Start(trigger string) bool {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return false
+	}
+	w.running = true
+	w.runs = make(map[string]*WarmupRun)
+	profiles := w.profiles
+	w.mu.Unlock()
+
+	go func() {
+		defer func() {
+			w.mu.Lock()
+			w.running = false
+			w.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), warmupTimeout)
+		defer cancel()
+		for _, profile := range profiles {
+			w.Run(ctx, profile, trigger)
+		}
+	}()
+	return true
+}
+
+// Schedule starts warm-ups every interval until Stop is called
+func (w *Warmer) Schedule(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	if w.stop != nil {
+		close(w.stop)
+	}
+	stop := make(chan struct{})
+	w.stop = stop
+	next := time.Now().Add(interval)
+	w.nextRun = &next
+	w.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.mu.Lock()
+				next := time.Now().Add(interval)
+				w.nextRun = &next
+				w.mu.Unlock()
+				w.Start(WarmupOnSchedule)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends scheduled warm-ups
+func (w *Warmer) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+	w.nextRun = nil
+}
+
+// Run loads a profile into the cache and returns its run
+func (w *Warmer) Run(ctx context.Context, profile WarmupProfile, trigger string) WarmupRun {
+	run := &WarmupRun{
+		Profile: profile.Name,
+		Trigger: trigger,
+		Status:  WarmupRunning,
+		Started: time.Now(),
+		Total:   len(profile.Targets),
+	}
+	w.mu.Lock()
+	w.runs[profile.Name] = run
+	w.mu.Unlock()
+
+	for _, target := range profile.Targets {
+		err := w.warm(ctx, profile, target)
+
+		w.mu.Lock()
+		if err != nil {
+			run.Failed++
+			run.Errors = append(run.Errors, fmt.Sprintf("%s: %v", target, err))
+		} else {
+			run.Completed++
+		}
+		w.mu.Unlock()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	finished := time.Now()
+	run.Finished = &finished
+	run.Status = WarmupCompleted
+	if run.Failed > 0 {
+		run.Status = WarmupCompletedWithErrors
+	}
+
+	log.Info().
+		Str("profile", run.Profile).
+		Str("trigger", trigger).
+		Int("completed", run.Completed).
+		Int("failed", run.Failed).
+		Dur("duration", finished.Sub(run.Started)).
+		Msg("Cache warm-up finished")
+
+	return *run
+}
+
+// warm loads one target
+func (w *Warmer) warm(ctx context.Context, profile WarmupProfile, target string) error {
+	var err error
+	switch target {
+	case WarmupProjects:
+		_, err = w.cache.Projects(ctx)
+	case WarmupBoards:
+		_, err = w.cache.Boards(ctx)
+	case WarmupActiveSprints:
+		err = w.warmActiveSprints(ctx, profile.Boards)
+	case WarmupWorkflows:
+		_, err = w.cache.Workflows(ctx)
+	case WarmupFields:
+		_, err = w.cache.CustomFields(ctx)
+	case WarmupLinkTypes:
+		_, err = w.cache.LinkTypes(ctx)
+	default:
+		err = fmt.Errorf("unknown warm-up target %q", target)
+	}
+	return err
+}
+
+// warmActiveSprints loads the sprints of the boards and the issues of the
+// active ones
+func (w *Warmer) warmActiveSprints(ctx context.Context, boardIDs []int) error {
+	if len(boardIDs) == 0 {
+		boards, err := w.cache.Boards(ctx)
+		if err != nil {
+			return err
+		}
+		for _, board := range boards.Values {
+			if board.Type == "scrum" {
+				boardIDs = append(boardIDs, board.ID)
+			}
+		}
+	}
+
+	var failures []string
+	for _, boardID := range boardIDs {
+		sprints, err := w.cache.Sprints(ctx, boardID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("board %d: %v", boardID, err))
+			continue
+		}
+		for _, sprint := range sprints.Values {
+			if sprint.State != "active" {
+				continue
+			}
+			if _, err := w.cache.SprintIssues(ctx, sprint.ID); err != nil {
+				failures = append(failures, fmt.Sprintf("sprint %d: %v", sprint.ID, err))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Progress returns the state of the current or latest warm-up
+func (w *Warmer) Progress() WarmupProgress {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	progress := WarmupProgress{Status: WarmupIdle, NextRun: w.nextRun}
+	total, done := 0, 0
+	for _, profile := range w.profiles {
+		total += len(profile.Targets)
+		run, ok := w.runs[profile.Name]
+		if !ok {
+			continue
+		}
+		copied := *run
+		copied.Errors = append([]string(nil), run.Errors...)
+		progress.Runs = append(progress.Runs, copied)
+		done += run.Completed + run.Failed
+
+		if run.Failed > 0 {
+			progress.Status = WarmupCompletedWithErrors
+		} else if progress.Status == WarmupIdle {
+			progress.Status = WarmupCompleted
+		}
+	}
+	if w.running {
+		progress.Status = WarmupRunning
+	}
+	if len(progress.Runs) > 0 && total > 0 {
+		progress.Percent = done * 100 / total
+	}
+	return progress
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ericfisherdev/GoJira/internal/cache"
 	"github.com/ericfisherdev/GoJira/internal/jira"
 	"github.com/rs/zerolog/log"
 )
@@ -23,7 +24,7 @@ type WorkflowService struct {
 // WorkflowCache provides in-memory caching for workflow data
 type WorkflowCache struct {
 	mu              sync.RWMutex
-	workflows       map[string]*jira.Workflow
+	workflows       *cache.ReadThroughCache
 	stateMachines   map[string]*jira.WorkflowStateMachine
 	schemes         map[string]*jira.WorkflowScheme
 	lastUpdate      map[string]time.Time
@@ -32,8 +33,8 @@ type WorkflowCache struct {
 
 // NewWorkflowService creates a new workflow service
 func NewWorkflowService(jiraClient jira.ClientInterface) *WorkflowService {
-	cache := &WorkflowCache{
-		workflows:     make(map[string]*jira.Workflow),
+	workflowCache := &WorkflowCache{
+		workflows:     cache.NewReadThroughCache(10*time.Minute, 10*time.Minute, 500),
 		stateMachines: make(map[string]*jira.WorkflowStateMachine),
 		schemes:       make(map[string]*jira.WorkflowScheme),
 		lastUpdate:    make(map[string]time.Time),
@@ -42,7 +43,7 @@ func NewWorkflowService(jiraClient jira.ClientInterface) *WorkflowService {
 
 	return &WorkflowService{
 		jiraClient:       jiraClient,
-		cache:            cache,
+		cache:            workflowCache,
 		transitionEngine: NewTransitionEngine(jiraClient),
 		validator:        NewWorkflowValidator(),
 	}
@@ -114,32 +115,19 @@ func NewWorkflowValidator() *WorkflowValidator {
 	}
 }
 
-// GetWorkflowWithCache retrieves a workflow with caching
+// GetWorkflowWithCache retrieves a workflow with caching. Concurrent misses
+// share one Jira call, and an expired workflow is served while it refreshes.
+// Workflows are not changed by issue writes, so they carry no tags.
 func (s *WorkflowService) GetWorkflowWithCache(ctx context.Context, workflowName string) (*jira.Workflow, error) {
-	// Check cache first
-	s.cache.mu.RLock()
-	if cached, exists := s.cache.workflows[workflowName]; exists {
-		if time.Since(s.cache.lastUpdate[workflowName]) < s.cache.ttl {
-			s.cache.mu.RUnlock()
-			log.Debug().Str("workflow", workflowName).Msg("Returning cached workflow")
-			return cached, nil
-		}
-	}
-	s.cache.mu.RUnlock()
-
-	// Fetch from Jira
-	workflow, err := s.jiraClient.GetWorkflow(workflowName)
+	value, err := s.cache.workflows.Get(ctx, workflowName, func(ctx context.Context) (interface{}, []string, error) {
+		workflow, err := s.jiraClient.GetWorkflow(workflowName)
+		return workflow, nil, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow: %w", err)
 	}
 
-	// Update cache
-	s.cache.mu.Lock()
-	s.cache.workflows[workflowName] = workflow
-	s.cache.lastUpdate[workflowName] = time.Now()
-	s.cache.mu.Unlock()
-
-	return workflow, nil
+	return value.(*jira.Workflow), nil
 }
 
 // GetStateMachine builds or retrieves cached state machine
@@ -0,0 +1,418 @@
+package textindex
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// BM25 parameters
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// summaryWeight makes a term of the summary count as much as that many
+// occurrences in the description or comments
+const summaryWeight = 3.0
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	// similarTerms is how many of an issue's most distinctive terms are
+	// searched for when looking for similar issues
+	similarTerms = 25
+)
+
+// Document describes an indexed issue
+type Document struct {
+	Key       string     `json:"key"`
+	Summary   string     `json:"summary"`
+	Project   string     `json:"project,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	IssueType string     `json:"issueType,omitempty"`
+	Updated   *time.Time `json:"updated,omitempty"`
+}
+
+// Hit is an issue matching a full-text search
+type Hit struct {
+	Document
+	Score        float64  `json:"score"`
+	MatchedTerms []string `json:"matchedTerms"`
+}
+
+// Match is an issue similar to another issue or text
+type Match struct {
+	Document
+	Score      float64 `json:"score,omitempty"` // BM25 relevance to the issue
+	Similarity float64 `json:"similarity"`      // estimated Jaccard similarity of summary and description
+	Duplicate  bool    `json:"duplicate"`       // similarity reaches DuplicateThreshold
+}
+
+// SearchOptions narrows a full-text search
+type SearchOptions struct {
+	Project string `json:"project,omitempty"`
+	Limit   int    `json:"limit,omitempty"` // defaults to 20, at most 100
+}
+
+// Stats describes the contents of an index
+type Stats struct {
+	Documents     int     `json:"documents"`
+	Terms         int     `json:"terms"`
+	AverageLength float64 `json:"averageLength"`
+}
+
+type entry struct {
+	doc       Document
+	terms     map[string]float64 // weighted term frequencies
+	length    float64
+	signature []uint64
+	bandKeys  []uint64
+}
+
+// Index is an in-memory full-text index of issue summaries, descriptions and
+// comments, ranked with BM25. Every issue also carries a MinHash signature of
+// its summary and description, bucketed with locality-sensitive hashing, to
+// find likely duplicates without comparing against every issue.
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[string]*entry
+	postings    map[string]map[string]float64 // term -> issue key -> weighted frequency
+	buckets     [bands]map[uint64]map[string]struct{}
+	totalLength float64
+}
+
+// GlobalIndex indexes the mirrored issues and the issues read through the API
+var GlobalIndex = NewIndex()
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	ix := &Index{
+		docs:     make(map[string]*entry),
+		postings: make(map[string]map[string]float64),
+	}
+	for i := range ix.buckets {
+		ix.buckets[i] = make(map[uint64]map[string]struct{})
+	}
+	return ix
+}
+
+// Add indexes issues, replacing their previous versions. An issue older than
+// the indexed version is ignored.
+func (ix *Index) Add(issues ...jira.Issue) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Key == "" {
+			continue
+		}
+		e := newEntry(issue)
+		if old, ok := ix.docs[e.doc.Key]; ok {
+			if old.doc.Updated != nil && e.doc.Updated != nil && e.doc.Updated.Before(*old.doc.Updated) {
+				continue
+			}
+			ix.remove(e.doc.Key)
+		}
+		ix.insert(e)
+	}
+}
+
+// Remove drops issues from the index
+func (ix *Index) Remove(keys ...string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, key := range keys {
+		ix.remove(strings.ToUpper(key))
+	}
+}
+
+// Contains reports whether an issue is indexed
+func (ix *Index) Contains(key string) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	_, ok := ix.docs[strings.ToUpper(key)]
+	return ok
+}
+
+// Stats describes the indexed issues
+func (ix *Index) Stats() Stats {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	stats := Stats{Documents: len(ix.docs), Terms: len(ix.postings)}
+	if len(ix.docs) > 0 {
+		stats.AverageLength = ix.totalLength / float64(len(ix.docs))
+	}
+	return stats
+}
+
+// Search ranks the issues matching any term of query with BM25
+func (ix *Index) Search(query string, opts SearchOptions) []Hit {
+	terms := make(map[string]float64)
+	for _, term := range Tokenize(query) {
+		terms[term] = 1
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	scores := ix.score(terms)
+	hits := make([]Hit, 0, len(scores))
+	for key, score := range scores {
+		e := ix.docs[key]
+		if opts.Project != "" && !strings.EqualFold(e.doc.Project, opts.Project) {
+			continue
+		}
+		hit := Hit{Document: e.doc, Score: score, MatchedTerms: []string{}}
+		for term := range terms {
+			if _, ok := e.terms[term]; ok {
+				hit.MatchedTerms = append(hit.MatchedTerms, term)
+			}
+		}
+		sort.Strings(hit.MatchedTerms)
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Key < hits[j].Key
+	})
+	return hits[:min(len(hits), limit(opts.Limit))]
+}
+
+// Similar ranks the issues most like an indexed issue, searching for its most
+// distinctive terms, and flags the likely duplicates. It returns false when
+// the issue is not indexed.
+func (ix *Index) Similar(key string, n int) ([]Match, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	e, ok := ix.docs[strings.ToUpper(key)]
+	if !ok {
+		return nil, false
+	}
+
+	scores := ix.score(ix.distinctiveTerms(e))
+	delete(scores, e.doc.Key)
+
+	matches := make([]Match, 0, len(scores))
+	for other, score := range scores {
+		sim := similarity(e.signature, ix.docs[other].signature)
+		matches = append(matches, Match{
+			Document:   ix.docs[other].doc,
+			Score:      score,
+			Similarity: sim,
+			Duplicate:  sim >= DuplicateThreshold,
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Key < matches[j].Key
+	})
+	return matches[:min(len(matches), limit(n))], true
+}
+
+// Duplicates returns the indexed issues whose summary and description are
+// likely duplicates of text, most similar first
+func (ix *Index) Duplicates(text string, n int) []Match {
+	sig := signature(Tokenize(text))
+	if sig == nil {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	candidates := make(map[string]struct{})
+	for band, key := range bandKeys(sig) {
+		for candidate := range ix.buckets[band][key] {
+			candidates[candidate] = struct{}{}
+		}
+	}
+
+	var matches []Match
+	for key := range candidates {
+		e := ix.docs[key]
+		if sim := similarity(sig, e.signature); sim >= DuplicateThreshold {
+			matches = append(matches, Match{Document: e.doc, Similarity: sim, Duplicate: true})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].Key < matches[j].Key
+	})
+	return matches[:min(len(matches), limit(n))]
+}
+
+// score computes the BM25 score of every issue containing a query term; ix.mu
+// must be held
+func (ix *Index) score(query map[string]float64) map[string]float64 {
+	scores := make(map[string]float64)
+	if len(ix.docs) == 0 {
+		return scores
+	}
+
+	n := float64(len(ix.docs))
+	avgLength := ix.totalLength / n
+	for term, weight := range query {
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for key, tf := range postings {
+			norm := 1 - b + b*ix.docs[key].length/avgLength
+			scores[key] += weight * idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+	return scores
+}
+
+// distinctiveTerms returns the terms of an issue with the highest tf-idf;
+// ix.mu must be held
+func (ix *Index) distinctiveTerms(e *entry) map[string]float64 {
+	type weighted struct {
+		term   string
+		weight float64
+	}
+	n := float64(len(ix.docs))
+	ranked := make([]weighted, 0, len(e.terms))
+	for term, tf := range e.terms {
+		df := float64(len(ix.postings[term]))
+		ranked = append(ranked, weighted{term, tf * math.Log(1+(n-df+0.5)/(df+0.5))})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].weight != ranked[j].weight {
+			return ranked[i].weight > ranked[j].weight
+		}
+		return ranked[i].term < ranked[j].term
+	})
+
+	terms := make(map[string]float64)
+	for _, w := range ranked[:min(len(ranked), similarTerms)] {
+		terms[w.term] = 1
+	}
+	return terms
+}
+
+// insert adds an entry; ix.mu must be held
+func (ix *Index) insert(e *entry) {
+	key := e.doc.Key
+	ix.docs[key] = e
+	ix.totalLength += e.length
+	for term, tf := range e.terms {
+		postings := ix.postings[term]
+		if postings == nil {
+			postings = make(map[string]float64)
+			ix.postings[term] = postings
+		}
+		postings[key] = tf
+	}
+	for band, bucket := range e.bandKeys {
+		keys := ix.buckets[band][bucket]
+		if keys == nil {
+			keys = make(map[string]struct{})
+			ix.buckets[band][bucket] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// remove drops an entry; ix.mu must be held
+func (ix *Index) remove(key string) {
+	e, ok := ix.docs[key]
+	if !ok {
+		return
+	}
+	delete(ix.docs, key)
+	ix.totalLength -= e.length
+	for term := range e.terms {
+		delete(ix.postings[term], key)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	for band, bucket := range e.bandKeys {
+		delete(ix.buckets[band][bucket], key)
+		if len(ix.buckets[band][bucket]) == 0 {
+			delete(ix.buckets[band], bucket)
+		}
+	}
+}
+
+// newEntry extracts the indexed text of an issue
+func newEntry(issue *jira.Issue) *entry {
+	e := &entry{
+		doc: Document{
+			Key:       strings.ToUpper(issue.Key),
+			Summary:   issue.Fields.Summary,
+			Project:   issueProject(issue),
+			IssueType: issue.Fields.IssueType.Name,
+		},
+		terms: make(map[string]float64),
+	}
+	if issue.Fields.Status != nil {
+		e.doc.Status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Updated != nil {
+		updated := issue.Fields.Updated.Time
+		e.doc.Updated = &updated
+	}
+
+	add := func(terms []string, weight float64) {
+		for _, term := range terms {
+			e.terms[term] += weight
+			e.length += weight
+		}
+	}
+
+	summary := Tokenize(issue.Fields.Summary)
+	description := Tokenize(jira.PlainText(issue.Fields.Description))
+	add(summary, summaryWeight)
+	add(description, 1)
+	if issue.Fields.Comment != nil {
+		for _, comment := range issue.Fields.Comment.Comments {
+			add(Tokenize(jira.PlainText(comment.Body)), 1)
+		}
+	}
+
+	// Comments drift from the problem reported, so duplicates are judged on
+	// the summary and description alone
+	e.signature = signature(append(summary, description...))
+	e.bandKeys = bandKeys(e.signature)
+	return e
+}
+
+// issueProject returns the project key of an issue
+func issueProject(issue *jira.Issue) string {
+	if issue.Fields.Project.Key != "" {
+		return strings.ToUpper(issue.Fields.Project.Key)
+	}
+	if i := strings.LastIndex(issue.Key, "-"); i > 0 {
+		return strings.ToUpper(issue.Key[:i])
+	}
+	return ""
+}
+
+func limit(n int) int {
+	if n <= 0 {
+		return defaultLimit
+	}
+	if n > maxLimit {
+		return maxLimit
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+package textindex
+
+import (
+	"hash/fnv"
+)
+
+const (
+	// signatureSize is the number of MinHash values kept per issue
+	signatureSize = 126
+	// bands and rows split a signature for locality-sensitive hashing: issues
+	// sharing every value of one band are compared. With 42 bands of 3 rows,
+	// pairs with a similarity of 0.5 become candidates 99.6% of the time.
+	bands = 42
+	rows  = signatureSize / bands
+)
+
+// DuplicateThreshold is the estimated similarity from which an issue is
+// reported as a likely duplicate
+const DuplicateThreshold = 0.5
+
+// hashSeeds derive the independent hash functions of a signature
+var hashSeeds = func() [signatureSize]uint64 {
+	var seeds [signatureSize]uint64
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix(state)
+	}
+	return seeds
+}()
+
+// shingles returns the set of terms and adjacent term pairs of a text, so
+// both shared vocabulary and shared phrasing count towards similarity
+func shingles(terms []string) map[string]struct{} {
+	set := make(map[string]struct{}, 2*len(terms))
+	for i, term := range terms {
+		set[term] = struct{}{}
+		if i > 0 {
+			set[terms[i-1]+" "+term] = struct{}{}
+		}
+	}
+	return set
+}
+
+// signature computes the MinHash signature of a text; it is nil for a text
+// without terms
+func signature(terms []string) []uint64 {
+	set := shingles(terms)
+	if len(set) == 0 {
+		return nil
+	}
+
+	sig := make([]uint64, signatureSize)
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for shingle := range set {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		base := h.Sum64()
+		for i, seed := range hashSeeds {
+			if v := mix(base ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// similarity estimates the Jaccard similarity of the shingles of two texts
+func similarity(a, b []uint64) float64 {
+	if len(a) != signatureSize || len(b) != signatureSize {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / signatureSize
+}
+
+// bandKeys returns the bucket of each band of a signature
+func bandKeys(sig []uint64) []uint64 {
+	if len(sig) != signatureSize {
+		return nil
+	}
+	keys := make([]uint64, bands)
+	for band := range keys {
+		key := uint64(band)
+		for _, v := range sig[band*rows : (band+1)*rows] {
+			key = mix(key ^ v)
+		}
+		keys[band] = key
+	}
+	return keys
+}
+
+// mix is the splitmix64 finalizer, spreading the bits of a value
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
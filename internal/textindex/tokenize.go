@@ -0,0 +1,53 @@
+package textindex
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are left out of the index; they match nearly every issue
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "can": true, "for": true, "from": true,
+	"has": true, "have": true, "if": true, "in": true, "into": true, "is": true,
+	"it": true, "its": true, "not": true, "of": true, "on": true, "or": true,
+	"so": true, "that": true, "the": true, "then": true, "there": true,
+	"this": true, "to": true, "was": true, "we": true, "when": true,
+	"where": true, "which": true, "will": true, "with": true,
+}
+
+// Tokenize splits text into the terms the index stores: lower-cased words and
+// numbers without stop words, with plural endings removed
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// stem removes plural endings, so "crash" also finds "crashes"
+func stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"sync"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// Handler receives the events it subscribed to. Handlers run synchronously
+// on the delivery request and should return quickly.
+type Handler func(event *Event)
+
+// maxRecentIDs bounds the delivery IDs remembered to drop Jira's retries
+const maxRecentIDs = 1024
+
+type subscription struct {
+	handler    Handler
+	categories map[string]bool // nil for every category
+}
+
+// Stats counts the events handled by a Bus
+type Stats struct {
+	Received   int64            `json:"received"`
+	Duplicates int64            `json:"duplicates"`
+	Delivered  int64            `json:"delivered"` // handler calls
+	Failed     int64            `json:"failed"`    // handler panics
+	ByType     map[string]int64 `json:"byType"`
+}
+
+// Bus dispatches webhook events to the subscribers of their category
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[int]subscription
+	nextID        int
+	recentIDs     map[string]bool
+	recentOrder   []string
+	stats         Stats
+}
+
+// GlobalBus is the bus the /webhooks/jira endpoint dispatches to
+var GlobalBus = NewBus()
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{
+		subscriptions: make(map[int]subscription),
+		recentIDs:     make(map[string]bool),
+		stats:         Stats{ByType: make(map[string]int64)},
+	}
+}
+
+// Subscribe registers a handler for events of the given categories, or of
+// every category when none is given, and returns a function that removes it
+func (b *Bus) Subscribe(handler Handler, categories ...string) (unsubscribe func()) {
+	sub := subscription{handler: handler}
+	if len(categories) > 0 {
+		sub.categories = make(map[string]bool, len(categories))
+		for _, category := range categories {
+			sub.categories[category] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscriptions[id] = sub
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscriptions, id)
+	}
+}
+
+// Dispatch delivers an event to its subscribers and returns how many
+// received it. A redelivery of an event ID already seen is dropped.
+func (b *Bus) Dispatch(event *Event) int {
+	category := event.Category()
+
+	b.mu.Lock()
+	b.stats.Received++
+	if event.ID != "" {
+		if b.recentIDs[event.ID] {
+			b.stats.Duplicates++
+			b.mu.Unlock()
+			return 0
+		}
+		b.remember(event.ID)
+	}
+	b.stats.ByType[event.Type]++
+
+	handlers := make([]Handler, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		if sub.categories == nil || sub.categories[category] {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.Unlock()
+
+	delivered := 0
+	for _, handler := range handlers {
+		if b.call(handler, event) {
+			delivered++
+		}
+	}
+
+	log.Debug().
+		Str("event", event.Type).
+		Str("category", category).
+		Str("issue", event.IssueKey()).
+		Int("delivered", delivered).
+		Msg("Webhook event dispatched")
+	return delivered
+}
+
+// call runs a handler, isolating the other subscribers from its panics
+func (b *Bus) call(handler Handler, event *Event) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Interface("panic", r).Str("event", event.Type).Msg("Webhook subscriber panicked")
+			ok = false
+		}
+		b.mu.Lock()
+		if ok {
+			b.stats.Delivered++
+		} else {
+			b.stats.Failed++
+		}
+		b.mu.Unlock()
+	}()
+	handler(event)
+	return true
+}
+
+// remember records a delivery ID, forgetting the oldest beyond maxRecentIDs
+func (b *Bus) remember(id string) {
+	b.recentIDs[id] = true
+	b.recentOrder = append(b.recentOrder, id)
+	if len(b.recentOrder) > maxRecentIDs {
+		delete(b.recentIDs, b.recentOrder[0])
+		b.recentOrder = b.recentOrder[1:]
+	}
+}
+
+// GetStats returns the bus statistics
+func (b *Bus) GetStats() Stats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := b.stats
+	stats.ByType = make(map[string]int64, len(b.stats.ByType))
+	for eventType, count := range b.stats.ByType {
+		stats.ByType[eventType] = count
+	}
+	return stats
+}
+
+// CacheInvalidator returns a handler that evicts the cached data changed by
+// each event, the same way GoJira's own writes do
+func CacheInvalidator(bus *cache.InvalidationBus) Handler {
+	return func(event *Event) {
+		if m, ok := event.Mutation(); ok {
+			bus.PublishMutation(m)
+		}
+	}
+}
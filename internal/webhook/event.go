@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+)
+
+// Event categories subscribers can filter on
+const (
+	CategoryIssue     = "issue"
+	CategoryComment   = "comment"
+	CategoryWorklog   = "worklog"
+	CategorySprint    = "sprint"
+	CategoryBoard     = "board"
+	CategoryIssueLink = "issuelink"
+	CategoryProject   = "project" // projects, versions and components
+	CategoryOther     = "other"
+)
+
+// Event is a webhook delivery from Jira. Only the entity matching the event
+// type is set, except for comment events which carry their issue too.
+type Event struct {
+	ID             string        `json:"id,omitempty"` // X-Atlassian-Webhook-Identifier, when Jira sends it
+	Type           string        `json:"webhookEvent"`
+	IssueEventType string        `json:"issue_event_type_name,omitempty"`
+	Timestamp      int64         `json:"timestamp"`
+	User           *jira.User    `json:"user,omitempty"`
+	Issue          *jira.Issue   `json:"issue,omitempty"`
+	Changelog      *Changelog    `json:"changelog,omitempty"`
+	Comment        *jira.Comment `json:"comment,omitempty"`
+	Worklog        *Worklog      `json:"worklog,omitempty"`
+	Sprint         *jira.Sprint  `json:"sprint,omitempty"`
+	Board          *jira.Board   `json:"board,omitempty"`
+}
+
+// Changelog lists the fields changed by an issue update
+type Changelog struct {
+	ID    string          `json:"id"`
+	Items []ChangelogItem `json:"items"`
+}
+
+// ChangelogItem is a single field change
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// Worklog is a worklog delivered by a webhook, which names its issue by ID only
+type Worklog struct {
+	jira.Worklog
+	IssueID string `json:"issueId"`
+}
+
+// Parse decodes a webhook payload
+func Parse(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("webhook payload has no webhookEvent")
+	}
+	return &event, nil
+}
+
+// Category returns the category of the event type
+func (e *Event) Category() string {
+	switch {
+	case strings.HasPrefix(e.Type, "jira:issue_"):
+		return CategoryIssue
+	case strings.HasPrefix(e.Type, "comment_"):
+		return CategoryComment
+	case strings.HasPrefix(e.Type, "worklog_"):
+		return CategoryWorklog
+	case strings.HasPrefix(e.Type, "sprint_"):
+		return CategorySprint
+	case strings.HasPrefix(e.Type, "board_"):
+		return CategoryBoard
+	case strings.HasPrefix(e.Type, "issuelink_"):
+		return CategoryIssueLink
+	case strings.HasPrefix(e.Type, "project_"), strings.HasPrefix(e.Type, "jira:version_"),
+		strings.HasPrefix(e.Type, "component_"):
+		return CategoryProject
+	}
+	return CategoryOther
+}
+
+// IssueKey returns the key of the issue the event is about, if known
+func (e *Event) IssueKey() string {
+	if e.Issue == nil {
+		return ""
+	}
+	return e.Issue.Key
+}
+
+// ProjectKey returns the key of the project the event is about, if known
+func (e *Event) ProjectKey() string {
+	if e.Issue != nil {
+		if key := e.Issue.Fields.Project.Key; key != "" {
+			return strings.ToUpper(key)
+		}
+		return jira.ProjectKeyFromIssueKey(e.Issue.Key)
+	}
+	if e.Board != nil {
+		return e.Board.Location.ProjectKey
+	}
+	return ""
+}
+
+// Mutation converts the event into the Jira data it changed, in the form
+// used for GoJira's own writes. Events naming no entity GoJira can tag, such
+// as worklogs identified by issue ID, yield an empty mutation, which may have
+// changed anything. ok is false for events that change no cached data.
+func (e *Event) Mutation() (m jira.Mutation, ok bool) {
+	m.Operation = e.Type
+
+	switch e.Category() {
+	case CategoryIssue, CategoryComment, CategoryWorklog:
+		if key := e.IssueKey(); key != "" {
+			m.Issues = []string{strings.ToUpper(key)}
+			if project := e.ProjectKey(); project != "" {
+				m.Projects = []string{project}
+			}
+			m.Sprints = e.changedSprints()
+		}
+	case CategorySprint:
+		if e.Sprint != nil {
+			m.Sprints = []int{e.Sprint.ID}
+			if e.Sprint.OriginBoardID != 0 {
+				m.Boards = []int{e.Sprint.OriginBoardID}
+			}
+		}
+	case CategoryBoard:
+		if e.Board != nil {
+			m.Boards = []int{e.Board.ID}
+		}
+	case CategoryIssueLink, CategoryProject:
+		// Referenced by ID only; treated as a change to anything
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// changedSprints returns the sprints an issue was moved between
+func (e *Event) changedSprints() []int {
+	if e.Changelog == nil {
+		return nil
+	}
+	var sprints []int
+	for _, item := range e.Changelog.Items {
+		if !strings.EqualFold(item.Field, "Sprint") {
+			continue
+		}
+		for _, value := range strings.Split(item.From+","+item.To, ",") {
+			if id, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				sprints = append(sprints, id)
+			}
+		}
+	}
+	return sprints
+}
+
+// Describe returns a one-line description of the event, such as
+// "PROJ-1 status changed from To Do to Done by Alice"
+func (e *Event) Describe() string {
+	subject := e.IssueKey()
+	switch {
+	case e.Sprint != nil:
+		subject = fmt.Sprintf("Sprint %q", e.Sprint.Name)
+	case e.Board != nil:
+		subject = fmt.Sprintf("Board %q", e.Board.Name)
+	case subject == "":
+		subject = "Jira"
+	}
+
+	var action string
+	switch e.Type {
+	case jira.WebhookIssueCreated:
+		action = "was created"
+	case jira.WebhookIssueDeleted:
+		action = "was deleted"
+	case jira.WebhookCommentCreated:
+		action = "was commented on"
+	case jira.WebhookWorklogCreated, jira.WebhookWorklogUpdated:
+		action = "had work logged"
+	case jira.WebhookSprintStarted:
+		action = "was started"
+	case jira.WebhookSprintClosed:
+		action = "was closed"
+	default:
+		action = "was updated"
+		if e.Changelog != nil && len(e.Changelog.Items) > 0 {
+			changes := make([]string, 0, len(e.Changelog.Items))
+			for _, item := range e.Changelog.Items {
+				changes = append(changes, fmt.Sprintf("%s changed from %s to %s",
+					item.Field, orNone(item.FromString), orNone(item.ToString)))
+			}
+			action = strings.Join(changes, ", ")
+		}
+	}
+
+	description := subject + " " + action
+	if e.User != nil && e.User.DisplayName != "" {
+		description += " by " + e.User.DisplayName
+	}
+	return description
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Verification errors
+var (
+	ErrNotConfigured      = errors.New("webhook verification is not configured")
+	ErrMissingCredentials = errors.New("webhook request carries no secret, signature or token")
+	ErrInvalidSecret      = errors.New("invalid webhook secret")
+	ErrInvalidSignature   = errors.New("invalid webhook signature")
+	ErrInvalidToken       = errors.New("invalid webhook token")
+	ErrTokenExpired       = errors.New("webhook token has expired")
+)
+
+// Headers carrying webhook credentials
+const (
+	SignatureHeader  = "X-Hub-Signature"         // HMAC-SHA256 of the body, sent by Jira Cloud
+	SecretHeader     = "X-GoJira-Webhook-Secret" // shared secret, for proxies that add a header
+	IdentifierHeader = "X-Atlassian-Webhook-Identifier"
+)
+
+// jwtLeeway tolerates clock skew between Jira and GoJira
+const jwtLeeway = 30 * time.Second
+
+// Verifier authenticates webhook deliveries. Jira Server and Data Center
+// cannot sign webhooks, so the shared secret is usually passed in the
+// registered URL as ?secret=; Jira Cloud signs the body with it instead.
+// Connect apps receive an HS256 JWT signed with their shared secret.
+type Verifier struct {
+	Secret    string // shared secret, compared or used as HMAC key
+	JWTSecret string // Connect app shared secret
+	JWTIssuer string // expected iss claim (client key), if set
+}
+
+// Configured reports whether any credential is set
+func (v *Verifier) Configured() bool {
+	return v != nil && (v.Secret != "" || v.JWTSecret != "")
+}
+
+// Verify authenticates a delivery against its raw body. A request is
+// accepted on the first credential it carries that the verifier knows.
+func (v *Verifier) Verify(r *http.Request, body []byte) error {
+	if !v.Configured() {
+		return ErrNotConfigured
+	}
+
+	if token := bearerJWT(r); token != "" && v.JWTSecret != "" {
+		return v.verifyJWT(r, token)
+	}
+
+	if v.Secret == "" {
+		return ErrMissingCredentials
+	}
+
+	if signature := r.Header.Get(SignatureHeader); signature != "" {
+		return v.verifySignature(signature, body)
+	}
+
+	secret := r.Header.Get(SecretHeader)
+	if secret == "" {
+		secret = r.URL.Query().Get("secret")
+	}
+	if secret == "" {
+		return ErrMissingCredentials
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(v.Secret)) != 1 {
+		return ErrInvalidSecret
+	}
+	return nil
+}
+
+// verifySignature checks a "sha256=<hex>" HMAC of the body
+func (v *Verifier) verifySignature(signature string, body []byte) error {
+	algorithm, digest, found := strings.Cut(signature, "=")
+	if !found || !strings.EqualFold(algorithm, "sha256") {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(v.Secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// jwtClaims are the claims checked on Connect tokens
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	ExpiresAt int64  `json:"exp"`
+	QSH       string `json:"qsh"`
+}
+
+// verifyJWT checks an HS256 token: signature, expiry, issuer and the query
+// string hash of the request, which binds the token to the delivery it came with
+func (v *Verifier) verifyJWT(r *http.Request, token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidToken
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Algorithm != "HS256" {
+		return ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalidToken
+	}
+	mac := hmac.New(sha256.New, []byte(v.JWTSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return ErrInvalidToken
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return ErrInvalidToken
+	}
+	if claims.ExpiresAt == 0 || time.Now().Add(-jwtLeeway).Unix() > claims.ExpiresAt {
+		return ErrTokenExpired
+	}
+	if v.JWTIssuer != "" && claims.Issuer != v.JWTIssuer {
+		return ErrInvalidToken
+	}
+	qsh := QueryStringHash(r.Method, r.URL)
+	if subtle.ConstantTimeCompare([]byte(claims.QSH), []byte(qsh)) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// QueryStringHash computes the Connect qsh claim of a request: the SHA-256 of
+// the method, path and sorted query, without the jwt parameter
+func QueryStringHash(method string, u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	query := u.Query()
+	query.Del("jwt")
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for i, value := range values {
+			values[i] = percentEncode(value)
+		}
+		params = append(params, percentEncode(key)+"="+strings.Join(values, ","))
+	}
+
+	canonical := strings.ToUpper(method) + "&" + path + "&" + strings.Join(params, "&")
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// percentEncode encodes as RFC 3986 requires, spaces included
+func percentEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// bearerJWT returns the Connect token of a request, sent as
+// "Authorization: JWT <token>" or in the jwt query parameter
+func bearerJWT(r *http.Request) string {
+	if scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " "); found && strings.EqualFold(scheme, "JWT") {
+		return strings.TrimSpace(token)
+	}
+	return r.URL.Query().Get("jwt")
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,219 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/cache/resptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiLevelCacheInspection(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	strategy.WriteThrough = true
+	mc := cache.NewMultiLevelCache(strategy)
+	defer mc.Stop()
+	mc.SetL2Cache(newTestRedisCache(t, server, cache.RedisOptions{}))
+
+	require.NoError(t, mc.SetWithTags("issue:PROJ-1", "first", time.Minute, cache.IssueTag("PROJ-1")))
+	require.NoError(t, mc.SetWithTags("issue:PROJ-2", "second", time.Minute, cache.IssueTag("PROJ-2")))
+	require.NoError(t, mc.Set("search:abc", "result", time.Minute))
+	for i := 0; i < 3; i++ {
+		_, found := mc.Get("issue:PROJ-1")
+		require.True(t, found)
+	}
+
+	entries := mc.Entries("issue:", "")
+	require.Len(t, entries, 4, "both issues in L1 and L2")
+	assert.Equal(t, "issue:PROJ-1", entries[0].Key)
+	assert.Equal(t, cache.LayerL1, entries[0].Layer)
+	assert.Equal(t, int64(3), entries[0].Hits)
+	assert.Positive(t, entries[0].Size)
+	assert.InDelta(t, strategy.L1TTL.Seconds(), entries[0].TTLLeft, 2)
+	assert.Equal(t, []string{"issue:PROJ-1"}, entries[0].Tags)
+	assert.Equal(t, cache.LayerL2, entries[1].Layer)
+	assert.InDelta(t, strategy.L2TTL.Seconds(), entries[1].TTLLeft, 2)
+
+	tagged := mc.Entries("", cache.IssueTag("PROJ-2"))
+	require.Len(t, tagged, 2)
+	assert.Equal(t, "issue:PROJ-2", tagged[0].Key)
+
+	// Flushing L1 leaves the shared level alone
+	require.NoError(t, mc.FlushLayer(cache.LayerL1))
+	layers := mc.Entry("search:abc")
+	require.Len(t, layers, 1)
+	assert.Equal(t, cache.LayerL2, layers[0].Layer)
+	assert.ErrorContains(t, mc.FlushLayer(cache.LayerL3), "not enabled")
+	assert.ErrorContains(t, mc.FlushLayer("l9"), "unknown cache layer")
+}
+
+func TestMultiLevelCacheStrategyChange(t *testing.T) {
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	mc := cache.NewMultiLevelCache(strategy)
+	defer mc.Stop()
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, mc.Set(key, key, time.Minute))
+	}
+
+	conservative, err := cache.StrategyByName("Conservative")
+	require.NoError(t, err)
+	conservative.MaxL1Size = 2
+	mc.SetStrategy(conservative)
+
+	assert.Equal(t, "conservative", mc.Strategy().Name)
+	assert.Len(t, mc.Entries("", ""), 2, "L1 shrinks to the new size")
+	_, found := mc.Get("a")
+	assert.False(t, found, "least recently used entry is evicted")
+
+	_, err = cache.StrategyByName("reckless")
+	assert.ErrorContains(t, err, "unknown cache strategy")
+}
+
+// adminResponse is the envelope of the cache admin endpoints
+type adminResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func TestCacheAdminAPI(t *testing.T) {
+	srv := setupTestServer(t)
+
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	mc := cache.NewMultiLevelCache(strategy)
+	t.Cleanup(mc.Stop)
+	cache.GlobalAdmin.SetMultiLevelCache(mc)
+	t.Cleanup(func() { cache.GlobalAdmin.SetMultiLevelCache(nil) })
+
+	rc := cache.NewResponseCache(100, time.Minute)
+	t.Cleanup(rc.Stop)
+	t.Cleanup(cache.GlobalAdmin.RegisterResponseCache(rc))
+
+	require.NoError(t, mc.SetWithTags("admintest:issue:PROJ-1", "issue", time.Minute, cache.IssueTag("PROJ-1")))
+	require.NoError(t, mc.Set("admintest:board:1", "board", time.Minute))
+	require.NoError(t, rc.Set("admintest:summary:PROJ-1", "summary", cache.WithTags(cache.IssueTag("PROJ-1"))))
+	mc.Get("admintest:issue:PROJ-1")
+
+	serve := func(method, target, body string) (int, json.RawMessage) {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, r)
+		var resp adminResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	// Statistics of every layer
+	code, data := serve(http.MethodGet, "/api/v1/admin/cache", "")
+	require.Equal(t, http.StatusOK, code)
+	var stats struct {
+		MultiLevel map[string]interface{} `json:"multiLevel"`
+		Response   []cache.CacheStats     `json:"response"`
+	}
+	require.NoError(t, json.Unmarshal(data, &stats))
+	assert.Contains(t, stats.MultiLevel, "l1")
+	assert.NotEmpty(t, stats.Response)
+
+	// Keys by prefix, tag and layer
+	var listing struct {
+		Entries []cache.EntryInfo `json:"entries"`
+		Total   int               `json:"total"`
+	}
+	code, data = serve(http.MethodGet, "/api/v1/admin/cache/keys?prefix=admintest:", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(data, &listing))
+	assert.Equal(t, 3, listing.Total)
+
+	code, data = serve(http.MethodGet, "/api/v1/admin/cache/keys?prefix=admintest:&tag=issue:PROJ-1&layer=response", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(data, &listing))
+	require.Len(t, listing.Entries, 1)
+	assert.Equal(t, "admintest:summary:PROJ-1", listing.Entries[0].Key)
+
+	code, _ = serve(http.MethodGet, "/api/v1/admin/cache/keys?layer=l9", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Entry metadata
+	var entry struct {
+		Entries []cache.EntryInfo `json:"entries"`
+	}
+	code, data = serve(http.MethodGet, "/api/v1/admin/cache/entry?key=admintest:issue:PROJ-1", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(data, &entry))
+	require.Len(t, entry.Entries, 1)
+	assert.Equal(t, cache.LayerL1, entry.Entries[0].Layer)
+	assert.Equal(t, int64(1), entry.Entries[0].Hits)
+	assert.Positive(t, entry.Entries[0].TTLLeft)
+
+	code, _ = serve(http.MethodGet, "/api/v1/admin/cache/entry?key=admintest:missing", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Eviction by tag reaches every layer carrying it
+	var eviction struct {
+		Evicted int `json:"evicted"`
+	}
+	code, data = serve(http.MethodDelete, "/api/v1/admin/cache/entries?tag=issue:PROJ-1", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(data, &eviction))
+	assert.GreaterOrEqual(t, eviction.Evicted, 2)
+	_, found := rc.Get("admintest:summary:PROJ-1")
+	assert.False(t, found)
+
+	code, data = serve(http.MethodDelete, "/api/v1/admin/cache/entries?key=admintest:board:1", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(data, &eviction))
+	assert.Equal(t, 1, eviction.Evicted)
+	assert.Empty(t, mc.Entries("admintest:", ""))
+
+	code, _ = serve(http.MethodDelete, "/api/v1/admin/cache/entries?key=a&tag=b", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(http.MethodDelete, "/api/v1/admin/cache/entries?pattern=(", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Flushing a layer
+	require.NoError(t, rc.Set("admintest:other", "value"))
+	code, _ = serve(http.MethodPost, "/api/v1/admin/cache/flush/response", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, rc.Entries("admintest:"))
+	code, _ = serve(http.MethodPost, "/api/v1/admin/cache/flush/l3", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(http.MethodPost, "/api/v1/admin/cache/flush/disk", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Changing the strategy at runtime
+	code, data = serve(http.MethodPut, "/api/v1/admin/cache/strategy", `{"name":"aggressive"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, string(data), `"name":"aggressive"`)
+	assert.Equal(t, cache.AggressiveStrategy().L1TTL, mc.Strategy().L1TTL)
+
+	code, _ = serve(http.MethodPut, "/api/v1/admin/cache/strategy", `{"name":"reckless"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, data = serve(http.MethodGet, "/api/v1/admin/cache/strategy", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, string(data), `"name":"aggressive"`)
+}
+
+func TestCacheAdminWithoutMultiLevelCache(t *testing.T) {
+	srv := setupTestServer(t)
+
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/strategy", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush/l1", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,280 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/cache/resptest"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMutationMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/rest/api/2/issue/LOCKED-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{"locked"}})
+	})
+	mux.HandleFunc("/rest/agile/1.0/sprint/5/issue", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+func TestJiraWritesPublishMutations(t *testing.T) {
+	client := newFakeJiraClient(t, newMutationMux())
+
+	var mu sync.Mutex
+	var mutations []jira.Mutation
+	client.OnMutation(func(m jira.Mutation) {
+		mu.Lock()
+		defer mu.Unlock()
+		mutations = append(mutations, m)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.UpdateIssue(ctx, "PROJ-1", &jira.UpdateIssueRequest{Fields: map[string]interface{}{"summary": "x"}}))
+	require.NoError(t, client.TransitionIssue(ctx, "PROJ-1", &jira.TransitionRequest{}))
+	require.NoError(t, client.MoveIssuesToSprint(5, []string{"PROJ-1", "WEB-7"}))
+	require.Error(t, client.UpdateIssue(ctx, "LOCKED-1", &jira.UpdateIssueRequest{}))
+
+	require.Len(t, mutations, 3, "failed writes publish nothing")
+	assert.Equal(t, jira.Mutation{Operation: jira.OpUpdateIssue, Issues: []string{"PROJ-1"}, Projects: []string{"PROJ"}}, mutations[0])
+	assert.Equal(t, jira.OpTransitionIssue, mutations[1].Operation)
+	assert.Equal(t, jira.Mutation{
+		Operation: jira.OpSprintIssues,
+		Issues:    []string{"PROJ-1", "WEB-7"},
+		Projects:  []string{"PROJ", "WEB"},
+		Sprints:   []int{5},
+	}, mutations[2])
+
+	assert.Equal(t, []string{"issue:*", "issue:PROJ-1", "issue:WEB-7", "project:PROJ", "project:WEB", "sprint:5"},
+		cache.MutationTags(mutations[2]))
+	assert.Equal(t, []string{"issue:*", "issue:PROJ-*", "project:PROJ"},
+		cache.MutationTags(jira.Mutation{Operation: jira.OpComponent, Projects: []string{"PROJ"}}),
+		"project-level writes reach every issue of the project")
+	assert.Equal(t, []string{"issue:*", "jira:*"}, cache.MutationTags(jira.Mutation{Operation: jira.OpIssueLink}))
+}
+
+func TestInvalidationBusEvictsTaggedEntries(t *testing.T) {
+	bus := cache.NewInvalidationBus()
+	client := newFakeJiraClient(t, newMutationMux())
+	client.OnMutation(func(m jira.Mutation) { bus.PublishMutation(m) })
+
+	responses := cache.NewResponseCache(100, time.Minute)
+	defer responses.Stop()
+	searches := cache.NewSearchCache(time.Minute, 100)
+	multi := cache.NewMultiLevelCache(cache.CacheStrategy{MaxL3Size: 0})
+	defer multi.Stop()
+
+	bus.Register(responses)
+	bus.Register(searches)
+	unregister := bus.Register(multi)
+	defer unregister()
+
+	proj1 := &jira.Issue{Key: "PROJ-1"}
+	proj2 := &jira.Issue{Key: "PROJ-2"}
+	responses.Set("show PROJ-1", "PROJ-1 is Open", cache.WithTags(cache.DependencyTags(proj1)...))
+	responses.Set("show PROJ-2", "PROJ-2 is Open", cache.WithTags(cache.DependencyTags(proj2)...))
+
+	result := func(keys ...string) *jira.ExtendedSearchResult {
+		r := &jira.ExtendedSearchResult{}
+		for _, key := range keys {
+			r.Issues = append(r.Issues, jira.Issue{Key: key})
+		}
+		return r
+	}
+	searches.Set("project = PROJ AND status = Open", nil, result("PROJ-1"))
+	searches.Set("project = WEB", nil, result("WEB-1"))
+	searches.Set("assignee = currentUser()", nil, result("WEB-2"))
+	searches.Set(`project in (WEB, "OPS") OR priority = High`, nil, result("WEB-3"))
+
+	require.NoError(t, multi.SetWithTags("issue:PROJ-1", "cached", time.Minute, cache.IssueTag("PROJ-1")))
+	require.NoError(t, multi.SetWithTags("issue:PROJ-2", "cached", time.Minute, cache.IssueTag("PROJ-2")))
+	require.NoError(t, multi.Set("untagged", "cached", time.Minute))
+
+	require.NoError(t, client.TransitionIssue(context.Background(), "PROJ-1", &jira.TransitionRequest{}))
+
+	_, found := responses.Get("show PROJ-1")
+	assert.False(t, found, "the response about the changed issue is evicted")
+	_, found = responses.Get("show PROJ-2")
+	assert.True(t, found)
+
+	_, found = searches.Get("project = PROJ AND status = Open", nil)
+	assert.False(t, found, "searches of the changed project are evicted")
+	_, found = searches.Get("project = WEB", nil)
+	assert.True(t, found, "searches restricted to other projects are kept")
+	_, found = searches.Get("assignee = currentUser()", nil)
+	assert.False(t, found, "unscoped searches are evicted by any issue write")
+	_, found = searches.Get(`project in (WEB, "OPS") OR priority = High`, nil)
+	assert.False(t, found, "OR widens a search beyond its projects")
+
+	_, found = multi.Get("issue:PROJ-1")
+	assert.False(t, found)
+	_, found = multi.Get("issue:PROJ-2")
+	assert.True(t, found)
+	_, found = multi.Get("untagged")
+	assert.True(t, found)
+
+	stats := bus.GetStats()
+	assert.Equal(t, int64(1), stats.Published)
+	assert.Equal(t, int64(5), stats.Evicted)
+}
+
+func TestSearchTagsScopeOnlyToProjectKeys(t *testing.T) {
+	tests := []struct {
+		jql      string
+		projects []string
+	}{
+		{jql: "project = PROJ AND status = Open ORDER BY rank", projects: []string{"PROJ"}},
+		{jql: "project in (WEB, OPS_2) AND NOT status = Done", projects: []string{"WEB", "OPS_2"}},
+		{jql: "status = Open AND (priority = High OR project = WEB)"},
+		{jql: `project = "Mobile App"`},
+		{jql: `project = "WEB"`},
+		{jql: "project = Mobile"},
+		{jql: "project = web AND status = Open"},
+		{jql: `project in (WEB, "Mobile App")`},
+		{jql: "project in projectsWhereUserHasRole(Developers)"},
+		{jql: "project = 10000"},
+		{jql: "project != WEB"},
+		{jql: "ORDER BY project"},
+		{jql: "project = (WEB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jql, func(t *testing.T) {
+			tags := cache.SearchTags(tt.jql, nil)
+			if len(tt.projects) == 0 {
+				assert.Contains(t, tags, cache.TagAnyIssue)
+				return
+			}
+			assert.NotContains(t, tags, cache.TagAnyIssue)
+			for _, project := range tt.projects {
+				assert.Contains(t, tags, cache.ProjectTag(project))
+			}
+		})
+	}
+}
+
+func TestTagInvalidationReachesEveryReplica(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	strategy.WriteThrough = true
+
+	// Each replica has its own bus, relayed through the shared server
+	replicas := make([]*cache.MultiLevelCache, 3)
+	buses := make([]*cache.InvalidationBus, 3)
+	for i := range replicas {
+		rc := newTestRedisCache(t, server, cache.RedisOptions{})
+		replicas[i] = cache.NewMultiLevelCache(strategy)
+		replicas[i].SetL2Cache(rc)
+
+		buses[i] = cache.NewInvalidationBus()
+		buses[i].Register(replicas[i])
+		stop, err := rc.RelayInvalidations(buses[i])
+		require.NoError(t, err)
+		t.Cleanup(stop)
+	}
+
+	issue := cachedIssueSummary{Key: "PROJ-1", Labels: []string{"web"}}
+	require.NoError(t, replicas[0].SetWithTags("issue:PROJ-1", issue, time.Minute, cache.IssueTag("PROJ-1"), cache.ProjectTag("PROJ")))
+	require.NoError(t, replicas[0].SetWithTags("issue:WEB-1", "other", time.Minute, cache.IssueTag("WEB-1")))
+	assert.Contains(t, server.Keys(0), "gojira:~tag:issue:PROJ-1")
+
+	// Replica 1 reads the entry into its L1 and learns its tags
+	value, found := replicas[1].Get("issue:PROJ-1")
+	require.True(t, found)
+	assert.Equal(t, issue, value)
+
+	// Replica 2 never saw the entry, yet its write evicts it everywhere
+	assert.Equal(t, 1, buses[2].PublishMutation(jira.Mutation{Operation: jira.OpUpdateIssue, Issues: []string{"PROJ-1"}, Projects: []string{"PROJ"}}))
+	assert.Equal(t, []string{"gojira:issue:WEB-1", "gojira:~tag:issue:WEB-1"}, server.Keys(0))
+
+	for i, replica := range replicas[:2] {
+		assert.Eventually(t, func() bool {
+			_, found := replica.Get("issue:PROJ-1")
+			return !found
+		}, 2*time.Second, 10*time.Millisecond, "replica %d still serves the stale entry", i)
+	}
+	assert.Eventually(t, func() bool {
+		return buses[1].GetStats().Received == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, found = replicas[0].Get("issue:WEB-1")
+	assert.True(t, found)
+	assert.Equal(t, int64(0), buses[2].GetStats().Received, "a bus ignores its own events")
+}
+
+func TestMultiLevelTagIndexForgetsEntriesL1Drops(t *testing.T) {
+	multi := cache.NewMultiLevelCache(cache.CacheStrategy{L1TTL: 20 * time.Millisecond, MaxL3Size: 0})
+	defer multi.Stop()
+
+	require.NoError(t, multi.SetWithTags("issue:PROJ-1", "cached", 0, cache.IssueTag("PROJ-1")))
+	require.NoError(t, multi.SetWithTags("issue:PROJ-2", "cached", 0, cache.IssueTag("PROJ-2")))
+	tags, keys := multi.TagIndexSize()
+	assert.Equal(t, 2, tags)
+	assert.Equal(t, 2, keys)
+
+	// Expired entries leave the index when L1 drops them
+	time.Sleep(30 * time.Millisecond)
+	for _, key := range []string{"issue:PROJ-1", "issue:PROJ-2"} {
+		_, found := multi.Get(key)
+		assert.False(t, found)
+	}
+	assert.Zero(t, multi.InvalidateTags(cache.IssueTag("PROJ-1")))
+	tags, keys = multi.TagIndexSize()
+	assert.Zero(t, tags)
+	assert.Zero(t, keys)
+
+	// So do entries evicted to make room
+	small := cache.NewMultiLevelCache(cache.CacheStrategy{MaxL1Size: 2, MaxL3Size: 0})
+	defer small.Stop()
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3"} {
+		require.NoError(t, small.SetWithTags("issue:"+key, "cached", time.Minute, cache.IssueTag(key)))
+	}
+	tags, keys = small.TagIndexSize()
+	assert.Equal(t, 2, tags)
+	assert.Equal(t, 2, keys)
+	assert.Zero(t, small.InvalidateTags(cache.IssueTag("PROJ-1")))
+}
+
+func TestDiskCacheTagsSurviveRestart(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	strategy := cache.CacheStrategy{MaxL3Size: 10 * 1024 * 1024, WriteThrough: true, PromoteToL1: true}
+
+	previous := cache.NewMultiLevelCache(strategy)
+	require.NoError(t, previous.SetWithTags("issue:PROJ-1", "cached", time.Minute, cache.IssueTag("PROJ-1")))
+	require.NoError(t, previous.SetWithTags("issue:PROJ-2", "cached", time.Minute, cache.IssueTag("PROJ-2")))
+	previous.Stop()
+
+	// Entries written before the restart are invalidated without being read first
+	multi := cache.NewMultiLevelCache(strategy)
+	defer multi.Stop()
+	assert.Equal(t, 1, multi.InvalidateTags(cache.IssueTag("PROJ-1")))
+	_, found := multi.Get("issue:PROJ-1")
+	assert.False(t, found)
+
+	// A hit in L3 re-indexes the tags stored with the entry
+	value, found := multi.Get("issue:PROJ-2")
+	require.True(t, found)
+	assert.Equal(t, "cached", value)
+	tags, keys := multi.TagIndexSize()
+	assert.Equal(t, 1, tags)
+	assert.Equal(t, 1, keys)
+	assert.Equal(t, 1, multi.InvalidateTags(cache.IssueTag("PROJ-2")))
+	_, found = multi.Get("issue:PROJ-2")
+	assert.False(t, found)
+}
@@ -0,0 +1,194 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneIssueRemapsFieldsAcrossProjects(t *testing.T) {
+	var created map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/SRC-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":  "1001",
+			"key": "SRC-1",
+			"fields": map[string]interface{}{
+				"summary":     "Login fails",
+				"project":     map[string]interface{}{"key": "SRC"},
+				"issuetype":   map[string]interface{}{"id": "1", "name": "Bug"},
+				"priority":    map[string]interface{}{"id": "3", "name": "Low"},
+				"components":  []map[string]interface{}{{"id": "5", "name": "Backend"}},
+				"environment": "prod",
+				"status":      map[string]interface{}{"name": "Open"},
+			},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta/DST/issuetypes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"values": []map[string]interface{}{{"id": "20001", "name": "Bug"}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta/DST/issuetypes/20001", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"isLast": true,
+			"values": []map[string]interface{}{
+				{"fieldId": "summary", "name": "Summary", "required": true, "schema": map[string]interface{}{"type": "string"}},
+				{"fieldId": "priority", "name": "Priority", "required": false, "schema": map[string]interface{}{"type": "priority"},
+					"allowedValues": []map[string]interface{}{{"id": "3", "name": "Low"}}},
+				{"fieldId": "components", "name": "Components", "required": false, "schema": map[string]interface{}{"type": "array", "items": "component"},
+					"allowedValues": []map[string]interface{}{{"id": "77", "name": "Backend"}}},
+			},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		var body struct {
+			Fields map[string]interface{} `json:"fields"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		created = body.Fields
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"id": "2001", "key": "DST-7"})
+	})
+
+	client := newFakeJiraClient(t, mux)
+
+	result, err := client.CloneIssue(context.Background(), "SRC-1", jira.CloneOptions{
+		TargetProject: "DST",
+		Fields:        []string{"summary", "priority", "components", "environment"},
+		SummaryPrefix: "CLONE - ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "DST-7", result.Key)
+	assert.Equal(t, "SRC-1", result.SourceKey)
+
+	require.Len(t, result.DroppedFields, 1)
+	assert.Equal(t, "environment", result.DroppedFields[0].Field)
+
+	require.NotNil(t, created)
+	assert.Equal(t, "CLONE - Login fails", created["summary"])
+	assert.Equal(t, map[string]interface{}{"key": "DST"}, created["project"])
+	assert.Equal(t, map[string]interface{}{"id": "20001"}, created["issuetype"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "77"}}, created["components"])
+	assert.NotContains(t, created, "environment")
+}
+
+func TestMoveIssueRejectsSameProject(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/SRC-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":  "1001",
+			"key": "SRC-1",
+			"fields": map[string]interface{}{
+				"summary":   "Login fails",
+				"project":   map[string]interface{}{"key": "SRC"},
+				"issuetype": map[string]interface{}{"id": "1", "name": "Bug"},
+			},
+		})
+	})
+
+	client := newFakeJiraClient(t, mux)
+
+	_, err := client.MoveIssue(context.Background(), "SRC-1", jira.MoveOptions{TargetProject: "src"})
+	assert.Error(t, err)
+
+	_, err = client.MoveIssue(context.Background(), "SRC-1", jira.MoveOptions{})
+	assert.Error(t, err)
+}
+
+func TestMoveIssueKeepsSourceUnlessEverythingCopied(t *testing.T) {
+	var (
+		sourceFields map[string]interface{}
+		deleted      bool
+		comments     int
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/SRC-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": "1001", "key": "SRC-1", "fields": sourceFields})
+	})
+	mux.HandleFunc("/rest/api/2/issue/SRC-1/comment", func(w http.ResponseWriter, r *http.Request) {
+		comments++
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"id": "1", "body": "Moved"})
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta/DST/issuetypes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"values": []map[string]interface{}{{"id": "20001", "name": "Bug"}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta/DST/issuetypes/20001", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"isLast": true,
+			"values": []map[string]interface{}{
+				{"fieldId": "summary", "name": "Summary", "required": true, "schema": map[string]interface{}{"type": "string"}},
+			},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"id": "2001", "key": "DST-7"})
+	})
+	mux.HandleFunc("/rest/api/2/issueLinkType", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"issueLinkTypes": []map[string]interface{}{{"id": "1", "name": "Cloners", "inward": "is cloned by", "outward": "clones"}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issueLink", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	client := newFakeJiraClient(t, mux)
+	move := func(fields map[string]interface{}, includeSubtasks bool) *jira.MoveResult {
+		sourceFields = map[string]interface{}{
+			"summary":   "Login fails",
+			"project":   map[string]interface{}{"key": "SRC"},
+			"issuetype": map[string]interface{}{"id": "1", "name": "Bug"},
+		}
+		for name, value := range fields {
+			sourceFields[name] = value
+		}
+		deleted, comments = false, 0
+
+		result, err := client.MoveIssue(context.Background(), "SRC-1", jira.MoveOptions{
+			TargetProject:   "DST",
+			IncludeSubtasks: includeSubtasks,
+			DeleteSource:    true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "DST-7", result.Key)
+		return result
+	}
+
+	// Deleting the source would delete the subtasks left behind
+	result := move(map[string]interface{}{
+		"subtasks": []map[string]interface{}{{"id": "1002", "key": "SRC-2"}},
+	}, false)
+	assert.False(t, deleted)
+	assert.False(t, result.SourceDeleted)
+	assert.Contains(t, result.SourceKept, "subtasks were not moved")
+	assert.Equal(t, 1, comments, "the kept source points to the new issue")
+
+	// An attachment that failed to copy would be lost
+	result = move(map[string]interface{}{
+		"attachment": []map[string]interface{}{{"id": "9", "filename": "trace.log", "content": "http://127.0.0.1:1/trace.log"}},
+	}, false)
+	assert.False(t, deleted)
+	assert.False(t, result.SourceDeleted)
+	assert.Contains(t, result.SourceKept, "not copied")
+
+	result = move(nil, false)
+	assert.True(t, deleted)
+	assert.True(t, result.SourceDeleted)
+	assert.Empty(t, result.SourceKept)
+	assert.Zero(t, comments)
+}
@@ -0,0 +1,265 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingIssueMux serves PROJ-1, holding every request until release is closed
+func blockingIssueMux(calls *int32, release <-chan struct{}) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		atomic.AddInt32(calls, 1)
+		<-release
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"key":    "PROJ-1",
+			"fields": map[string]interface{}{"summary": "Shared"},
+		})
+	})
+	return mux
+}
+
+func TestConcurrentIdenticalReadsShareOneCall(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	client := newFakeJiraClient(t, blockingIssueMux(&calls, release))
+
+	const readers = 10
+	results := make([]*jira.Issue, readers)
+	errs := make([]error, readers)
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = client.GetIssue(context.Background(), "PROJ-1", nil)
+		}(i)
+	}
+
+	require.Eventually(t, func() bool {
+		return client.GetCoalescingStats().Coalesced == readers-1
+	}, 2*time.Second, 5*time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < readers; i++ {
+		require.NoError(t, errs[i])
+		assert.Same(t, results[0], results[i], "coalesced readers share the result")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	stats := client.GetCoalescingStats()
+	assert.Equal(t, int64(1), stats.Calls)
+	assert.Equal(t, 0, stats.InFlight)
+
+	// Sequential reads are not cached by the client
+	_, err := client.GetIssue(context.Background(), "PROJ-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCoalescedReaderCancellationLeavesOthersWaiting(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	client := newFakeJiraClient(t, blockingIssueMux(&calls, release))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetIssue(context.Background(), "PROJ-1", nil)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetIssue(ctx, "PROJ-1", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestWriteForgetsInFlightReads(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	client := newFakeJiraClient(t, blockingIssueMux(&calls, release))
+
+	var wg sync.WaitGroup
+	read := func() {
+		defer wg.Done()
+		_, err := client.GetIssue(context.Background(), "PROJ-1", nil)
+		assert.NoError(t, err)
+	}
+
+	wg.Add(1)
+	go read()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, client.UpdateIssue(context.Background(), "PROJ-1", &jira.UpdateIssueRequest{}))
+
+	// A read after the write must not join the read started before it
+	wg.Add(1)
+	go read()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, 2*time.Second, 5*time.Millisecond)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int64(0), client.GetCoalescingStats().Coalesced)
+}
+
+func TestReadThroughCacheCoalescesMisses(t *testing.T) {
+	rtc := cache.NewReadThroughCache(time.Minute, time.Minute, 0)
+
+	var loads int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, []string, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return "workflow", nil, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := rtc.Get(context.Background(), "wf", load)
+			assert.NoError(t, err)
+			assert.Equal(t, "workflow", value)
+		}()
+	}
+	require.Eventually(t, func() bool { return rtc.GetStats().Misses == 5 }, 2*time.Second, 5*time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	value, err := rtc.Get(context.Background(), "wf", load)
+	require.NoError(t, err)
+	assert.Equal(t, "workflow", value)
+
+	stats := rtc.GetStats()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+	assert.Equal(t, int64(4), stats.Coalesced)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, 1, stats.Size)
+}
+
+func TestReadThroughCacheServesStaleWhileRevalidating(t *testing.T) {
+	rtc := cache.NewReadThroughCache(20*time.Millisecond, time.Hour, 0)
+
+	var loads int32
+	refresh := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, []string, error) {
+		n := atomic.AddInt32(&loads, 1)
+		if n > 1 {
+			<-refresh
+		}
+		return n, []string{cache.SprintTag(5)}, nil
+	}
+
+	value, err := rtc.Get(context.Background(), "sprint:5", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), value)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Expired: served stale to every caller while a single refresh runs
+	for i := 0; i < 5; i++ {
+		value, err := rtc.Get(context.Background(), "sprint:5", load)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), value)
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&loads) == 2 }, 2*time.Second, 5*time.Millisecond)
+	close(refresh)
+
+	require.Eventually(t, func() bool { return rtc.GetStats().Refreshes == 1 }, 2*time.Second, 5*time.Millisecond)
+	value, err = rtc.Get(context.Background(), "sprint:5", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), value)
+
+	stats := rtc.GetStats()
+	assert.Equal(t, int64(5), stats.StaleHits)
+	assert.Equal(t, int64(1), stats.Refreshes)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&loads))
+
+	// Invalidated entries are never served stale
+	assert.Equal(t, 1, rtc.InvalidateTags(cache.SprintTag(5)))
+	value, err = rtc.Get(context.Background(), "sprint:5", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), value)
+}
+
+func TestReadThroughCacheKeepsStaleEntryWhenRefreshFails(t *testing.T) {
+	rtc := cache.NewReadThroughCache(10*time.Millisecond, time.Hour, 0)
+
+	var fail atomic.Bool
+	load := func(ctx context.Context) (interface{}, []string, error) {
+		if fail.Load() {
+			return nil, nil, errors.New("jira unavailable")
+		}
+		return "cached", nil, nil
+	}
+
+	_, err := rtc.Get(context.Background(), "key", load)
+	require.NoError(t, err)
+
+	fail.Store(true)
+	time.Sleep(20 * time.Millisecond)
+
+	value, err := rtc.Get(context.Background(), "key", load)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", value)
+	require.Eventually(t, func() bool { return rtc.GetStats().RefreshErrors == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	value, err = rtc.Get(context.Background(), "key", load)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", value)
+}
+
+func TestReadThroughCacheRefreshesAgainAfterInvalidationDuringRefresh(t *testing.T) {
+	rtc := cache.NewReadThroughCache(10*time.Millisecond, time.Hour, 0)
+
+	var loads int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, []string, error) {
+		n := atomic.AddInt32(&loads, 1)
+		if n == 2 {
+			<-release
+		}
+		return n, []string{cache.SprintTag(5)}, nil
+	}
+
+	_, err := rtc.Get(context.Background(), "sprint:5", load)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	// An unrelated invalidation while the refresh runs discards its value
+	value, err := rtc.Get(context.Background(), "sprint:5", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), value)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&loads) == 2 }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, 0, rtc.InvalidateTags(cache.SprintTag(9)))
+	close(release)
+	require.Eventually(t, func() bool { return rtc.GetStats().Refreshes == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	// The stale entry is still served and a later read refreshes it again
+	require.Eventually(t, func() bool {
+		value, err := rtc.Get(context.Background(), "sprint:5", load)
+		return err == nil && value == int32(3)
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(2), rtc.GetStats().Refreshes)
+}
@@ -0,0 +1,168 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/cache/resptest"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codecSummary struct {
+	Key    string   `json:"key"`
+	Labels []string `json:"labels"`
+}
+
+func newTestDiskCache(t *testing.T) *cache.DiskCache {
+	t.Helper()
+	dc := cache.NewDiskCache(10*1024*1024, time.Hour)
+	if dc == nil {
+		t.Skip("Disk cache creation failed, skipping test")
+	}
+	t.Cleanup(dc.Stop)
+	return dc
+}
+
+func TestDiskCacheRoundTripsConcreteTypes(t *testing.T) {
+	dc := newTestDiskCache(t)
+	key := "codec-test:" + t.Name()
+	t.Cleanup(func() { dc.Delete(key) })
+
+	result := &jira.ExtendedSearchResult{
+		Total:  1,
+		Issues: []jira.Issue{{Key: "PROJ-1", Fields: jira.IssueFields{Summary: strings.Repeat("Login fails ", 200)}}},
+	}
+	require.NoError(t, dc.Set(key, result, time.Hour))
+
+	value, err := dc.Get(key)
+	require.NoError(t, err)
+	cached, ok := value.(*jira.ExtendedSearchResult)
+	require.True(t, ok, "L3 returns %T", value)
+	assert.Equal(t, "PROJ-1", cached.Issues[0].Key)
+	assert.Equal(t, result.Issues[0].Fields.Summary, cached.Issues[0].Fields.Summary)
+
+	entry, ok := dc.Entry(key)
+	require.True(t, ok)
+	assert.Less(t, entry.Size, int64(len(result.Issues[0].Fields.Summary)), "large issue data is compressed")
+}
+
+func TestStaleSchemaEntriesAreEvicted(t *testing.T) {
+	dc := newTestDiskCache(t)
+	key := "codec-test:" + t.Name()
+	t.Cleanup(func() { dc.Delete(key) })
+
+	v1 := cache.NewCodecRegistry()
+	require.NoError(t, cache.RegisterType[codecSummary](v1, "summary", 1, cache.CompressNone))
+	dc.SetCodecs(v1)
+	t.Cleanup(func() { dc.SetCodecs(cache.DefaultCodecs) })
+
+	require.NoError(t, dc.Set(key, codecSummary{Key: "PROJ-1"}, time.Hour))
+	value, err := dc.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, codecSummary{Key: "PROJ-1"}, value)
+
+	// The schema moves on: the entry written with version 1 is a miss
+	v2 := cache.NewCodecRegistry()
+	require.NoError(t, cache.RegisterType[codecSummary](v2, "summary", 2, cache.CompressNone))
+	dc.SetCodecs(v2)
+
+	_, err = dc.Get(key)
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.ErrorContains(t, err, "schema version 1, expected 2")
+	assert.Equal(t, int64(1), dc.GetStats().StaleEvictions)
+	_, ok := dc.Entry(key)
+	assert.False(t, ok, "stale entry is removed from disk")
+}
+
+func TestRedisCacheEvictsStaleSchemas(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	v1 := cache.NewCodecRegistry()
+	require.NoError(t, cache.RegisterType[codecSummary](v1, "summary", 1, cache.CompressAuto))
+	writer := newTestRedisCache(t, server, cache.RedisOptions{Codecs: v1})
+	require.NoError(t, writer.Set("summary:PROJ-1", codecSummary{Key: "PROJ-1"}, time.Minute))
+
+	value, err := writer.Get("summary:PROJ-1")
+	require.NoError(t, err)
+	assert.IsType(t, codecSummary{}, value)
+
+	// A replica running without the codec treats the entry as stale
+	reader := newTestRedisCache(t, server, cache.RedisOptions{Codecs: cache.NewCodecRegistry()})
+	_, err = reader.Get("summary:PROJ-1")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.Equal(t, int64(1), reader.GetStats().StaleEvictions)
+	assert.Equal(t, int64(0), reader.GetStats().Errors)
+	assert.Empty(t, server.Keys(0))
+}
+
+func TestMultiLevelCacheKeepsTypesAndTagsThroughL2(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	strategy.WriteThrough = true
+
+	replicas := make([]*cache.MultiLevelCache, 2)
+	for i := range replicas {
+		replicas[i] = cache.NewMultiLevelCache(strategy)
+		replicas[i].SetL2Cache(newTestRedisCache(t, server, cache.RedisOptions{}))
+		defer replicas[i].Stop()
+	}
+
+	issue := &jira.Issue{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "Checkout is slow"}}
+	require.NoError(t, replicas[0].SetWithTags("issue:PROJ-1", issue, time.Minute, cache.DependencyTags(issue)...))
+
+	value, found := replicas[1].Get("issue:PROJ-1")
+	require.True(t, found)
+	cached, ok := value.(*jira.Issue)
+	require.True(t, ok, "L2 returns %T", value)
+	assert.Equal(t, "Checkout is slow", cached.Fields.Summary)
+
+	// The replica learned the tags stored with the value
+	assert.Positive(t, replicas[1].InvalidateTags(cache.IssueTag("PROJ-1")))
+	_, found = replicas[1].Get("issue:PROJ-1")
+	assert.False(t, found)
+}
+
+func TestCodecRegistry(t *testing.T) {
+	registry := cache.NewCodecRegistry()
+	require.NoError(t, cache.RegisterType[codecSummary](registry, "summary", 1, cache.CompressGzip))
+
+	assert.ErrorContains(t, cache.RegisterType[string](registry, "summary", 1, cache.CompressNone), "already registered")
+	assert.ErrorContains(t, cache.RegisterType[int](registry, "count", 1, "zstd"), "unknown compression")
+	assert.ErrorContains(t, cache.RegisterType[int](registry, "gob", 1, cache.CompressNone), "invalid codec tag")
+
+	// Compression is chosen per type
+	long := codecSummary{Key: "PROJ-1", Labels: []string{strings.Repeat("backend", 500)}}
+	gzipped, err := registry.Encode(long)
+	require.NoError(t, err)
+	require.NoError(t, cache.RegisterType[codecSummary](registry, "summary", 1, cache.CompressNone))
+	plain, err := registry.Encode(long)
+	require.NoError(t, err)
+	assert.Less(t, len(gzipped), len(plain)/4)
+
+	decoded, err := registry.Decode(gzipped)
+	require.NoError(t, err)
+	assert.Equal(t, long, decoded)
+
+	_, err = registry.Decode([]byte("not an entry"))
+	assert.ErrorIs(t, err, cache.ErrStaleEntry)
+
+	infos := registry.Codecs()
+	require.Len(t, infos, 1)
+	assert.Equal(t, "summary", infos[0].Tag)
+	assert.Equal(t, cache.CompressNone, infos[0].Compression)
+
+	tags := map[string]bool{}
+	for _, info := range cache.DefaultCodecs.Codecs() {
+		tags[info.Tag] = true
+	}
+	assert.True(t, tags["jira.searchExtended"])
+	assert.True(t, tags["jira.issue"])
+}
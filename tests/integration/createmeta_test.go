@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCreateMetaMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/createmeta/PROJ/issuetypes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"values": []map[string]interface{}{
+				{"id": "10001", "name": "Bug"},
+				{"id": "10002", "name": "Story"},
+			},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta/PROJ/issuetypes/10001", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"total":  7,
+			"isLast": true,
+			"values": []map[string]interface{}{
+				{"fieldId": "summary", "name": "Summary", "required": true, "schema": map[string]interface{}{"type": "string"}},
+				{"fieldId": "priority", "name": "Priority", "required": true, "schema": map[string]interface{}{"type": "priority"},
+					"allowedValues": []map[string]interface{}{{"id": "1", "name": "High"}, {"id": "3", "name": "Low"}}},
+				{"fieldId": "components", "name": "Components", "required": false, "schema": map[string]interface{}{"type": "array", "items": "component"},
+					"allowedValues": []map[string]interface{}{{"id": "10", "name": "Backend"}}},
+				{"fieldId": "duedate", "name": "Due date", "required": false, "schema": map[string]interface{}{"type": "date"}},
+				{"fieldId": "customfield_10020", "name": "Severity", "required": true,
+					"schema":        map[string]interface{}{"type": "option", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:select"},
+					"allowedValues": []map[string]interface{}{{"id": "100", "value": "Critical"}, {"id": "101", "value": "Minor"}}},
+				{"fieldId": "customfield_10030", "name": "Story Points", "required": false, "schema": map[string]interface{}{"type": "number"}},
+				{"fieldId": "issuelinks", "name": "Linked Issues", "required": false, "schema": map[string]interface{}{"type": "array", "items": "issuelinks"}},
+			},
+		})
+	})
+	return mux
+}
+
+func TestCreateMetaValidationCoercesValues(t *testing.T) {
+	client := newFakeJiraClient(t, newCreateMetaMux())
+
+	meta, err := client.GetCreateMeta("PROJ", "bug")
+	require.NoError(t, err)
+	assert.Equal(t, "10001", meta.IssueType.ID)
+	assert.Len(t, meta.Fields, 7)
+
+	link := map[string]interface{}{"add": map[string]interface{}{
+		"type":         map[string]interface{}{"name": "Blocks"},
+		"outwardIssue": map[string]interface{}{"key": "PROJ-2"},
+	}}
+	result := meta.ValidateFields(map[string]interface{}{
+		"summary":      "Login fails",
+		"priority":     map[string]interface{}{"name": "high"},
+		"components":   []map[string]interface{}{{"name": "Backend"}},
+		"duedate":      "2026-11-02T10:00:00Z",
+		"Severity":     "Critical",
+		"Story Points": "5",
+		"issuelinks":   []map[string]interface{}{link},
+	}, nil)
+
+	require.True(t, result.Valid, "unexpected issues: %+v %+v", result.Missing, result.Invalid)
+	assert.Equal(t, map[string]interface{}{"id": "1"}, result.Fields["priority"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "10"}}, result.Fields["components"])
+	assert.Equal(t, "2026-11-02", result.Fields["duedate"])
+	assert.Equal(t, map[string]interface{}{"id": "100"}, result.Fields["customfield_10020"])
+	assert.Equal(t, 5.0, result.Fields["customfield_10030"])
+	// Issue links keep the shape the caller gave them
+	assert.Equal(t, []interface{}{link}, result.Fields["issuelinks"])
+}
+
+func TestCreateMetaValidationReportsProblems(t *testing.T) {
+	client := newFakeJiraClient(t, newCreateMetaMux())
+
+	meta, err := client.GetCreateMeta("PROJ", "10001")
+	require.NoError(t, err)
+
+	result := meta.ValidateFields(map[string]interface{}{
+		"summary":           "Login fails",
+		"priority":          "Urgent",
+		"customfield_10030": "lots",
+		"environment":       "prod",
+	}, nil)
+
+	assert.False(t, result.Valid)
+
+	require.Len(t, result.Missing, 1)
+	assert.Equal(t, "customfield_10020", result.Missing[0].Field)
+	assert.Equal(t, []string{"Critical", "Minor"}, result.Missing[0].AllowedValues)
+
+	codes := make(map[string]string)
+	for _, issue := range result.Invalid {
+		codes[issue.Field] = issue.Code
+	}
+	assert.Equal(t, jira.FieldIssueUnknownField, codes["environment"])
+	assert.Equal(t, jira.FieldIssueInvalidValue, codes["priority"])
+	assert.Equal(t, jira.FieldIssueInvalidType, codes["customfield_10030"])
+}
+
+func TestCreateMetaUnknownIssueType(t *testing.T) {
+	client := newFakeJiraClient(t, newCreateMetaMux())
+
+	_, err := client.GetCreateMeta("PROJ", "Epic")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Bug, Story")
+}
@@ -0,0 +1,257 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/middleware"
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newValidatingJiraClient returns a fake Jira client keeping validated
+// responses in a store registered on its own invalidation bus
+func newValidatingJiraClient(t *testing.T, mux *http.ServeMux) (*jira.Client, *cache.InvalidationBus) {
+	t.Helper()
+	store := cache.NewValidatedResponseCache(100, time.Minute)
+	t.Cleanup(store.Stop)
+	bus := cache.NewInvalidationBus()
+	bus.Register(store)
+
+	client := newFakeJiraClient(t, mux)
+	client.SetResponseStore(store)
+	client.OnMutation(func(m jira.Mutation) { bus.PublishMutation(m) })
+	return client, bus
+}
+
+func TestJiraIssueReadsRevalidateWithETag(t *testing.T) {
+	var mu sync.Mutex
+	etag, summary := `"v1"`, "First"
+	var conditional []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"key":    "PROJ-1",
+			"fields": map[string]interface{}{"summary": summary},
+		})
+	})
+	client, _ := newValidatingJiraClient(t, mux)
+	ctx := context.Background()
+
+	issue, err := client.GetIssue(ctx, "PROJ-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "First", issue.Fields.Summary)
+
+	// Unchanged: Jira answers 304 and the stored body is used
+	issue, err = client.GetIssue(ctx, "PROJ-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "First", issue.Fields.Summary)
+
+	mu.Lock()
+	etag, summary = `"v2"`, "Second"
+	mu.Unlock()
+
+	issue, err = client.GetIssue(ctx, "PROJ-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Second", issue.Fields.Summary)
+
+	assert.Equal(t, []string{"", `"v1"`, `"v1"`}, conditional)
+
+	stats := client.GetConditionalStats()
+	assert.Equal(t, int64(2), stats.Requests)
+	assert.Equal(t, int64(1), stats.NotModified)
+	assert.Positive(t, stats.BytesSaved)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestJiraIssueReadsWithoutValidatorsAreNotStored(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"key": "PROJ-1"})
+	})
+	mux.HandleFunc("/rest/api/2/issue/GONE-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"errorMessages": []string{"Issue does not exist"}})
+	})
+	client, _ := newValidatingJiraClient(t, mux)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetIssue(context.Background(), "PROJ-1", nil)
+		require.NoError(t, err)
+	}
+	_, err := client.GetIssue(context.Background(), "GONE-1", nil)
+	assert.ErrorContains(t, err, "Issue does not exist")
+
+	assert.Equal(t, 0, client.GetConditionalStats().Entries)
+}
+
+func TestJiraSearchesAndAgileReadsRevalidateWithETag(t *testing.T) {
+	var mu sync.Mutex
+	conditional := map[string][]string{}
+	mux := http.NewServeMux()
+	validated := func(path string, body interface{}) {
+		etag := fmt.Sprintf("%q", path)
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			conditional[path] = append(conditional[path], r.Header.Get("If-None-Match"))
+			mu.Unlock()
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			writeJSON(w, http.StatusOK, body)
+		})
+	}
+	validated("/rest/api/2/search", map[string]interface{}{
+		"total":  1,
+		"issues": []map[string]interface{}{{"key": "PROJ-1", "fields": map[string]interface{}{"summary": "Cached"}}},
+	})
+	validated("/rest/agile/1.0/sprint/7/issue", map[string]interface{}{
+		"total":  1,
+		"issues": []map[string]interface{}{{"key": "PROJ-1"}},
+	})
+	validated("/rest/agile/1.0/board/3", map[string]interface{}{"id": 3, "name": "Team board"})
+	client, bus := newValidatingJiraClient(t, mux)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := client.SearchIssues(ctx, "project = PROJ", 0, 50, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, "Cached", result.Issues[0].Fields.Summary, "a 304 returns the stored body")
+
+		sprintIssues, err := client.GetSprintIssues(7)
+		require.NoError(t, err)
+		assert.Len(t, sprintIssues.Issues, 1)
+
+		board, err := client.GetBoard(3)
+		require.NoError(t, err)
+		assert.Equal(t, "Team board", board.Name)
+	}
+
+	mu.Lock()
+	for _, path := range []string{"/rest/api/2/search", "/rest/agile/1.0/sprint/7/issue", "/rest/agile/1.0/board/3"} {
+		assert.Equal(t, []string{"", fmt.Sprintf("%q", path)}, conditional[path], path)
+	}
+	mu.Unlock()
+	stats := client.GetConditionalStats()
+	assert.Equal(t, int64(3), stats.NotModified)
+	assert.Equal(t, 3, stats.Entries)
+
+	// A write to the project evicts the validators with the data
+	bus.PublishMutation(jira.Mutation{Operation: jira.OpUpdateIssue, Issues: []string{"PROJ-1"}, Projects: []string{"PROJ"}})
+	assert.Equal(t, 1, client.GetConditionalStats().Entries, "only the board read is kept")
+
+	_, err := client.SearchIssues(ctx, "project = PROJ", 0, 50, nil)
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Empty(t, conditional["/rest/api/2/search"][2])
+	mu.Unlock()
+}
+
+func TestETagMiddleware(t *testing.T) {
+	body := `{"success":true}`
+	handler := middleware.ETag()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/issues/PROJ-1", nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, body, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	cached := get(etag)
+	assert.Equal(t, http.StatusNotModified, cached.Code)
+	assert.Empty(t, cached.Body.String())
+	assert.Equal(t, etag, cached.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusNotModified, get(`"other", W/`+etag).Code, "weak comparison over a list")
+	assert.Equal(t, http.StatusOK, get(`"other"`).Code)
+
+	body = `{"success":false}`
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestETagMiddlewarePassesThroughErrorsWritesAndStreams(t *testing.T) {
+	handler := middleware.ETag()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+		case "/stream":
+			w.Write([]byte("data: 1\n\n"))
+			require.NoError(t, http.NewResponseController(w).Flush())
+			w.Write([]byte("data: 2\n\n"))
+		default:
+			w.Write([]byte("created"))
+		}
+	}))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	missing := serve(http.MethodGet, "/missing")
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	assert.Empty(t, missing.Header().Get("ETag"))
+
+	post := serve(http.MethodPost, "/")
+	assert.Equal(t, "created", post.Body.String())
+	assert.Empty(t, post.Header().Get("ETag"))
+
+	stream := serve(http.MethodGet, "/stream")
+	assert.True(t, stream.Flushed)
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", stream.Body.String())
+	assert.Empty(t, stream.Header().Get("ETag"))
+}
+
+func TestAPIResponsesCarryETags(t *testing.T) {
+	srv := setupTestServer(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/stats", nil)
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/stats", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Zero(t, w.Body.Len())
+}
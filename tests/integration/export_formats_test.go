@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestResult() *jira.ExtendedSearchResult {
+	created := &jira.JiraTime{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+	return &jira.ExtendedSearchResult{
+		Total: 3,
+		Issues: []jira.Issue{
+			{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "Fix <login> & logout", Status: &jira.Status{Name: "Done"}, Created: created}},
+			{Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Add reports", Status: &jira.Status{Name: "In Progress"}, Created: created}},
+			{Key: "PROJ-3", Fields: jira.IssueFields{Summary: "Tidy up", Status: &jira.Status{Name: "Done"}}},
+		},
+	}
+}
+
+// readXLSX returns the parts of a workbook, checking every XML part is well-formed
+func readXLSX(t *testing.T, data []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := make(map[string]string)
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else {
+				require.NoError(t, err, file.Name)
+			}
+		}
+		parts[file.Name] = string(content)
+	}
+	return parts
+}
+
+func TestExportSearchResultsXLSX(t *testing.T) {
+	client := newFakeJiraClient(t, http.NewServeMux())
+
+	result, err := client.ExportSearchResults(exportTestResult(), jira.ExportRequest{
+		Format:  jira.FormatXLSX,
+		Fields:  []string{"key", "summary", "created"},
+		GroupBy: "status",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", result.ContentType)
+	assert.True(t, strings.HasSuffix(result.Filename, ".xlsx"))
+
+	parts := readXLSX(t, result.Data)
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	// One sheet per status, in name order
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Done" sheetId="1" r:id="rId1"/><sheet name="In Progress" sheetId="2" r:id="rId2"/>`)
+	done := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, done, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">key</t></is></c>`)
+	assert.Contains(t, done, `Fix &lt;login&gt; &amp; logout`)
+	assert.Contains(t, done, `<c r="C2" s="2"><v>46296.5</v></c>`)
+	assert.Contains(t, done, `state="frozen"`)
+	assert.NotContains(t, done, `r="C3"`, "missing dates produce no cell")
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], "PROJ-2")
+}
+
+func TestWriteXLSXSheetNamesAndTypes(t *testing.T) {
+	data, err := jira.WriteXLSX(&jira.ExportDocument{Tables: []jira.ExportTable{
+		{Title: "Team: Alpha/Beta [web] with a very long name indeed", Header: []string{"n", "ok"}, Rows: [][]interface{}{{42, true}, {1.5, false}}},
+		{Title: "Team: Alpha/Beta [web] with a very long name indeed"},
+		{Title: ""},
+	}})
+	require.NoError(t, err)
+
+	parts := readXLSX(t, data)
+	workbook := parts["xl/workbook.xml"]
+	assert.Contains(t, workbook, `name="Team- Alpha-Beta (web) with a v"`)
+	assert.Contains(t, workbook, `name="Team- Alpha-Beta (web) with (2)"`)
+	assert.Contains(t, workbook, `name="Sheet3"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A2"><v>42</v></c><c r="B2" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="A3"><v>1.5</v></c><c r="B3" t="b"><v>0</v></c>`)
+}
+
+func TestExportSearchResultsHTML(t *testing.T) {
+	client := newFakeJiraClient(t, http.NewServeMux())
+
+	result, err := client.ExportSearchResults(exportTestResult(), jira.ExportRequest{
+		Format: jira.FormatHTML,
+		Fields: []string{"key", "summary", "created"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", result.ContentType)
+
+	page := string(result.Data)
+	assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+	assert.Contains(t, page, `<h2>Issues (3)</h2>`)
+	assert.Contains(t, page, `Fix &lt;login&gt; &amp; logout`)
+	assert.Contains(t, page, `data-sort="2026-10-01T12:00:00Z">2026-10-01 12:00</td>`)
+	assert.Contains(t, page, `table.sortable`)
+	assert.NotContains(t, page, `<link`, "the page must not depend on external resources")
+}
+
+func TestSprintReportExports(t *testing.T) {
+	report := &jira.SprintReport{
+		Sprint:    jira.Sprint{ID: 7, Name: "Sprint 7", State: "closed"},
+		Velocity:  jira.SprintVelocity{Committed: 20, Completed: 13, CommittedIssues: 5, CompletedIssues: 3},
+		Burndown:  []jira.BurndownDataPoint{{Date: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), StoryPoints: 20, IssueCount: 5, IdealProgress: 20}},
+		Issues:    map[string][]jira.SprintIssue{"completed": {{Key: "PROJ-1"}}, "incomplete": {{Key: "PROJ-2"}}},
+		StartDate: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+	}
+
+	doc := jira.SprintReportDocument(report)
+	titles := make([]string, len(doc.Tables))
+	for i, table := range doc.Tables {
+		titles[i] = table.Title
+	}
+	assert.Equal(t, []string{"Summary", "Burndown", "Completed", "Incomplete"}, titles)
+
+	xlsx, err := jira.RenderExport(doc, jira.FormatXLSX, "sprint_7_report")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(xlsx.Filename, "sprint_7_report_"))
+	assert.Len(t, readXLSX(t, xlsx.Data), 9)
+
+	html, err := jira.RenderExport(doc, jira.FormatHTML, "sprint_7_report")
+	require.NoError(t, err)
+	assert.Contains(t, string(html.Data), "Sprint Report: Sprint 7")
+
+	_, err = jira.RenderExport(doc, jira.FormatCSV, "sprint_7_report")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterCreateUpdateAndDelete(t *testing.T) {
+	var created, updated map[string]interface{}
+	deleted := false
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/filter", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		json.NewDecoder(r.Body).Decode(&created)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": "10100", "name": created["name"], "jql": created["jql"], "favourite": true})
+	})
+	mux.HandleFunc("/rest/api/2/filter/10100", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&updated)
+			writeJSON(w, http.StatusOK, map[string]interface{}{"id": "10100", "name": updated["name"], "jql": updated["jql"]})
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	client := newFakeJiraClient(t, mux)
+
+	favourite := true
+	filter, err := client.CreateFilter(&jira.FilterRequest{
+		Name:      "My open bugs",
+		JQL:       `project = PROJ AND type = Bug`,
+		Favourite: &favourite,
+		Share: []jira.SharePermissionRequest{
+			{Type: jira.ShareProjectRole, ProjectID: "10000", ProjectRoleID: "10002"},
+			{Type: jira.ShareGroup, GroupName: "developers"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "10100", filter.ID)
+	assert.True(t, filter.Favourite)
+	assert.Equal(t, true, created["favourite"])
+	assert.NotContains(t, created, "description")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "projectRole", "project": map[string]interface{}{"id": "10000"}, "role": map[string]interface{}{"id": "10002"}},
+		map[string]interface{}{"type": "group", "group": map[string]interface{}{"name": "developers"}},
+	}, created["sharePermissions"])
+
+	filter, err = client.UpdateFilter("10100", &jira.FilterRequest{
+		Name:  "My bugs",
+		JQL:   `project = PROJ AND type = Bug`,
+		Share: []jira.SharePermissionRequest{{Type: jira.ShareGlobal}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "My bugs", filter.Name)
+	assert.NotContains(t, updated, "sharePermissions", "shares are edited through the permission API")
+
+	require.NoError(t, client.DeleteFilter("10100"))
+	assert.True(t, deleted)
+}
+
+func TestFilterFavouritesPermissionsAndColumns(t *testing.T) {
+	favourite := false
+	var permission map[string]interface{}
+	var deletedPermission string
+	var columns []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/filter/10100/favourite", func(w http.ResponseWriter, r *http.Request) {
+		favourite = r.Method == http.MethodPut
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": "10100", "name": "My bugs", "favourite": favourite})
+	})
+	mux.HandleFunc("/rest/api/2/filter/10100/permission", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&permission)
+		}
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"id": 1, "type": "global"},
+			{"id": 2, "type": "project", "project": map[string]interface{}{"id": "10000", "key": "PROJ"}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/filter/10100/permission/2", func(w http.ResponseWriter, r *http.Request) {
+		deletedPermission = r.Method
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/rest/api/2/filter/10100/columns", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&columns)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			columns = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, http.StatusOK, []map[string]string{{"label": "Key", "value": "issuekey"}, {"label": "Summary", "value": "summary"}})
+		}
+	})
+	mux.HandleFunc("/rest/api/2/filter/10100", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "subscriptions", r.URL.Query().Get("expand"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id": "10100",
+			"subscriptions": map[string]interface{}{
+				"size":  1,
+				"items": []map[string]interface{}{{"id": 7, "group": map[string]string{"name": "developers"}}},
+			},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/filter/404/columns", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"errorMessages": []string{"The selected filter is not available to you"}})
+	})
+	client := newFakeJiraClient(t, mux)
+
+	filter, err := client.SetFilterFavourite("10100", true)
+	require.NoError(t, err)
+	assert.True(t, filter.Favourite)
+	filter, err = client.SetFilterFavourite("10100", false)
+	require.NoError(t, err)
+	assert.False(t, filter.Favourite)
+
+	permissions, err := client.AddFilterSharePermission("10100", jira.SharePermissionRequest{Type: jira.ShareProject, ProjectID: "10000"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "project", "projectId": "10000"}, permission)
+	require.Len(t, permissions, 2)
+	assert.Equal(t, "PROJ", permissions[1].Project.Key)
+
+	require.NoError(t, client.DeleteFilterSharePermission("10100", 2))
+	assert.Equal(t, http.MethodDelete, deletedPermission)
+
+	current, err := client.GetFilterColumns("10100")
+	require.NoError(t, err)
+	assert.Equal(t, []jira.FilterColumn{{Label: "Key", Value: "issuekey"}, {Label: "Summary", Value: "summary"}}, current)
+
+	require.NoError(t, client.SetFilterColumns("10100", []string{"issuekey", "status"}))
+	assert.Equal(t, []string{"issuekey", "status"}, columns)
+	require.NoError(t, client.ResetFilterColumns("10100"))
+	assert.Nil(t, columns)
+
+	subscriptions, err := client.GetFilterSubscriptions("10100")
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, "developers", subscriptions[0].Group.Name)
+
+	_, err = client.GetFilterColumns("404")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not available to you")
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hierarchyIssue(key, issueType, status, category string, points interface{}) map[string]interface{} {
+	fields := map[string]interface{}{
+		"summary":           key + " summary",
+		"issuetype":         map[string]interface{}{"name": issueType},
+		"status":            map[string]interface{}{"name": status, "statusCategory": map[string]interface{}{"key": category}},
+		"customfield_10016": points,
+	}
+	return map[string]interface{}{"id": key, "key": key, "fields": fields}
+}
+
+// withParent links a hierarchy issue to its parent through the given field
+func withParent(issue map[string]interface{}, field string, value interface{}) map[string]interface{} {
+	issue["fields"].(map[string]interface{})[field] = value
+	return issue
+}
+
+func TestHierarchyTreeAndRollup(t *testing.T) {
+	issues := []map[string]interface{}{
+		withParent(hierarchyIssue("PROJ-1", "Story", "Done", "done", 5.0), "customfield_10014", "EPIC-1"),
+		withParent(hierarchyIssue("PROJ-2", "Story", "In Progress", "indeterminate", 3.0), "parent", map[string]interface{}{"key": "EPIC-1"}),
+		withParent(hierarchyIssue("PROJ-3", "Task", "To Do", "new", nil), "customfield_10014", "EPIC-1"),
+		withParent(hierarchyIssue("PROJ-4", "Sub-task", "Done", "done", 8.0), "parent", map[string]interface{}{"key": "PROJ-2"}),
+	}
+	parentOf := func(issue map[string]interface{}) string {
+		fields := issue["fields"].(map[string]interface{})
+		if parent, ok := fields["parent"].(map[string]interface{}); ok {
+			return parent["key"].(string)
+		}
+		epic, _ := fields["customfield_10014"].(string)
+		return epic
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"id": "customfield_10014", "name": "Epic Link", "schema": map[string]interface{}{"type": "any", "custom": "com.pyxis.greenhopper.jira:gh-epic-link"}},
+			{"id": "customfield_10016", "name": "Story Points", "schema": map[string]interface{}{"type": "number"}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue/EPIC-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, hierarchyIssue("EPIC-1", "Epic", "In Progress", "indeterminate", nil))
+	})
+	var searches []string
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var req jira.SearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		searches = append(searches, req.JQL)
+
+		list, _, ok := strings.Cut(strings.TrimPrefix(req.JQL, "parent IN ("), ")")
+		require.True(t, ok, req.JQL)
+		assert.Contains(t, req.JQL, "cf[10014] IN ("+list+")")
+		parents := strings.Split(list, ", ")
+
+		matched := []map[string]interface{}{}
+		for _, issue := range issues {
+			if slices.Contains(parents, parentOf(issue)) {
+				matched = append(matched, issue)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"total": len(matched), "issues": matched})
+	})
+
+	client := newFakeJiraClient(t, mux)
+
+	hf, err := client.GetHierarchyFields()
+	require.NoError(t, err)
+	assert.Equal(t, "customfield_10014", hf.EpicLinkField)
+	assert.Equal(t, "customfield_10016", hf.StoryPointsField)
+
+	tree, err := client.GetHierarchy("EPIC-1", hf, 0)
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 3)
+	require.Len(t, tree.Children[1].Children, 1)
+	assert.Len(t, tree.Flatten(), 4)
+	assert.Equal(t, "PROJ-2", tree.Children[1].Children[0].ParentKey)
+
+	// One search per level of the tree, not one per issue
+	require.Len(t, searches, 3)
+	assert.True(t, strings.HasPrefix(searches[1], "parent IN (PROJ-1, PROJ-2, PROJ-3)"), searches[1])
+
+	rollup := tree.Rollup()
+	assert.Equal(t, 4, rollup.Total)
+	assert.Equal(t, 2, rollup.Done)
+	assert.Equal(t, 2, rollup.ByStatus["Done"])
+	// Subtask points are ignored because the parent story is estimated
+	assert.Equal(t, 8.0, rollup.StoryPoints)
+	assert.Equal(t, 5.0, rollup.CompletedStoryPoints)
+	assert.Equal(t, 1, rollup.Unestimated)
+	assert.Equal(t, "storyPoints", rollup.PercentDoneBy)
+	assert.InDelta(t, 62.5, rollup.PercentDone, 0.001)
+}
+
+func TestSetParentFallsBackToEpicLink(t *testing.T) {
+	var updates []map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/EPIC-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, hierarchyIssue("EPIC-1", "Epic", "To Do", "new", nil))
+	})
+	mux.HandleFunc("/rest/api/2/issue/PROJ-7", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		var body struct {
+			Fields map[string]interface{} `json:"fields"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		updates = append(updates, body.Fields)
+
+		if _, ok := body.Fields["parent"]; ok {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"errors": map[string]string{"parent": "Field 'parent' cannot be set"},
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := newFakeJiraClient(t, mux)
+
+	err := client.SetParent("PROJ-7", "EPIC-1", &jira.HierarchyFields{EpicLinkField: "customfield_10014"})
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, "EPIC-1", updates[1]["customfield_10014"])
+}
@@ -0,0 +1,213 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importTestValidator requires a summary and rejects unknown priorities
+func importTestValidator(projectKey, issueType string, fields map[string]interface{}) (*jira.FieldValidationResult, error) {
+	if projectKey == "NOPE" {
+		return nil, fmt.Errorf("project '%s' not found", projectKey)
+	}
+	result := &jira.FieldValidationResult{Valid: true, Fields: make(map[string]interface{})}
+	if _, ok := fields["summary"]; !ok {
+		result.Missing = append(result.Missing, jira.FieldIssue{Field: "summary", Code: "missing"})
+	}
+	for name, value := range fields {
+		if name == "priority" {
+			if value != "High" && value != "Low" {
+				result.Invalid = append(result.Invalid, jira.FieldIssue{Field: name, Code: "invalid_value"})
+				continue
+			}
+			value = map[string]interface{}{"name": value}
+		}
+		result.Fields[name] = value
+	}
+	result.Valid = len(result.Missing) == 0 && len(result.Invalid) == 0
+	return result, nil
+}
+
+func TestParseImportCSVWithMapping(t *testing.T) {
+	data := "\xef\xbb\xbfId,Title,Tags,Parent Id,Notes\n" +
+		"epic-1,Checkout epic,\"web, payments\",,ignored\n" +
+		",,,,\n" +
+		"story-1,Pay by card,web,epic-1,\n"
+
+	rows, err := jira.ParseImport(jira.ImportRequest{
+		Format:  jira.ImportCSV,
+		Data:    data,
+		Mapping: map[string]string{"Id": "ref", "Title": "summary", "Tags": "labels", "Parent Id": "Parent"},
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2, "blank lines are skipped")
+
+	assert.Equal(t, 1, rows[0].Row)
+	assert.Equal(t, "epic-1", rows[0].Ref)
+	assert.Equal(t, []interface{}{"web", "payments"}, rows[0].Fields["labels"])
+	assert.NotContains(t, rows[0].Fields, "Notes", "unmapped columns are ignored")
+
+	assert.Equal(t, 2, rows[1].Row)
+	assert.Equal(t, "epic-1", rows[1].ParentRef)
+	assert.Equal(t, "Pay by card", rows[1].Fields["summary"])
+
+	_, err = jira.ParseImport(jira.ImportRequest{Format: "xml", Data: "<a/>"})
+	assert.Error(t, err)
+}
+
+func TestPlanImportReferencesAndValidation(t *testing.T) {
+	rows, err := jira.ParseImport(jira.ImportRequest{
+		Format: jira.ImportJSON,
+		Data: `[
+			{"ref": "epic", "summary": "Epic", "issuetype": "Epic"},
+			{"summary": "Story", "parent": "#1", "priority": "High"},
+			{"summary": "Sub-task", "parent": "2"},
+			{"key": "PROJ-9", "priority": "Low", "epic": "epic"},
+			{"ref": "a", "summary": "A", "parent": "b"},
+			{"ref": "b", "summary": "B", "parent": "a"},
+			{"summary": "Bad", "priority": "Urgent"},
+			{"summary": "Child of bad", "parent": "#7"},
+			{"summary": "Elsewhere", "project": "NOPE"},
+			{"summary": "Existing parent", "parent": "PROJ-1"}
+		]`,
+	})
+	require.NoError(t, err)
+
+	plan := jira.PlanImport(rows, jira.ImportRequest{Project: "PROJ", IssueType: "Story"}, importTestValidator)
+	status := make([]string, len(plan.Reports))
+	for i, report := range plan.Reports {
+		status[i] = report.Status
+	}
+	assert.Equal(t, []string{"valid", "valid", "valid", "valid", "invalid", "invalid", "invalid", "blocked", "invalid", "valid"}, status)
+	assert.False(t, plan.Valid)
+	assert.Equal(t, jira.ImportSummary{Total: 10, Valid: 5, Invalid: 4, Blocked: 1, Creates: 4, Updates: 1}, plan.Summary)
+
+	// The update resolves its epic reference but is not checked for missing fields
+	assert.Equal(t, "update", plan.Reports[3].Action)
+	assert.Equal(t, 1, plan.Reports[3].EpicRow)
+	assert.Empty(t, plan.Reports[3].Missing)
+
+	assert.Equal(t, map[string]interface{}{"name": "High"}, plan.Rows[1].Fields["priority"], "coerced fields are kept")
+	assert.Equal(t, "PROJ-1", plan.Rows[9].Parent)
+	assert.Equal(t, [][]int{{0, 9}, {1, 3}, {2}}, plan.Levels)
+}
+
+func TestImportChunkReportsPartialFailures(t *testing.T) {
+	var updated map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/bulk", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			IssueUpdates []struct {
+				Fields map[string]interface{} `json:"fields"`
+			} `json:"issueUpdates"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.IssueUpdates, 2)
+		assert.Equal(t, map[string]interface{}{"key": "PROJ-1"}, body.IssueUpdates[0].Fields["parent"])
+		assert.Equal(t, "PROJ-5", body.IssueUpdates[0].Fields["customfield_10014"])
+		assert.Equal(t, map[string]interface{}{"id": "10001"}, body.IssueUpdates[1].Fields["issuetype"])
+
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"issues": []map[string]string{{"id": "100", "key": "PROJ-10"}},
+			"errors": []map[string]interface{}{{
+				"status":              400,
+				"failedElementNumber": 1,
+				"elementErrors":       map[string]interface{}{"errors": map[string]string{"summary": "Summary is too long"}},
+			}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/issue/PROJ-9", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		json.NewDecoder(r.Body).Decode(&updated)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := newFakeJiraClient(t, mux)
+
+	reports := client.ImportChunk(context.Background(), []jira.ImportRow{
+		{Row: 1, Project: "PROJ", IssueType: "Story", Parent: "PROJ-1", Epic: "PROJ-5", Fields: map[string]interface{}{"summary": "One"}},
+		{Row: 2, Key: "PROJ-9", Fields: map[string]interface{}{"summary": "Renamed"}},
+		{Row: 3, Project: "PROJ", IssueType: "10001", Fields: map[string]interface{}{"summary": strings.Repeat("x", 300)}},
+	}, "customfield_10014")
+
+	require.Len(t, reports, 3)
+	assert.Equal(t, jira.ImportRowCreated, reports[0].Status)
+	assert.Equal(t, "PROJ-10", reports[0].Key)
+	assert.Equal(t, jira.ImportRowUpdated, reports[1].Status)
+	assert.Equal(t, "Renamed", updated["fields"].(map[string]interface{})["summary"])
+	assert.Equal(t, jira.ImportRowFailed, reports[2].Status)
+	require.Len(t, reports[2].Errors, 1)
+	assert.Contains(t, reports[2].Errors[0], "Summary is too long")
+}
+
+// fakeImportClient creates one issue per row, numbering keys in call order
+type fakeImportClient struct {
+	mu      sync.Mutex
+	next    int
+	parents map[int]string
+}
+
+func (f *fakeImportClient) ImportChunk(ctx context.Context, rows []jira.ImportRow, epicLinkField string) []jira.ImportRowReport {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reports := make([]jira.ImportRowReport, len(rows))
+	for i, row := range rows {
+		f.next++
+		f.parents[row.Row] = row.Parent
+		reports[i] = jira.ImportRowReport{Row: row.Row, Action: "create", Status: jira.ImportRowCreated, Key: fmt.Sprintf("PROJ-%d", 100+f.next)}
+	}
+	return reports
+}
+
+func TestImportServiceRunsLevelsInOrder(t *testing.T) {
+	rows, err := jira.ParseImport(jira.ImportRequest{
+		Format: jira.ImportJSON,
+		Data: `[
+			{"ref": "epic", "summary": "Epic"},
+			{"summary": "Story 1", "parent": "epic"},
+			{"summary": "Story 2", "parent": "epic"},
+			{"summary": "Bad", "priority": "Urgent"}
+		]`,
+	})
+	require.NoError(t, err)
+	plan := jira.PlanImport(rows, jira.ImportRequest{Project: "PROJ", IssueType: "Story"}, importTestValidator)
+
+	client := &fakeImportClient{parents: make(map[int]string)}
+	var service *services.ImportService
+	var chunks [][]int
+	service = services.NewImportService(client, func(importID string, rows []int) error {
+		chunks = append(chunks, rows)
+		go service.RunChunk(context.Background(), importID, rows)
+		return nil
+	})
+
+	run, err := service.Start(plan, 1, "")
+	require.NoError(t, err)
+
+	var current *services.ImportRun
+	require.Eventually(t, func() bool {
+		current, _ = service.Get(run.ID)
+		return current.Finished != nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, services.ImportCompletedWithErrors, current.Status)
+	assert.Equal(t, services.ImportRunSummary{Total: 4, Created: 3, Skipped: 1}, current.Summary)
+	assert.Equal(t, "PROJ-101", current.Rows[0].Key)
+	assert.Equal(t, "PROJ-101", client.parents[2], "children receive the key created for their parent")
+	assert.Equal(t, "PROJ-101", client.parents[3])
+	assert.Len(t, chunks, 3)
+
+	_, ok := service.Get("missing")
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/jql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJQLBuilderOutput(t *testing.T) {
+	cases := []struct {
+		query    interface{ String() string }
+		expected string
+	}{
+		{
+			jql.Field("status").In("To Do", "In Progress").And(jql.Field("assignee").Eq(jql.CurrentUser())),
+			`status IN ("To Do", "In Progress") AND assignee = currentUser()`,
+		},
+		{
+			jql.Field("summary").Contains(`say "hi" \o/`),
+			`summary ~ "say \"hi\" \\o/"`,
+		},
+		{
+			jql.And(jql.Field("project").Eq("PROJ"), jql.Or(jql.Field("priority").Eq("High"), jql.Field("labels").IsEmpty())),
+			`project = PROJ AND (priority = High OR labels IS EMPTY)`,
+		},
+		{
+			jql.Not(jql.Field("status").Eq("Done").Or(jql.Field("status").Eq("Closed"))),
+			`NOT (status = Done OR status = Closed)`,
+		},
+		{
+			jql.Field("sprint").In(jql.OpenSprints()).OrderBy("rank", jql.Asc).OrderBy("Story Points", jql.Desc),
+			`sprint IN openSprints() ORDER BY rank ASC, "Story Points" DESC`,
+		},
+		{
+			jql.CustomField("customfield_10016").Gt(3).And(jql.Field("status").Eq("to")),
+			`cf[10016] > 3 AND status = "to"`,
+		},
+		{
+			jql.Field("status").Changed().History("from", "In Progress").During(jql.StartOfWeek("-1w"), jql.Now()),
+			`status CHANGED FROM "In Progress" DURING (startOfWeek(-1w), now())`,
+		},
+		{
+			jql.Field("created").Gte(time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)),
+			`created >= "2026-10-01 09:30"`,
+		},
+		{
+			jql.Where(jql.And()).OrderBy("created"),
+			`ORDER BY created`,
+		},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, tc.query.String())
+
+		// Builder output is already in the parser's canonical form
+		parsed, err := jira.ParseJQL(tc.query.String())
+		require.NoError(t, err, tc.expected)
+		assert.Equal(t, tc.expected, parsed.String())
+	}
+}
@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJQLCanonicalForm(t *testing.T) {
+	cases := map[string]string{
+		`project = PROJ and status in ("To Do", 'In Progress') order by created desc`: `project = PROJ AND status IN ("To Do", "In Progress") ORDER BY created DESC`,
+		`(assignee = currentUser() OR assignee is empty) AND priority=High`:           `(assignee = currentUser() OR assignee IS EMPTY) AND priority = High`,
+		`created >= -7d && text ~ "login bug"`:                                        `created >= -7d AND text ~ "login bug"`,
+		`not (status = Done or status = Closed)`:                                      `NOT (status = Done OR status = Closed)`,
+		`"Story Points" > 3 AND cf[10020] not in (1, 2)`:                              `"Story Points" > 3 AND cf[10020] NOT IN (1, 2)`,
+		`status was not in (Done) by currentUser() during ("2026-01-01", now())`:      `status WAS NOT IN (Done) BY currentUser() DURING ("2026-01-01", now())`,
+		`status changed from "In Progress" to Done after -1w`:                         `status CHANGED FROM "In Progress" TO Done AFTER -1w`,
+		`ORDER BY rank`: `ORDER BY rank`,
+		``:              ``,
+	}
+
+	for input, expected := range cases {
+		query, err := jira.ParseJQL(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, query.String(), input)
+
+		// The canonical form is a fixed point
+		reparsed, err := jira.ParseJQL(query.String())
+		require.NoError(t, err)
+		assert.Equal(t, expected, reparsed.String())
+	}
+}
+
+func TestParseJQLErrorPositions(t *testing.T) {
+	cases := []struct {
+		jql    string
+		line   int
+		column int
+	}{
+		{`project = `, 1, 11},
+		{`project = PROJ AND`, 1, 19},
+		{`status in (Open, "Done"`, 1, 24},
+		{"project = PROJ\n  AND summary ~ \"unterminated", 2, 17},
+		{`project PROJ`, 1, 9},
+		{`project = PROJ ORDER created`, 1, 16},
+	}
+
+	for _, tc := range cases {
+		_, err := jira.ParseJQL(tc.jql)
+		require.Error(t, err, tc.jql)
+
+		jqlErr, ok := err.(*jira.JQLError)
+		require.True(t, ok, tc.jql)
+		assert.Equal(t, tc.line, jqlErr.Pos.Line, "%q: %v", tc.jql, err)
+		assert.Equal(t, tc.column, jqlErr.Pos.Column, "%q: %v", tc.jql, err)
+	}
+}
+
+func TestJQLValidatorChecksFieldsOperatorsAndFunctions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/jql/autocompletedata", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"visibleFieldNames": []map[string]interface{}{
+				{"value": "project", "displayName": "project", "orderable": "true", "searchable": "true",
+					"operators": []string{"=", "!=", "in", "not in", "is", "is not"}},
+				{"value": "assignee", "displayName": "assignee", "orderable": "true", "searchable": "true",
+					"operators": []string{"=", "!=", "in", "not in", "is", "is not", "was", "changed"}},
+				{"value": "summary", "displayName": "summary", "orderable": "true", "searchable": "true",
+					"operators": []string{"~", "!~", "is", "is not"}},
+				{"value": "\"Story Points\"", "displayName": "Story Points - cf[10016]", "cfid": "cf[10016]",
+					"orderable": "true", "searchable": "true", "types": []string{"java.lang.Number"}},
+				{"value": "comment", "displayName": "comment", "orderable": "false", "searchable": "true",
+					"operators": []string{"~", "!~"}},
+			},
+			"visibleFunctionNames": []map[string]interface{}{
+				{"value": "currentUser()", "displayName": "currentUser()"},
+				{"value": "membersOf()", "displayName": "membersOf()", "isList": "true"},
+			},
+		})
+	})
+
+	client := newFakeJiraClient(t, mux)
+	data, err := client.GetJQLAutocompleteData()
+	require.NoError(t, err)
+	validator := jira.NewJQLValidator(data)
+
+	query, errs := validator.Validate(`project = PROJ AND assignee in membersOf("devs") AND cf[10016] >= 3 AND "story points" < 8 ORDER BY summary`)
+	require.NotNil(t, query)
+	assert.Empty(t, errs)
+
+	_, errs = validator.Validate(`projekt = PROJ AND summary = "x" AND assignee = membersOf() AND assignee in currentUser() AND assignee = nowhere() ORDER BY comment`)
+	require.Len(t, errs, 7)
+	assert.Contains(t, errs[0].Message, "'projekt' does not exist")
+	assert.Equal(t, 1, errs[0].Pos.Column)
+	assert.Contains(t, errs[1].Message, "operator '=' cannot be used with field 'summary'")
+	assert.Equal(t, 28, errs[1].Pos.Column)
+	assert.Contains(t, errs[2].Message, "list function membersOf()")
+	assert.Contains(t, errs[3].Message, "expects 1 argument but got 0")
+	assert.Contains(t, errs[4].Message, "requires a list")
+	assert.Contains(t, errs[5].Message, "'nowhere' does not exist")
+	assert.Contains(t, errs[6].Message, "cannot be used in ORDER BY")
+
+	query, errs = validator.Validate(`project = `)
+	assert.Nil(t, query)
+	require.Len(t, errs, 1)
+	assert.Equal(t, 11, errs[0].Pos.Column)
+}
@@ -0,0 +1,463 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/auth"
+	"github.com/ericfisherdev/GoJira/internal/config"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/mirror"
+	"github.com/ericfisherdev/GoJira/internal/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJira serves a small Jira project and can be switched to answering 503,
+// as a proxy in front of an unreachable Jira would
+type fakeJira struct {
+	*http.ServeMux
+	down     atomic.Bool
+	mu       sync.Mutex
+	searches []string
+	comments []string
+}
+
+func newFakeJira() *fakeJira {
+	f := &fakeJira{ServeMux: http.NewServeMux()}
+	f.HandleFunc("/rest/api/2/serverInfo", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"version": "9.0.0"})
+	})
+	f.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var req jira.SearchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.mu.Lock()
+		f.searches = append(f.searches, req.JQL)
+		f.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"startAt": 0, "maxResults": 100, "total": 2,
+			"issues": []map[string]interface{}{
+				mirrorIssueJSON("PROJ-1", "Login fails on Safari", "In Progress", "2024-03-01T10:00:00.000+0000",
+					map[string]interface{}{"total": 2, "comments": []map[string]interface{}{{"id": "1", "body": "Seen on 17.2"}}}),
+				mirrorIssueJSON("PROJ-2", "Export to CSV", "Done", "2024-03-02T10:00:00.000+0000",
+					map[string]interface{}{"total": 0, "comments": []map[string]interface{}{}}),
+			},
+		})
+	})
+	f.HandleFunc("/rest/api/2/issue/PROJ-1/comment", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			f.mu.Lock()
+			f.comments = append(f.comments, fmt.Sprint(body["body"]))
+			f.mu.Unlock()
+			writeJSON(w, http.StatusCreated, map[string]interface{}{"id": "99", "body": body["body"]})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"startAt": 0, "maxResults": 100, "total": 2,
+			"comments": []map[string]interface{}{
+				{"id": "1", "body": "Seen on 17.2"},
+				{"id": "2", "body": "Also on iOS"},
+			},
+		})
+	})
+	f.HandleFunc("/rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"values": []map[string]interface{}{
+				{"id": 1, "name": "PROJ board", "type": "scrum", "location": map[string]interface{}{"projectKey": "PROJ"}},
+				{"id": 2, "name": "Other board", "type": "kanban", "location": map[string]interface{}{"projectKey": "OTHER"}},
+			},
+		})
+	})
+	f.HandleFunc("/rest/agile/1.0/board/1/sprint", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"values": []map[string]interface{}{{"id": 7, "name": "Sprint 7", "state": "active"}},
+		})
+	})
+	return f
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.down.Load() {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	f.ServeMux.ServeHTTP(w, r)
+}
+
+func mirrorIssueJSON(key, summary, status, updated string, comments map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"key": key,
+		"fields": map[string]interface{}{
+			"summary":   summary,
+			"status":    map[string]interface{}{"name": status},
+			"project":   map[string]interface{}{"key": "PROJ"},
+			"issuetype": map[string]interface{}{"name": "Bug"},
+			"labels":    []string{"web"},
+			"updated":   updated,
+			"comment":   comments,
+		},
+	}
+}
+
+// validAuthenticator is always authenticated
+type validAuthenticator struct{}
+
+func (validAuthenticator) Authenticate(ctx context.Context) error { return nil }
+func (validAuthenticator) GetHeaders() map[string]string          { return map[string]string{} }
+func (validAuthenticator) Refresh(ctx context.Context) error      { return nil }
+func (validAuthenticator) IsValid() bool                          { return true }
+func (validAuthenticator) GetUser() (*auth.User, error)           { return &auth.User{}, nil }
+func (validAuthenticator) Type() string                           { return "test" }
+
+func TestMirrorSyncsProjectsIncrementally(t *testing.T) {
+	fake := newFakeJira()
+	client := newFakeJiraClient(t, fake.ServeMux)
+
+	dir := t.TempDir()
+	store, err := mirror.Open(dir)
+	require.NoError(t, err)
+	syncer := mirror.NewSyncer(client, store, mirror.Options{Projects: []string{"proj"}, Location: time.UTC})
+
+	require.NoError(t, syncer.Sync(context.Background()))
+
+	issue, ok := store.Issue("proj-1")
+	require.True(t, ok)
+	assert.Equal(t, "Login fails on Safari", issue.Fields.Summary)
+	require.NotNil(t, issue.Fields.Comment)
+	assert.Len(t, issue.Fields.Comment.Comments, 2, "truncated comments are fetched in full")
+
+	project, ok := store.Project("PROJ")
+	require.True(t, ok)
+	require.NotNil(t, project.LastSync)
+	assert.Len(t, project.Boards, 1)
+	assert.Len(t, project.Sprints, 1)
+
+	// The next sync only asks for issues updated since the last one
+	require.NoError(t, syncer.Sync(context.Background()))
+	require.Len(t, fake.searches, 2)
+	assert.NotContains(t, fake.searches[0], "updated >=")
+	assert.Contains(t, fake.searches[1], "project = PROJ AND updated >= ")
+	assert.Contains(t, fake.searches[1], "ORDER BY updated ASC")
+
+	status := syncer.Status()
+	assert.True(t, status.Online)
+	require.Len(t, status.Projects, 1)
+	assert.Equal(t, 2, status.Projects[0].Issues)
+	assert.Equal(t, 2, status.Store.Comments)
+
+	// The mirror survives a restart
+	reopened, err := mirror.Open(dir)
+	require.NoError(t, err)
+	assert.Len(t, reopened.Issues("PROJ"), 2)
+	assert.NotNil(t, reopened.StaleAsOf("PROJ"))
+}
+
+func TestMirrorSearchEvaluatesJQL(t *testing.T) {
+	store, err := mirror.Open(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	issues := []jira.Issue{
+		{Key: "PROJ-1", Fields: jira.IssueFields{Summary: "Login fails on Safari", Labels: []string{"web"},
+			Status: &jira.Status{Name: "In Progress"}, Updated: &jira.JiraTime{Time: now.Add(-time.Hour)}}},
+		{Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Export to CSV", Labels: []string{"api"},
+			Status: &jira.Status{Name: "Done"}, Updated: &jira.JiraTime{Time: now.AddDate(0, 0, -10)}}},
+		{Key: "PROJ-10", Fields: jira.IssueFields{Summary: "Login page layout", Labels: []string{"web", "ui"},
+			Status: &jira.Status{Name: "To Do"}, Updated: &jira.JiraTime{Time: now.Add(-2 * time.Hour)}}},
+		{Key: "OTHER-1", Fields: jira.IssueFields{Summary: "Login audit", Status: &jira.Status{Name: "To Do"},
+			Updated: &jira.JiraTime{Time: now}}},
+	}
+	require.NoError(t, store.PutIssues(issues))
+
+	keys := func(jql string) []string {
+		t.Helper()
+		result, err := store.Search(jql, 0, 50)
+		require.NoError(t, err, jql)
+		var found []string
+		for _, issue := range result.Issues {
+			found = append(found, issue.Key)
+		}
+		return found
+	}
+
+	assert.Equal(t, []string{"PROJ-1", "PROJ-2", "PROJ-10"}, keys("project = PROJ ORDER BY key ASC"))
+	assert.Equal(t, []string{"OTHER-1", "PROJ-1", "PROJ-10", "PROJ-2"}, keys("ORDER BY updated DESC"))
+	assert.Equal(t, []string{"PROJ-1", "PROJ-10"}, keys(`project = PROJ AND summary ~ "login" ORDER BY key`))
+	assert.Equal(t, []string{"PROJ-10"}, keys(`labels in (ui) OR status = "Done" AND labels = web`))
+	assert.Equal(t, []string{"PROJ-2"}, keys(`project = PROJ AND NOT status in ("In Progress", "To Do")`))
+	assert.Equal(t, []string{"OTHER-1", "PROJ-1", "PROJ-10"}, keys("updated >= -1w ORDER BY key"))
+	assert.Equal(t, []string{"OTHER-1"}, keys("labels is EMPTY"))
+
+	page, err := store.Search("project = PROJ ORDER BY key", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Issues, 1)
+	assert.Equal(t, "PROJ-2", page.Issues[0].Key)
+
+	// Queries the mirror cannot answer faithfully are refused
+	for _, jql := range []string{"sprint in openSprints()", "assignee = currentUser()", `cf[10010] = 3`} {
+		_, err := store.Search(jql, 0, 50)
+		assert.True(t, errors.Is(err, mirror.ErrUnsupportedQuery), jql)
+	}
+}
+
+func TestOutboxReplaysInOrderAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	outbox, err := queue.NewOutbox(dir)
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		_, err := outbox.Add(queue.Job{Type: queue.JobTypeJiraWrite, Payload: map[string]interface{}{"name": name}})
+		require.NoError(t, err)
+	}
+
+	// Held jobs survive a restart, in order
+	outbox, err = queue.NewOutbox(dir)
+	require.NoError(t, err)
+	require.Equal(t, 4, outbox.Pending())
+
+	var ran []string
+	unreachable := true
+	q := queue.NewJobQueue(queue.QueueConfig{MaxWorkers: 1, MaxQueueSize: 10})
+	q.RegisterHandler(queue.JobTypeJiraWrite, func(ctx context.Context, job queue.Job) (interface{}, error) {
+		name := job.Payload.(map[string]interface{})["name"].(string)
+		switch {
+		case name == "second":
+			return nil, queue.Permanent(fmt.Errorf("issue does not exist"))
+		case name == "third" && unreachable:
+			return nil, fmt.Errorf("jira API error: 503 503 Service Unavailable")
+		}
+		ran = append(ran, name)
+		return name, nil
+	})
+
+	replay := outbox.Replay(context.Background(), q)
+	assert.Equal(t, 1, replay.Replayed)
+	assert.Equal(t, 1, replay.Failed)
+	assert.Equal(t, 2, replay.Remaining)
+	assert.Contains(t, replay.Error, "503")
+	assert.Equal(t, []string{"first"}, ran, "later jobs never overtake a job that could not run")
+
+	unreachable = false
+	replay = outbox.Replay(context.Background(), q)
+	assert.Equal(t, 2, replay.Replayed)
+	assert.Equal(t, 0, replay.Remaining)
+	assert.Equal(t, []string{"first", "third", "fourth"}, ran)
+
+	// The permanent failure is kept for inspection until discarded
+	entries := outbox.Entries()
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].Failed)
+	assert.Contains(t, entries[0].LastError, "does not exist")
+	require.NoError(t, outbox.Discard(entries[0].Job.ID))
+
+	outbox, err = queue.NewOutbox(dir)
+	require.NoError(t, err)
+	assert.Empty(t, outbox.Entries())
+}
+
+func TestOfflineModeServesMirrorAndHoldsWrites(t *testing.T) {
+	srv := setupTestServer(t)
+	manager := auth.NewManager(nil)
+	manager.AddAuthenticator("test", validAuthenticator{})
+	require.NoError(t, manager.SetCurrent("test"))
+	handlers.SetAuthManager(manager)
+
+	fake := newFakeJira()
+	jiraServer := httptest.NewServer(fake)
+	t.Cleanup(jiraServer.Close)
+	handlers.SetJiraClient(jira.NewClient(jiraServer.URL, nil, &jira.ClientOptions{Timeout: 5 * time.Second}))
+	require.NoError(t, handlers.SetOfflineMirror(t.TempDir(), mirror.Options{Projects: []string{"PROJ"}}))
+	t.Cleanup(func() {
+		handlers.SetOfflineMirror("", mirror.Options{})
+		handlers.SetJiraClient(nil)
+		handlers.SetAuthManager(auth.NewManager(nil))
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) map[string]interface{} {
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+		return body
+	}
+
+	w := do("POST", "/api/v1/mirror/sync?wait=true", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	fake.down.Store(true)
+
+	// The first failed read switches to the mirror
+	w = do("GET", "/api/v1/issues/PROJ-1", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotEmpty(t, w.Header().Get("X-GoJira-Stale-As-Of"))
+	body := decode(w)
+	assert.Equal(t, true, body["offline"])
+	assert.NotEmpty(t, body["staleAsOf"])
+	assert.Equal(t, "PROJ-1", body["data"].(map[string]interface{})["key"])
+
+	w = do("GET", "/api/v1/search?jql="+strings.ReplaceAll(`project = PROJ AND status = "Done"`, " ", "+"), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	body = decode(w)
+	assert.Equal(t, true, body["offline"])
+	assert.Equal(t, float64(1), body["data"].(map[string]interface{})["total"])
+
+	w = do("GET", "/api/v1/search?jql=sprint+in+openSprints()", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = do("GET", "/api/v1/issues/PROJ-404", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = do("POST", "/api/v1/claude/search", `{"jql": "project = PROJ"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	body = decode(w)
+	assert.Contains(t, body["summary"], "stale as of")
+	assert.NotEmpty(t, body["metadata"].(map[string]interface{})["staleAsOf"])
+
+	// Writes are held in the outbox
+	w = do("POST", "/api/v1/issues/PROJ-1/comments", `{"body": "Written offline"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	data := decode(w)["data"].(map[string]interface{})
+	assert.Equal(t, "queued_offline", data["status"])
+	assert.Equal(t, "comment", data["operation"])
+
+	w = do("GET", "/api/v1/mirror/outbox", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, float64(1), decode(w)["data"].(map[string]interface{})["pending"])
+
+	w = do("GET", "/health", "")
+	health := decode(w)
+	assert.Equal(t, false, health["mirror"].(map[string]interface{})["online"])
+
+	// Once Jira answers, the held writes are replayed before syncing
+	fake.down.Store(false)
+	w = do("POST", "/api/v1/mirror/sync?wait=true", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"Written offline"}, fake.comments)
+
+	w = do("GET", "/api/v1/mirror", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	data = decode(w)["data"].(map[string]interface{})
+	assert.Equal(t, true, data["mirror"].(map[string]interface{})["online"])
+	assert.Equal(t, float64(0), data["outbox"].(map[string]interface{})["pending"])
+}
+
+func TestOfflineWritesResolveAssigneeOnReplay(t *testing.T) {
+	srv := setupTestServer(t)
+	manager := auth.NewManager(nil)
+	manager.AddAuthenticator("test", validAuthenticator{})
+	require.NoError(t, manager.SetCurrent("test"))
+	handlers.SetAuthManager(manager)
+
+	const janeID = "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077"
+	fake := newFakeJira()
+	var writes []map[string]interface{}
+	fake.HandleFunc("/rest/api/2/user/assignable/search", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"accountId": janeID, "displayName": "Jane Doe", "emailAddress": "jane@example.com", "active": true},
+		})
+	})
+	recordWrite := func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		fake.mu.Lock()
+		writes = append(writes, body["fields"].(map[string]interface{}))
+		fake.mu.Unlock()
+		if r.Method == http.MethodPost {
+			writeJSON(w, http.StatusCreated, map[string]interface{}{"id": "10003", "key": "PROJ-3"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	fake.HandleFunc("/rest/api/2/issue", recordWrite)
+	fake.HandleFunc("/rest/api/2/issue/PROJ-1", recordWrite)
+
+	jiraServer := httptest.NewServer(fake)
+	t.Cleanup(jiraServer.Close)
+	handlers.SetJiraClient(jira.NewClient(jiraServer.URL, nil, &jira.ClientOptions{Timeout: 5 * time.Second}))
+	require.NoError(t, handlers.SetOfflineMirror(t.TempDir(), mirror.Options{Projects: []string{"PROJ"}}))
+	t.Cleanup(func() {
+		handlers.SetOfflineMirror("", mirror.Options{})
+		handlers.SetJiraClient(nil)
+		handlers.SetAuthManager(auth.NewManager(nil))
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/v1/mirror/sync?wait=true", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	fake.down.Store(true)
+	w = do("GET", "/api/v1/issues/PROJ-1", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The assignee cannot be looked up while Jira is unreachable
+	w = do("POST", "/api/v1/issues", `{"project": "PROJ", "summary": "Written offline", "issueType": "Task", "assignee": "jane@example.com"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	w = do("PUT", "/api/v1/issues/PROJ-1", `{"assignee": "jane@example.com"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	fake.down.Store(false)
+	w = do("POST", "/api/v1/mirror/sync?wait=true", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	require.Len(t, writes, 2)
+	assert.Equal(t, "Written offline", writes[0]["summary"])
+	for _, fields := range writes {
+		assert.Equal(t, map[string]interface{}{"accountId": janeID}, fields["assignee"])
+	}
+}
+
+func TestOfflineMirrorFromConfig(t *testing.T) {
+	srv := setupTestServer(t)
+	jiraServer := httptest.NewServer(newFakeJira())
+	t.Cleanup(jiraServer.Close)
+	handlers.SetJiraClient(jira.NewClient(jiraServer.URL, nil, &jira.ClientOptions{Timeout: 5 * time.Second}))
+	t.Cleanup(func() {
+		handlers.SetOfflineMirror("", mirror.Options{})
+		handlers.SetJiraClient(nil)
+	})
+
+	require.NoError(t, handlers.Configure(&config.Config{Mirror: config.MirrorConfig{
+		Dir:      t.TempDir(),
+		Projects: []string{"proj"},
+		Interval: 15,
+	}}))
+
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/mirror/sync?wait=true", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Data mirror.Status `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data.Projects, 1)
+	assert.Equal(t, "PROJ", response.Data.Projects[0].Project)
+	assert.Equal(t, 2, response.Data.Store.Issues)
+}
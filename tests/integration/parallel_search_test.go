@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAllPagesParallelMergesInOrder(t *testing.T) {
+	var (
+		mu                sync.Mutex
+		inFlight, maxSeen int
+		requests          int32
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		var req jira.SearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// Later pages answer first, so the merge cannot rely on arrival order
+		time.Sleep(time.Duration(50-req.StartAt/10) * time.Millisecond)
+
+		issues := []map[string]interface{}{}
+		for i := req.StartAt; i < 95 && i < req.StartAt+req.MaxResults; i++ {
+			issues = append(issues, map[string]interface{}{"id": fmt.Sprint(i + 1), "key": fmt.Sprintf("PROJ-%d", i+1)})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"startAt": req.StartAt, "maxResults": req.MaxResults, "total": 95, "issues": issues,
+		})
+	})
+	client := newFakeJiraClient(t, mux)
+
+	result, err := client.SearchAllPagesParallel(context.Background(), jira.SearchRequest{JQL: "project = PROJ", MaxResults: 10},
+		jira.ParallelSearchOptions{Concurrency: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, result.Pages)
+	assert.Equal(t, 0, result.Duplicates)
+	require.Len(t, result.Issues, 95)
+	for i, issue := range result.Issues {
+		assert.Equal(t, fmt.Sprintf("PROJ-%d", i+1), issue.Key)
+	}
+	assert.EqualValues(t, 10, atomic.LoadInt32(&requests))
+	assert.LessOrEqual(t, maxSeen, 3)
+	assert.Nil(t, result.SnapshotAt)
+}
+
+func TestSearchAllPagesParallelDedupesShiftedIssues(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var req jira.SearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// An issue was added ahead of the second page, pushing PROJ-2 onto it
+		pages := map[int][]string{0: {"PROJ-1", "PROJ-2"}, 2: {"PROJ-2", "PROJ-3"}, 4: {"PROJ-4"}}
+		issues := []map[string]interface{}{}
+		for _, key := range pages[req.StartAt] {
+			issues = append(issues, map[string]interface{}{"key": key})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"startAt": req.StartAt, "maxResults": req.MaxResults, "total": 5, "issues": issues,
+		})
+	})
+	client := newFakeJiraClient(t, mux)
+
+	result, err := client.SearchAllPagesParallel(context.Background(), jira.SearchRequest{JQL: "project = PROJ", MaxResults: 2},
+		jira.ParallelSearchOptions{})
+	require.NoError(t, err)
+
+	keys := make([]string, len(result.Issues))
+	for i, issue := range result.Issues {
+		keys[i] = issue.Key
+	}
+	assert.Equal(t, []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"}, keys)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.NotEmpty(t, result.WarningMessages)
+}
+
+func TestSearchAllPagesParallelSnapshotAndErrors(t *testing.T) {
+	var seenJQL []string
+	var mu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var req jira.SearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		seenJQL = append(seenJQL, req.JQL)
+		mu.Unlock()
+
+		if req.StartAt == 20 {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{"boom"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"startAt": req.StartAt, "maxResults": req.MaxResults, "total": 40,
+			"issues": []map[string]interface{}{{"key": fmt.Sprintf("PROJ-%d", req.StartAt+1)}},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"accountId": "me", "timeZone": "Pacific/Auckland"})
+	})
+	client := newFakeJiraClient(t, mux)
+
+	started := time.Now()
+	_, err := client.SearchAllPagesParallel(context.Background(),
+		jira.SearchRequest{JQL: "project = PROJ OR labels = x ORDER BY created DESC", MaxResults: 10},
+		jira.ParallelSearchOptions{Snapshot: true, Concurrency: 3})
+	require.Error(t, err)
+	// Pages cancelled after page 3 failed do not mask its error
+	assert.Contains(t, err.Error(), "failed to fetch page 3")
+	assert.NotContains(t, err.Error(), "context canceled")
+
+	// Cancelled pages may still be in flight on the server
+	mu.Lock()
+	queries := append([]string(nil), seenJQL...)
+	mu.Unlock()
+
+	// Every page is pinned to the same bound and keeps the original ordering
+	require.NotEmpty(t, queries)
+	for _, query := range queries {
+		assert.Equal(t, queries[0], query)
+	}
+	prefix := `(project = PROJ OR labels = x) AND updated <= "`
+	require.True(t, strings.HasPrefix(queries[0], prefix), queries[0])
+	assert.True(t, strings.HasSuffix(queries[0], " ORDER BY created DESC"), queries[0])
+
+	// The bound is the next minute in the Jira user's time zone
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	require.NoError(t, err)
+	bound, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimPrefix(queries[0], prefix)[:16], auckland)
+	require.NoError(t, err)
+	assert.False(t, bound.Before(started), "bound %s is before the search started", bound)
+	assert.WithinDuration(t, started, bound, time.Minute)
+}
@@ -315,14 +315,14 @@ func TestMemoryCacheFeatures(t *testing.T) {
 		assert.Equal(t, 3, memCache.Size())
 		
 		// First entries should be evicted
-		_, exists := memCache.Get("key-0")
-		assert.False(t, exists)
-		_, exists = memCache.Get("key-1")
-		assert.False(t, exists)
+		_, err := memCache.Get("key-0")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		_, err = memCache.Get("key-1")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
 		
 		// Recent entries should still exist
-		_, exists = memCache.Get("key-4")
-		assert.True(t, exists)
+		_, err = memCache.Get("key-4")
+		assert.NoError(t, err)
 	})
 
 	t.Run("MemoryCache_Expiration", func(t *testing.T) {
@@ -334,15 +334,15 @@ func TestMemoryCacheFeatures(t *testing.T) {
 		require.NoError(t, err)
 		
 		// Should exist immediately
-		_, exists := memCache.Get("expire-test")
-		assert.True(t, exists)
+		_, err = memCache.Get("expire-test")
+		assert.NoError(t, err)
 		
 		// Wait for expiration
 		time.Sleep(100 * time.Millisecond)
 		
 		// Should be expired
-		_, exists = memCache.Get("expire-test")
-		assert.False(t, exists)
+		_, err = memCache.Get("expire-test")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
 	})
 }
 
@@ -370,7 +370,7 @@ func TestDiskCacheFeatures(t *testing.T) {
 		assert.NoError(t, err)
 		
 		_, err = diskCache.Get(key)
-		assert.Error(t, err) // Should not exist after delete
+		assert.ErrorIs(t, err, cache.ErrCacheMiss) // Should not exist after delete
 	})
 }
 
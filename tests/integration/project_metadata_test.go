@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/auth"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeJiraClient starts an httptest server backed by mux and returns a client pointed at it
+func newFakeJiraClient(t *testing.T, mux *http.ServeMux) *jira.Client {
+	t.Helper()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return jira.NewClient(server.URL, nil, &jira.ClientOptions{Timeout: 5 * time.Second})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestProjectComponentsAndVersions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/project/PROJ/components", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"id": "10", "name": "Backend"},
+			{"id": "11", "name": "Legacy", "archived": true},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/project/PROJ/versions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"id": "20", "name": "1.0", "released": true, "releaseDate": "2026-01-15"},
+			{"id": "21", "name": "1.1"},
+		})
+	})
+
+	client := newFakeJiraClient(t, mux)
+	ctx := context.Background()
+
+	components, err := client.GetProjectComponents(ctx, "PROJ")
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+	assert.True(t, components[1].Archived)
+
+	versions, err := client.GetProjectVersions(ctx, "PROJ")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.NotNil(t, versions[0].ReleaseDate)
+	assert.Equal(t, 2026, versions[0].ReleaseDate.Year())
+}
+
+func TestReleaseVersionUnresolvedIssues(t *testing.T) {
+	var released map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/version/21/relatedIssueCounts", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]int{"issuesFixedCount": 5})
+	})
+	mux.HandleFunc("/rest/api/2/version/21/unresolvedIssueCount", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]int{"issuesUnresolvedCount": 2})
+	})
+	mux.HandleFunc("/rest/api/2/version/22", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": "22", "name": "1.2"})
+	})
+	mux.HandleFunc("/rest/api/2/version/21", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		json.NewDecoder(r.Body).Decode(&released)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": "21", "name": "1.1", "released": true})
+	})
+
+	client := newFakeJiraClient(t, mux)
+	ctx := context.Background()
+
+	_, err := client.ReleaseVersion(ctx, "21", jira.ReleaseVersionOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 unresolved issues")
+
+	version, err := client.ReleaseVersion(ctx, "21", jira.ReleaseVersionOptions{MoveUnfixedIssuesTo: "22"})
+	require.NoError(t, err)
+	assert.True(t, version.Released)
+	assert.Equal(t, true, released["released"])
+	assert.Contains(t, released["moveUnfixedIssuesTo"], "/rest/api/2/version/22")
+}
+
+func TestGetProjectRoles(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/project/PROJ/role", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"Developers":     "http://jira/rest/api/2/project/PROJ/role/10001",
+			"Administrators": "http://jira/rest/api/2/project/PROJ/role/10002",
+			"Users":          "http://jira/rest/api/2/project/PROJ/role/10000",
+		})
+	})
+	mux.HandleFunc("/rest/api/2/project/PROJ/role/10001", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":   10001,
+			"name": "Developers",
+			"actors": []map[string]interface{}{
+				{"id": 1, "displayName": "Sam Park", "type": "atlassian-user-role-actor", "actorUser": map[string]string{"accountId": "abc"}},
+			},
+		})
+	})
+
+	client := newFakeJiraClient(t, mux)
+	ctx := context.Background()
+
+	roles, err := client.GetProjectRoles(ctx, "PROJ")
+	require.NoError(t, err)
+	require.Len(t, roles, 3)
+	// Roles are ordered by ID, not by Jira's map order
+	ids := []int{roles[0].ID, roles[1].ID, roles[2].ID}
+	assert.Equal(t, []int{10000, 10001, 10002}, ids)
+	assert.Equal(t, "Developers", roles[1].Name)
+
+	role, err := client.GetProjectRole(ctx, "PROJ", roles[1].ID)
+	require.NoError(t, err)
+	require.Len(t, role.Actors, 1)
+	assert.Equal(t, "abc", role.Actors[0].ActorUser.AccountID)
+}
+
+func TestProjectMetadataFeedsNLPParser(t *testing.T) {
+	srv := setupTestServer(t)
+	manager := auth.NewManager(nil)
+	manager.AddAuthenticator("test", validAuthenticator{})
+	require.NoError(t, manager.SetCurrent("test"))
+	handlers.SetAuthManager(manager)
+
+	// Names are recorded per project, so every run uses its own project
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+	projectKey := "NLP" + strings.ToUpper(suffix)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/project/"+projectKey+"/components", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"id": "10", "name": "Backend-" + suffix},
+			{"id": "11", "name": "Legacy-" + suffix, "archived": true},
+		})
+	})
+	mux.HandleFunc("/rest/api/2/project/"+projectKey+"/versions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"id": "20", "name": "1.0-" + suffix, "released": true},
+			{"id": "21", "name": "1.1-" + suffix},
+		})
+	})
+	handlers.SetJiraClient(newFakeJiraClient(t, mux))
+	t.Cleanup(func() {
+		handlers.SetJiraClient(nil)
+		handlers.SetAuthManager(auth.NewManager(nil))
+	})
+
+	get := func(path string) map[string]interface{} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+	cacheStats := func() map[string]interface{} {
+		return get("/api/v1/nlp/status")["cacheStats"].(map[string]interface{})
+	}
+
+	before := cacheStats()
+	get("/api/v1/projects/" + projectKey + "/components")
+	get("/api/v1/projects/" + projectKey + "/versions")
+	after := cacheStats()
+
+	// Archived components are not offered to the parser
+	assert.Equal(t, before["components"].(float64)+1, after["components"])
+	assert.Equal(t, before["versions"].(float64)+2, after["versions"])
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRankMux(t *testing.T, backlog []string, requests *[]jira.RankIssuesRequest) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/agile/1.0/issue/rank", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		var req jira.RankIssuesRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/rest/agile/1.0/board/7/backlog", func(w http.ResponseWriter, r *http.Request) {
+		issues := make([]map[string]interface{}, len(backlog))
+		for i, key := range backlog {
+			issues[i] = map[string]interface{}{"key": key}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"total": len(backlog), "issues": issues})
+	})
+	return mux
+}
+
+func TestRankIssuesBatchesKeepOrder(t *testing.T) {
+	var requests []jira.RankIssuesRequest
+	client := newFakeJiraClient(t, newRankMux(t, nil, &requests))
+
+	keys := make([]string, 120)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("PROJ-%d", i+1)
+	}
+
+	result, err := client.RankBefore(keys, "PROJ-500")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Batches)
+	assert.Equal(t, 120, result.Ranked)
+
+	require.Len(t, requests, 3)
+	assert.Len(t, requests[0].Issues, 50)
+	assert.Equal(t, "PROJ-500", requests[0].RankBeforeIssue)
+	assert.Equal(t, "PROJ-50", requests[1].RankAfterIssue)
+	assert.Empty(t, requests[1].RankBeforeIssue)
+	assert.Equal(t, "PROJ-100", requests[2].RankAfterIssue)
+	assert.Len(t, requests[2].Issues, 20)
+}
+
+func TestRankInBacklogPositions(t *testing.T) {
+	backlog := []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"}
+
+	tests := []struct {
+		name   string
+		rank   func(c *jira.Client) (*jira.RankResult, error)
+		before string
+		after  string
+	}{
+		{"top", func(c *jira.Client) (*jira.RankResult, error) { return c.RankToTop(7, []string{"PROJ-3"}) }, "PROJ-1", ""},
+		{"top when already first", func(c *jira.Client) (*jira.RankResult, error) { return c.RankToTop(7, []string{"PROJ-1"}) }, "PROJ-2", ""},
+		{"bottom", func(c *jira.Client) (*jira.RankResult, error) { return c.RankToBottom(7, []string{"PROJ-4", "PROJ-1"}) }, "", "PROJ-3"},
+		{"index", func(c *jira.Client) (*jira.RankResult, error) { return c.RankInBacklog(7, []string{"PROJ-1"}, 2) }, "PROJ-4", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []jira.RankIssuesRequest
+			client := newFakeJiraClient(t, newRankMux(t, backlog, &requests))
+
+			_, err := tt.rank(client)
+			require.NoError(t, err)
+			require.Len(t, requests, 1)
+			assert.Equal(t, tt.before, requests[0].RankBeforeIssue)
+			assert.Equal(t, tt.after, requests[0].RankAfterIssue)
+		})
+	}
+}
+
+func TestRankEndpointReportsFailedIssues(t *testing.T) {
+	srv := setupTestServer(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/agile/1.0/issue/rank", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusMultiStatus, map[string]interface{}{
+			"entries": []map[string]interface{}{
+				{"issueId": 1, "issueKey": "PROJ-1", "status": 200},
+				{"issueId": 2, "issueKey": "PROJ-2", "status": 403, "errors": []string{"No permission"}},
+			},
+		})
+	})
+	handlers.SetJiraClient(newFakeJiraClient(t, mux))
+	t.Cleanup(func() { handlers.SetJiraClient(nil) })
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/issues/rank",
+		strings.NewReader(`{"issues":["PROJ-1","PROJ-2"],"rankBeforeIssue":"PROJ-9"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, false, body["success"])
+	assert.Equal(t, []interface{}{"PROJ-2"}, body["failedIssues"])
+	assert.Equal(t, "1 of 2 issues could not be ranked", body["message"])
+}
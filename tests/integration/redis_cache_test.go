@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"encoding/gob"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/cache/resptest"
+	"github.com/ericfisherdev/GoJira/internal/config"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cachedIssueSummary struct {
+	Key    string
+	Labels []string
+}
+
+func init() {
+	gob.Register(cachedIssueSummary{})
+}
+
+func newTestRedisCache(t *testing.T, server *resptest.Server, opts cache.RedisOptions) *cache.RedisCache {
+	t.Helper()
+	opts.Addr = server.Addr
+	rc, err := cache.NewRedisCache(opts)
+	require.NoError(t, err)
+	t.Cleanup(rc.Stop)
+	return rc
+}
+
+func TestRedisCacheOperations(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	server.RequirePassword("s3cret")
+
+	_, err := cache.NewRedisCache(cache.RedisOptions{Addr: server.Addr, Password: "wrong"})
+	require.Error(t, err)
+
+	rc := newTestRedisCache(t, server, cache.RedisOptions{Password: "s3cret", DB: 2})
+
+	issue := cachedIssueSummary{Key: "PROJ-1", Labels: []string{"web"}}
+	require.NoError(t, rc.Set("issue:PROJ-1", issue, time.Minute))
+	require.NoError(t, rc.Set("issue:PROJ-2", "plain string", 0))
+	require.NoError(t, rc.Set("search:abc", []string{"PROJ-1"}, time.Minute))
+
+	value, err := rc.Get("issue:PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, issue, value)
+
+	assert.Equal(t, []string{"gojira:issue:PROJ-1", "gojira:issue:PROJ-2", "gojira:search:abc"}, server.Keys(2))
+	assert.Empty(t, server.Keys(0), "keys are written to the selected database")
+	assert.InDelta(t, time.Minute, server.TTL(2, "gojira:issue:PROJ-1"), float64(time.Second))
+	assert.InDelta(t, 15*time.Minute, server.TTL(2, "gojira:issue:PROJ-2"), float64(time.Second), "zero TTL uses the default")
+
+	_, err = rc.Get("issue:PROJ-9")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+
+	assert.Equal(t, 2, rc.InvalidatePattern(`^issue:`))
+	assert.Equal(t, []string{"gojira:search:abc"}, server.Keys(2))
+
+	server.FastForward(2 * time.Minute)
+	_, err = rc.Get("search:abc")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss, "entries expire on the server")
+
+	stats := rc.GetStats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, int64(0), stats.Errors)
+}
+
+func TestRedisCacheClearKeepsOtherPrefixes(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	ours := newTestRedisCache(t, server, cache.RedisOptions{Prefix: "gojira:*:"})
+	theirs := newTestRedisCache(t, server, cache.RedisOptions{Prefix: "other:"})
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, ours.Set(key, key, time.Minute))
+	}
+	require.NoError(t, theirs.Set("a", "kept", time.Minute))
+
+	require.NoError(t, ours.Clear())
+	assert.Equal(t, []string{"other:a"}, server.Keys(0), "glob characters in the prefix are escaped")
+
+	value, err := theirs.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "kept", value)
+}
+
+func TestRedisCacheReconnects(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	rc := newTestRedisCache(t, server, cache.RedisOptions{})
+	require.NoError(t, rc.Set("k", "v", time.Minute))
+
+	// Pooled connections dropped by the server are replaced transparently
+	server.CloseClientConnections()
+	value, err := rc.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", value)
+
+	server.Close()
+	_, err = rc.Get("k")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, cache.ErrCacheMiss)
+	assert.Equal(t, int64(1), rc.GetStats().Errors)
+}
+
+func TestRedisCacheRetriesOnlyCommandsThatCannotRunTwice(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	rc := newTestRedisCache(t, server, cache.RedisOptions{})
+	require.NoError(t, rc.Set("k", "v", time.Minute))
+
+	// A read that loses its reply is sent again on a new connection
+	server.DropReply("GET")
+	value, err := rc.Get("k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", value)
+	assert.Equal(t, 2, server.CommandCount("GET"))
+
+	// A write the server may have applied is not
+	server.DropReply("PUBLISH")
+	err = rc.PublishInvalidation(cache.InvalidationEvent{Tags: []string{"issue:PROJ-1"}})
+	require.Error(t, err)
+	assert.Equal(t, 1, server.CommandCount("PUBLISH"))
+}
+
+func TestRedisCacheBoundsActiveConnections(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	rc := newTestRedisCache(t, server, cache.RedisOptions{PoolSize: 2})
+	require.NoError(t, rc.Set("k", "v", time.Minute))
+	server.SetLatency(5 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := rc.Get("k")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, server.PeakConnections(), 2)
+	assert.Equal(t, 21, server.CommandCount("GET")+server.CommandCount("SET"))
+}
+
+func TestMultiLevelCacheSharesL2AcrossReplicas(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	strategy.WriteThrough = true
+
+	replicas := make([]*cache.MultiLevelCache, 2)
+	for i := range replicas {
+		replicas[i] = cache.NewMultiLevelCache(strategy)
+		replicas[i].SetL2Cache(newTestRedisCache(t, server, cache.RedisOptions{}))
+	}
+
+	require.NoError(t, replicas[0].Set("issue:PROJ-1", "cached", time.Minute))
+
+	value, found := replicas[1].Get("issue:PROJ-1")
+	require.True(t, found)
+	assert.Equal(t, "cached", value)
+
+	_, found = replicas[1].Get("issue:PROJ-2")
+	assert.False(t, found)
+
+	stats := replicas[1].GetStats()
+	assert.Equal(t, int64(1), stats.L2Hits)
+	assert.Equal(t, int64(0), stats.L2Errors, "misses are not counted as errors")
+
+	// Invalidation on one replica reaches the shared level
+	assert.Equal(t, 2, replicas[0].Invalidate(`^issue:`))
+	assert.Empty(t, server.Keys(0))
+}
+
+func TestSharedCacheFromConfig(t *testing.T) {
+	server := resptest.NewServer()
+	defer server.Close()
+	server.RequirePassword("s3cret")
+	t.Cleanup(func() { require.NoError(t, handlers.SetSharedCache(cache.RedisOptions{})) })
+
+	err := handlers.Configure(&config.Config{Cache: config.CacheConfig{L2: config.L2CacheConfig{
+		Addr:     server.Addr,
+		Password: "wrong",
+	}}})
+	require.Error(t, err)
+
+	require.NoError(t, handlers.Configure(&config.Config{Cache: config.CacheConfig{L2: config.L2CacheConfig{
+		Addr:     server.Addr,
+		Password: "s3cret",
+		Prefix:   "team:",
+		PoolSize: 2,
+		TTL:      5,
+	}}}))
+
+	shared := cache.GlobalAdmin.MultiLevelCache()
+	require.NotNil(t, shared)
+	require.NoError(t, shared.SetWithTags("issue:PROJ-1", "cached", time.Minute, cache.IssueTag("PROJ-1")))
+	assert.Contains(t, server.Keys(0), "team:issue:PROJ-1")
+
+	// Another replica on the same server reads the entry from L2
+	replica := newTestRedisCache(t, server, cache.RedisOptions{Password: "s3cret", Prefix: "team:"})
+	strategy := cache.DefaultMultiLevelStrategy()
+	strategy.MaxL3Size = 0
+	other := cache.NewMultiLevelCache(strategy)
+	defer other.Stop()
+	other.SetL2Cache(replica)
+	value, found := other.Get("issue:PROJ-1")
+	require.True(t, found)
+	assert.Equal(t, "cached", value)
+
+	// A write published by that replica reaches the configured cache
+	bus := cache.NewInvalidationBus()
+	stop, err := replica.RelayInvalidations(bus)
+	require.NoError(t, err)
+	defer stop()
+	bus.PublishMutation(jira.Mutation{Operation: jira.OpUpdateIssue, Issues: []string{"PROJ-1"}, Projects: []string{"PROJ"}})
+
+	assert.Eventually(t, func() bool {
+		_, found := shared.Get("issue:PROJ-1")
+		return !found
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, handlers.SetSharedCache(cache.RedisOptions{}))
+	assert.Nil(t, cache.GlobalAdmin.MultiLevelCache())
+}
@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRemoteLinkMux emulates Jira's remote link upsert: a POST with an existing globalId updates that link
+func newRemoteLinkMux(t *testing.T, links map[string]jira.RemoteLink) *http.ServeMux {
+	nextID := 1
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1/remotelink", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var link jira.RemoteLink
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&link))
+
+			status := http.StatusOK
+			if existing, ok := links[link.GlobalID]; ok {
+				link.ID = existing.ID
+			} else {
+				link.ID = nextID
+				nextID++
+				status = http.StatusCreated
+			}
+			links[link.GlobalID] = link
+			writeJSON(w, status, map[string]interface{}{"id": link.ID, "self": fmt.Sprintf("remotelink/%d", link.ID)})
+		case http.MethodGet:
+			if globalID := r.URL.Query().Get("globalId"); globalID != "" {
+				link, ok := links[globalID]
+				if !ok {
+					writeJSON(w, http.StatusNotFound, map[string]interface{}{"errorMessages": []string{"not found"}})
+					return
+				}
+				writeJSON(w, http.StatusOK, link)
+				return
+			}
+			all := []jira.RemoteLink{}
+			for _, link := range links {
+				all = append(all, link)
+			}
+			writeJSON(w, http.StatusOK, all)
+		case http.MethodDelete:
+			delete(links, r.URL.Query().Get("globalId"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	return mux
+}
+
+func TestRemoteLinkUpsertIsIdempotent(t *testing.T) {
+	links := make(map[string]jira.RemoteLink)
+	client := newFakeJiraClient(t, newRemoteLinkMux(t, links))
+
+	pr, err := jira.NewPullRequestRemoteLink(jira.PullRequestLinkOptions{
+		URL:    "https://github.com/acme/app/pull/42",
+		Title:  "Fix login redirect",
+		Number: 42,
+		State:  "open",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "#42 Fix login redirect", pr.Object.Title)
+	assert.False(t, pr.Object.Status.Resolved)
+
+	first, err := client.UpsertRemoteLink("PROJ-1", pr)
+	require.NoError(t, err)
+	assert.True(t, first.Created)
+
+	merged, err := jira.NewPullRequestRemoteLink(jira.PullRequestLinkOptions{
+		URL:    "https://github.com/acme/app/pull/42",
+		Title:  "Fix login redirect",
+		Number: 42,
+		State:  "merged",
+	})
+	require.NoError(t, err)
+
+	second, err := client.UpsertRemoteLink("PROJ-1", merged)
+	require.NoError(t, err)
+	assert.False(t, second.Created)
+	assert.Equal(t, first.ID, second.ID)
+
+	all, err := client.GetRemoteLinks("PROJ-1")
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.True(t, all[0].Object.Status.Resolved)
+
+	found, err := client.GetRemoteLinkByGlobalID("PROJ-1", pr.GlobalID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	require.NoError(t, client.DeleteRemoteLinkByGlobalID("PROJ-1", pr.GlobalID))
+	missing, err := client.GetRemoteLinkByGlobalID("PROJ-1", pr.GlobalID)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestRemoteLinkHelpers(t *testing.T) {
+	commit, err := jira.NewCommitRemoteLink(jira.CommitLinkOptions{
+		RepositoryURL: "https://github.com/acme/app.git",
+		SHA:           "0123456789abcdef",
+		Message:       "Fix redirect loop\n\nLonger explanation",
+		Author:        "Sam Park",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/app/commit/0123456789abcdef", commit.Object.URL)
+	assert.Equal(t, "0123456 Fix redirect loop", commit.Object.Title)
+	assert.Equal(t, "git-commit=https://github.com/acme/app@0123456789abcdef", commit.GlobalID)
+
+	run, err := jira.NewCIRunRemoteLink(jira.CIRunLinkOptions{
+		URL:      "https://ci.example.com/runs/99",
+		Pipeline: "build",
+		RunID:    "99",
+		Status:   "Success",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "build #99 (success)", run.Object.Title)
+	assert.True(t, run.Object.Status.Resolved)
+
+	_, err = jira.NewCommitRemoteLink(jira.CommitLinkOptions{SHA: "abc"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSearchMux serves total issues in pages, honouring startAt and maxResults
+func newSearchMux(t *testing.T, total int, requests *int32) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		var req jira.SearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		issues := []map[string]interface{}{}
+		for i := req.StartAt; i < total && i < req.StartAt+req.MaxResults; i++ {
+			issues = append(issues, map[string]interface{}{
+				"key":    fmt.Sprintf("PROJ-%d", i+1),
+				"fields": map[string]interface{}{"summary": fmt.Sprintf("Issue, number %d", i+1)},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"startAt": req.StartAt, "maxResults": req.MaxResults, "total": total, "issues": issues,
+		})
+	})
+	return mux
+}
+
+func TestStreamSearchDeliversPagesInOrder(t *testing.T) {
+	var requests int32
+	client := newFakeJiraClient(t, newSearchMux(t, 250, &requests))
+
+	var keys []string
+	summary, err := client.StreamSearch(context.Background(), jira.SearchRequest{JQL: "project = PROJ"}, jira.SearchStreamOptions{},
+		func(page *jira.ExtendedSearchResult) error {
+			for _, issue := range page.Issues {
+				keys = append(keys, issue.Key)
+			}
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, 250, summary.Delivered)
+	assert.Equal(t, 3, summary.Pages)
+	assert.False(t, summary.Truncated)
+	require.Len(t, keys, 250)
+	assert.Equal(t, "PROJ-1", keys[0])
+	assert.Equal(t, "PROJ-250", keys[249])
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+}
+
+func TestStreamSearchStopsAtLimitAndOnConsumerError(t *testing.T) {
+	var requests int32
+	client := newFakeJiraClient(t, newSearchMux(t, 1000, &requests))
+
+	summary, err := client.StreamSearch(context.Background(), jira.SearchRequest{JQL: "project = PROJ", MaxResults: 50},
+		jira.SearchStreamOptions{MaxIssues: 120}, func(page *jira.ExtendedSearchResult) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 120, summary.Delivered)
+	assert.True(t, summary.Truncated)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+
+	// A failing consumer stops the stream; with one page of prefetch at most
+	// two further pages are requested
+	atomic.StoreInt32(&requests, 0)
+	_, err = client.StreamSearch(context.Background(), jira.SearchRequest{JQL: "project = PROJ", MaxResults: 10},
+		jira.SearchStreamOptions{}, func(page *jira.ExtendedSearchResult) error { return fmt.Errorf("client went away") })
+	require.EqualError(t, err, "client went away")
+	assert.LessOrEqual(t, atomic.LoadInt32(&requests), int32(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = client.StreamSearch(ctx, jira.SearchRequest{JQL: "project = PROJ", MaxResults: 10},
+		jira.SearchStreamOptions{}, func(page *jira.ExtendedSearchResult) error {
+			cancel()
+			return nil
+		})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCSVStreamWriter(t *testing.T) {
+	client := newFakeJiraClient(t, http.NewServeMux())
+
+	var buf bytes.Buffer
+	writer := client.NewCSVStreamWriter(&buf, []string{"key", "summary"})
+	require.NoError(t, writer.WriteIssues([]jira.Issue{{Key: "PROJ-1", Fields: jira.IssueFields{Summary: `Say "hi", then leave`}}}))
+	require.NoError(t, writer.WriteIssues([]jira.Issue{{Key: "PROJ-2", Fields: jira.IssueFields{Summary: "Second"}}}))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"key", "summary"},
+		{"PROJ-1", `Say "hi", then leave`},
+		{"PROJ-2", "Second"},
+	}, records)
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/claude"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/nlp"
+	"github.com/ericfisherdev/GoJira/internal/textindex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func indexedIssue(key, summary, description string, comments ...string) jira.Issue {
+	issue := jira.Issue{Key: key, Fields: jira.IssueFields{Summary: summary, Description: description}}
+	if len(comments) > 0 {
+		issue.Fields.Comment = &jira.CommentResult{Total: len(comments)}
+		for _, body := range comments {
+			issue.Fields.Comment.Comments = append(issue.Fields.Comment.Comments, jira.Comment{Body: body})
+		}
+	}
+	return issue
+}
+
+func hitKeys(hits []textindex.Hit) []string {
+	keys := make([]string, len(hits))
+	for i, hit := range hits {
+		keys[i] = hit.Key
+	}
+	return keys
+}
+
+func TestLocalIndexRanksWithBM25(t *testing.T) {
+	ix := textindex.NewIndex()
+	ix.Add(
+		indexedIssue("WEB-1", "Login fails on Safari", "Users cannot sign in with Safari 17."),
+		indexedIssue("WEB-2", "Checkout page is slow", "Safari users mention login prompts appearing twice during checkout."),
+		indexedIssue("WEB-3", "Update footer links", "Marketing asked for new links."),
+		indexedIssue("API-1", "Worker crashes on empty payload", "", "It crashed again overnight"),
+		jira.Issue{Key: "API-2", Fields: jira.IssueFields{
+			Summary:     "Export times out",
+			Description: map[string]interface{}{"type": "doc", "content": []interface{}{map[string]interface{}{"type": "paragraph", "content": []interface{}{map[string]interface{}{"type": "text", "text": "Large CSV export of the backlog hits the gateway timeout"}}}}},
+		}},
+	)
+
+	// A term in the summary outweighs the same term in a description
+	assert.Equal(t, []string{"WEB-1", "WEB-2"}, hitKeys(ix.Search("safari login", textindex.SearchOptions{})))
+
+	// Plural forms, comments and ADF descriptions are indexed
+	assert.Equal(t, []string{"API-1"}, hitKeys(ix.Search("crash", textindex.SearchOptions{})))
+	assert.Equal(t, []string{"API-1"}, hitKeys(ix.Search("overnight", textindex.SearchOptions{})))
+	assert.Equal(t, []string{"API-2"}, hitKeys(ix.Search("gateway timeout", textindex.SearchOptions{})))
+
+	hits := ix.Search("login safari", textindex.SearchOptions{Project: "web", Limit: 1})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "WEB", hits[0].Project)
+	assert.Equal(t, []string{"login", "safari"}, hits[0].MatchedTerms)
+	assert.Empty(t, ix.Search("the and of", textindex.SearchOptions{}))
+
+	// Newer versions replace older ones; older ones are ignored
+	updated := indexedIssue("WEB-3", "Footer links render in Safari only", "")
+	updated.Fields.Updated = &jira.JiraTime{Time: time.Now()}
+	ix.Add(updated)
+	stale := indexedIssue("WEB-3", "Update footer links", "")
+	stale.Fields.Updated = &jira.JiraTime{Time: time.Now().Add(-time.Hour)}
+	ix.Add(stale)
+	assert.Contains(t, hitKeys(ix.Search("render", textindex.SearchOptions{})), "WEB-3")
+	assert.Empty(t, ix.Search("marketing", textindex.SearchOptions{}))
+
+	ix.Remove("web-1")
+	assert.False(t, ix.Contains("WEB-1"))
+	assert.Equal(t, 4, ix.Stats().Documents)
+}
+
+func TestLocalIndexFindsDuplicates(t *testing.T) {
+	ix := textindex.NewIndex()
+	ix.Add(
+		indexedIssue("PROJ-1", "Null pointer in payment webhook handler", "The payment webhook handler panics with a nil pointer when the customer has no billing address."),
+		indexedIssue("PROJ-2", "Payment webhook handler panics on nil pointer", "The payment webhook handler panics with a nil pointer when the customer has no billing address.", "Seen in production"),
+		indexedIssue("PROJ-3", "Payment page typo", "The payment page says 'adress'."),
+		indexedIssue("PROJ-4", "Dark mode for settings", "Add a dark theme to the settings screen."),
+	)
+
+	similar, ok := ix.Similar("PROJ-1", 10)
+	require.True(t, ok)
+	require.NotEmpty(t, similar)
+	assert.Equal(t, "PROJ-2", similar[0].Key)
+	assert.True(t, similar[0].Duplicate)
+	assert.GreaterOrEqual(t, similar[0].Similarity, textindex.DuplicateThreshold)
+	for _, match := range similar[1:] {
+		assert.False(t, match.Duplicate, match.Key)
+	}
+	for _, match := range similar {
+		assert.NotEqual(t, "PROJ-4", match.Key, "issues without shared terms are not similar")
+	}
+
+	duplicates := ix.Duplicates("Payment webhook handler panics with a nil pointer when the customer has no billing address", 5)
+	require.Len(t, duplicates, 2)
+	assert.ElementsMatch(t, []string{"PROJ-1", "PROJ-2"}, []string{duplicates[0].Key, duplicates[1].Key})
+	assert.Empty(t, ix.Duplicates("Dark mode for the login screen", 5))
+
+	_, ok = ix.Similar("PROJ-404", 10)
+	assert.False(t, ok)
+}
+
+func TestLocalSearchEndpoints(t *testing.T) {
+	srv := setupTestServer(t)
+	textindex.GlobalIndex.Add(
+		indexedIssue("LSE-1", "Session expires during upload", "Long uploads lose the session and fail."),
+		indexedIssue("LSE-2", "Upload fails when session expires", "Long uploads lose the session and fail."),
+		indexedIssue("LSE-3", "Rename upload button", ""),
+	)
+	t.Cleanup(func() { textindex.GlobalIndex.Remove("LSE-1", "LSE-2", "LSE-3") })
+
+	get := func(path string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body
+	}
+
+	status, body := get("/api/v1/search/local?q=session+upload&project=LSE")
+	require.Equal(t, http.StatusOK, status)
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, float64(3), data["total"])
+	hits := data["hits"].([]interface{})
+	assert.Equal(t, "LSE-1", hits[0].(map[string]interface{})["key"])
+
+	status, _ = get("/api/v1/search/local")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = get("/api/v1/issues/LSE-1/similar")
+	require.Equal(t, http.StatusOK, status)
+	data = body["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["duplicates"])
+	similar := data["similar"].([]interface{})
+	first := similar[0].(map[string]interface{})
+	assert.Equal(t, "LSE-2", first["key"])
+	assert.Equal(t, true, first["duplicate"])
+
+	status, _ = get("/api/v1/issues/LSE-404/similar")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestCreateBugFromCodeWarnsAboutDuplicates(t *testing.T) {
+	var handler claude.CommandHandler
+	for _, pattern := range claude.InitializePatterns() {
+		if pattern.Name == "CreateBugFromCode" {
+			handler = pattern.Handler
+		}
+	}
+	require.NotNil(t, handler)
+
+	run := func(input string) *claude.CommandResult {
+		result, err := handler(&claude.CommandContext{Input: input, Intent: &nlp.Intent{Raw: input}})
+		require.NoError(t, err)
+		return result
+	}
+
+	input := "Create a bug for the SQL injection vulnerability in ledger.go line 145"
+	result := run(input)
+	assert.NotContains(t, result.Data.(map[string]interface{}), "possibleDuplicates")
+
+	textindex.GlobalIndex.Add(
+		indexedIssue("DUP-7", "Security issue in ledger.go", "Security vulnerability found in ledger.go at line 145"),
+		indexedIssue("DUP-8", "Security issue in wallet.go", "Security vulnerability found in wallet.go at line 12"),
+	)
+	t.Cleanup(func() { textindex.GlobalIndex.Remove("DUP-7", "DUP-8") })
+
+	result = run(input)
+	duplicates, ok := result.Data.(map[string]interface{})["possibleDuplicates"].([]textindex.Match)
+	require.True(t, ok)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, "DUP-7", duplicates[0].Key)
+	assert.Contains(t, result.Message, "DUP-7")
+	assert.Equal(t, "review-duplicates", result.Actions[0].ID)
+}
@@ -0,0 +1,294 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/config"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMux counts the requests made to each path
+type countingMux struct {
+	*http.ServeMux
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newCountingMux() *countingMux {
+	return &countingMux{ServeMux: http.NewServeMux(), calls: make(map[string]int)}
+}
+
+func (m *countingMux) handle(path string, status int, body interface{}) {
+	m.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.calls[path]++
+		m.mu.Unlock()
+		writeJSON(w, status, body)
+	})
+}
+
+func (m *countingMux) count(path string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[path]
+}
+
+func newWarmupMux() *countingMux {
+	mux := newCountingMux()
+	mux.handle("/rest/api/2/project", http.StatusOK, []map[string]interface{}{{"id": "1", "key": "PROJ", "name": "Project"}})
+	mux.handle("/rest/agile/1.0/board", http.StatusOK, map[string]interface{}{
+		"values": []map[string]interface{}{
+			{"id": 1, "name": "Scrum", "type": "scrum"},
+			{"id": 2, "name": "Kanban", "type": "kanban"},
+		},
+	})
+	mux.handle("/rest/agile/1.0/board/1/sprint", http.StatusOK, map[string]interface{}{
+		"values": []map[string]interface{}{
+			{"id": 10, "name": "Sprint 10", "state": "active", "originBoardId": 1},
+			{"id": 9, "name": "Sprint 9", "state": "closed", "originBoardId": 1},
+		},
+	})
+	mux.handle("/rest/agile/1.0/sprint/10/issue", http.StatusOK, map[string]interface{}{
+		"issues": []map[string]interface{}{{"id": "100", "key": "PROJ-1"}},
+		"total":  1,
+	})
+	mux.handle("/rest/api/2/workflow", http.StatusOK, map[string]interface{}{"values": []map[string]interface{}{{"id": "1", "name": "Default"}}})
+	mux.handle("/rest/api/2/field", http.StatusOK, []map[string]interface{}{
+		{"id": "summary", "name": "Summary"},
+		{"id": "customfield_10001", "name": "Story Points"},
+	})
+	mux.handle("/rest/api/2/issueLinkType", http.StatusOK, map[string]interface{}{
+		"issueLinkTypes": []map[string]interface{}{{"id": "1", "name": "Blocks"}},
+	})
+	return mux
+}
+
+func TestWarmupProfileLoadsJiraCache(t *testing.T) {
+	mux := newWarmupMux()
+	jiraCache := services.NewJiraCache(newFakeJiraClient(t, mux.ServeMux))
+
+	warmer, err := services.NewWarmer(jiraCache, nil)
+	require.NoError(t, err)
+	assert.Equal(t, services.WarmupIdle, warmer.Progress().Status)
+
+	run := warmer.Run(context.Background(), services.DefaultWarmupProfile, services.WarmupOnConnect)
+	assert.Equal(t, services.WarmupCompleted, run.Status)
+	assert.Equal(t, 6, run.Total)
+	assert.Equal(t, 6, run.Completed)
+	assert.Empty(t, run.Errors)
+
+	progress := warmer.Progress()
+	assert.Equal(t, services.WarmupCompleted, progress.Status)
+	assert.Equal(t, 100, progress.Percent)
+	require.Len(t, progress.Runs, 1)
+
+	// Only the active sprint of the scrum board is loaded
+	assert.Equal(t, 1, mux.count("/rest/agile/1.0/sprint/10/issue"))
+
+	// Everything the first commands need is now served from the cache
+	hits := func() int64 {
+		stats := jiraCache.GetStats()
+		return stats.Metadata.Hits + stats.Issues.Hits
+	}
+	warmHits := hits()
+	ctx := context.Background()
+	projects, err := jiraCache.Projects(ctx)
+	require.NoError(t, err)
+	assert.Len(t, projects, 1)
+	_, err = jiraCache.Boards(ctx)
+	require.NoError(t, err)
+	_, err = jiraCache.Sprints(ctx, 1)
+	require.NoError(t, err)
+	_, err = jiraCache.SprintIssues(ctx, 10)
+	require.NoError(t, err)
+	_, err = jiraCache.Workflows(ctx)
+	require.NoError(t, err)
+	fields, err := jiraCache.CustomFields(ctx)
+	require.NoError(t, err)
+	assert.Len(t, fields, 1)
+	linkTypes, err := jiraCache.LinkTypes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Blocks", linkTypes[0].Name)
+
+	for _, path := range []string{"/rest/api/2/project", "/rest/agile/1.0/board", "/rest/agile/1.0/board/1/sprint",
+		"/rest/api/2/workflow", "/rest/api/2/field", "/rest/api/2/issueLinkType"} {
+		assert.Equal(t, 1, mux.count(path), path)
+	}
+	assert.Equal(t, int64(7), hits()-warmHits)
+}
+
+func TestWarmupReportsFailedTargets(t *testing.T) {
+	mux := newCountingMux()
+	mux.handle("/rest/api/2/project", http.StatusOK, []map[string]interface{}{})
+	mux.handle("/rest/api/2/issueLinkType", http.StatusForbidden, map[string]interface{}{"errorMessages": []string{"forbidden"}})
+	jiraCache := services.NewJiraCache(newFakeJiraClient(t, mux.ServeMux))
+
+	profile := services.WarmupProfile{Name: "links", Targets: []string{services.WarmupProjects, services.WarmupLinkTypes}}
+	warmer, err := services.NewWarmer(jiraCache, []services.WarmupProfile{profile})
+	require.NoError(t, err)
+
+	require.True(t, warmer.Start(services.WarmupOnSchedule))
+	require.Eventually(t, func() bool {
+		return warmer.Progress().Status != services.WarmupRunning
+	}, 2*time.Second, 5*time.Millisecond)
+
+	progress := warmer.Progress()
+	assert.Equal(t, services.WarmupCompletedWithErrors, progress.Status)
+	assert.Equal(t, 100, progress.Percent)
+	require.Len(t, progress.Runs, 1)
+	assert.Equal(t, services.WarmupOnSchedule, progress.Runs[0].Trigger)
+	assert.Equal(t, 1, progress.Runs[0].Completed)
+	assert.Equal(t, 1, progress.Runs[0].Failed)
+	require.Len(t, progress.Runs[0].Errors, 1)
+	assert.Contains(t, progress.Runs[0].Errors[0], "linkTypes")
+}
+
+func TestWarmupProfileValidation(t *testing.T) {
+	_, err := services.NewWarmer(nil, []services.WarmupProfile{{Name: "bad", Targets: []string{"dashboards"}}})
+	assert.ErrorContains(t, err, `unknown target "dashboards"`)
+
+	_, err = services.NewWarmer(nil, []services.WarmupProfile{{Name: "empty"}})
+	assert.ErrorContains(t, err, "no targets")
+}
+
+func TestScheduledWarmup(t *testing.T) {
+	mux := newWarmupMux()
+	jiraCache := services.NewJiraCache(newFakeJiraClient(t, mux.ServeMux))
+
+	profile := services.WarmupProfile{Name: "projects", Targets: []string{services.WarmupProjects}}
+	warmer, err := services.NewWarmer(jiraCache, []services.WarmupProfile{profile})
+	require.NoError(t, err)
+
+	warmer.Schedule(20 * time.Millisecond)
+	t.Cleanup(warmer.Stop)
+	assert.NotNil(t, warmer.Progress().NextRun)
+
+	require.Eventually(t, func() bool {
+		return len(warmer.Progress().Runs) == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, services.WarmupOnSchedule, warmer.Progress().Runs[0].Trigger)
+
+	warmer.Stop()
+	assert.Nil(t, warmer.Progress().NextRun)
+}
+
+func TestPrefetchAfterIssueAndSprintReads(t *testing.T) {
+	mux := newCountingMux()
+	mux.handle("/rest/api/2/issue/PROJ-1", http.StatusOK, map[string]interface{}{
+		"key": "PROJ-1",
+		"fields": map[string]interface{}{
+			"issuelinks": []map[string]interface{}{
+				{"type": map[string]string{"name": "Blocks"}, "outwardIssue": map[string]string{"key": "PROJ-2"}},
+				{"type": map[string]string{"name": "Relates"}, "inwardIssue": map[string]string{"key": "WEB-3"}},
+			},
+		},
+	})
+	mux.handle("/rest/api/2/issue/PROJ-1/transitions", http.StatusOK, map[string]interface{}{
+		"transitions": []map[string]interface{}{{"id": "31", "name": "Done"}},
+	})
+	mux.handle("/rest/api/2/issue/PROJ-2", http.StatusOK, map[string]interface{}{"key": "PROJ-2"})
+	mux.handle("/rest/api/2/issue/WEB-3", http.StatusOK, map[string]interface{}{"key": "WEB-3"})
+	mux.handle("/rest/agile/1.0/sprint/10/issue", http.StatusOK, map[string]interface{}{
+		"issues": []map[string]interface{}{{"id": "100", "key": "PROJ-1"}},
+	})
+	jiraCache := services.NewJiraCache(newFakeJiraClient(t, mux.ServeMux))
+	ctx := context.Background()
+
+	issue, err := jiraCache.Issue(ctx, "PROJ-1")
+	require.NoError(t, err)
+	jiraCache.PrefetchIssue(issue)
+	jiraCache.PrefetchSprint(10)
+
+	require.Eventually(t, func() bool {
+		return mux.count("/rest/api/2/issue/PROJ-1/transitions") == 1 &&
+			mux.count("/rest/api/2/issue/PROJ-2") == 1 &&
+			mux.count("/rest/api/2/issue/WEB-3") == 1 &&
+			mux.count("/rest/agile/1.0/sprint/10/issue") == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	// Wait for the prefetched values to be stored
+	require.Eventually(t, func() bool { return jiraCache.GetStats().Issues.Size == 5 }, 2*time.Second, 5*time.Millisecond)
+
+	transitions, err := jiraCache.Transitions(ctx, "PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Done", transitions.Transitions[0].Name)
+	links, err := jiraCache.Links(ctx, "PROJ-1")
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+	_, err = jiraCache.Issue(ctx, "web-3")
+	require.NoError(t, err)
+	_, err = jiraCache.SprintIssues(ctx, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, mux.count("/rest/api/2/issue/PROJ-1"), "links come from the cached issue")
+	assert.Equal(t, 1, mux.count("/rest/api/2/issue/PROJ-1/transitions"))
+	assert.Equal(t, 1, mux.count("/rest/api/2/issue/WEB-3"))
+	assert.Equal(t, int64(2), jiraCache.GetStats().Prefetches)
+
+	// A write to the issue evicts it and everything derived from it
+	jiraCache.InvalidateTags(cache.MutationTags(jira.Mutation{Issues: []string{"PROJ-1"}, Projects: []string{"PROJ"}})...)
+	_, err = jiraCache.Transitions(ctx, "PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, mux.count("/rest/api/2/issue/PROJ-1/transitions"))
+	_, err = jiraCache.Issue(ctx, "WEB-3")
+	require.NoError(t, err)
+	assert.Equal(t, 1, mux.count("/rest/api/2/issue/WEB-3"), "other issues stay cached")
+}
+
+func TestWarmupProfilesFromConfig(t *testing.T) {
+	t.Cleanup(func() { handlers.SetWarmupProfiles(nil, 30*time.Minute) })
+
+	err := handlers.Configure(&config.Config{Warmup: config.WarmupConfig{
+		Interval: 15,
+		Profiles: []config.WarmupProfileConfig{{Name: "bad", Targets: []string{"dashboards"}}},
+	}})
+	assert.ErrorContains(t, err, `unknown target "dashboards"`)
+
+	require.NoError(t, handlers.Configure(&config.Config{Warmup: config.WarmupConfig{
+		Interval: 15,
+		Profiles: []config.WarmupProfileConfig{{Name: "team", Targets: []string{services.WarmupProjects, services.WarmupBoards}}},
+	}}))
+}
+
+func TestJiraReadsFollowTheConnectedClient(t *testing.T) {
+	srv := setupTestServer(t)
+	mux := newWarmupMux()
+	t.Cleanup(func() { handlers.SetJiraClient(nil) })
+
+	// Handlers read the cache while the client is replaced
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 4; i++ {
+			handlers.SetJiraClient(newFakeJiraClient(t, mux.ServeMux))
+		}
+	}()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache", nil))
+		}()
+	}
+	wg.Wait()
+
+	handlers.SetJiraClient(newFakeJiraClient(t, mux.ServeMux))
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/boards", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/boards", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, mux.count("/rest/agile/1.0/board"), "the second read is served by the cache")
+}
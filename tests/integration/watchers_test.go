@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchersAndVotes(t *testing.T) {
+	var addedBody, removedQuery string
+	votes := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1/watchers", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"isWatching": true,
+				"watchCount": 1,
+				"watchers":   []map[string]interface{}{{"accountId": "abc", "displayName": "Sam Park"}},
+			})
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			addedBody = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			removedQuery = r.URL.RawQuery
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1/votes", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			votes++
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			votes--
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"votes": votes, "hasVoted": votes > 0})
+		}
+	})
+
+	client := newFakeJiraClient(t, mux)
+
+	watchers, err := client.GetWatchers("PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, watchers.WatchCount)
+	assert.Equal(t, "Sam Park", watchers.Watchers[0].DisplayName)
+
+	require.NoError(t, client.AddWatcher("PROJ-1", "5b10a2844c20165700ede21g"))
+	assert.Equal(t, `"5b10a2844c20165700ede21g"`, addedBody)
+
+	require.NoError(t, client.RemoveWatcher("PROJ-1", "5b10a2844c20165700ede21g"))
+	assert.Equal(t, "accountId=5b10a2844c20165700ede21g", removedQuery)
+
+	require.NoError(t, client.AddVote("PROJ-1"))
+	v, err := client.GetVotes("PROJ-1")
+	require.NoError(t, err)
+	assert.True(t, v.HasVoted)
+
+	require.NoError(t, client.RemoveVote("PROJ-1"))
+	assert.Equal(t, 0, votes)
+}
+
+func TestNotifyIssue(t *testing.T) {
+	var sent map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/PROJ-1/notify", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := newFakeJiraClient(t, mux)
+
+	err := client.NotifyIssue("PROJ-1", &jira.NotifyRequest{Subject: "Heads up"})
+	require.Error(t, err, "a notification without recipients must be rejected")
+
+	err = client.NotifyIssue("PROJ-1", &jira.NotifyRequest{
+		Subject:  "Heads up",
+		TextBody: "Deployment is delayed",
+		To: jira.NotifyRecipients{
+			Watchers: true,
+			Users:    []jira.NotifyUser{{AccountID: "abc"}},
+			Groups:   []jira.NotifyGroupRef{{Name: "release-managers"}},
+		},
+	})
+	require.NoError(t, err)
+
+	to := sent["to"].(map[string]interface{})
+	assert.Equal(t, true, to["watchers"])
+	assert.Equal(t, "abc", to["users"].([]interface{})[0].(map[string]interface{})["accountId"])
+	assert.Equal(t, "release-managers", to["groups"].([]interface{})[0].(map[string]interface{})["name"])
+}
@@ -0,0 +1,310 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ericfisherdev/GoJira/internal/api/handlers"
+	"github.com/ericfisherdev/GoJira/internal/cache"
+	"github.com/ericfisherdev/GoJira/internal/claude"
+	"github.com/ericfisherdev/GoJira/internal/config"
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/nlp"
+	"github.com/ericfisherdev/GoJira/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readWebhookPayload loads a payload recorded from Jira Cloud
+func readWebhookPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", name))
+	require.NoError(t, err)
+	return body
+}
+
+func signJWT(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	unsigned := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestRecordedWebhookPayloads(t *testing.T) {
+	tests := []struct {
+		file     string
+		category string
+		issueKey string
+		mutation jira.Mutation
+		describe string
+	}{
+		{
+			file:     "issue_updated.json",
+			category: webhook.CategoryIssue,
+			issueKey: "PROJ-1",
+			mutation: jira.Mutation{Operation: jira.WebhookIssueUpdated, Issues: []string{"PROJ-1"}, Projects: []string{"PROJ"}, Sprints: []int{5}},
+			describe: "PROJ-1 status changed from To Do to Done, Sprint changed from none to Sprint 5 by Alice Smith",
+		},
+		{
+			file:     "comment_created.json",
+			category: webhook.CategoryComment,
+			issueKey: "PROJ-2",
+			mutation: jira.Mutation{Operation: jira.WebhookCommentCreated, Issues: []string{"PROJ-2"}, Projects: []string{"PROJ"}},
+			describe: "PROJ-2 was commented on",
+		},
+		{
+			// Worklogs name their issue by ID only, so anything may have changed
+			file:     "worklog_created.json",
+			category: webhook.CategoryWorklog,
+			mutation: jira.Mutation{Operation: jira.WebhookWorklogCreated},
+			describe: "Jira had work logged",
+		},
+		{
+			file:     "sprint_started.json",
+			category: webhook.CategorySprint,
+			mutation: jira.Mutation{Operation: jira.WebhookSprintStarted, Sprints: []int{5}, Boards: []int{3}},
+			describe: `Sprint "Sprint 5" was started`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			event, err := webhook.Parse(readWebhookPayload(t, tt.file))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.category, event.Category())
+			assert.Equal(t, tt.issueKey, event.IssueKey())
+			assert.Equal(t, tt.describe, event.Describe())
+
+			mutation, ok := event.Mutation()
+			require.True(t, ok)
+			assert.Equal(t, tt.mutation, mutation)
+		})
+	}
+
+	event, err := webhook.Parse([]byte(`{"webhookEvent":"user_created","user":{"displayName":"Carol"}}`))
+	require.NoError(t, err)
+	_, ok := event.Mutation()
+	assert.False(t, ok, "user events change no cached data")
+
+	_, err = webhook.Parse([]byte(`{"timestamp":1}`))
+	assert.Error(t, err)
+}
+
+func TestWebhookVerifier(t *testing.T) {
+	body := readWebhookPayload(t, "issue_updated.json")
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	request := func(target string, headers map[string]string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+		return r
+	}
+
+	secret := &webhook.Verifier{Secret: "s3cret"}
+	assert.NoError(t, secret.Verify(request("/webhooks/jira?secret=s3cret", nil), body))
+	assert.NoError(t, secret.Verify(request("/webhooks/jira", map[string]string{webhook.SecretHeader: "s3cret"}), body))
+	assert.NoError(t, secret.Verify(request("/webhooks/jira", map[string]string{webhook.SignatureHeader: signature}), body))
+	assert.ErrorIs(t, secret.Verify(request("/webhooks/jira?secret=guess", nil), body), webhook.ErrInvalidSecret)
+	assert.ErrorIs(t, secret.Verify(request("/webhooks/jira", map[string]string{webhook.SignatureHeader: signature}), []byte(`{}`)),
+		webhook.ErrInvalidSignature, "the signature covers the body")
+	assert.ErrorIs(t, secret.Verify(request("/webhooks/jira", nil), body), webhook.ErrMissingCredentials)
+	assert.ErrorIs(t, (&webhook.Verifier{}).Verify(request("/webhooks/jira?secret=s3cret", nil), body), webhook.ErrNotConfigured)
+
+	connect := &webhook.Verifier{JWTSecret: "shared", JWTIssuer: "client-key"}
+	target := "/webhooks/jira?user_id=alice"
+	qsh := webhook.QueryStringHash(http.MethodPost, request(target, nil).URL)
+	bearer := func(claims map[string]interface{}) map[string]string {
+		return map[string]string{"Authorization": "JWT " + signJWT(t, "shared", claims)}
+	}
+	valid := map[string]interface{}{"iss": "client-key", "exp": time.Now().Add(time.Minute).Unix(), "qsh": qsh}
+
+	assert.NoError(t, connect.Verify(request(target, bearer(valid)), body))
+	assert.ErrorIs(t, connect.Verify(request("/webhooks/jira?user_id=bob", bearer(valid)), body), webhook.ErrInvalidToken,
+		"the token is bound to the query it was issued for")
+	assert.ErrorIs(t, connect.Verify(request(target, bearer(map[string]interface{}{
+		"iss": "client-key", "exp": time.Now().Add(-time.Hour).Unix(), "qsh": qsh,
+	})), body), webhook.ErrTokenExpired)
+	assert.ErrorIs(t, connect.Verify(request(target, bearer(map[string]interface{}{
+		"iss": "other-app", "exp": time.Now().Add(time.Minute).Unix(), "qsh": "context-qsh",
+	})), body), webhook.ErrInvalidToken)
+	assert.ErrorIs(t, connect.Verify(request(target, map[string]string{
+		"Authorization": "JWT " + signJWT(t, "wrong", valid),
+	}), body), webhook.ErrInvalidToken)
+
+	// Tokens not bound to the request are rejected
+	for _, qsh := range []interface{}{nil, "", "context-qsh"} {
+		claims := map[string]interface{}{"iss": "client-key", "exp": time.Now().Add(time.Minute).Unix()}
+		if qsh != nil {
+			claims["qsh"] = qsh
+		}
+		assert.ErrorIs(t, connect.Verify(request(target, bearer(claims)), body), webhook.ErrInvalidToken, "qsh %v", qsh)
+	}
+}
+
+func TestJiraWebhookEndpointDispatchesRecordedPayloads(t *testing.T) {
+	srv := setupTestServer(t)
+	require.NoError(t, handlers.Configure(&config.Config{Webhooks: config.WebhookConfig{Secret: "s3cret"}}))
+	t.Cleanup(func() { handlers.SetWebhookVerifier(nil) })
+
+	// The global bus drops delivery IDs it has seen, so every run uses its own
+	deliveryID := fmt.Sprintf("delivery-%d", time.Now().UnixNano())
+
+	var received []*webhook.Event
+	t.Cleanup(webhook.GlobalBus.Subscribe(func(event *webhook.Event) {
+		received = append(received, event)
+	}, webhook.CategoryIssue))
+
+	responses := cache.NewResponseCache(10, time.Minute)
+	defer responses.Stop()
+	t.Cleanup(cache.GlobalInvalidationBus.Register(responses))
+	responses.Set("show PROJ-1", "PROJ-1 is To Do", cache.WithTags(cache.DependencyTags(&jira.Issue{Key: "PROJ-1"})...))
+
+	post := func(target, deliveryID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(readWebhookPayload(t, "issue_updated.json")))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set(webhook.IdentifierHeader, deliveryID)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, r)
+		return w
+	}
+
+	w := post("/webhooks/jira?secret=guess", deliveryID)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, received)
+
+	before := webhook.GlobalBus.GetStats()
+	w = post("/webhooks/jira?secret=s3cret", deliveryID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Success bool                   `json:"success"`
+		Data    map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, "jira:issue_updated", response.Data["event"])
+	assert.Equal(t, "PROJ-1", response.Data["issueKey"])
+
+	require.Len(t, received, 1)
+	assert.Equal(t, deliveryID, received[0].ID)
+	_, found := responses.Get("show PROJ-1")
+	assert.False(t, found, "the change made in Jira evicts the cached response")
+
+	// Jira retries a delivery with the same identifier
+	w = post("/webhooks/jira?secret=s3cret", deliveryID)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, received, 1)
+
+	after := webhook.GlobalBus.GetStats()
+	assert.Equal(t, int64(2), after.Received-before.Received)
+	assert.Equal(t, int64(1), after.Duplicates-before.Duplicates)
+	assert.Equal(t, int64(1), after.ByType["jira:issue_updated"]-before.ByType["jira:issue_updated"])
+}
+
+func TestWebhookEventsReachSessionsAndSuggestions(t *testing.T) {
+	sessions := claude.NewSessionManager(time.Hour)
+	defer sessions.Stop()
+	suggestions := claude.NewSuggestionEngine(claude.NewPatternManager(), sessions)
+
+	bus := webhook.NewBus()
+	bus.Subscribe(sessions.HandleJiraEvent)
+	bus.Subscribe(suggestions.HandleJiraEvent, webhook.CategoryIssue, webhook.CategoryComment)
+
+	onProject := sessions.CreateSession("alice", "conv-1")
+	require.NoError(t, sessions.UpdateSession(onProject.ID, claude.Command{
+		Input:  "show PROJ bugs",
+		Intent: &nlp.Intent{Entities: map[string]nlp.Entity{"project": {Value: "PROJ"}}},
+	}))
+	elsewhere := sessions.CreateSession("bob", "conv-2")
+
+	for _, file := range []string{"comment_created.json", "sprint_started.json"} {
+		event, err := webhook.Parse(readWebhookPayload(t, file))
+		require.NoError(t, err)
+		bus.Dispatch(event)
+	}
+
+	changes, ok := onProject.Context["jiraChanges"].([]claude.ExternalChange)
+	require.True(t, ok)
+	require.Len(t, changes, 1, "sprint events name no project")
+	assert.Equal(t, "PROJ-2", changes[0].IssueKey)
+	assert.Equal(t, "PROJ-2 was commented on", onProject.Context["lastJiraChange"])
+	assert.NotContains(t, elsewhere.Context, "jiraChanges")
+
+	ctx, err := sessions.GetSessionContext(onProject.ID)
+	require.NoError(t, err)
+	var offered []string
+	for _, suggestion := range suggestions.GetSuggestions(ctx) {
+		offered = append(offered, suggestion.Command)
+	}
+	assert.Contains(t, offered, "Show PROJ-2")
+}
+
+func TestWebhookRegistration(t *testing.T) {
+	var registered map[string]interface{}
+	deleted := ""
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/webhooks/1.0/webhook", func(w http.ResponseWriter, r *http.Request) {
+		hook := map[string]interface{}{
+			"self":    "https://example.atlassian.net/rest/webhooks/1.0/webhook/7",
+			"name":    "GoJira",
+			"url":     "https://gojira.example.com/webhooks/jira?secret=s3cret",
+			"events":  jira.DefaultWebhookEvents,
+			"enabled": true,
+		}
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&registered)
+			writeJSON(w, http.StatusCreated, hook)
+			return
+		}
+		writeJSON(w, http.StatusOK, []map[string]interface{}{hook})
+	})
+	mux.HandleFunc("/rest/webhooks/1.0/webhook/7", func(w http.ResponseWriter, r *http.Request) {
+		deleted = r.Method
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := newFakeJiraClient(t, mux)
+	ctx := context.Background()
+
+	hook, err := client.RegisterWebhook(ctx, &jira.WebhookRequest{
+		Name: "GoJira",
+		URL:  "https://gojira.example.com/webhooks/jira?secret=s3cret",
+		JQL:  "project = PROJ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "7", hook.ID())
+	assert.Len(t, registered["events"], len(jira.DefaultWebhookEvents), "all synced events by default")
+	assert.Equal(t, map[string]interface{}{"issue-related-events-section": "project = PROJ"}, registered["filters"])
+	assert.NotContains(t, registered, "secret")
+
+	hooks, err := client.GetWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.True(t, hooks[0].Enabled)
+
+	require.NoError(t, client.DeleteWebhook(ctx, hook.ID()))
+	assert.Equal(t, http.MethodDelete, deleted)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ericfisherdev/GoJira/internal/jira"
+	"github.com/ericfisherdev/GoJira/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUserClient serves a fixed set of users and counts upstream calls
+type MockUserClient struct {
+	users []jira.User
+	calls int
+}
+
+func (m *MockUserClient) SearchUsers(query string, maxResults int) ([]jira.User, error) {
+	m.calls++
+	return m.users, nil
+}
+
+func (m *MockUserClient) FindAssignableUsers(q jira.AssignableUserQuery) ([]jira.User, error) {
+	m.calls++
+	return m.users, nil
+}
+
+func (m *MockUserClient) GetUser(accountID string) (*jira.User, error) {
+	m.calls++
+	for _, user := range m.users {
+		if user.AccountID == accountID {
+			return &user, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func newMockUserClient() *MockUserClient {
+	return &MockUserClient{
+		users: []jira.User{
+			{AccountID: "5b10a2844c20165700ede21g", DisplayName: "Sam Park", EmailAddress: "sam.park@example.com", Active: true},
+			{AccountID: "5b10a2844c20165700ede22h", DisplayName: "Sam Peters", EmailAddress: "speters@example.com", Active: true},
+			{AccountID: "5b10a2844c20165700ede23i", DisplayName: "Alex Kim", EmailAddress: "alex@example.com", Active: true},
+		},
+	}
+}
+
+func TestUserDirectoryResolve(t *testing.T) {
+	directory := services.NewUserDirectory(newMockUserClient())
+
+	tests := []struct {
+		input     string
+		accountID string
+		ambiguous bool
+	}{
+		{input: "sam.park@example.com", accountID: "5b10a2844c20165700ede21g"},
+		{input: "@speters", accountID: "5b10a2844c20165700ede22h"},
+		{input: "Alex Kim", accountID: "5b10a2844c20165700ede23i"},
+		{input: "sam.park", accountID: "5b10a2844c20165700ede21g"},
+		{input: "Sam P", ambiguous: true},
+		{input: "@sam", ambiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			resolution, err := directory.Resolve(tt.input, jira.AssignableUserQuery{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.ambiguous, resolution.Ambiguous)
+			if tt.ambiguous {
+				assert.False(t, resolution.Resolved)
+				assert.Len(t, resolution.Candidates, 2)
+			} else {
+				assert.True(t, resolution.Resolved)
+				assert.Equal(t, tt.accountID, resolution.AccountID)
+			}
+		})
+	}
+}
+
+func TestUserDirectoryUnknownUser(t *testing.T) {
+	directory := services.NewUserDirectory(newMockUserClient())
+
+	resolution, err := directory.Resolve("Jordan", jira.AssignableUserQuery{ProjectKey: "PROJ"})
+	require.NoError(t, err)
+	assert.False(t, resolution.Resolved)
+	assert.False(t, resolution.Ambiguous)
+	assert.NotEmpty(t, resolution.Message)
+}
+
+func TestUserDirectoryCachingAndNLPUsers(t *testing.T) {
+	client := newMockUserClient()
+	directory := services.NewUserDirectory(client)
+
+	_, err := directory.Search("sam", 10)
+	require.NoError(t, err)
+	_, err = directory.Search("SAM", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.calls, "repeated query should be served from cache")
+	assert.NotZero(t, directory.Version())
+
+	users := directory.NLPUsers()
+	require.Contains(t, users, "sam.park")
+	assert.Equal(t, "5b10a2844c20165700ede21g", users["sam.park"].Username)
+	assert.Contains(t, users, "speters")
+	assert.Contains(t, users, "alex@example.com")
+}
+
+func TestUserDirectoryBoundsCachedUsers(t *testing.T) {
+	client := &MockUserClient{}
+	directory := services.NewUserDirectory(client)
+
+	batch := func(prefix string, n int) []jira.User {
+		users := make([]jira.User, n)
+		for i := range users {
+			users[i] = jira.User{AccountID: fmt.Sprintf("%s%023x", prefix, i), DisplayName: fmt.Sprintf("User %s%d", prefix, i)}
+		}
+		return users
+	}
+
+	client.users = batch("a", 3000)
+	_, err := directory.Search("first", 10)
+	require.NoError(t, err)
+	version := directory.Version()
+
+	client.users = batch("b", 3000)
+	_, err = directory.Search("second", 10)
+	require.NoError(t, err)
+	assert.Len(t, directory.Users(), 5000, "the least recently seen users are dropped")
+	assert.Greater(t, directory.Version(), version)
+
+	// A lookup whose users were dropped is asked again
+	calls := client.calls
+	_, err = directory.Search("first", 10)
+	require.NoError(t, err)
+	assert.Equal(t, calls+1, client.calls)
+}